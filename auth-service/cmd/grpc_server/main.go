@@ -50,9 +50,15 @@ func main() {
 	// Initialize repositories
 	userRepo := repo.NewUserRepository(db)
 	sessionRepo := repo.NewSessionRepository(db)
+	apiKeyRepo := repo.NewAPIKeyRepository(db)
+	denylistRepo := repo.NewTokenDenylistRepository(db)
+	twoFactorRepo := repo.NewTwoFactorRepository(db)
+	throttleRepo := repo.NewLoginThrottleRepository(db)
+	resetRepo := repo.NewPasswordResetRepository(db)
+	emailVerificationRepo := repo.NewEmailVerificationRepository(db)
 
 	// Initialize application
-	application := app.NewApp(ctx, userRepo, sessionRepo, db)
+	application := app.NewApp(ctx, userRepo, sessionRepo, apiKeyRepo, denylistRepo, twoFactorRepo, throttleRepo, resetRepo, emailVerificationRepo, db)
 
 	// Get gRPC server port
 	port := os.Getenv("GRPC_SERVER_PORT")
@@ -50,6 +50,9 @@ func run() error {
 	if err := gw.RegisterAccessServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
 		return err
 	}
+	if err := gw.RegisterBotServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return err
+	}
 
 	server := &http.Server{
 		Addr:    ":" + httpPort,
@@ -2,13 +2,23 @@ package api
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"log"
+	"math/big"
+	"time"
 
 	pb "auth.service/api/proto"
 	"auth.service/internal/service"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// publicKeysMaxAge is how long verifiers may cache public keys before asking again
+const publicKeysMaxAge = 5 * time.Minute
+
 type AccessServiceHandler struct {
 	pb.UnimplementedAccessServiceServer
 	accessService service.AccessService
@@ -38,3 +48,72 @@ func (h *AccessServiceHandler) Check(ctx context.Context, req *pb.CheckAccessReq
 		UserId:  userID,
 	}, nil
 }
+
+func (h *AccessServiceHandler) GetPublicKeys(ctx context.Context, req *pb.GetPublicKeysRequest) (*pb.GetPublicKeysResponse, error) {
+	keys, err := h.accessService.PublicKeys(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get public keys")
+	}
+
+	resp := &pb.GetPublicKeysResponse{
+		MaxAgeSeconds: int32(publicKeysMaxAge.Seconds()),
+	}
+	for _, key := range keys {
+		jwk, ok := publicKeyToJWK(key)
+		if !ok {
+			log.Printf("skipping key %s: unsupported key type %T", key.ID, key.Key)
+			continue
+		}
+		resp.Keys = append(resp.Keys, jwk)
+	}
+
+	return resp, nil
+}
+
+func (h *AccessServiceHandler) ListRevokedTokens(ctx context.Context, req *pb.ListRevokedTokensRequest) (*pb.ListRevokedTokensResponse, error) {
+	var since time.Time
+	if req.Since != nil {
+		since = req.Since.AsTime()
+	}
+
+	tokens, serverTime, err := h.accessService.RevokedTokens(ctx, since)
+	if err != nil {
+		log.Printf("Error listing revoked tokens: %v", err)
+		return nil, status.Error(codes.Internal, "failed to list revoked tokens")
+	}
+
+	resp := &pb.ListRevokedTokensResponse{
+		ServerTime: timestamppb.New(serverTime),
+	}
+	for _, token := range tokens {
+		resp.Tokens = append(resp.Tokens, &pb.RevokedToken{
+			Jti:       token.JTI,
+			ExpiresAt: timestamppb.New(token.ExpiresAt),
+		})
+	}
+
+	return resp, nil
+}
+
+func publicKeyToJWK(key service.PublicKey) (*pb.JSONWebKey, bool) {
+	jwk := &pb.JSONWebKey{
+		Kid: key.ID,
+		Alg: key.Algorithm,
+		Use: "sig",
+	}
+
+	switch k := key.Key.(type) {
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	default:
+		return nil, false
+	}
+
+	return jwk, true
+}
@@ -2,23 +2,33 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net"
+	"net/netip"
+	"strings"
+	"unicode/utf8"
 
 	pb "auth.service/api/proto"
 	"auth.service/internal/service"
 	"auth.service/internal/service/auth_service"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type AuthServiceHandler struct {
 	pb.UnimplementedAuthServiceServer
 	authService service.AuthService
+	proxies     *TrustedProxies
 }
 
-func NewAuthServiceHandler(authService service.AuthService) *AuthServiceHandler {
+func NewAuthServiceHandler(authService service.AuthService, proxies *TrustedProxies) *AuthServiceHandler {
 	return &AuthServiceHandler{
 		authService: authService,
+		proxies:     proxies,
 	}
 }
 
@@ -27,22 +37,65 @@ func (h *AuthServiceHandler) Login(ctx context.Context, req *pb.LoginRequest) (*
 		return nil, status.Error(codes.InvalidArgument, "Username and password are required")
 	}
 
-	tokens, err := h.authService.Login(ctx, req.Username, req.Password)
+	result, err := h.authService.Login(ctx, req.Username, req.Password, h.proxies.clientInfo(ctx, req.DeviceName))
 	if err != nil {
 		log.Printf("Error logging in: %v", err)
 		switch err {
 		case auth_service.ErrInvalidCredentials:
 			return nil, status.Error(codes.Unauthenticated, "Invalid credentials")
+		case auth_service.ErrTooManyAttempts:
+			return nil, status.Error(codes.ResourceExhausted, "Too many failed login attempts, try again later")
+		case auth_service.ErrAccountLocked:
+			return nil, status.Error(codes.PermissionDenied, "Account is locked after too many failed login attempts, contact an administrator")
 		default:
 			return nil, status.Error(codes.Internal, "Internal server error")
 		}
 	}
 
+	if result.Challenge != nil {
+		return &pb.LoginResponse{
+			SecondFactorRequired: true,
+			ChallengeToken:       result.Challenge.Token,
+			ChallengeExpiresAt:   timestamppb.New(result.Challenge.ExpiresAt),
+		}, nil
+	}
+
+	return loginResponse(result.Tokens), nil
+}
+
+func (h *AuthServiceHandler) VerifySecondFactor(ctx context.Context, req *pb.VerifySecondFactorRequest) (*pb.LoginResponse, error) {
+	if req.ChallengeToken == "" || req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "Challenge token and code are required")
+	}
+
+	tokens, err := h.authService.VerifySecondFactor(ctx, req.ChallengeToken, req.Code, h.proxies.clientInfo(ctx, ""))
+	if err != nil {
+		log.Printf("Error verifying second factor: %v", err)
+		switch err {
+		case auth_service.ErrInvalidChallenge:
+			return nil, status.Error(codes.Unauthenticated, "Login challenge is invalid or has expired, log in again")
+		case auth_service.ErrInvalidSecondFactor:
+			return nil, status.Error(codes.Unauthenticated, "Invalid code")
+		case auth_service.ErrTooManyAttempts:
+			return nil, status.Error(codes.ResourceExhausted, "Too many failed login attempts, try again later")
+		case auth_service.ErrAccountLocked:
+			return nil, status.Error(codes.PermissionDenied, "Account is locked after too many failed login attempts, contact an administrator")
+		default:
+			return nil, status.Error(codes.Internal, "Internal server error")
+		}
+	}
+
+	return loginResponse(tokens), nil
+}
+
+func loginResponse(tokens *service.TokenPair) *pb.LoginResponse {
 	return &pb.LoginResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		UserId:       tokens.UserID,
-	}, nil
+		AccessToken:           tokens.AccessToken,
+		RefreshToken:          tokens.RefreshToken,
+		UserId:                tokens.UserID,
+		AccessTokenExpiresAt:  timestamppb.New(tokens.AccessTokenExpiresAt),
+		RefreshTokenExpiresAt: timestamppb.New(tokens.RefreshTokenExpiresAt),
+	}
 }
 
 func (h *AuthServiceHandler) GetAccessToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.AccessTokenResponse, error) {
@@ -50,7 +103,7 @@ func (h *AuthServiceHandler) GetAccessToken(ctx context.Context, req *pb.Refresh
 		return nil, status.Error(codes.InvalidArgument, "Refresh token is required")
 	}
 
-	tokens, err := h.authService.RefreshTokens(ctx, req.RefreshToken)
+	tokens, err := h.authService.RefreshTokens(ctx, req.RefreshToken, h.proxies.clientInfo(ctx, ""))
 	if err != nil {
 		log.Printf("Error refreshing tokens: %v", err)
 		switch err {
@@ -60,12 +113,274 @@ func (h *AuthServiceHandler) GetAccessToken(ctx context.Context, req *pb.Refresh
 			return nil, status.Error(codes.Unauthenticated, "Refresh token has expired")
 		case auth_service.ErrTokenNotFound:
 			return nil, status.Error(codes.Unauthenticated, "Refresh token not found")
+		case auth_service.ErrTokenReused:
+			return nil, status.Error(codes.Unauthenticated, "Refresh token has already been used, log in again")
 		default:
 			return nil, status.Error(codes.Internal, "Internal server error")
 		}
 	}
 
 	return &pb.AccessTokenResponse{
-		AccessToken: tokens.AccessToken,
+		AccessToken:           tokens.AccessToken,
+		RefreshToken:          tokens.RefreshToken,
+		AccessTokenExpiresAt:  timestamppb.New(tokens.AccessTokenExpiresAt),
+		RefreshTokenExpiresAt: timestamppb.New(tokens.RefreshTokenExpiresAt),
 	}, nil
 }
+
+func (h *AuthServiceHandler) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "Refresh token is required")
+	}
+
+	if err := h.authService.Logout(ctx, req.RefreshToken, bearerToken(ctx)); err != nil {
+		log.Printf("Error logging out: %v", err)
+		switch err {
+		case auth_service.ErrTokenNotFound:
+			return nil, status.Error(codes.Unauthenticated, "Refresh token not found")
+		default:
+			return nil, status.Error(codes.Internal, "Internal server error")
+		}
+	}
+
+	return &pb.LogoutResponse{}, nil
+}
+
+func (h *AuthServiceHandler) LogoutAll(ctx context.Context, req *pb.LogoutAllRequest) (*pb.LogoutResponse, error) {
+	userID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.authService.LogoutAll(ctx, userID, bearerToken(ctx)); err != nil {
+		log.Printf("Error logging out of all sessions: %v", err)
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+
+	return &pb.LogoutResponse{}, nil
+}
+
+func (h *AuthServiceHandler) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	userID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := h.authService.ListSessions(ctx, userID)
+	if err != nil {
+		log.Printf("Error listing sessions: %v", err)
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+
+	// The current session is known from the access token the request was made with
+	var currentID string
+	if claims, err := h.authService.ValidateToken(ctx, bearerToken(ctx)); err == nil {
+		currentID = claims.SessionID
+	}
+
+	resp := &pb.ListSessionsResponse{}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, &pb.Session{
+			SessionId:  session.ID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IPAddress,
+			CreatedAt:  timestamppb.New(session.CreatedAt),
+			LastUsedAt: timestamppb.New(session.LastUsedAt),
+			ExpiresAt:  timestamppb.New(session.ExpiresAt),
+			Current:    session.ID == currentID,
+		})
+	}
+
+	return resp, nil
+}
+
+func (h *AuthServiceHandler) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	if req.SessionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "Session ID is required")
+	}
+
+	userID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.authService.RevokeSession(ctx, userID, req.SessionId, bearerToken(ctx)); err != nil {
+		log.Printf("Error revoking session: %v", err)
+		switch err {
+		case auth_service.ErrSessionNotFound:
+			return nil, status.Error(codes.NotFound, "Session not found")
+		default:
+			return nil, status.Error(codes.Internal, "Internal server error")
+		}
+	}
+
+	return &pb.RevokeSessionResponse{}, nil
+}
+
+// Limits of the session columns, longer values are cut
+const (
+	maxDeviceNameLength = 255
+	maxUserAgentLength  = 512
+	maxIPAddressLength  = 64
+)
+
+// TrustedProxies lists the addresses of the HTTP gateway and proxies in front of the service.
+// Clients can put anything into x-forwarded-for, so the header is only trusted when the request
+// comes from one of these addresses
+type TrustedProxies struct {
+	prefixes []netip.Prefix
+}
+
+// ParseTrustedProxies parses IP addresses and CIDR ranges, e.g. "127.0.0.1" or "10.0.0.0/8"
+func ParseTrustedProxies(values []string) (*TrustedProxies, error) {
+	op := "api.ParseTrustedProxies"
+
+	proxies := &TrustedProxies{}
+	for _, value := range values {
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			proxies.prefixes = append(proxies.prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		addr = addr.Unmap()
+		proxies.prefixes = append(proxies.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return proxies, nil
+}
+
+// trusts reports whether ip belongs to a trusted gateway or proxy
+func (p *TrustedProxies) trusts(ip string) bool {
+	if p == nil {
+		return false
+	}
+
+	addr, err := parseHop(ip)
+	if err != nil {
+		return false
+	}
+
+	for _, prefix := range p.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// remoteIP returns the client address for login throttling. Without a trusted peer it is the
+// address of the gRPC connection. Every trusted gateway or proxy appends the address it got the
+// request from to x-forwarded-for, so the hops are walked from the right and the first one that
+// is not a trusted proxy is the client; anything to the left of it may be forged by the client
+func (p *TrustedProxies) remoteIP(ctx context.Context) string {
+	ip := peerIP(ctx)
+	if !p.trusts(ip) {
+		return ip
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ip
+	}
+
+	var hops []string
+	for _, value := range md.Get("x-forwarded-for") {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !p.trusts(hops[i]) {
+			return hops[i]
+		}
+		ip = hops[i]
+	}
+
+	// Every hop is a trusted proxy, the request was sent from inside the deployment
+	return ip
+}
+
+// parseHop parses an x-forwarded-for entry, some proxies add the client port to the address
+func parseHop(hop string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(hop)
+	if err != nil {
+		addrPort, portErr := netip.ParseAddrPort(hop)
+		if portErr != nil {
+			return netip.Addr{}, err
+		}
+		addr = addrPort.Addr()
+	}
+
+	return addr.Unmap(), nil
+}
+
+// peerIP returns the address of the gRPC connection without the port
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
+
+// clientInfo describes the device a request comes from. The stored address is the one
+// resolved through the trusted proxies, so a client can not put an arbitrary value into it
+func (p *TrustedProxies) clientInfo(ctx context.Context, deviceName string) service.ClientInfo {
+	info := service.ClientInfo{DeviceName: deviceName}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("grpcgateway-user-agent"); len(values) > 0 {
+			info.UserAgent = values[0]
+		} else if values := md.Get("user-agent"); len(values) > 0 {
+			info.UserAgent = values[0]
+		}
+	}
+
+	info.RemoteIP = p.remoteIP(ctx)
+	// A trusted proxy may still forward garbage, only a valid address is stored
+	if addr, err := parseHop(info.RemoteIP); err == nil {
+		info.IPAddress = addr.String()
+	}
+
+	if info.DeviceName == "" {
+		info.DeviceName = info.UserAgent
+	}
+
+	info.DeviceName = truncate(info.DeviceName, maxDeviceNameLength)
+	info.UserAgent = truncate(info.UserAgent, maxUserAgentLength)
+	info.IPAddress = truncate(info.IPAddress, maxIPAddressLength)
+
+	return info
+}
+
+// truncate cuts s to at most max bytes without splitting a UTF-8 character
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+
+	return s[:max]
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func requestContext(peerAddr string, forwardedFor ...string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(peerAddr), Port: 40000}})
+
+	md := metadata.MD{}
+	for _, value := range forwardedFor {
+		md.Append("x-forwarded-for", value)
+	}
+
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+func TestRemoteIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"127.0.0.1", "10.1.0.0/16"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		want         string
+	}{
+		{"direct client", "203.0.113.7", nil, "203.0.113.7"},
+		{"untrusted peer cannot forge the header", "203.0.113.7", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"untrusted private peer", "10.2.0.5", []string{"198.51.100.1"}, "10.2.0.5"},
+		{"gateway", "127.0.0.1", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"forged hops left of the client are ignored", "127.0.0.1", []string{"1.1.1.1, 198.51.100.1"}, "198.51.100.1"},
+		{"trusted proxy chain", "127.0.0.1", []string{"1.1.1.1, 198.51.100.1, 10.1.0.3"}, "198.51.100.1"},
+		{"several header values", "127.0.0.1", []string{"1.1.1.1", "198.51.100.1, 10.1.0.3"}, "198.51.100.1"},
+		{"hop with a port", "127.0.0.1", []string{"198.51.100.1:5123, 10.1.0.3"}, "198.51.100.1:5123"},
+		{"only trusted hops", "127.0.0.1", []string{"10.1.0.4, 10.1.0.3"}, "10.1.0.4"},
+		{"gateway without the header", "127.0.0.1", nil, "127.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := proxies.remoteIP(requestContext(tt.peer, tt.forwardedFor...)); got != tt.want {
+				t.Errorf("remoteIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteIPWithoutTrustedProxies(t *testing.T) {
+	var proxies *TrustedProxies
+	if got := proxies.remoteIP(requestContext("127.0.0.1", "198.51.100.1")); got != "127.0.0.1" {
+		t.Errorf("remoteIP() = %q, want the peer address", got)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	for _, value := range []string{"localhost", "10.0.0.0/33", "300.1.1.1"} {
+		if _, err := ParseTrustedProxies([]string{value}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) = nil error, want an error", value)
+		}
+	}
+}
+
+func TestClientInfoIPAddress(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"127.0.0.1"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		want         string
+	}{
+		{"direct client", "203.0.113.7", nil, "203.0.113.7"},
+		{"untrusted peer cannot forge the address", "203.0.113.7", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"forged hops left of the client are ignored", "127.0.0.1", []string{"1.1.1.1, 198.51.100.1"}, "198.51.100.1"},
+		{"port is dropped", "127.0.0.1", []string{"198.51.100.1:5123"}, "198.51.100.1"},
+		{"garbage from the gateway is not stored", "127.0.0.1", []string{strings.Repeat("x", 100)}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := proxies.clientInfo(requestContext(tt.peer, tt.forwardedFor...), "")
+			if info.IPAddress != tt.want {
+				t.Errorf("IPAddress = %q, want %q", info.IPAddress, tt.want)
+			}
+			if len(info.IPAddress) > maxIPAddressLength {
+				t.Errorf("IPAddress is %d bytes, the column holds %d", len(info.IPAddress), maxIPAddressLength)
+			}
+		})
+	}
+}
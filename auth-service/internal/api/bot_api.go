@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	pb "auth.service/api/proto"
+	"auth.service/internal/service"
+	"auth.service/internal/service/auth_service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type BotServiceHandler struct {
+	pb.UnimplementedBotServiceServer
+	botService  service.BotService
+	authService service.AuthService
+}
+
+func NewBotServiceHandler(botService service.BotService, authService service.AuthService) *BotServiceHandler {
+	return &BotServiceHandler{
+		botService:  botService,
+		authService: authService,
+	}
+}
+
+func (h *BotServiceHandler) CreateBot(ctx context.Context, req *pb.CreateBotRequest) (*pb.CreateBotResponse, error) {
+	ownerID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Username == "" {
+		return nil, status.Error(codes.InvalidArgument, "username is required")
+	}
+
+	bot, err := h.botService.CreateBot(ctx, ownerID, req.Username)
+	if err != nil {
+		log.Printf("failed to create bot: %v", err)
+		return nil, botError(err)
+	}
+
+	return &pb.CreateBotResponse{
+		UserId:   bot.ID,
+		Username: bot.Username,
+		ApiKeyId: bot.APIKey.ID,
+		ApiKey:   bot.APIKey.Key,
+	}, nil
+}
+
+func (h *BotServiceHandler) CreateBotAPIKey(ctx context.Context, req *pb.CreateBotAPIKeyRequest) (*pb.BotAPIKeyResponse, error) {
+	ownerID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BotId == "" {
+		return nil, status.Error(codes.InvalidArgument, "bot ID is required")
+	}
+
+	key, err := h.botService.CreateAPIKey(ctx, ownerID, req.BotId)
+	if err != nil {
+		log.Printf("failed to create bot api key: %v", err)
+		return nil, botError(err)
+	}
+
+	return &pb.BotAPIKeyResponse{
+		ApiKeyId: key.ID,
+		ApiKey:   key.Key,
+	}, nil
+}
+
+func (h *BotServiceHandler) RevokeBotAPIKey(ctx context.Context, req *pb.RevokeBotAPIKeyRequest) (*pb.RevokeBotAPIKeyResponse, error) {
+	ownerID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ApiKeyId == "" {
+		return nil, status.Error(codes.InvalidArgument, "api key ID is required")
+	}
+
+	if err := h.botService.RevokeAPIKey(ctx, ownerID, req.ApiKeyId); err != nil {
+		log.Printf("failed to revoke bot api key: %v", err)
+		return nil, botError(err)
+	}
+
+	return &pb.RevokeBotAPIKeyResponse{}, nil
+}
+
+func botError(err error) error {
+	if st, ok := validationStatus(err); ok {
+		return st
+	}
+
+	switch err {
+	case auth_service.ErrUserAlreadyExists:
+		return status.Error(codes.AlreadyExists, "user already exists")
+	case auth_service.ErrNotBotOwner:
+		return status.Error(codes.PermissionDenied, "only the bot owner can manage its api keys")
+	case auth_service.ErrInvalidAPIKey:
+		return status.Error(codes.NotFound, "api key not found")
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+// authenticatedUserID validates the bearer access token from the request metadata
+// and returns the ID of the user it was issued to.
+func authenticatedUserID(ctx context.Context, authService service.AuthService) (string, error) {
+	token := bearerToken(ctx)
+	if token == "" {
+		return "", status.Error(codes.Unauthenticated, "access token is required")
+	}
+
+	claims, err := authService.ValidateToken(ctx, token)
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, "invalid access token")
+	}
+
+	return claims.UserID, nil
+}
+
+// bearerToken returns the bearer access token from the request metadata, or an empty string
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+		return ""
+	}
+
+	return strings.TrimPrefix(values[0], "Bearer ")
+}
@@ -2,10 +2,13 @@ package api
 
 import (
 	"context"
+	"errors"
 	"log"
 
 	pb "auth.service/api/proto"
 	"auth.service/internal/service"
+	"auth.service/internal/service/auth_service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -13,22 +16,27 @@ import (
 type UserServiceHandler struct {
 	pb.UnimplementedUserServiceServer
 	userService service.UserService
+	authService service.AuthService
+	proxies     *TrustedProxies
 }
 
-func NewUserServiceHandler(userService service.UserService) *UserServiceHandler {
+func NewUserServiceHandler(userService service.UserService, authService service.AuthService, proxies *TrustedProxies) *UserServiceHandler {
 	return &UserServiceHandler{
 		userService: userService,
+		authService: authService,
+		proxies:     proxies,
 	}
 }
 
+// CreateUser registers a user. Empty fields are reported by the credential policy
+// together with the other violations
 func (h *UserServiceHandler) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.UserResponse, error) {
-	if req.Username == "" || req.Password == "" {
-		return nil, status.Error(codes.InvalidArgument, "username and password are required")
-	}
-
-	userID, err := h.userService.CreateUser(ctx, req.Username, req.Password)
+	userID, err := h.userService.CreateUser(ctx, req.Username, req.Password, req.Email)
 	if err != nil {
 		log.Printf("failed to create user: %v", err)
+		if st, ok := validationStatus(err); ok {
+			return nil, st
+		}
 		switch err {
 		case service.ErrUserAlreadyExists:
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
@@ -62,35 +70,106 @@ func (h *UserServiceHandler) GetUser(ctx context.Context, req *pb.GetUserRequest
 	return &pb.UserResponse{
 		UserId:   user.ID,
 		Username: user.Username,
+		IsBot:    user.IsBot,
+	}, nil
+}
+
+// maxUsersPerLookup limits the number of IDs in one GetUsers request
+const maxUsersPerLookup = 1000
+
+func (h *UserServiceHandler) GetUsers(ctx context.Context, req *pb.GetUsersRequest) (*pb.GetUsersResponse, error) {
+	if len(req.UserIds) > maxUsersPerLookup {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d user IDs are allowed", maxUsersPerLookup)
+	}
+
+	if len(req.UserIds) == 0 {
+		return &pb.GetUsersResponse{}, nil
+	}
+
+	users, err := h.userService.UsersByIDs(ctx, req.UserIds)
+	if err != nil {
+		log.Printf("failed to get users: %v", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	resp := &pb.GetUsersResponse{Users: make([]*pb.UserResponse, 0, len(users))}
+	for _, user := range users {
+		resp.Users = append(resp.Users, &pb.UserResponse{
+			UserId:   user.ID,
+			Username: user.Username,
+			IsBot:    user.IsBot,
+		})
+	}
+
+	return resp, nil
+}
+
+func (h *UserServiceHandler) GetUserByUsername(ctx context.Context, req *pb.GetUserByUsernameRequest) (*pb.UserResponse, error) {
+	if req.Username == "" {
+		return nil, status.Error(codes.InvalidArgument, "username is required")
+	}
+
+	user, err := h.userService.UserByUsername(ctx, req.Username)
+	if err != nil {
+		log.Printf("failed to get user by username: %v", err)
+		switch err {
+		case service.ErrUserNotFound:
+			return nil, status.Error(codes.NotFound, "user not found")
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &pb.UserResponse{
+		UserId:   user.ID,
+		Username: user.Username,
+		IsBot:    user.IsBot,
 	}, nil
 }
 
+// UpdateUser changes the caller's own account only. A new email is set after ConfirmEmail
 func (h *UserServiceHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserResponse, error) {
 	if req.UserId == nil {
 		return nil, status.Error(codes.InvalidArgument, "user ID is required")
 	}
 
+	callerID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
 	userID := req.UserId.Value
-	var username, password string
+	if userID != callerID {
+		return nil, status.Error(codes.PermissionDenied, "users can only update their own account")
+	}
+	var username, password, email string
 	if req.Username != nil {
 		username = req.Username.Value
 	}
 	if req.Password != nil {
 		password = req.Password.Value
 	}
+	if req.Email != nil {
+		email = req.Email.Value
+	}
 
-	if username == "" && password == "" {
-		return nil, status.Error(codes.InvalidArgument, "at least one field (username or password) is required")
+	if username == "" && password == "" && email == "" {
+		return nil, status.Error(codes.InvalidArgument, "at least one field (username, password or email) is required")
 	}
 
-	err := h.userService.UpdateUser(ctx, userID, username, password)
+	err = h.userService.UpdateUser(ctx, userID, username, password, email)
 	if err != nil {
 		log.Printf("failed to update user: %v", err)
+		if st, ok := validationStatus(err); ok {
+			return nil, st
+		}
 		switch err {
 		case service.ErrUserNotFound:
 			return nil, status.Error(codes.NotFound, "user not found")
 		case service.ErrUserAlreadyExists:
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
+		case auth_service.ErrTooManyEmailVerifications:
+			return nil, status.Error(codes.ResourceExhausted, "too many email changes, try again later")
 		default:
 			return nil, status.Error(codes.Internal, "internal server error")
 		}
@@ -134,3 +213,195 @@ func (h *UserServiceHandler) DeleteUser(ctx context.Context, req *pb.DeleteUserR
 		Username: user.Username,
 	}, nil
 }
+
+func (h *UserServiceHandler) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	userID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := h.userService.EnrollTOTP(ctx, userID)
+	if err != nil {
+		log.Printf("failed to enroll TOTP: %v", err)
+		switch err {
+		case auth_service.ErrUserNotFound:
+			return nil, status.Error(codes.NotFound, "user not found")
+		case auth_service.ErrTwoFactorUnavailable:
+			return nil, status.Error(codes.FailedPrecondition, "two-factor authentication is not available")
+		case auth_service.ErrTwoFactorAlreadyEnabled:
+			return nil, status.Error(codes.AlreadyExists, "two-factor authentication is already enabled")
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &pb.EnrollTOTPResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningUri: enrollment.ProvisioningURI,
+	}, nil
+}
+
+func (h *UserServiceHandler) ConfirmTOTP(ctx context.Context, req *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	if req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	userID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := h.userService.ConfirmTOTP(ctx, userID, req.Code, h.proxies.clientInfo(ctx, ""))
+	if err != nil {
+		log.Printf("failed to confirm TOTP: %v", err)
+		switch err {
+		case auth_service.ErrTooManyAttempts:
+			return nil, status.Error(codes.ResourceExhausted, "Too many failed login attempts, try again later")
+		case auth_service.ErrAccountLocked:
+			return nil, status.Error(codes.PermissionDenied, "Account is locked after too many failed login attempts, contact an administrator")
+		case auth_service.ErrTwoFactorNotEnrolled:
+			return nil, status.Error(codes.FailedPrecondition, "two-factor enrollment has not been started")
+		case auth_service.ErrTwoFactorAlreadyEnabled:
+			return nil, status.Error(codes.AlreadyExists, "two-factor authentication is already enabled")
+		case auth_service.ErrInvalidSecondFactor:
+			return nil, status.Error(codes.InvalidArgument, "invalid code")
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &pb.ConfirmTOTPResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (h *UserServiceHandler) DisableTOTP(ctx context.Context, req *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error) {
+	if req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	userID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.userService.DisableTOTP(ctx, userID, req.Code, h.proxies.clientInfo(ctx, "")); err != nil {
+		log.Printf("failed to disable TOTP: %v", err)
+		switch err {
+		case auth_service.ErrTooManyAttempts:
+			return nil, status.Error(codes.ResourceExhausted, "Too many failed login attempts, try again later")
+		case auth_service.ErrAccountLocked:
+			return nil, status.Error(codes.PermissionDenied, "Account is locked after too many failed login attempts, contact an administrator")
+		case auth_service.ErrTwoFactorNotEnrolled:
+			return nil, status.Error(codes.FailedPrecondition, "two-factor authentication is not enabled")
+		case auth_service.ErrInvalidSecondFactor:
+			return nil, status.Error(codes.InvalidArgument, "invalid code")
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &pb.DisableTOTPResponse{}, nil
+}
+
+func (h *UserServiceHandler) UnlockUser(ctx context.Context, req *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error) {
+	if req.Username == "" {
+		return nil, status.Error(codes.InvalidArgument, "username is required")
+	}
+
+	adminID, err := authenticatedUserID(ctx, h.authService)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.userService.UnlockUser(ctx, adminID, req.Username); err != nil {
+		log.Printf("failed to unlock user: %v", err)
+		switch err {
+		case auth_service.ErrNotAdmin:
+			return nil, status.Error(codes.PermissionDenied, "only admins can unlock users")
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &pb.UnlockUserResponse{}, nil
+}
+
+// RequestPasswordReset answers the same whether the email is registered or not
+func (h *UserServiceHandler) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := h.userService.RequestPasswordReset(ctx, req.Email); err != nil {
+		log.Printf("failed to request password reset: %v", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+func (h *UserServiceHandler) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := h.userService.ResetPassword(ctx, req.Token, req.NewPassword); err != nil {
+		log.Printf("failed to reset password: %v", err)
+		if st, ok := validationStatus(err); ok {
+			return nil, st
+		}
+		switch err {
+		case auth_service.ErrInvalidResetToken:
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired reset token")
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &pb.ResetPasswordResponse{}, nil
+}
+
+func (h *UserServiceHandler) ConfirmEmail(ctx context.Context, req *pb.ConfirmEmailRequest) (*pb.ConfirmEmailResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := h.userService.ConfirmEmail(ctx, req.Token); err != nil {
+		log.Printf("failed to confirm email: %v", err)
+		switch err {
+		case auth_service.ErrInvalidEmailToken:
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired confirmation token")
+		case auth_service.ErrEmailAlreadyUsed:
+			return nil, status.Error(codes.AlreadyExists, "email is already used")
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &pb.ConfirmEmailResponse{}, nil
+}
+
+// validationStatus converts a credential policy violation into InvalidArgument with
+// a BadRequest detail listing every violated rule
+func validationStatus(err error) (error, bool) {
+	var validationErr *auth_service.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, false
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range validationErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Description,
+		})
+	}
+
+	st, detailsErr := status.New(codes.InvalidArgument, validationErr.Error()).WithDetails(badRequest)
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, validationErr.Error()), true
+	}
+
+	return st.Err(), true
+}
@@ -6,11 +6,13 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	pb "auth.service/api/proto"
 	"auth.service/internal/api"
+	"auth.service/internal/mailer"
 	"auth.service/internal/repository"
 	"auth.service/internal/service/auth_service"
 	"github.com/jmoiron/sqlx"
@@ -19,46 +21,109 @@ import (
 )
 
 type App struct {
-	userRepo    repository.UserRepository
-	sessionRepo repository.SessionRepository
-	grpcServer  *grpc.Server
-	port        string
-	db          *sqlx.DB
+	userRepo          repository.UserRepository
+	sessionRepo       repository.SessionRepository
+	apiKeyRepo        repository.APIKeyRepository
+	denylistRepo      repository.TokenDenylistRepository
+	twoFactor         *auth_service.TwoFactor
+	throttle          *auth_service.LoginThrottle
+	policy            *auth_service.CredentialPolicy
+	passwordReset     *auth_service.PasswordReset
+	emailVerification *auth_service.EmailVerification
+	adminIDs          []string
+	trustedProxies    *api.TrustedProxies
+	keys              *auth_service.KeyStore
+	grpcServer        *grpc.Server
+	port              string
+	db                *sqlx.DB
 }
 
-func NewApp(ctx context.Context, userRepo repository.UserRepository, sessionRepo repository.SessionRepository, db *sqlx.DB) *App {
+func NewApp(ctx context.Context, userRepo repository.UserRepository, sessionRepo repository.SessionRepository, apiKeyRepo repository.APIKeyRepository, denylistRepo repository.TokenDenylistRepository, twoFactorRepo repository.TwoFactorRepository, throttleRepo repository.LoginThrottleRepository, resetRepo repository.PasswordResetRepository, emailVerificationRepo repository.EmailVerificationRepository, db *sqlx.DB) *App {
 	// Run migrations during app initialization
 	if err := InitMigrations(db); err != nil {
 		log.Printf("Error executing migrations: %v", err)
 	}
 	port := getEnv("GRPC_PORT", "50051")
 
+	// Tokens cannot be issued without a signing key, so a broken key store is fatal
+	keys, err := auth_service.NewKeyStore(auth_service.KeyStoreConfigFromEnv())
+	if err != nil {
+		log.Fatalf("failed to load signing keys: %v", err)
+	}
+	go keys.RunRotation(ctx)
+
+	// A bad encryption key would make stored TOTP secrets unreadable, so it is fatal as well
+	twoFactorConfig, err := auth_service.TwoFactorConfigFromEnv()
+	if err != nil {
+		log.Fatalf("invalid two-factor configuration: %v", err)
+	}
+	twoFactor, err := auth_service.NewTwoFactor(twoFactorRepo, twoFactorConfig)
+	if err != nil {
+		log.Fatalf("failed to initialize two-factor authentication: %v", err)
+	}
+
+	throttle := auth_service.NewLoginThrottle(throttleRepo, auth_service.LoginThrottleConfigFromEnv())
+
+	policy, err := auth_service.NewCredentialPolicy(auth_service.CredentialPolicyConfigFromEnv())
+	if err != nil {
+		log.Fatalf("failed to load credential policy: %v", err)
+	}
+
+	mail, err := mailer.New(mailer.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("invalid mailer configuration: %v", err)
+	}
+	// The gateway shipped with the service runs next to it and connects over loopback
+	trustedProxies, err := api.ParseTrustedProxies(splitList(getEnv("TRUSTED_PROXIES", "127.0.0.1,::1")))
+	if err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	passwordReset := auth_service.NewPasswordReset(resetRepo, userRepo, sessionRepo, mail, policy, throttle, auth_service.PasswordResetConfigFromEnv())
+	emailVerification := auth_service.NewEmailVerification(emailVerificationRepo, userRepo, mail, auth_service.EmailVerificationConfigFromEnv())
+
 	return &App{
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
-		port:        port,
-		db:          db,
+		userRepo:          userRepo,
+		sessionRepo:       sessionRepo,
+		apiKeyRepo:        apiKeyRepo,
+		denylistRepo:      denylistRepo,
+		twoFactor:         twoFactor,
+		throttle:          throttle,
+		policy:            policy,
+		passwordReset:     passwordReset,
+		emailVerification: emailVerification,
+		adminIDs:          splitList(getEnv("ADMIN_USER_IDS", "")),
+		trustedProxies:    trustedProxies,
+		keys:              keys,
+		port:              port,
+		db:                db,
 	}
 }
 
 func (a *App) RegisterServices(grpcServer *grpc.Server) {
-	userService := auth_service.NewUserService(a.userRepo)
+	userService := auth_service.NewUserService(a.userRepo, a.twoFactor, a.throttle, a.policy, a.passwordReset, a.emailVerification, a.adminIDs)
 	authService := auth_service.NewAuthService(
 		a.userRepo,
 		a.sessionRepo,
-		nil,
+		a.denylistRepo,
+		a.twoFactor,
+		a.throttle,
+		a.keys,
 		time.Duration(0),
 		time.Duration(0),
 	)
-	accessService := auth_service.NewAccessService(authService)
+	botService := auth_service.NewBotService(a.userRepo, a.apiKeyRepo, a.policy)
+	accessService := auth_service.NewAccessService(authService, botService)
 
-	userHandler := api.NewUserServiceHandler(userService)
-	authHandler := api.NewAuthServiceHandler(authService)
+	userHandler := api.NewUserServiceHandler(userService, authService, a.trustedProxies)
+	authHandler := api.NewAuthServiceHandler(authService, a.trustedProxies)
 	accessHandler := api.NewAccessServiceHandler(accessService)
+	botHandler := api.NewBotServiceHandler(botService, authService)
 
 	pb.RegisterUserServiceServer(grpcServer, userHandler)
 	pb.RegisterAuthServiceServer(grpcServer, authHandler)
 	pb.RegisterAccessServiceServer(grpcServer, accessHandler)
+	pb.RegisterBotServiceServer(grpcServer, botHandler)
 
 	reflection.Register(grpcServer)
 }
@@ -121,3 +186,15 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// splitList splits a comma separated list, skipping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
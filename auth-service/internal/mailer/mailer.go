@@ -0,0 +1,178 @@
+// Package mailer sends emails to users. The sender is chosen by configuration:
+// SMTP in production, a log or a file where there is no mail server, e.g. in development and tests
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config selects and configures the mailer
+type Config struct {
+	Driver       string // smtp, log or file
+	From         string
+	SMTPAddr     string // host:port
+	SMTPUsername string // No authentication if empty
+	SMTPPassword string
+	File         string // Messages are appended to this file by the file driver
+}
+
+// ConfigFromEnv reads the mailer configuration from the environment, by default messages are logged
+func ConfigFromEnv() Config {
+	return Config{
+		Driver:       getEnv("MAILER", "log"),
+		From:         getEnv("MAIL_FROM", "no-reply@chatik.local"),
+		SMTPAddr:     getEnv("SMTP_ADDR", ""),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		File:         getEnv("MAILER_FILE", "mail.log"),
+	}
+}
+
+// New creates the mailer selected by the configuration
+func New(config Config) (Mailer, error) {
+	switch config.Driver {
+	case "smtp":
+		if config.SMTPAddr == "" {
+			return nil, fmt.Errorf("SMTP_ADDR is required for the smtp mailer")
+		}
+		return NewSMTPMailer(config.SMTPAddr, config.SMTPUsername, config.SMTPPassword, config.From), nil
+	case "log":
+		return NewLogMailer(), nil
+	case "file":
+		return NewFileMailer(config.File, config.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q, expected smtp, log or file", config.Driver)
+	}
+}
+
+// SMTPMailer sends messages through an SMTP server. With credentials it authenticates
+// with PLAIN, which net/smtp only allows over TLS or to localhost
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(addr, username, password, from string) *SMTPMailer {
+	m := &SMTPMailer{
+		addr: addr,
+		from: from,
+	}
+
+	if username != "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return m
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	// net/smtp has no context support, the call is abandoned when the context is done
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, format(m.from, msg))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("SMTPMailer.Send: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("SMTPMailer.Send: %w", ctx.Err())
+	}
+}
+
+// LogMailer writes messages to the service log instead of sending them
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// FileMailer appends messages to a file in the format they would be sent in,
+// separated by empty lines
+type FileMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+}
+
+func NewFileMailer(path, from string) *FileMailer {
+	return &FileMailer{
+		path: path,
+		from: from,
+	}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("FileMailer.Send: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(format(m.from, msg), "\r\n"...)); err != nil {
+		return fmt.Errorf("FileMailer.Send: %w", err)
+	}
+
+	return nil
+}
+
+// format builds an RFC 5322 message. Header values come from the service, not from users,
+// except the recipient, which is validated when the email is saved
+func format(from string, msg Message) []byte {
+	var b strings.Builder
+
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+
+	return []byte(b.String())
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+
+	return defaultValue
+}
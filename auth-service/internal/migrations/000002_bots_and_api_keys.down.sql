@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS api_keys;
+ALTER TABLE users DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE users DROP COLUMN IF EXISTS is_bot;
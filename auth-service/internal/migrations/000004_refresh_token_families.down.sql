@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_sessions_expires_at;
+DROP INDEX IF EXISTS idx_sessions_family_id;
+
+-- Rotated refresh tokens must not become usable again
+DELETE FROM sessions WHERE rotated_at IS NOT NULL;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS rotated_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS family_id;
@@ -0,0 +1,9 @@
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS family_id UUID;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMP;
+
+-- Sessions created before refresh token families each start their own family
+UPDATE sessions SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE sessions ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions(family_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
@@ -0,0 +1,7 @@
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS device_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS user_agent VARCHAR(512) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS ip_address VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP;
+
+UPDATE sessions SET last_used_at = created_at WHERE last_used_at IS NULL;
+ALTER TABLE sessions ALTER COLUMN last_used_at SET NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS password_reset_tokens;
+
+DROP INDEX IF EXISTS idx_users_email;
+ALTER TABLE users DROP COLUMN IF EXISTS email;
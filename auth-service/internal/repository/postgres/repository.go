@@ -2,12 +2,15 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"auth.service/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type PostgresUserRepository struct {
@@ -46,11 +49,11 @@ func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *repositor
 	user.UpdatedAt = now
 
 	query := `
-		INSERT INTO users (id, username, password_hash, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users (id, username, email, password_hash, is_bot, owner_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	_, err := r.db.ExecContext(ctx, query, user.ID, user.Username, user.PasswordHash, user.CreatedAt, user.UpdatedAt)
+	_, err := r.db.ExecContext(ctx, query, user.ID, user.Username, user.Email, user.PasswordHash, user.IsBot, user.OwnerID, user.CreatedAt, user.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -63,7 +66,7 @@ func (r *PostgresUserRepository) UserByID(ctx context.Context, id string) (*repo
 	var op = "repository.PostgresUserRepository.UserByID"
 
 	query := `
-		SELECT id, username, password_hash, created_at, updated_at
+		SELECT id, username, email, password_hash, is_bot, owner_id, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -77,11 +80,28 @@ func (r *PostgresUserRepository) UserByID(ctx context.Context, id string) (*repo
 	return &user, nil
 }
 
+func (r *PostgresUserRepository) UsersByIDs(ctx context.Context, ids []string) ([]*repository.User, error) {
+	var op = "repository.PostgresUserRepository.UsersByIDs"
+
+	query := `
+		SELECT id, username, email, password_hash, is_bot, owner_id, created_at, updated_at
+		FROM users
+		WHERE id::text = ANY($1)
+	`
+
+	var users []*repository.User
+	if err := r.db.SelectContext(ctx, &users, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
 func (r *PostgresUserRepository) UserByUsername(ctx context.Context, username string) (*repository.User, error) {
 	var op = "repository.PostgresUserRepository.UserByUsername"
 
 	query := `
-		SELECT id, username, password_hash, created_at, updated_at
+		SELECT id, username, email, password_hash, is_bot, owner_id, created_at, updated_at
 		FROM users
 		WHERE username = $1
 	`
@@ -100,6 +120,26 @@ func (r *PostgresUserRepository) UserByUsername(ctx context.Context, username st
 	return user, nil
 }
 
+func (r *PostgresUserRepository) UserByEmail(ctx context.Context, email string) (*repository.User, error) {
+	var op = "repository.PostgresUserRepository.UserByEmail"
+
+	query := `
+		SELECT id, username, email, password_hash, is_bot, owner_id, created_at, updated_at
+		FROM users
+		WHERE email = $1
+	`
+
+	user := &repository.User{}
+	if err := r.db.GetContext(ctx, user, query, email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
 func (r *PostgresUserRepository) UpdateUser(ctx context.Context, user *repository.User) error {
 	var op = "repository.PostgresUserRepository.UpdateUser"
 
@@ -107,11 +147,11 @@ func (r *PostgresUserRepository) UpdateUser(ctx context.Context, user *repositor
 
 	query := `
 		UPDATE users
-		SET username = $1, password_hash = $2, updated_at = $3
-		WHERE id = $4
+		SET username = $1, email = $2, password_hash = $3, updated_at = $4
+		WHERE id = $5
 	`
 
-	_, err := r.db.ExecContext(ctx, query, user.Username, user.PasswordHash, user.UpdatedAt, user.ID)
+	_, err := r.db.ExecContext(ctx, query, user.Username, user.Email, user.PasswordHash, user.UpdatedAt, user.ID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -146,9 +186,21 @@ func (r *PostgresSessionRepository) CreateSession(ctx context.Context, session *
 		session.ID = uuid.String()
 	}
 
+	if session.FamilyID == "" {
+		session.FamilyID = session.ID
+	}
+
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+
+	if session.LastUsedAt.IsZero() {
+		session.LastUsedAt = session.CreatedAt
+	}
+
 	query := `
-		INSERT INTO sessions (id, user_id, refresh_token, expires_at, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO sessions (id, user_id, family_id, refresh_token, device_name, user_agent, ip_address, expires_at, created_at, last_used_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	_, err := r.db.ExecContext(
@@ -156,9 +208,14 @@ func (r *PostgresSessionRepository) CreateSession(ctx context.Context, session *
 		query,
 		session.ID,
 		session.UserID,
+		session.FamilyID,
 		session.RefreshToken,
+		session.DeviceName,
+		session.UserAgent,
+		session.IPAddress,
 		session.ExpiresAt,
 		session.CreatedAt,
+		session.LastUsedAt,
 	)
 
 	if err != nil {
@@ -172,7 +229,7 @@ func (r *PostgresSessionRepository) GetByRefreshToken(ctx context.Context, refre
 	var op = "repository.PostgresSessionRepository.GetByRefreshToken"
 
 	query := `
-		SELECT id, user_id, refresh_token, expires_at, created_at
+		SELECT id, user_id, family_id, refresh_token, device_name, user_agent, ip_address, expires_at, created_at, last_used_at, rotated_at
 		FROM sessions
 		WHERE refresh_token = $1
 	`
@@ -180,12 +237,55 @@ func (r *PostgresSessionRepository) GetByRefreshToken(ctx context.Context, refre
 	var session repository.Session
 	err := r.db.GetContext(ctx, &session, query, refreshToken)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrSessionNotFound
+		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return &session, nil
 }
 
+func (r *PostgresSessionRepository) ActiveByUserID(ctx context.Context, userID string, now time.Time) ([]*repository.Session, error) {
+	var op = "repository.PostgresSessionRepository.ActiveByUserID"
+
+	query := `
+		SELECT id, user_id, family_id, refresh_token, device_name, user_agent, ip_address, expires_at, created_at, last_used_at, rotated_at
+		FROM sessions
+		WHERE user_id = $1 AND rotated_at IS NULL AND expires_at > $2
+		ORDER BY last_used_at DESC
+	`
+
+	var sessions []*repository.Session
+	if err := r.db.SelectContext(ctx, &sessions, query, userID, now); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+func (r *PostgresSessionRepository) MarkRotated(ctx context.Context, id string, rotatedAt time.Time) (bool, error) {
+	var op = "repository.PostgresSessionRepository.MarkRotated"
+
+	query := `
+		UPDATE sessions
+		SET rotated_at = $1
+		WHERE id = $2 AND rotated_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, rotatedAt, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
 func (r *PostgresSessionRepository) DeleteSession(ctx context.Context, id string) error {
 	var op = "repository.PostgresSessionRepository.DeleteSession"
 
@@ -202,6 +302,43 @@ func (r *PostgresSessionRepository) DeleteSession(ctx context.Context, id string
 	return nil
 }
 
+func (r *PostgresSessionRepository) DeleteFamily(ctx context.Context, familyID string) error {
+	var op = "repository.PostgresSessionRepository.DeleteFamily"
+
+	query := `
+		DELETE FROM sessions
+		WHERE family_id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresSessionRepository) DeleteUserFamily(ctx context.Context, userID, familyID string) (bool, error) {
+	var op = "repository.PostgresSessionRepository.DeleteUserFamily"
+
+	query := `
+		DELETE FROM sessions
+		WHERE user_id = $1 AND family_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, familyID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
 func (r *PostgresSessionRepository) DeleteByUserID(ctx context.Context, userID string) error {
 	var op = "repository.PostgresSessionRepository.DeleteByUserID"
 
@@ -217,3 +354,816 @@ func (r *PostgresSessionRepository) DeleteByUserID(ctx context.Context, userID s
 
 	return nil
 }
+
+func (r *PostgresSessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	var op = "repository.PostgresSessionRepository.DeleteExpired"
+
+	query := `
+		DELETE FROM sessions
+		WHERE expires_at <= $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type PostgresAPIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key *repository.APIKey) error {
+	var op = "repository.PostgresAPIKeyRepository.CreateAPIKey"
+
+	if key.ID == "" {
+		key.ID = uuid.New().String()
+	}
+	key.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO api_keys (id, user_id, key_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, key.ID, key.UserID, key.KeyHash, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAPIKeyRepository) APIKeyByID(ctx context.Context, id string) (*repository.APIKey, error) {
+	var op = "repository.PostgresAPIKeyRepository.APIKeyByID"
+
+	query := `
+		SELECT id, user_id, key_hash, created_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE id = $1
+	`
+
+	return r.getAPIKey(ctx, op, query, id)
+}
+
+func (r *PostgresAPIKeyRepository) APIKeyByHash(ctx context.Context, keyHash string) (*repository.APIKey, error) {
+	var op = "repository.PostgresAPIKeyRepository.APIKeyByHash"
+
+	query := `
+		SELECT id, user_id, key_hash, created_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	return r.getAPIKey(ctx, op, query, keyHash)
+}
+
+func (r *PostgresAPIKeyRepository) getAPIKey(ctx context.Context, op, query string, arg string) (*repository.APIKey, error) {
+	key := &repository.APIKey{}
+	err := r.db.GetContext(ctx, key, query, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (r *PostgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	var op = "repository.PostgresAPIKeyRepository.RevokeAPIKey"
+
+	query := `
+		UPDATE api_keys
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, revokedAt, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAPIKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	var op = "repository.PostgresAPIKeyRepository.TouchAPIKey"
+
+	query := `
+		UPDATE api_keys
+		SET last_used_at = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, usedAt, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type PostgresTokenDenylistRepository struct {
+	db *sqlx.DB
+}
+
+func NewTokenDenylistRepository(db *sqlx.DB) *PostgresTokenDenylistRepository {
+	return &PostgresTokenDenylistRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresTokenDenylistRepository) RevokeToken(ctx context.Context, token *repository.RevokedToken) error {
+	var op = "repository.PostgresTokenDenylistRepository.RevokeToken"
+
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, token.JTI, token.UserID, token.ExpiresAt, token.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTokenDenylistRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var op = "repository.PostgresTokenDenylistRepository.IsTokenRevoked"
+
+	query := `
+		SELECT COUNT(*)
+		FROM revoked_tokens
+		WHERE jti = $1
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, jti); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *PostgresTokenDenylistRepository) RevokedTokensSince(ctx context.Context, since, now time.Time) ([]*repository.RevokedToken, error) {
+	var op = "repository.PostgresTokenDenylistRepository.RevokedTokensSince"
+
+	query := `
+		SELECT jti, user_id, expires_at, revoked_at
+		FROM revoked_tokens
+		WHERE revoked_at >= $1 AND expires_at > $2
+		ORDER BY revoked_at
+	`
+
+	var tokens []*repository.RevokedToken
+	if err := r.db.SelectContext(ctx, &tokens, query, since, now); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+func (r *PostgresTokenDenylistRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	var op = "repository.PostgresTokenDenylistRepository.DeleteExpired"
+
+	query := `
+		DELETE FROM revoked_tokens
+		WHERE expires_at <= $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type PostgresTwoFactorRepository struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorRepository(db *sqlx.DB) *PostgresTwoFactorRepository {
+	return &PostgresTwoFactorRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresTwoFactorRepository) SaveTOTP(ctx context.Context, totp *repository.TOTP) error {
+	var op = "repository.PostgresTwoFactorRepository.SaveTOTP"
+
+	query := `
+		INSERT INTO user_totp (user_id, secret_encrypted, enabled, last_used_step, created_at)
+		VALUES ($1, $2, FALSE, 0, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret_encrypted = excluded.secret_encrypted, last_used_step = 0, created_at = excluded.created_at
+		WHERE user_totp.enabled = FALSE
+	`
+
+	_, err := r.db.ExecContext(ctx, query, totp.UserID, totp.SecretEncrypted, totp.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTwoFactorRepository) TOTPByUserID(ctx context.Context, userID string) (*repository.TOTP, error) {
+	var op = "repository.PostgresTwoFactorRepository.TOTPByUserID"
+
+	query := `
+		SELECT user_id, secret_encrypted, enabled, last_used_step, created_at, enabled_at
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	totp := &repository.TOTP{}
+	if err := r.db.GetContext(ctx, totp, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrTOTPNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return totp, nil
+}
+
+func (r *PostgresTwoFactorRepository) EnableTOTP(ctx context.Context, userID string, enabledAt time.Time) error {
+	var op = "repository.PostgresTwoFactorRepository.EnableTOTP"
+
+	query := `
+		UPDATE user_totp
+		SET enabled = TRUE, enabled_at = $1
+		WHERE user_id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, enabledAt, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTwoFactorRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	var op = "repository.PostgresTwoFactorRepository.UseTOTPStep"
+
+	query := `
+		UPDATE user_totp
+		SET last_used_step = $1
+		WHERE user_id = $2 AND last_used_step < $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, step, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *PostgresTwoFactorRepository) DeleteTOTP(ctx context.Context, userID string) error {
+	var op = "repository.PostgresTwoFactorRepository.DeleteTOTP"
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string, createdAt time.Time) error {
+	var op = "repository.PostgresTwoFactorRepository.ReplaceRecoveryCodes"
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `
+		INSERT INTO recovery_codes (id, user_id, code_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	for _, codeHash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, query, uuid.New().String(), userID, codeHash, createdAt); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error) {
+	var op = "repository.PostgresTwoFactorRepository.UseRecoveryCode"
+
+	query := `
+		UPDATE recovery_codes
+		SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, usedAt, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *PostgresTwoFactorRepository) CreateChallenge(ctx context.Context, challenge *repository.LoginChallenge) error {
+	var op = "repository.PostgresTwoFactorRepository.CreateChallenge"
+
+	if challenge.ID == "" {
+		challenge.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO login_challenges (id, user_id, token_hash, device_name, attempts, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, 0, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, challenge.ID, challenge.UserID, challenge.TokenHash, challenge.DeviceName, challenge.CreatedAt, challenge.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTwoFactorRepository) ChallengeByTokenHash(ctx context.Context, tokenHash string) (*repository.LoginChallenge, error) {
+	var op = "repository.PostgresTwoFactorRepository.ChallengeByTokenHash"
+
+	query := `
+		SELECT id, user_id, token_hash, device_name, attempts, created_at, expires_at
+		FROM login_challenges
+		WHERE token_hash = $1
+	`
+
+	challenge := &repository.LoginChallenge{}
+	if err := r.db.GetContext(ctx, challenge, query, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrChallengeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenge, nil
+}
+
+func (r *PostgresTwoFactorRepository) AddChallengeAttempt(ctx context.Context, id string) (int, error) {
+	var op = "repository.PostgresTwoFactorRepository.AddChallengeAttempt"
+
+	query := `
+		UPDATE login_challenges
+		SET attempts = attempts + 1
+		WHERE id = $1
+		RETURNING attempts
+	`
+
+	var attempts int
+	if err := r.db.GetContext(ctx, &attempts, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, repository.ErrChallengeNotFound
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attempts, nil
+}
+
+func (r *PostgresTwoFactorRepository) DeleteChallenge(ctx context.Context, id string) error {
+	var op = "repository.PostgresTwoFactorRepository.DeleteChallenge"
+
+	query := `
+		DELETE FROM login_challenges
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTwoFactorRepository) DeleteExpiredChallenges(ctx context.Context, now time.Time) error {
+	var op = "repository.PostgresTwoFactorRepository.DeleteExpiredChallenges"
+
+	query := `
+		DELETE FROM login_challenges
+		WHERE expires_at <= $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type PostgresLoginThrottleRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoginThrottleRepository(db *sqlx.DB) *PostgresLoginThrottleRepository {
+	return &PostgresLoginThrottleRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresLoginThrottleRepository) LoginThrottle(ctx context.Context, scope, subject string) (*repository.LoginThrottle, error) {
+	var op = "repository.PostgresLoginThrottleRepository.LoginThrottle"
+
+	query := `
+		SELECT scope, subject, failures, last_failure_at, blocked_until, locked_at
+		FROM login_throttles
+		WHERE scope = $1 AND subject = $2
+	`
+
+	throttle := &repository.LoginThrottle{}
+	if err := r.db.GetContext(ctx, throttle, query, scope, subject); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrThrottleNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return throttle, nil
+}
+
+func (r *PostgresLoginThrottleRepository) AddFailure(ctx context.Context, scope, subject string, now, resetBefore time.Time) (int, error) {
+	var op = "repository.PostgresLoginThrottleRepository.AddFailure"
+
+	query := `
+		INSERT INTO login_throttles (scope, subject, failures, last_failure_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, subject) DO UPDATE
+		SET failures = CASE WHEN login_throttles.last_failure_at < $4 THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failure_at = excluded.last_failure_at
+		RETURNING failures
+	`
+
+	var failures int
+	if err := r.db.GetContext(ctx, &failures, query, scope, subject, now, resetBefore); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+func (r *PostgresLoginThrottleRepository) Block(ctx context.Context, scope, subject string, until time.Time) error {
+	var op = "repository.PostgresLoginThrottleRepository.Block"
+
+	query := `
+		UPDATE login_throttles
+		SET blocked_until = $1
+		WHERE scope = $2 AND subject = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, query, until, scope, subject)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresLoginThrottleRepository) Lock(ctx context.Context, scope, subject string, lockedAt time.Time) error {
+	var op = "repository.PostgresLoginThrottleRepository.Lock"
+
+	query := `
+		UPDATE login_throttles
+		SET locked_at = $1
+		WHERE scope = $2 AND subject = $3 AND locked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, lockedAt, scope, subject)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresLoginThrottleRepository) Reset(ctx context.Context, scope, subject string) error {
+	var op = "repository.PostgresLoginThrottleRepository.Reset"
+
+	query := `
+		DELETE FROM login_throttles
+		WHERE scope = $1 AND subject = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, scope, subject)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresLoginThrottleRepository) DeleteStale(ctx context.Context, before time.Time) error {
+	var op = "repository.PostgresLoginThrottleRepository.DeleteStale"
+
+	query := `
+		DELETE FROM login_throttles
+		WHERE last_failure_at < $1 AND locked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type PostgresPasswordResetRepository struct {
+	db *sqlx.DB
+}
+
+func NewPasswordResetRepository(db *sqlx.DB) *PostgresPasswordResetRepository {
+	return &PostgresPasswordResetRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresPasswordResetRepository) CreateResetToken(ctx context.Context, token *repository.PasswordResetToken) error {
+	var op = "repository.PostgresPasswordResetRepository.CreateResetToken"
+
+	if token.ID == "" {
+		token.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, token.ID, token.UserID, token.TokenHash, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresPasswordResetRepository) ResetTokenByHash(ctx context.Context, tokenHash string) (*repository.PasswordResetToken, error) {
+	var op = "repository.PostgresPasswordResetRepository.ResetTokenByHash"
+
+	query := `
+		SELECT id, user_id, token_hash, created_at, expires_at, used_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1
+	`
+
+	token := &repository.PasswordResetToken{}
+	if err := r.db.GetContext(ctx, token, query, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrResetNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+func (r *PostgresPasswordResetRepository) CountResetTokensSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	var op = "repository.PostgresPasswordResetRepository.CountResetTokensSince"
+
+	query := `
+		SELECT COUNT(*)
+		FROM password_reset_tokens
+		WHERE user_id = $1 AND created_at >= $2
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userID, since); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *PostgresPasswordResetRepository) UseResetToken(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	var op = "repository.PostgresPasswordResetRepository.UseResetToken"
+
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = $1
+		WHERE id = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, usedAt, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *PostgresPasswordResetRepository) DeleteUserResetTokens(ctx context.Context, userID string) error {
+	var op = "repository.PostgresPasswordResetRepository.DeleteUserResetTokens"
+
+	query := `
+		DELETE FROM password_reset_tokens
+		WHERE user_id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresPasswordResetRepository) DeleteExpiredResetTokens(ctx context.Context, now time.Time) error {
+	var op = "repository.PostgresPasswordResetRepository.DeleteExpiredResetTokens"
+
+	query := `
+		DELETE FROM password_reset_tokens
+		WHERE expires_at <= $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type PostgresEmailVerificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewEmailVerificationRepository(db *sqlx.DB) *PostgresEmailVerificationRepository {
+	return &PostgresEmailVerificationRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresEmailVerificationRepository) CreateEmailVerification(ctx context.Context, verification *repository.EmailVerification) error {
+	var op = "repository.PostgresEmailVerificationRepository.CreateEmailVerification"
+
+	if verification.ID == "" {
+		verification.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO email_verifications (id, user_id, email, token_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, verification.ID, verification.UserID, verification.Email, verification.TokenHash, verification.CreatedAt, verification.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresEmailVerificationRepository) EmailVerificationByHash(ctx context.Context, tokenHash string) (*repository.EmailVerification, error) {
+	var op = "repository.PostgresEmailVerificationRepository.EmailVerificationByHash"
+
+	query := `
+		SELECT id, user_id, email, token_hash, created_at, expires_at, used_at
+		FROM email_verifications
+		WHERE token_hash = $1
+	`
+
+	verification := &repository.EmailVerification{}
+	if err := r.db.GetContext(ctx, verification, query, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrEmailVerificationNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return verification, nil
+}
+
+func (r *PostgresEmailVerificationRepository) CountEmailVerificationsSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	var op = "repository.PostgresEmailVerificationRepository.CountEmailVerificationsSince"
+
+	query := `
+		SELECT COUNT(*)
+		FROM email_verifications
+		WHERE user_id = $1 AND created_at >= $2
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userID, since); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *PostgresEmailVerificationRepository) UseEmailVerification(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	var op = "repository.PostgresEmailVerificationRepository.UseEmailVerification"
+
+	query := `
+		UPDATE email_verifications
+		SET used_at = $1
+		WHERE id = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, usedAt, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *PostgresEmailVerificationRepository) CloseUserEmailVerifications(ctx context.Context, userID string, usedAt time.Time) error {
+	var op = "repository.PostgresEmailVerificationRepository.CloseUserEmailVerifications"
+
+	query := `
+		UPDATE email_verifications
+		SET used_at = $1
+		WHERE user_id = $2 AND used_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, usedAt, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresEmailVerificationRepository) DeleteExpiredEmailVerifications(ctx context.Context, now time.Time) error {
+	var op = "repository.PostgresEmailVerificationRepository.DeleteExpiredEmailVerifications"
+
+	query := `
+		DELETE FROM email_verifications
+		WHERE expires_at <= $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
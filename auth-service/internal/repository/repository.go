@@ -7,30 +7,125 @@ import (
 )
 
 var (
-	ErrUserAlreadyExists = errors.New("user already exists")
-	ErrUserNotFound      = errors.New("user not found")
+	ErrUserAlreadyExists         = errors.New("user already exists")
+	ErrUserNotFound              = errors.New("user not found")
+	ErrAPIKeyNotFound            = errors.New("api key not found")
+	ErrSessionNotFound           = errors.New("session not found")
+	ErrTOTPNotFound              = errors.New("totp not found")
+	ErrChallengeNotFound         = errors.New("login challenge not found")
+	ErrThrottleNotFound          = errors.New("login throttle not found")
+	ErrResetNotFound             = errors.New("password reset token not found")
+	ErrEmailVerificationNotFound = errors.New("email verification not found")
 )
 
 type User struct {
 	ID           string    `db:"id"`
 	Username     string    `db:"username"`
+	Email        *string   `db:"email"` // Lowercased, nil for users without an email and bots
 	PasswordHash string    `db:"password_hash"`
+	IsBot        bool      `db:"is_bot"`
+	OwnerID      *string   `db:"owner_id"`
 	CreatedAt    time.Time `db:"created_at"`
 	UpdatedAt    time.Time `db:"updated_at"`
 }
 
+// Session is a single refresh token. Tokens obtained by rotating each other share a FamilyID;
+// a rotated token is kept with RotatedAt set so that its reuse can be detected.
+// A family is what users see as a session on a device: CreatedAt is the login time
+// and is carried over on rotation, LastUsedAt is the time of the last login or refresh
 type Session struct {
-	ID           string    `db:"id"`
-	UserID       string    `db:"user_id"`
-	RefreshToken string    `db:"refresh_token"`
-	ExpiresAt    time.Time `db:"expires_at"`
-	CreatedAt    time.Time `db:"created_at"`
+	ID           string     `db:"id"`
+	UserID       string     `db:"user_id"`
+	FamilyID     string     `db:"family_id"`
+	RefreshToken string     `db:"refresh_token"`
+	DeviceName   string     `db:"device_name"`
+	UserAgent    string     `db:"user_agent"`
+	IPAddress    string     `db:"ip_address"`
+	ExpiresAt    time.Time  `db:"expires_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+	LastUsedAt   time.Time  `db:"last_used_at"`
+	RotatedAt    *time.Time `db:"rotated_at"`
+}
+
+type APIKey struct {
+	ID         string     `db:"id"`
+	UserID     string     `db:"user_id"`
+	KeyHash    string     `db:"key_hash"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
+// RevokedToken is an access token revoked before its expiry, identified by its jti claim
+type RevokedToken struct {
+	JTI       string    `db:"jti"`
+	UserID    string    `db:"user_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+	RevokedAt time.Time `db:"revoked_at"`
+}
+
+// TOTP is the user's TOTP authenticator. The secret is encrypted by the service,
+// Enabled is set once the user confirms the enrollment with a valid code
+type TOTP struct {
+	UserID          string     `db:"user_id"`
+	SecretEncrypted string     `db:"secret_encrypted"`
+	Enabled         bool       `db:"enabled"`
+	LastUsedStep    int64      `db:"last_used_step"`
+	CreatedAt       time.Time  `db:"created_at"`
+	EnabledAt       *time.Time `db:"enabled_at"`
+}
+
+// LoginChallenge is a pending login waiting for the second factor
+type LoginChallenge struct {
+	ID         string    `db:"id"`
+	UserID     string    `db:"user_id"`
+	TokenHash  string    `db:"token_hash"`
+	DeviceName string    `db:"device_name"`
+	Attempts   int       `db:"attempts"`
+	CreatedAt  time.Time `db:"created_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+}
+
+// LoginThrottle counts failed logins of a username or a client IP address (Scope),
+// Subject is the username or the address
+type LoginThrottle struct {
+	Scope         string     `db:"scope"`
+	Subject       string     `db:"subject"`
+	Failures      int        `db:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at"`
+	BlockedUntil  *time.Time `db:"blocked_until"`
+	LockedAt      *time.Time `db:"locked_at"`
+}
+
+// PasswordResetToken is a one-time token mailed to the user, only its hash is stored
+type PasswordResetToken struct {
+	ID        string     `db:"id"`
+	UserID    string     `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+}
+
+// EmailVerification is a pending email of a user, set when the mailed token is confirmed.
+// Only the hash of the token is stored
+type EmailVerification struct {
+	ID        string     `db:"id"`
+	UserID    string     `db:"user_id"`
+	Email     string     `db:"email"`
+	TokenHash string     `db:"token_hash"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
 }
 
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *User) error
 	UserByID(ctx context.Context, id string) (*User, error)
+	// UsersByIDs returns the users with the given IDs, unknown IDs are skipped
+	UsersByIDs(ctx context.Context, ids []string) ([]*User, error)
 	UserByUsername(ctx context.Context, username string) (*User, error)
+	UserByEmail(ctx context.Context, email string) (*User, error)
 	UpdateUser(ctx context.Context, user *User) error
 	DeleteUser(ctx context.Context, id string) error
 }
@@ -38,6 +133,90 @@ type UserRepository interface {
 type SessionRepository interface {
 	CreateSession(ctx context.Context, session *Session) error
 	GetByRefreshToken(ctx context.Context, refreshToken string) (*Session, error)
+	// ActiveByUserID returns the current refresh token of every unexpired family of the user
+	ActiveByUserID(ctx context.Context, userID string, now time.Time) ([]*Session, error)
+	// MarkRotated marks the session as rotated and reports whether it was not rotated before,
+	// so of concurrent refreshes with the same token only one succeeds
+	MarkRotated(ctx context.Context, id string, rotatedAt time.Time) (bool, error)
 	DeleteSession(ctx context.Context, id string) error
+	DeleteFamily(ctx context.Context, familyID string) error
+	// DeleteUserFamily deletes the family if it belongs to the user and reports whether it existed
+	DeleteUserFamily(ctx context.Context, userID, familyID string) (bool, error)
 	DeleteByUserID(ctx context.Context, userID string) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+type TokenDenylistRepository interface {
+	RevokeToken(ctx context.Context, token *RevokedToken) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	RevokedTokensSince(ctx context.Context, since, now time.Time) ([]*RevokedToken, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+type TwoFactorRepository interface {
+	// SaveTOTP stores a new enrollment, replacing an unconfirmed one
+	SaveTOTP(ctx context.Context, totp *TOTP) error
+	TOTPByUserID(ctx context.Context, userID string) (*TOTP, error)
+	EnableTOTP(ctx context.Context, userID string, enabledAt time.Time) error
+	// UseTOTPStep records the time step of an accepted code and reports whether it is newer than
+	// the last accepted one, so a code can not be used twice
+	UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	// DeleteTOTP removes the authenticator and the recovery codes of the user
+	DeleteTOTP(ctx context.Context, userID string) error
+
+	// ReplaceRecoveryCodes deletes the user's recovery codes and stores the new ones
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string, createdAt time.Time) error
+	// UseRecoveryCode marks an unused recovery code as used and reports whether there was one
+	UseRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error)
+
+	CreateChallenge(ctx context.Context, challenge *LoginChallenge) error
+	ChallengeByTokenHash(ctx context.Context, tokenHash string) (*LoginChallenge, error)
+	// AddChallengeAttempt counts a failed attempt and returns the number of attempts so far
+	AddChallengeAttempt(ctx context.Context, id string) (int, error)
+	DeleteChallenge(ctx context.Context, id string) error
+	DeleteExpiredChallenges(ctx context.Context, now time.Time) error
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	APIKeyByID(ctx context.Context, id string) (*APIKey, error)
+	APIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
+
+type LoginThrottleRepository interface {
+	LoginThrottle(ctx context.Context, scope, subject string) (*LoginThrottle, error)
+	// AddFailure counts a failed login and returns the number of failures. Failures made
+	// before resetBefore are forgotten, the count starts over
+	AddFailure(ctx context.Context, scope, subject string, now, resetBefore time.Time) (int, error)
+	Block(ctx context.Context, scope, subject string, until time.Time) error
+	Lock(ctx context.Context, scope, subject string, lockedAt time.Time) error
+	Reset(ctx context.Context, scope, subject string) error
+	// DeleteStale deletes throttles without failures since before, locked ones are kept
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+type PasswordResetRepository interface {
+	CreateResetToken(ctx context.Context, token *PasswordResetToken) error
+	ResetTokenByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	// CountResetTokensSince counts the tokens issued to the user since the given time
+	CountResetTokensSince(ctx context.Context, userID string, since time.Time) (int, error)
+	// UseResetToken marks an unused token as used and reports whether it was unused
+	UseResetToken(ctx context.Context, id string, usedAt time.Time) (bool, error)
+	DeleteUserResetTokens(ctx context.Context, userID string) error
+	DeleteExpiredResetTokens(ctx context.Context, now time.Time) error
+}
+
+type EmailVerificationRepository interface {
+	CreateEmailVerification(ctx context.Context, verification *EmailVerification) error
+	EmailVerificationByHash(ctx context.Context, tokenHash string) (*EmailVerification, error)
+	// CountEmailVerificationsSince counts the verifications started by the user since the given time
+	CountEmailVerificationsSince(ctx context.Context, userID string, since time.Time) (int, error)
+	// UseEmailVerification marks an unused verification as used and reports whether it was unused
+	UseEmailVerification(ctx context.Context, id string, usedAt time.Time) (bool, error)
+	// CloseUserEmailVerifications marks every unused verification of the user as used,
+	// so only the newest requested email can be confirmed
+	CloseUserEmailVerifications(ctx context.Context, userID string, usedAt time.Time) error
+	DeleteExpiredEmailVerifications(ctx context.Context, now time.Time) error
 }
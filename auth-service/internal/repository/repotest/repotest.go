@@ -0,0 +1,114 @@
+// Package repotest opens databases with the service schema for tests. SQLite databases live in memory
+// and are always available; Postgres tests run against TEST_DATABASE_URL and are skipped without it
+package repotest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"auth.service/internal/migrations"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Sqlite opens an empty in-memory SQLite database with all migrations applied
+func Sqlite(t testing.TB) *sqlx.DB {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite3", "file::memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// Every connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	files, err := filepath.Glob(filepath.Join(migrationsDir(), "*.up.sql"))
+	if err != nil {
+		t.Fatalf("list migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read migration: %v", err)
+		}
+
+		for _, statement := range strings.Split(string(content), ";") {
+			statement = sqliteStatement(statement)
+			if statement == "" {
+				continue
+			}
+
+			if _, err := db.Exec(statement); err != nil {
+				t.Fatalf("%s: %v\n%s", filepath.Base(file), err, statement)
+			}
+		}
+	}
+
+	return db
+}
+
+// Postgres creates a schema of its own in the TEST_DATABASE_URL database, migrates it
+// and drops it when the test ends. The test is skipped when TEST_DATABASE_URL is not set
+func Postgres(t testing.TB) *sqlx.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sqlx.Connect("postgres", url)
+	if err != nil {
+		t.Fatalf("connect to postgres: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := "test_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+
+	db, err := sqlx.Connect("postgres", fmt.Sprintf("%s%ssearch_path=%s", url, separator, schema))
+	if err != nil {
+		t.Fatalf("connect to postgres schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrations.RunMigrations(db, migrationsDir()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return db
+}
+
+// sqliteStatement adapts a Postgres migration statement to SQLite. SQLite columns
+// can not be altered, the NOT NULL added after a backfill is left out
+func sqliteStatement(statement string) string {
+	statement = strings.TrimSpace(statement)
+	if strings.Contains(statement, "ALTER COLUMN") {
+		return ""
+	}
+
+	return strings.ReplaceAll(statement, "ADD COLUMN IF NOT EXISTS", "ADD COLUMN")
+}
+
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "migrations")
+}
@@ -0,0 +1,167 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth.service/internal/repository"
+	"auth.service/internal/repository/postgres"
+	"auth.service/internal/repository/repotest"
+	"auth.service/internal/repository/sqlite"
+	"github.com/google/uuid"
+)
+
+// sessionBackend creates the user and session repositories of one database
+type sessionBackend struct {
+	name string
+	open func(t *testing.T) (repository.UserRepository, repository.SessionRepository)
+}
+
+var sessionBackends = []sessionBackend{
+	{"sqlite", func(t *testing.T) (repository.UserRepository, repository.SessionRepository) {
+		db := repotest.Sqlite(t)
+		return sqlite.NewUserRepository(db), sqlite.NewSessionRepository(db)
+	}},
+	{"postgres", func(t *testing.T) (repository.UserRepository, repository.SessionRepository) {
+		db := repotest.Postgres(t)
+		return postgres.NewUserRepository(db), postgres.NewSessionRepository(db)
+	}},
+}
+
+func TestMarkRotated(t *testing.T) {
+	for _, backend := range sessionBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			users, sessions := backend.open(t)
+			now := time.Now().UTC().Truncate(time.Second)
+
+			session := newSession(t, sessions, createUser(t, users), "", now.Add(time.Hour))
+
+			rotated, err := sessions.MarkRotated(ctx, session.ID, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !rotated {
+				t.Fatal("first MarkRotated must report the session as rotated")
+			}
+
+			// A concurrent refresh with the same token loses
+			rotated, err = sessions.MarkRotated(ctx, session.ID, now.Add(time.Second))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rotated {
+				t.Fatal("second MarkRotated must report the session as already rotated")
+			}
+
+			stored, err := sessions.GetByRefreshToken(ctx, session.RefreshToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.RotatedAt == nil || !stored.RotatedAt.Equal(now) {
+				t.Fatalf("rotated_at = %v, want %v", stored.RotatedAt, now)
+			}
+
+			// Rotated tokens are kept for reuse detection but are not active sessions
+			active, err := sessions.ActiveByUserID(ctx, session.UserID, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(active) != 0 {
+				t.Fatalf("got %d active sessions, want 0", len(active))
+			}
+		})
+	}
+}
+
+func TestDeleteFamily(t *testing.T) {
+	for _, backend := range sessionBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			users, sessions := backend.open(t)
+			userID := createUser(t, users)
+			expiresAt := time.Now().UTC().Add(time.Hour)
+
+			first := newSession(t, sessions, userID, "", expiresAt)
+			rotated := newSession(t, sessions, userID, first.FamilyID, expiresAt)
+			other := newSession(t, sessions, userID, "", expiresAt)
+
+			if err := sessions.DeleteFamily(ctx, first.FamilyID); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, deleted := range []*repository.Session{first, rotated} {
+				if _, err := sessions.GetByRefreshToken(ctx, deleted.RefreshToken); !errors.Is(err, repository.ErrSessionNotFound) {
+					t.Fatalf("token of the deleted family: got %v, want ErrSessionNotFound", err)
+				}
+			}
+
+			if _, err := sessions.GetByRefreshToken(ctx, other.RefreshToken); err != nil {
+				t.Fatalf("token of another family must stay: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	for _, backend := range sessionBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			users, sessions := backend.open(t)
+			userID := createUser(t, users)
+			now := time.Now().UTC()
+
+			expired := newSession(t, sessions, userID, "", now.Add(-time.Minute))
+			atNow := newSession(t, sessions, userID, "", now)
+			valid := newSession(t, sessions, userID, "", now.Add(time.Minute))
+
+			if err := sessions.DeleteExpired(ctx, now); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, deleted := range []*repository.Session{expired, atNow} {
+				if _, err := sessions.GetByRefreshToken(ctx, deleted.RefreshToken); !errors.Is(err, repository.ErrSessionNotFound) {
+					t.Fatalf("expired token: got %v, want ErrSessionNotFound", err)
+				}
+			}
+
+			if _, err := sessions.GetByRefreshToken(ctx, valid.RefreshToken); err != nil {
+				t.Fatalf("unexpired token must stay: %v", err)
+			}
+		})
+	}
+}
+
+func createUser(t *testing.T, users repository.UserRepository) string {
+	t.Helper()
+
+	user := &repository.User{
+		Username:     "user-" + uuid.New().String()[:8],
+		PasswordHash: "hash",
+	}
+	if err := users.CreateUser(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	return user.ID
+}
+
+// newSession stores a session in the family, or in a family of its own when familyID is empty
+func newSession(t *testing.T, sessions repository.SessionRepository, userID, familyID string, expiresAt time.Time) *repository.Session {
+	t.Helper()
+
+	session := &repository.Session{
+		UserID:       userID,
+		FamilyID:     familyID,
+		RefreshToken: uuid.New().String(),
+		ExpiresAt:    expiresAt,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := sessions.CreateSession(context.Background(), session); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	return session
+}
@@ -2,6 +2,8 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -42,11 +44,11 @@ func (r *SqliteUserRepository) CreateUser(ctx context.Context, user *repository.
 	user.UpdatedAt = now
 
 	query := `
-		INSERT INTO users (id, username, password_hash, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO users (id, username, email, password_hash, is_bot, owner_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.ExecContext(ctx, query, user.ID, user.Username, user.PasswordHash, user.CreatedAt, user.UpdatedAt)
+	_, err := r.db.ExecContext(ctx, query, user.ID, user.Username, user.Email, user.PasswordHash, user.IsBot, user.OwnerID, user.CreatedAt, user.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -61,7 +63,7 @@ func (r *SqliteUserRepository) UserByID(ctx context.Context, id string) (*reposi
 	user := &repository.User{}
 
 	query := `
-		SELECT id, username, password_hash, created_at, updated_at
+		SELECT id, username, email, password_hash, is_bot, owner_id, created_at, updated_at
 		FROM users
 		WHERE id = ?
 	`
@@ -74,13 +76,37 @@ func (r *SqliteUserRepository) UserByID(ctx context.Context, id string) (*reposi
 	return user, nil
 }
 
+func (r *SqliteUserRepository) UsersByIDs(ctx context.Context, ids []string) ([]*repository.User, error) {
+	var op = "repository.SqliteUserRepository.UsersByIDs"
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id, username, email, password_hash, is_bot, owner_id, created_at, updated_at
+		FROM users
+		WHERE id IN (?)
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var users []*repository.User
+	if err := r.db.SelectContext(ctx, &users, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
 func (r *SqliteUserRepository) UserByUsername(ctx context.Context, username string) (*repository.User, error) {
 	var op = "repository.SqliteUserRepository.UserByUsername"
 
 	user := &repository.User{}
 
 	query := `
-		SELECT id, username, password_hash, created_at, updated_at
+		SELECT id, username, email, password_hash, is_bot, owner_id, created_at, updated_at
 		FROM users
 		WHERE username = ?
 	`
@@ -98,6 +124,26 @@ func (r *SqliteUserRepository) UserByUsername(ctx context.Context, username stri
 	return user, nil
 }
 
+func (r *SqliteUserRepository) UserByEmail(ctx context.Context, email string) (*repository.User, error) {
+	var op = "repository.SqliteUserRepository.UserByEmail"
+
+	query := `
+		SELECT id, username, email, password_hash, is_bot, owner_id, created_at, updated_at
+		FROM users
+		WHERE email = ?
+	`
+
+	user := &repository.User{}
+	if err := r.db.GetContext(ctx, user, query, email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
 func (r *SqliteUserRepository) UpdateUser(ctx context.Context, user *repository.User) error {
 	var op = "repository.SqliteUserRepository.UpdateUser"
 
@@ -105,11 +151,11 @@ func (r *SqliteUserRepository) UpdateUser(ctx context.Context, user *repository.
 
 	query := `
 		UPDATE users
-		SET username = ?, password_hash = ?, updated_at = ?
+		SET username = ?, email = ?, password_hash = ?, updated_at = ?
 		WHERE id = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query, user.Username, user.PasswordHash, user.UpdatedAt, user.ID)
+	result, err := r.db.ExecContext(ctx, query, user.Username, user.Email, user.PasswordHash, user.UpdatedAt, user.ID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -158,16 +204,24 @@ func (r *SqliteSessionRepository) CreateSession(ctx context.Context, session *re
 		session.ID = uuid.New().String()
 	}
 
-	now := time.Now()
-	session.CreatedAt = now
-	session.ExpiresAt = now.Add(24 * time.Hour)
+	if session.FamilyID == "" {
+		session.FamilyID = session.ID
+	}
+
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+
+	if session.LastUsedAt.IsZero() {
+		session.LastUsedAt = session.CreatedAt
+	}
 
 	query := `
-		INSERT INTO sessions (id, user_id, refresh_token, expires_at, created_at, expires_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO sessions (id, user_id, family_id, refresh_token, device_name, user_agent, ip_address, expires_at, created_at, last_used_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.ExecContext(ctx, query, session.ID, session.UserID, session.RefreshToken, session.ExpiresAt, session.CreatedAt, session.ExpiresAt)
+	_, err := r.db.ExecContext(ctx, query, session.ID, session.UserID, session.FamilyID, session.RefreshToken, session.DeviceName, session.UserAgent, session.IPAddress, session.ExpiresAt, session.CreatedAt, session.LastUsedAt)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -181,19 +235,62 @@ func (r *SqliteSessionRepository) GetByRefreshToken(ctx context.Context, refresh
 	session := &repository.Session{}
 
 	query := `
-		SELECT id, user_id, refresh_token, expires_at, created_at
+		SELECT id, user_id, family_id, refresh_token, device_name, user_agent, ip_address, expires_at, created_at, last_used_at, rotated_at
 		FROM sessions
 		WHERE refresh_token = ?
 	`
 
 	err := r.db.GetContext(ctx, session, query, refreshToken)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrSessionNotFound
+		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return session, nil
 }
 
+func (r *SqliteSessionRepository) ActiveByUserID(ctx context.Context, userID string, now time.Time) ([]*repository.Session, error) {
+	var op = "repository.SqliteSessionRepository.ActiveByUserID"
+
+	query := `
+		SELECT id, user_id, family_id, refresh_token, device_name, user_agent, ip_address, expires_at, created_at, last_used_at, rotated_at
+		FROM sessions
+		WHERE user_id = ? AND rotated_at IS NULL AND expires_at > ?
+		ORDER BY last_used_at DESC
+	`
+
+	var sessions []*repository.Session
+	if err := r.db.SelectContext(ctx, &sessions, query, userID, now); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+func (r *SqliteSessionRepository) MarkRotated(ctx context.Context, id string, rotatedAt time.Time) (bool, error) {
+	var op = "repository.SqliteSessionRepository.MarkRotated"
+
+	query := `
+		UPDATE sessions
+		SET rotated_at = ?
+		WHERE id = ? AND rotated_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, rotatedAt, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
 func (r *SqliteSessionRepository) DeleteSession(ctx context.Context, id string) error {
 	var op = "repository.SqliteSessionRepository.DeleteSession"
 
@@ -219,6 +316,43 @@ func (r *SqliteSessionRepository) DeleteSession(ctx context.Context, id string)
 	return nil
 }
 
+func (r *SqliteSessionRepository) DeleteFamily(ctx context.Context, familyID string) error {
+	var op = "repository.SqliteSessionRepository.DeleteFamily"
+
+	query := `
+		DELETE FROM sessions
+		WHERE family_id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteSessionRepository) DeleteUserFamily(ctx context.Context, userID, familyID string) (bool, error) {
+	var op = "repository.SqliteSessionRepository.DeleteUserFamily"
+
+	query := `
+		DELETE FROM sessions
+		WHERE user_id = ? AND family_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, familyID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
 func (r *SqliteSessionRepository) DeleteByUserID(ctx context.Context, userID string) error {
 	var op = "repository.SqliteSessionRepository.DeleteByUserID"
 
@@ -227,18 +361,822 @@ func (r *SqliteSessionRepository) DeleteByUserID(ctx context.Context, userID str
 		WHERE user_id = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query, userID)
+	_, err := r.db.ExecContext(ctx, query, userID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return nil
+}
+
+func (r *SqliteSessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	var op = "repository.SqliteSessionRepository.DeleteExpired"
+
+	query := `
+		DELETE FROM sessions
+		WHERE expires_at <= ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("%s: %s", op, "session not found")
+	return nil
+}
+
+type SqliteAPIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *SqliteAPIKeyRepository {
+	return &SqliteAPIKeyRepository{
+		db: db,
+	}
+}
+
+func (r *SqliteAPIKeyRepository) CreateAPIKey(ctx context.Context, key *repository.APIKey) error {
+	var op = "repository.SqliteAPIKeyRepository.CreateAPIKey"
+
+	if key.ID == "" {
+		key.ID = uuid.New().String()
+	}
+	key.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO api_keys (id, user_id, key_hash, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, key.ID, key.UserID, key.KeyHash, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteAPIKeyRepository) APIKeyByID(ctx context.Context, id string) (*repository.APIKey, error) {
+	var op = "repository.SqliteAPIKeyRepository.APIKeyByID"
+
+	query := `
+		SELECT id, user_id, key_hash, created_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE id = ?
+	`
+
+	return r.getAPIKey(ctx, op, query, id)
+}
+
+func (r *SqliteAPIKeyRepository) APIKeyByHash(ctx context.Context, keyHash string) (*repository.APIKey, error) {
+	var op = "repository.SqliteAPIKeyRepository.APIKeyByHash"
+
+	query := `
+		SELECT id, user_id, key_hash, created_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = ?
+	`
+
+	return r.getAPIKey(ctx, op, query, keyHash)
+}
+
+func (r *SqliteAPIKeyRepository) getAPIKey(ctx context.Context, op, query string, arg string) (*repository.APIKey, error) {
+	key := &repository.APIKey{}
+	err := r.db.GetContext(ctx, key, query, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (r *SqliteAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	var op = "repository.SqliteAPIKeyRepository.RevokeAPIKey"
+
+	query := `
+		UPDATE api_keys
+		SET revoked_at = ?
+		WHERE id = ? AND revoked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, revokedAt, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteAPIKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	var op = "repository.SqliteAPIKeyRepository.TouchAPIKey"
+
+	query := `
+		UPDATE api_keys
+		SET last_used_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, usedAt, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type SqliteTokenDenylistRepository struct {
+	db *sqlx.DB
+}
+
+func NewTokenDenylistRepository(db *sqlx.DB) *SqliteTokenDenylistRepository {
+	return &SqliteTokenDenylistRepository{
+		db: db,
+	}
+}
+
+func (r *SqliteTokenDenylistRepository) RevokeToken(ctx context.Context, token *repository.RevokedToken) error {
+	var op = "repository.SqliteTokenDenylistRepository.RevokeToken"
+
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, token.JTI, token.UserID, token.ExpiresAt, token.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteTokenDenylistRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var op = "repository.SqliteTokenDenylistRepository.IsTokenRevoked"
+
+	query := `
+		SELECT COUNT(*)
+		FROM revoked_tokens
+		WHERE jti = ?
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, jti); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *SqliteTokenDenylistRepository) RevokedTokensSince(ctx context.Context, since, now time.Time) ([]*repository.RevokedToken, error) {
+	var op = "repository.SqliteTokenDenylistRepository.RevokedTokensSince"
+
+	query := `
+		SELECT jti, user_id, expires_at, revoked_at
+		FROM revoked_tokens
+		WHERE revoked_at >= ? AND expires_at > ?
+		ORDER BY revoked_at
+	`
+
+	var tokens []*repository.RevokedToken
+	if err := r.db.SelectContext(ctx, &tokens, query, since, now); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+func (r *SqliteTokenDenylistRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	var op = "repository.SqliteTokenDenylistRepository.DeleteExpired"
+
+	query := `
+		DELETE FROM revoked_tokens
+		WHERE expires_at <= ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type SqliteTwoFactorRepository struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorRepository(db *sqlx.DB) *SqliteTwoFactorRepository {
+	return &SqliteTwoFactorRepository{
+		db: db,
+	}
+}
+
+func (r *SqliteTwoFactorRepository) SaveTOTP(ctx context.Context, totp *repository.TOTP) error {
+	var op = "repository.SqliteTwoFactorRepository.SaveTOTP"
+
+	query := `
+		INSERT INTO user_totp (user_id, secret_encrypted, enabled, last_used_step, created_at)
+		VALUES (?, ?, FALSE, 0, ?)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret_encrypted = excluded.secret_encrypted, last_used_step = 0, created_at = excluded.created_at
+		WHERE user_totp.enabled = FALSE
+	`
+
+	_, err := r.db.ExecContext(ctx, query, totp.UserID, totp.SecretEncrypted, totp.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteTwoFactorRepository) TOTPByUserID(ctx context.Context, userID string) (*repository.TOTP, error) {
+	var op = "repository.SqliteTwoFactorRepository.TOTPByUserID"
+
+	query := `
+		SELECT user_id, secret_encrypted, enabled, last_used_step, created_at, enabled_at
+		FROM user_totp
+		WHERE user_id = ?
+	`
+
+	totp := &repository.TOTP{}
+	if err := r.db.GetContext(ctx, totp, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrTOTPNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return totp, nil
+}
+
+func (r *SqliteTwoFactorRepository) EnableTOTP(ctx context.Context, userID string, enabledAt time.Time) error {
+	var op = "repository.SqliteTwoFactorRepository.EnableTOTP"
+
+	query := `
+		UPDATE user_totp
+		SET enabled = TRUE, enabled_at = ?
+		WHERE user_id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, enabledAt, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteTwoFactorRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	var op = "repository.SqliteTwoFactorRepository.UseTOTPStep"
+
+	query := `
+		UPDATE user_totp
+		SET last_used_step = ?
+		WHERE user_id = ? AND last_used_step < ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, step, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *SqliteTwoFactorRepository) DeleteTOTP(ctx context.Context, userID string) error {
+	var op = "repository.SqliteTwoFactorRepository.DeleteTOTP"
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string, createdAt time.Time) error {
+	var op = "repository.SqliteTwoFactorRepository.ReplaceRecoveryCodes"
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `
+		INSERT INTO recovery_codes (id, user_id, code_hash, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	for _, codeHash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, query, uuid.New().String(), userID, codeHash, createdAt); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error) {
+	var op = "repository.SqliteTwoFactorRepository.UseRecoveryCode"
+
+	query := `
+		UPDATE recovery_codes
+		SET used_at = ?
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, usedAt, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *SqliteTwoFactorRepository) CreateChallenge(ctx context.Context, challenge *repository.LoginChallenge) error {
+	var op = "repository.SqliteTwoFactorRepository.CreateChallenge"
+
+	if challenge.ID == "" {
+		challenge.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO login_challenges (id, user_id, token_hash, device_name, attempts, created_at, expires_at)
+		VALUES (?, ?, ?, ?, 0, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, challenge.ID, challenge.UserID, challenge.TokenHash, challenge.DeviceName, challenge.CreatedAt, challenge.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteTwoFactorRepository) ChallengeByTokenHash(ctx context.Context, tokenHash string) (*repository.LoginChallenge, error) {
+	var op = "repository.SqliteTwoFactorRepository.ChallengeByTokenHash"
+
+	query := `
+		SELECT id, user_id, token_hash, device_name, attempts, created_at, expires_at
+		FROM login_challenges
+		WHERE token_hash = ?
+	`
+
+	challenge := &repository.LoginChallenge{}
+	if err := r.db.GetContext(ctx, challenge, query, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrChallengeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenge, nil
+}
+
+func (r *SqliteTwoFactorRepository) AddChallengeAttempt(ctx context.Context, id string) (int, error) {
+	var op = "repository.SqliteTwoFactorRepository.AddChallengeAttempt"
+
+	query := `
+		UPDATE login_challenges
+		SET attempts = attempts + 1
+		WHERE id = ?
+		RETURNING attempts
+	`
+
+	var attempts int
+	if err := r.db.GetContext(ctx, &attempts, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, repository.ErrChallengeNotFound
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attempts, nil
+}
+
+func (r *SqliteTwoFactorRepository) DeleteChallenge(ctx context.Context, id string) error {
+	var op = "repository.SqliteTwoFactorRepository.DeleteChallenge"
+
+	query := `
+		DELETE FROM login_challenges
+		WHERE id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteTwoFactorRepository) DeleteExpiredChallenges(ctx context.Context, now time.Time) error {
+	var op = "repository.SqliteTwoFactorRepository.DeleteExpiredChallenges"
+
+	query := `
+		DELETE FROM login_challenges
+		WHERE expires_at <= ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type SqliteLoginThrottleRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoginThrottleRepository(db *sqlx.DB) *SqliteLoginThrottleRepository {
+	return &SqliteLoginThrottleRepository{
+		db: db,
+	}
+}
+
+func (r *SqliteLoginThrottleRepository) LoginThrottle(ctx context.Context, scope, subject string) (*repository.LoginThrottle, error) {
+	var op = "repository.SqliteLoginThrottleRepository.LoginThrottle"
+
+	query := `
+		SELECT scope, subject, failures, last_failure_at, blocked_until, locked_at
+		FROM login_throttles
+		WHERE scope = ? AND subject = ?
+	`
+
+	throttle := &repository.LoginThrottle{}
+	if err := r.db.GetContext(ctx, throttle, query, scope, subject); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrThrottleNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return throttle, nil
+}
+
+func (r *SqliteLoginThrottleRepository) AddFailure(ctx context.Context, scope, subject string, now, resetBefore time.Time) (int, error) {
+	var op = "repository.SqliteLoginThrottleRepository.AddFailure"
+
+	query := `
+		INSERT INTO login_throttles (scope, subject, failures, last_failure_at)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT (scope, subject) DO UPDATE
+		SET failures = CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failure_at = excluded.last_failure_at
+		RETURNING failures
+	`
+
+	var failures int
+	if err := r.db.GetContext(ctx, &failures, query, scope, subject, now, resetBefore); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+func (r *SqliteLoginThrottleRepository) Block(ctx context.Context, scope, subject string, until time.Time) error {
+	var op = "repository.SqliteLoginThrottleRepository.Block"
+
+	query := `
+		UPDATE login_throttles
+		SET blocked_until = ?
+		WHERE scope = ? AND subject = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, until, scope, subject)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteLoginThrottleRepository) Lock(ctx context.Context, scope, subject string, lockedAt time.Time) error {
+	var op = "repository.SqliteLoginThrottleRepository.Lock"
+
+	query := `
+		UPDATE login_throttles
+		SET locked_at = ?
+		WHERE scope = ? AND subject = ? AND locked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, lockedAt, scope, subject)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteLoginThrottleRepository) Reset(ctx context.Context, scope, subject string) error {
+	var op = "repository.SqliteLoginThrottleRepository.Reset"
+
+	query := `
+		DELETE FROM login_throttles
+		WHERE scope = ? AND subject = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, scope, subject)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteLoginThrottleRepository) DeleteStale(ctx context.Context, before time.Time) error {
+	var op = "repository.SqliteLoginThrottleRepository.DeleteStale"
+
+	query := `
+		DELETE FROM login_throttles
+		WHERE last_failure_at < ? AND locked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type SqlitePasswordResetRepository struct {
+	db *sqlx.DB
+}
+
+func NewPasswordResetRepository(db *sqlx.DB) *SqlitePasswordResetRepository {
+	return &SqlitePasswordResetRepository{
+		db: db,
+	}
+}
+
+func (r *SqlitePasswordResetRepository) CreateResetToken(ctx context.Context, token *repository.PasswordResetToken) error {
+	var op = "repository.SqlitePasswordResetRepository.CreateResetToken"
+
+	if token.ID == "" {
+		token.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, token.ID, token.UserID, token.TokenHash, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqlitePasswordResetRepository) ResetTokenByHash(ctx context.Context, tokenHash string) (*repository.PasswordResetToken, error) {
+	var op = "repository.SqlitePasswordResetRepository.ResetTokenByHash"
+
+	query := `
+		SELECT id, user_id, token_hash, created_at, expires_at, used_at
+		FROM password_reset_tokens
+		WHERE token_hash = ?
+	`
+
+	token := &repository.PasswordResetToken{}
+	if err := r.db.GetContext(ctx, token, query, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrResetNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+func (r *SqlitePasswordResetRepository) CountResetTokensSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	var op = "repository.SqlitePasswordResetRepository.CountResetTokensSince"
+
+	query := `
+		SELECT COUNT(*)
+		FROM password_reset_tokens
+		WHERE user_id = ? AND created_at >= ?
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userID, since); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *SqlitePasswordResetRepository) UseResetToken(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	var op = "repository.SqlitePasswordResetRepository.UseResetToken"
+
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = ?
+		WHERE id = ? AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, usedAt, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *SqlitePasswordResetRepository) DeleteUserResetTokens(ctx context.Context, userID string) error {
+	var op = "repository.SqlitePasswordResetRepository.DeleteUserResetTokens"
+
+	query := `
+		DELETE FROM password_reset_tokens
+		WHERE user_id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqlitePasswordResetRepository) DeleteExpiredResetTokens(ctx context.Context, now time.Time) error {
+	var op = "repository.SqlitePasswordResetRepository.DeleteExpiredResetTokens"
+
+	query := `
+		DELETE FROM password_reset_tokens
+		WHERE expires_at <= ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type SqliteEmailVerificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewEmailVerificationRepository(db *sqlx.DB) *SqliteEmailVerificationRepository {
+	return &SqliteEmailVerificationRepository{
+		db: db,
+	}
+}
+
+func (r *SqliteEmailVerificationRepository) CreateEmailVerification(ctx context.Context, verification *repository.EmailVerification) error {
+	var op = "repository.SqliteEmailVerificationRepository.CreateEmailVerification"
+
+	if verification.ID == "" {
+		verification.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO email_verifications (id, user_id, email, token_hash, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, verification.ID, verification.UserID, verification.Email, verification.TokenHash, verification.CreatedAt, verification.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteEmailVerificationRepository) EmailVerificationByHash(ctx context.Context, tokenHash string) (*repository.EmailVerification, error) {
+	var op = "repository.SqliteEmailVerificationRepository.EmailVerificationByHash"
+
+	query := `
+		SELECT id, user_id, email, token_hash, created_at, expires_at, used_at
+		FROM email_verifications
+		WHERE token_hash = ?
+	`
+
+	verification := &repository.EmailVerification{}
+	if err := r.db.GetContext(ctx, verification, query, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrEmailVerificationNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return verification, nil
+}
+
+func (r *SqliteEmailVerificationRepository) CountEmailVerificationsSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	var op = "repository.SqliteEmailVerificationRepository.CountEmailVerificationsSince"
+
+	query := `
+		SELECT COUNT(*)
+		FROM email_verifications
+		WHERE user_id = ? AND created_at >= ?
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userID, since); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (r *SqliteEmailVerificationRepository) UseEmailVerification(ctx context.Context, id string, usedAt time.Time) (bool, error) {
+	var op = "repository.SqliteEmailVerificationRepository.UseEmailVerification"
+
+	query := `
+		UPDATE email_verifications
+		SET used_at = ?
+		WHERE id = ? AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, usedAt, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *SqliteEmailVerificationRepository) CloseUserEmailVerifications(ctx context.Context, userID string, usedAt time.Time) error {
+	var op = "repository.SqliteEmailVerificationRepository.CloseUserEmailVerifications"
+
+	query := `
+		UPDATE email_verifications
+		SET used_at = ?
+		WHERE user_id = ? AND used_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, usedAt, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *SqliteEmailVerificationRepository) DeleteExpiredEmailVerifications(ctx context.Context, now time.Time) error {
+	var op = "repository.SqliteEmailVerificationRepository.DeleteExpiredEmailVerifications"
+
+	query := `
+		DELETE FROM email_verifications
+		WHERE expires_at <= ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
@@ -2,21 +2,34 @@ package auth_service
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"auth.service/internal/service"
 )
 
 type AccessServiceImpl struct {
 	authService service.AuthService
+	botService  service.BotService
 }
 
-func NewAccessService(authService service.AuthService) *AccessServiceImpl {
+func NewAccessService(authService service.AuthService, botService service.BotService) *AccessServiceImpl {
 	return &AccessServiceImpl{
 		authService: authService,
+		botService:  botService,
 	}
 }
 
 func (s *AccessServiceImpl) Check(ctx context.Context, accessToken string) (bool, string, error) {
+	// Bots authenticate with long-lived API keys instead of JWT access tokens
+	if strings.HasPrefix(accessToken, APIKeyPrefix) {
+		user, err := s.botService.AuthenticateAPIKey(ctx, accessToken)
+		if err != nil {
+			return false, "", ErrInvalidToken
+		}
+		return true, user.ID, nil
+	}
+
 	claims, err := s.authService.ValidateToken(ctx, accessToken)
 	if err != nil {
 		if err == ErrExpiredToken {
@@ -27,3 +40,13 @@ func (s *AccessServiceImpl) Check(ctx context.Context, accessToken string) (bool
 
 	return true, claims.UserID, nil
 }
+
+// PublicKeys returns the keys other services use to verify access tokens without calling Check
+func (s *AccessServiceImpl) PublicKeys(ctx context.Context) ([]service.PublicKey, error) {
+	return s.authService.PublicKeys(ctx)
+}
+
+// RevokedTokens returns unexpired access tokens revoked since the given time and the current server time
+func (s *AccessServiceImpl) RevokedTokens(ctx context.Context, since time.Time) ([]service.RevokedToken, time.Time, error) {
+	return s.authService.RevokedTokens(ctx, since)
+}
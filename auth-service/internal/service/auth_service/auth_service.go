@@ -2,8 +2,11 @@ package auth_service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"strconv"
 	"time"
@@ -15,48 +18,158 @@ import (
 )
 
 var (
-	ErrInvalidToken  = errors.New("Invalid token")
-	ErrExpiredToken  = errors.New("Expired token")
-	ErrTokenNotFound = errors.New("Token not found")
+	ErrInvalidToken    = errors.New("Invalid token")
+	ErrExpiredToken    = errors.New("Expired token")
+	ErrTokenNotFound   = errors.New("Token not found")
+	ErrRevokedToken    = errors.New("Revoked token")
+	ErrTokenReused     = errors.New("Refresh token reuse detected")
+	ErrSessionNotFound = errors.New("Session not found")
 )
 
 type CustomClaims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type AuthServiceImpl struct {
-	userRepo    repository.UserRepository
-	sessionRepo repository.SessionRepository
-	jwtSecret   []byte
-	accessTTL   time.Duration
-	refreshTTL  time.Duration
+	userRepo     repository.UserRepository
+	sessionRepo  repository.SessionRepository
+	denylistRepo repository.TokenDenylistRepository
+	twoFactor    *TwoFactor
+	throttle     *LoginThrottle
+	keys         *KeyStore
+	accessTTL    time.Duration
+	refreshTTL   time.Duration
 }
 
-func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, jwtSecret []byte, accessTTL time.Duration, refreshTTL time.Duration) *AuthServiceImpl {
+func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, denylistRepo repository.TokenDenylistRepository, twoFactor *TwoFactor, throttle *LoginThrottle, keys *KeyStore, accessTTL time.Duration, refreshTTL time.Duration) *AuthServiceImpl {
 	return &AuthServiceImpl{
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
-		jwtSecret:   []byte(getEnv("JWT_SECRET_KEY", "capybara_enjoys")),
-		accessTTL:   parseDuration(getEnv("ACCESS_TOKEN_TTL", "15m")),
-		refreshTTL:  parseDuration(getEnv("REFRESH_TOKEN_TTL", "24h")),
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		denylistRepo: denylistRepo,
+		twoFactor:    twoFactor,
+		throttle:     throttle,
+		keys:         keys,
+		accessTTL:    parseDuration(getEnv("ACCESS_TOKEN_TTL", "15m")),
+		refreshTTL:   parseDuration(getEnv("REFRESH_TOKEN_TTL", "24h")),
 	}
 }
 
-func (s *AuthServiceImpl) Login(ctx context.Context, username, password string) (*service.TokenPair, error) {
+// Login checks the password. Users with two-factor authentication get a challenge instead of tokens,
+// the login is completed with VerifySecondFactor. Failed logins are throttled per username and client IP
+func (s *AuthServiceImpl) Login(ctx context.Context, username, password string, client service.ClientInfo) (*service.LoginResult, error) {
 	op := "AuthService.Login"
 
+	if err := s.throttle.Check(ctx, username, client.RemoteIP); err != nil {
+		if errors.Is(err, ErrAccountLocked) || errors.Is(err, ErrTooManyAttempts) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	user, err := s.userRepo.UserByUsername(ctx, username)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Unknown users and bots, which have no password and must use API keys, go through
+	// the same bcrypt comparison and failure accounting as a wrong password,
+	// so neither the response nor its timing tells whether the username exists
+	passwordHash := dummyPasswordHash()
+	if user != nil && !user.IsBot {
+		passwordHash = user.PasswordHash
+	}
+
+	if !checkPasswordHash(password, passwordHash) || user == nil || user.IsBot {
+		if err := s.throttle.Failure(ctx, username, client.RemoteIP); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	enabled, err := s.twoFactor.Enabled(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if enabled {
+		token, expiresAt, err := s.twoFactor.StartChallenge(ctx, user.ID, client.DeviceName)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return &service.LoginResult{
+			Challenge: &service.LoginChallenge{Token: token, ExpiresAt: expiresAt},
+		}, nil
+	}
+
+	if err := s.throttle.Success(ctx, username); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	u := &service.User{
+		ID:       user.ID,
+		Username: user.Username,
+	}
+
+	tokens, err := s.createTokens(ctx, u, nil, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.LoginResult{Tokens: tokens}, nil
+}
+
+// VerifySecondFactor completes a login challenge with a TOTP code or a recovery code.
+// The device name given at Login is kept for the new session. Wrong codes count as failed logins,
+// so knowing the password does not give unlimited attempts at the second factor
+func (s *AuthServiceImpl) VerifySecondFactor(ctx context.Context, challengeToken, code string, client service.ClientInfo) (*service.TokenPair, error) {
+	op := "AuthService.VerifySecondFactor"
+
+	challenge, err := s.twoFactor.Challenge(ctx, challengeToken)
+	if err != nil {
+		if errors.Is(err, ErrInvalidChallenge) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.userRepo.UserByID(ctx, challenge.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	if user == nil {
-		return nil, ErrInvalidCredentials
+		return nil, ErrInvalidChallenge
 	}
 
-	if !checkPasswordHash(password, user.PasswordHash) {
-		return nil, ErrInvalidCredentials
+	if err := s.throttle.Check(ctx, user.Username, client.RemoteIP); err != nil {
+		if errors.Is(err, ErrAccountLocked) || errors.Is(err, ErrTooManyAttempts) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := s.twoFactor.CompleteChallenge(ctx, challengeToken, code); err != nil {
+		if errors.Is(err, ErrInvalidSecondFactor) {
+			if err := s.throttle.Failure(ctx, user.Username, client.RemoteIP); err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			return nil, ErrInvalidSecondFactor
+		}
+		if errors.Is(err, ErrInvalidChallenge) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.throttle.Success(ctx, user.Username); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if client.DeviceName == "" {
+		client.DeviceName = challenge.DeviceName
 	}
 
 	u := &service.User{
@@ -64,43 +177,71 @@ func (s *AuthServiceImpl) Login(ctx context.Context, username, password string)
 		Username: user.Username,
 	}
 
-	return s.createTokens(ctx, u)
+	return s.createTokens(ctx, u, nil, client)
 }
 
-func (s *AuthServiceImpl) RefreshTokens(ctx context.Context, refreshToken string) (*service.TokenPair, error) {
+// RefreshTokens exchanges a refresh token for a new token pair in the same family.
+// The old refresh token stays stored as rotated: presenting it again means it was stolen,
+// so the whole family is revoked and the caller has to log in again
+func (s *AuthServiceImpl) RefreshTokens(ctx context.Context, refreshToken string, client service.ClientInfo) (*service.TokenPair, error) {
 	op := "AuthService.RefreshTokens"
 
 	session, err := s.sessionRepo.GetByRefreshToken(ctx, refreshToken)
 	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil, ErrTokenNotFound
+		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if time.Now().After(session.ExpiresAt) {
+	now := time.Now()
+	if !now.Before(session.ExpiresAt) {
 		_ = s.sessionRepo.DeleteSession(ctx, session.ID)
+		return nil, ErrExpiredToken
 	}
 
-	user, err := s.userRepo.UserByID(ctx, session.UserID)
+	if session.RotatedAt != nil {
+		return nil, s.revokeFamily(ctx, session)
+	}
+
+	// Concurrent refreshes with the same token race here, the loser is treated as reuse
+	rotated, err := s.sessionRepo.MarkRotated(ctx, session.ID, now)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	if !rotated {
+		return nil, s.revokeFamily(ctx, session)
+	}
 
-	_ = s.sessionRepo.DeleteSession(ctx, session.ID)
+	user, err := s.userRepo.UserByID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
 	u := &service.User{
 		ID:       user.ID,
 		Username: user.Username,
 	}
 
-	return s.createTokens(ctx, u)
+	return s.createTokens(ctx, u, session, client)
+}
+
+// revokeFamily deletes every refresh token of the session's family after reuse of a rotated token.
+// Access tokens already issued to the family stay valid until they expire
+func (s *AuthServiceImpl) revokeFamily(ctx context.Context, session *repository.Session) error {
+	op := "AuthService.revokeFamily"
+
+	if err := s.sessionRepo.DeleteFamily(ctx, session.FamilyID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Printf("refresh token reuse detected, revoked token family %s of user %s", session.FamilyID, session.UserID)
+
+	return ErrTokenReused
 }
 
 func (s *AuthServiceImpl) ValidateToken(ctx context.Context, accessToken string) (*service.TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(accessToken, &CustomClaims{}, s.keys.keyFunc)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -111,9 +252,21 @@ func (s *AuthServiceImpl) ValidateToken(ctx context.Context, accessToken string)
 			return nil, ErrExpiredToken
 		}
 
+		if claims.ID != "" {
+			revoked, err := s.denylistRepo.IsTokenRevoked(ctx, claims.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check token revocation: %w", err)
+			}
+			if revoked {
+				return nil, ErrRevokedToken
+			}
+		}
+
 		return &service.TokenClaims{
+			ID:        claims.ID,
 			UserID:    claims.UserID,
 			Username:  claims.Username,
+			SessionID: claims.SessionID,
 			ExpiresAt: claims.ExpiresAt.Time,
 		}, nil
 	}
@@ -121,51 +274,222 @@ func (s *AuthServiceImpl) ValidateToken(ctx context.Context, accessToken string)
 	return nil, ErrInvalidToken
 }
 
-func (s *AuthServiceImpl) createTokens(ctx context.Context, user *service.User) (*service.TokenPair, error) {
+// Logout ends the session of the refresh token and revokes the access token, if one is given
+func (s *AuthServiceImpl) Logout(ctx context.Context, refreshToken, accessToken string) error {
+	op := "AuthService.Logout"
+
+	session, err := s.sessionRepo.GetByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return ErrTokenNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.sessionRepo.DeleteFamily(ctx, session.FamilyID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.revokeAccessToken(ctx, session.UserID, accessToken); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// LogoutAll ends every session of the user and revokes the access token the request was made with.
+// Other access tokens of the user stay valid until they expire
+func (s *AuthServiceImpl) LogoutAll(ctx context.Context, userID, accessToken string) error {
+	op := "AuthService.LogoutAll"
+
+	if err := s.sessionRepo.DeleteByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.revokeAccessToken(ctx, userID, accessToken); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ListSessions returns the user's active sessions, most recently used first
+func (s *AuthServiceImpl) ListSessions(ctx context.Context, userID string) ([]service.Session, error) {
+	op := "AuthService.ListSessions"
+
+	sessions, err := s.sessionRepo.ActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := make([]service.Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, service.Session{
+			ID:         session.FamilyID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	return result, nil
+}
+
+// RevokeSession ends one of the user's sessions. When it is the session of the access token
+// the request was made with, that token is revoked as well; access tokens of other devices
+// stay valid until they expire
+func (s *AuthServiceImpl) RevokeSession(ctx context.Context, userID, sessionID, accessToken string) error {
+	op := "AuthService.RevokeSession"
+
+	deleted, err := s.sessionRepo.DeleteUserFamily(ctx, userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !deleted {
+		return ErrSessionNotFound
+	}
+
+	if claims, err := s.ValidateToken(ctx, accessToken); err == nil && claims.SessionID == sessionID {
+		if err := s.revokeAccessToken(ctx, userID, accessToken); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// RevokedTokens returns unexpired access tokens revoked since the given time and the current time,
+// which callers pass as since in the next request
+func (s *AuthServiceImpl) RevokedTokens(ctx context.Context, since time.Time) ([]service.RevokedToken, time.Time, error) {
+	op := "AuthService.RevokedTokens"
+
+	now := time.Now()
+	tokens, err := s.denylistRepo.RevokedTokensSince(ctx, since, now)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := make([]service.RevokedToken, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, service.RevokedToken{
+			JTI:       token.JTI,
+			ExpiresAt: token.ExpiresAt,
+		})
+	}
+
+	return result, now, nil
+}
+
+// revokeAccessToken puts the access token on the denylist until it expires. Tokens that are already
+// invalid, have no jti or belong to another user are ignored
+func (s *AuthServiceImpl) revokeAccessToken(ctx context.Context, userID, accessToken string) error {
+	if accessToken == "" {
+		return nil
+	}
+
+	claims, err := s.ValidateToken(ctx, accessToken)
+	if err != nil || claims.ID == "" || claims.UserID != userID {
+		return nil
+	}
+
+	now := time.Now()
+	err = s.denylistRepo.RevokeToken(ctx, &repository.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt,
+		RevokedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Expired tokens are rejected anyway, so their entries are no longer needed
+	return s.denylistRepo.DeleteExpired(ctx, now)
+}
+
+// PublicKeys returns the keys that verify access tokens. HMAC secrets must never leave the service,
+// so with HS256 there is nothing to publish and verifiers fall back to Check
+func (s *AuthServiceImpl) PublicKeys(ctx context.Context) ([]service.PublicKey, error) {
+	return s.keys.PublicKeys(), nil
+}
+
+// createTokens issues a token pair. With a nil previous session a new session (refresh token family)
+// is started, otherwise the new refresh token continues the previous one's session
+func (s *AuthServiceImpl) createTokens(ctx context.Context, user *service.User, previous *repository.Session, client service.ClientInfo) (*service.TokenPair, error) {
 	op := "AuthService.createTokens"
 
 	now := time.Now()
 
-	accessToken, err := s.generateAccessToken(user, now)
+	session := &repository.Session{
+		ID:         uuid.New().String(),
+		UserID:     user.ID,
+		DeviceName: client.DeviceName,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		ExpiresAt:  now.Add(s.refreshTTL),
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	session.FamilyID = session.ID
+
+	if previous != nil {
+		session.FamilyID = previous.FamilyID
+		session.CreatedAt = previous.CreatedAt
+		// The device name is given at login only
+		session.DeviceName = previous.DeviceName
+	}
+
+	accessToken, err := s.generateAccessToken(user, session.FamilyID, now)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	refresh_token := uuid.New().String()
 
-	session := &repository.Session{
-		UserID:       user.ID,
-		RefreshToken: refresh_token,
-		ExpiresAt:    now.Add(s.refreshTTL),
-	}
+	session.RefreshToken = refresh_token
 
 	if err := s.sessionRepo.CreateSession(ctx, session); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	// Rotated tokens are kept for reuse detection only until they expire
+	if err := s.sessionRepo.DeleteExpired(ctx, now); err != nil {
+		log.Printf("failed to delete expired sessions: %v", err)
+	}
+
 	return &service.TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refresh_token,
-		UserID:       user.ID,
+		AccessToken:           accessToken,
+		RefreshToken:          refresh_token,
+		UserID:                user.ID,
+		AccessTokenExpiresAt:  now.Add(s.accessTTL),
+		RefreshTokenExpiresAt: session.ExpiresAt,
 	}, nil
 }
 
-func (s *AuthServiceImpl) generateAccessToken(user *service.User, now time.Time) (string, error) {
+func (s *AuthServiceImpl) generateAccessToken(user *service.User, sessionID string, now time.Time) (string, error) {
 	claims := CustomClaims{
-		UserID:   user.ID,
-		Username: user.Username,
+		UserID:    user.ID,
+		Username:  user.Username,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "auth.service",
 			Subject:   user.ID,
+			ID:        uuid.New().String(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	key := s.keys.signingKey()
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
 
-	signedToken, err := token.SignedString(s.jwtSecret)
+	signedToken, err := token.SignedString(key.private)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -173,6 +497,13 @@ func (s *AuthServiceImpl) generateAccessToken(user *service.User, now time.Time)
 	return signedToken, nil
 }
 
+// hmacKeyID derives a stable key ID from the secret so verifiers can tell keys apart
+// without learning anything useful about the secret itself
+func hmacKeyID(secret []byte) string {
+	sum := sha256.Sum256(secret)
+	return "hs256-" + hex.EncodeToString(sum[:8])
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -0,0 +1,121 @@
+package auth_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth.service/internal/repository"
+	"auth.service/internal/service"
+)
+
+func TestRefreshTokensRotates(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+
+	first := env.login(t, "alice")
+
+	second, err := env.authService.RefreshTokens(ctx, first.RefreshToken, service.ClientInfo{})
+	if err != nil {
+		t.Fatalf("RefreshTokens: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.UserID != userID {
+		t.Fatalf("refresh must issue a new refresh token of the same user, got %+v", second)
+	}
+
+	firstSession, err := env.sessions.GetByRefreshToken(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondSession, err := env.sessions.GetByRefreshToken(ctx, second.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstSession.RotatedAt == nil {
+		t.Fatal("the used refresh token must be kept as rotated")
+	}
+	if secondSession.FamilyID != firstSession.FamilyID {
+		t.Fatal("the new refresh token must stay in the family")
+	}
+
+	if _, err := env.authService.RefreshTokens(ctx, second.RefreshToken, service.ClientInfo{}); err != nil {
+		t.Fatalf("refresh with the newest token: %v", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.createUser(t, "alice")
+
+	stolen := env.login(t, "alice")
+	otherDevice := env.login(t, "alice")
+
+	current, err := env.authService.RefreshTokens(ctx, stolen.RefreshToken, service.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rotated token comes back: whoever holds the family can not be trusted
+	if _, err := env.authService.RefreshTokens(ctx, stolen.RefreshToken, service.ClientInfo{}); !errors.Is(err, ErrTokenReused) {
+		t.Fatalf("reused token: got %v, want ErrTokenReused", err)
+	}
+
+	if _, err := env.authService.RefreshTokens(ctx, current.RefreshToken, service.ClientInfo{}); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("newest token of the revoked family: got %v, want ErrTokenNotFound", err)
+	}
+
+	// Other families of the user are not affected
+	if _, err := env.authService.RefreshTokens(ctx, otherDevice.RefreshToken, service.ClientInfo{}); err != nil {
+		t.Fatalf("token of another family: %v", err)
+	}
+}
+
+func TestRefreshTokenExpired(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+
+	session := &repository.Session{
+		UserID:       userID,
+		RefreshToken: "expired-refresh-token",
+		ExpiresAt:    time.Now().Add(-time.Second),
+	}
+	if err := env.sessions.CreateSession(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.authService.RefreshTokens(ctx, session.RefreshToken, service.ClientInfo{}); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("expired token: got %v, want ErrExpiredToken", err)
+	}
+
+	// The expired session is removed, a second try does not find it
+	if _, err := env.authService.RefreshTokens(ctx, session.RefreshToken, service.ClientInfo{}); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("second try: got %v, want ErrTokenNotFound", err)
+	}
+}
+
+func TestRefreshTokenUnknown(t *testing.T) {
+	env := newTestEnv(t)
+
+	if _, err := env.authService.RefreshTokens(context.Background(), "unknown", service.ClientInfo{}); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("unknown token: got %v, want ErrTokenNotFound", err)
+	}
+}
+
+// login logs in with testPassword as a user without 2FA and returns the tokens
+func (env *testEnv) login(t *testing.T, username string) *service.TokenPair {
+	t.Helper()
+
+	result, err := env.authService.Login(context.Background(), username, testPassword, service.ClientInfo{})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if result.Tokens == nil {
+		t.Fatal("Login returned no tokens")
+	}
+
+	return result.Tokens
+}
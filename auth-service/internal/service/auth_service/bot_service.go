@@ -0,0 +1,226 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"auth.service/internal/repository"
+	"auth.service/internal/service"
+)
+
+// APIKeyPrefix distinguishes bot API keys from JWT access tokens.
+const APIKeyPrefix = "bot_"
+
+var (
+	ErrNotBotOwner   = errors.New("Not a bot owner")
+	ErrInvalidAPIKey = errors.New("Invalid API key")
+)
+
+type BotServiceImpl struct {
+	userRepo   repository.UserRepository
+	apiKeyRepo repository.APIKeyRepository
+	policy     *CredentialPolicy
+}
+
+func NewBotService(userRepo repository.UserRepository, apiKeyRepo repository.APIKeyRepository, policy *CredentialPolicy) *BotServiceImpl {
+	return &BotServiceImpl{
+		userRepo:   userRepo,
+		apiKeyRepo: apiKeyRepo,
+		policy:     policy,
+	}
+}
+
+// CreateBot registers a bot user owned by ownerID and issues its first API key.
+func (s *BotServiceImpl) CreateBot(ctx context.Context, ownerID, username string) (*service.Bot, error) {
+	op := "BotService.CreateBot"
+
+	if err := s.checkHumanUser(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
+	// Bots share the username namespace with users, so the same rules apply
+	if violations := s.policy.ValidateUsername(username); len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
+	}
+
+	existingUser, err := s.userRepo.UserByUsername(ctx, username)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if existingUser != nil {
+		return nil, ErrUserAlreadyExists
+	}
+
+	// Bots have no password and can only authenticate with API keys
+	user := &repository.User{
+		Username: username,
+		IsBot:    true,
+		OwnerID:  &ownerID,
+	}
+
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := s.issueAPIKey(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &service.Bot{
+		ID:       user.ID,
+		Username: user.Username,
+		OwnerID:  ownerID,
+		APIKey:   key,
+	}, nil
+}
+
+// CreateAPIKey issues an additional API key for a bot, e.g. for key rotation.
+func (s *BotServiceImpl) CreateAPIKey(ctx context.Context, ownerID, botID string) (*service.APIKey, error) {
+	op := "BotService.CreateAPIKey"
+
+	if err := s.checkBotOwner(ctx, ownerID, botID); err != nil {
+		return nil, err
+	}
+
+	key, err := s.issueAPIKey(ctx, botID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (s *BotServiceImpl) RevokeAPIKey(ctx context.Context, ownerID, keyID string) error {
+	op := "BotService.RevokeAPIKey"
+
+	key, err := s.apiKeyRepo.APIKeyByID(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return ErrInvalidAPIKey
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.checkBotOwner(ctx, ownerID, key.UserID); err != nil {
+		return err
+	}
+
+	if err := s.apiKeyRepo.RevokeAPIKey(ctx, key.ID, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey resolves an API key to the bot user it was issued for.
+func (s *BotServiceImpl) AuthenticateAPIKey(ctx context.Context, key string) (*service.User, error) {
+	op := "BotService.AuthenticateAPIKey"
+
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepo.APIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if apiKey.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.userRepo.UserByID(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !user.IsBot {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Usage tracking is best effort and must not block authentication
+	_ = s.apiKeyRepo.TouchAPIKey(ctx, apiKey.ID, time.Now())
+
+	return &service.User{
+		ID:       user.ID,
+		Username: user.Username,
+		IsBot:    true,
+	}, nil
+}
+
+func (s *BotServiceImpl) issueAPIKey(ctx context.Context, botID string) (*service.APIKey, error) {
+	key, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &repository.APIKey{
+		UserID:  botID,
+		KeyHash: hashAPIKey(key),
+	}
+
+	if err := s.apiKeyRepo.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	return &service.APIKey{
+		ID:  apiKey.ID,
+		Key: key,
+	}, nil
+}
+
+func (s *BotServiceImpl) checkHumanUser(ctx context.Context, userID string) error {
+	op := "BotService.checkHumanUser"
+
+	user, err := s.userRepo.UserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.IsBot {
+		return ErrNotBotOwner
+	}
+
+	return nil
+}
+
+func (s *BotServiceImpl) checkBotOwner(ctx context.Context, ownerID, botID string) error {
+	op := "BotService.checkBotOwner"
+
+	bot, err := s.userRepo.UserByID(ctx, botID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !bot.IsBot || bot.OwnerID == nil || *bot.OwnerID != ownerID {
+		return ErrNotBotOwner
+	}
+
+	return nil
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	return APIKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashAPIKey returns the value stored in the database; raw keys are never persisted.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
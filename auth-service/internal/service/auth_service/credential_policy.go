@@ -0,0 +1,247 @@
+package auth_service
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// bcrypt only looks at the first 72 bytes, longer passwords are refused by the hasher
+	maxPasswordBytes = 72
+	// Length of the hash prefix breached passwords are grouped by, as in k-anonymity range queries
+	breachedPrefixLength = 5
+	// Longest address SMTP can deliver to
+	maxEmailLength = 254
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Violation is a rule a request field breaks
+type Violation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError lists every rule a request breaks, so all of them can be fixed at once
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		parts = append(parts, violation.Field+": "+violation.Description)
+	}
+
+	return "Invalid request: " + strings.Join(parts, "; ")
+}
+
+// CredentialPolicyConfig configures the rules for usernames and passwords
+type CredentialPolicyConfig struct {
+	UsernameMinLength int
+	UsernameMaxLength int
+	PasswordMinLength int
+	// PasswordMinClasses is how many of lowercase letters, uppercase letters, digits
+	// and other characters a password must contain
+	PasswordMinClasses int
+	// RejectUsername refuses passwords that contain the username
+	RejectUsername bool
+	// BreachedFile is a file of SHA-1 hashes of breached passwords, one "HASH" or "HASH:COUNT"
+	// per line as in Have I Been Pwned downloads. No breached check without it
+	BreachedFile string
+}
+
+// CredentialPolicyConfigFromEnv reads the username and password rules from the environment
+func CredentialPolicyConfigFromEnv() CredentialPolicyConfig {
+	return CredentialPolicyConfig{
+		UsernameMinLength:  getIntEnv("USERNAME_MIN_LENGTH", 3),
+		UsernameMaxLength:  getIntEnv("USERNAME_MAX_LENGTH", 32),
+		PasswordMinLength:  getIntEnv("PASSWORD_MIN_LENGTH", 8),
+		PasswordMinClasses: getIntEnv("PASSWORD_MIN_CLASSES", 2),
+		RejectUsername:     getEnv("PASSWORD_REJECT_USERNAME", "true") != "false",
+		BreachedFile:       getEnv("PASSWORD_BREACHED_FILE", ""),
+	}
+}
+
+// CredentialPolicy validates usernames and passwords of new and updated users
+type CredentialPolicy struct {
+	config   CredentialPolicyConfig
+	breached *BreachedPasswords // nil when no breached password list is configured
+}
+
+func NewCredentialPolicy(config CredentialPolicyConfig) (*CredentialPolicy, error) {
+	policy := &CredentialPolicy{
+		config: config,
+	}
+
+	if config.BreachedFile != "" {
+		breached, err := LoadBreachedPasswords(config.BreachedFile)
+		if err != nil {
+			return nil, fmt.Errorf("CredentialPolicy.New: %w", err)
+		}
+		policy.breached = breached
+	}
+
+	return policy, nil
+}
+
+// ValidateUsername returns the username rules the username breaks
+func (p *CredentialPolicy) ValidateUsername(username string) []Violation {
+	var violations []Violation
+
+	length := utf8.RuneCountInString(username)
+	switch {
+	case length < p.config.UsernameMinLength:
+		violations = append(violations, Violation{"username", fmt.Sprintf("must be at least %d characters long", p.config.UsernameMinLength)})
+	case p.config.UsernameMaxLength > 0 && length > p.config.UsernameMaxLength:
+		violations = append(violations, Violation{"username", fmt.Sprintf("must be at most %d characters long", p.config.UsernameMaxLength)})
+	}
+
+	if username != "" && !usernamePattern.MatchString(username) {
+		violations = append(violations, Violation{"username", "may contain only latin letters, digits, '_', '.' and '-' and must start with a letter or a digit"})
+	}
+
+	return violations
+}
+
+// ValidateEmail returns the email rules the email breaks. Only a bare address is accepted,
+// without a display name or angle brackets
+func (p *CredentialPolicy) ValidateEmail(email string) []Violation {
+	if len(email) > maxEmailLength {
+		return []Violation{{"email", fmt.Sprintf("must be at most %d characters long", maxEmailLength)}}
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return []Violation{{"email", "must be a valid email address"}}
+	}
+
+	return nil
+}
+
+// normalizeEmail makes emails that differ only in case or surrounding spaces equal
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ValidatePassword returns the password rules the password breaks. The username is the one
+// the password is set for, it is used by the reject-if-contains-username rule
+func (p *CredentialPolicy) ValidatePassword(password, username string) []Violation {
+	var violations []Violation
+
+	if utf8.RuneCountInString(password) < p.config.PasswordMinLength {
+		violations = append(violations, Violation{"password", fmt.Sprintf("must be at least %d characters long", p.config.PasswordMinLength)})
+	}
+
+	if len(password) > maxPasswordBytes {
+		violations = append(violations, Violation{"password", fmt.Sprintf("must be at most %d bytes long", maxPasswordBytes)})
+	}
+
+	if characterClasses(password) < p.config.PasswordMinClasses {
+		violations = append(violations, Violation{"password", fmt.Sprintf("must contain at least %d of: lowercase letters, uppercase letters, digits, other characters", p.config.PasswordMinClasses)})
+	}
+
+	// Very short usernames would match too many passwords by chance
+	if p.config.RejectUsername && utf8.RuneCountInString(username) >= 3 &&
+		strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		violations = append(violations, Violation{"password", "must not contain the username"})
+	}
+
+	if p.breached != nil && password != "" && p.breached.Contains(password) {
+		violations = append(violations, Violation{"password", "appears in a list of breached passwords, choose another one"})
+	}
+
+	return violations
+}
+
+// characterClasses counts which of lowercase letters, uppercase letters, digits
+// and other characters the password contains
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, other} {
+		if present {
+			classes++
+		}
+	}
+
+	return classes
+}
+
+// BreachedPasswords is a local list of breached password hashes. Hashes are grouped by prefix
+// like in k-anonymity range queries: a lookup only searches the suffixes of one prefix
+type BreachedPasswords struct {
+	ranges map[string][]string // Hash prefix -> sorted hash suffixes
+}
+
+// LoadBreachedPasswords reads SHA-1 hashes of breached passwords, one "HASH" or "HASH:COUNT"
+// per line. Empty lines and lines starting with # are skipped
+func LoadBreachedPasswords(path string) (*BreachedPasswords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	breached := &BreachedPasswords{
+		ranges: make(map[string][]string),
+	}
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("%s:%d: expected a SHA-1 hash", path, line)
+		}
+
+		prefix := hash[:breachedPrefixLength]
+		breached.ranges[prefix] = append(breached.ranges[prefix], hash[breachedPrefixLength:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, suffixes := range breached.ranges {
+		slices.Sort(suffixes)
+	}
+
+	return breached, nil
+}
+
+// Contains reports whether the password is on the list
+func (b *BreachedPasswords) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, found := slices.BinarySearch(b.ranges[hash[:breachedPrefixLength]], hash[breachedPrefixLength:])
+	return found
+}
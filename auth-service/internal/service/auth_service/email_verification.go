@@ -0,0 +1,193 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"auth.service/internal/mailer"
+	"auth.service/internal/repository"
+)
+
+const (
+	emailTokenSize = 32
+	// Confirmation emails per user per hour, the addresses are chosen by the user and may belong to anyone
+	maxEmailVerificationsPerHour = 3
+	mailTimeout                  = 30 * time.Second
+)
+
+var (
+	ErrInvalidEmailToken         = errors.New("Invalid or expired email confirmation token")
+	ErrTooManyEmailVerifications = errors.New("Too many email changes, try again later")
+)
+
+// EmailVerificationConfig configures the email confirmation emails
+type EmailVerificationConfig struct {
+	TokenTTL time.Duration
+	// URL is the page that confirms the email, the token is added as the token query parameter.
+	// Without it the email contains just the token
+	URL string
+}
+
+// EmailVerificationConfigFromEnv reads the email confirmation configuration from the environment
+func EmailVerificationConfigFromEnv() EmailVerificationConfig {
+	return EmailVerificationConfig{
+		TokenTTL: parseDuration(getEnv("EMAIL_VERIFICATION_TTL", "24h")),
+		URL:      getEnv("EMAIL_VERIFICATION_URL", ""),
+	}
+}
+
+// EmailVerification sets user emails only after a token mailed to the new address is confirmed,
+// so nobody can attach an address they do not own and receive password resets for it
+type EmailVerification struct {
+	repo     repository.EmailVerificationRepository
+	userRepo repository.UserRepository
+	mailer   mailer.Mailer
+	config   EmailVerificationConfig
+	now      func() time.Time
+}
+
+func NewEmailVerification(repo repository.EmailVerificationRepository, userRepo repository.UserRepository, mail mailer.Mailer, config EmailVerificationConfig) *EmailVerification {
+	return &EmailVerification{
+		repo:     repo,
+		userRepo: userRepo,
+		mailer:   mail,
+		config:   config,
+		now:      time.Now,
+	}
+}
+
+// Start mails a confirmation token to the email. The current email of the user stays until
+// the token is confirmed, and tokens of earlier requested emails stop working
+func (v *EmailVerification) Start(ctx context.Context, user *repository.User, email string) error {
+	op := "EmailVerification.Start"
+
+	now := v.now()
+
+	recent, err := v.repo.CountEmailVerificationsSince(ctx, user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if recent >= maxEmailVerificationsPerHour {
+		return ErrTooManyEmailVerifications
+	}
+
+	token, err := newMailToken()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := v.repo.CloseUserEmailVerifications(ctx, user.ID, now); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = v.repo.CreateEmailVerification(ctx, &repository.EmailVerification{
+		UserID:    user.ID,
+		Email:     email,
+		TokenHash: hashSecret(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(v.config.TokenTTL),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := v.repo.DeleteExpiredEmailVerifications(ctx, now); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	body := fmt.Sprintf("Hello, %s!\n\nTo use this address for your account, %s\n"+
+		"It expires in %s. If you did not ask for it, ignore this email.\n",
+		user.Username, mailAction(v.config.URL, token, "confirm it"), v.config.TokenTTL)
+
+	go sendMail(v.mailer, mailer.Message{To: email, Subject: "Confirm your email", Body: body})
+
+	return nil
+}
+
+// Confirm sets the email the token was mailed to. The email is checked to be free only now,
+// so starting a verification does not tell whether an address is registered
+func (v *EmailVerification) Confirm(ctx context.Context, token string) error {
+	op := "EmailVerification.Confirm"
+
+	verification, err := v.repo.EmailVerificationByHash(ctx, hashSecret(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailVerificationNotFound) {
+			return ErrInvalidEmailToken
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := v.now()
+	if verification.UsedAt != nil || !now.Before(verification.ExpiresAt) {
+		return ErrInvalidEmailToken
+	}
+
+	existing, err := v.userRepo.UserByEmail(ctx, verification.Email)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if existing != nil && existing.ID != verification.UserID {
+		return ErrEmailAlreadyUsed
+	}
+
+	used, err := v.repo.UseEmailVerification(ctx, verification.ID, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !used {
+		return ErrInvalidEmailToken
+	}
+
+	user, err := v.userRepo.UserByID(ctx, verification.UserID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user.Email = &verification.Email
+	if err := v.userRepo.UpdateUser(ctx, user); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// newMailToken returns a random token for a link in an email
+func newMailToken() (string, error) {
+	buf := make([]byte, emailTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// mailAction tells how to use a mailed token: a link when the page URL is configured, the token otherwise
+func mailAction(pageURL, token, action string) string {
+	if pageURL != "" {
+		if parsed, err := url.Parse(pageURL); err == nil {
+			query := parsed.Query()
+			query.Set("token", token)
+			parsed.RawQuery = query.Encode()
+			pageURL = parsed.String()
+		}
+		return action + " with this link:\n\n" + pageURL + "\n"
+	}
+
+	return action + " with this token:\n\n" + token + "\n"
+}
+
+// sendMail sends a message outside of the request, failures are only logged
+func sendMail(m mailer.Mailer, msg mailer.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+	defer cancel()
+
+	if err := m.Send(ctx, msg); err != nil {
+		log.Printf("failed to send %q email: %v", msg.Subject, err)
+	}
+}
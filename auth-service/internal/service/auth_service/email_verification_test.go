@@ -0,0 +1,142 @@
+package auth_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// userEmail returns the confirmed email of the user, empty without one
+func (env *testEnv) userEmail(t *testing.T, userID string) string {
+	t.Helper()
+
+	user, err := env.users.UserByID(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email == nil {
+		return ""
+	}
+
+	return *user.Email
+}
+
+func TestEmailVerificationConfirm(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	userID, err := env.userService.CreateUser(ctx, "alice", testPassword, "Alice@Example.com")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	msg := env.mailer.next(t)
+	if msg.To != "alice@example.com" || msg.Subject != "Confirm your email" {
+		t.Fatalf("unexpected email %+v", msg)
+	}
+	if email := env.userEmail(t, userID); email != "" {
+		t.Fatalf("the email is set before it is confirmed: %q", email)
+	}
+
+	token := mailedToken(t, msg)
+	if err := env.userService.ConfirmEmail(ctx, token); err != nil {
+		t.Fatalf("ConfirmEmail: %v", err)
+	}
+	if email := env.userEmail(t, userID); email != "alice@example.com" {
+		t.Fatalf("email = %q, want the confirmed one", email)
+	}
+
+	if err := env.userService.ConfirmEmail(ctx, token); !errors.Is(err, ErrInvalidEmailToken) {
+		t.Fatalf("second use of the token: got %v, want ErrInvalidEmailToken", err)
+	}
+}
+
+func TestEmailVerificationNewerRequestClosesOlder(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	if err := env.userService.UpdateUser(ctx, userID, "", "", "first@example.com"); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	first := mailedToken(t, env.mailer.next(t))
+
+	if err := env.userService.UpdateUser(ctx, userID, "", "", "second@example.com"); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	second := mailedToken(t, env.mailer.next(t))
+
+	// The current email stays until the new one is confirmed
+	if email := env.userEmail(t, userID); email != "alice@example.com" {
+		t.Fatalf("email = %q, want the old one until the change is confirmed", email)
+	}
+
+	if err := env.userService.ConfirmEmail(ctx, first); !errors.Is(err, ErrInvalidEmailToken) {
+		t.Fatalf("token of an earlier request: got %v, want ErrInvalidEmailToken", err)
+	}
+	if err := env.userService.ConfirmEmail(ctx, second); err != nil {
+		t.Fatalf("ConfirmEmail: %v", err)
+	}
+	if email := env.userEmail(t, userID); email != "second@example.com" {
+		t.Fatalf("email = %q, want second@example.com", email)
+	}
+}
+
+func TestEmailVerificationTokenExpires(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	userID, err := env.userService.CreateUser(ctx, "alice", testPassword, "alice@example.com")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	token := mailedToken(t, env.mailer.next(t))
+
+	env.now = env.now.Add(24 * time.Hour)
+	if err := env.userService.ConfirmEmail(ctx, token); !errors.Is(err, ErrInvalidEmailToken) {
+		t.Fatalf("expired token: got %v, want ErrInvalidEmailToken", err)
+	}
+	if email := env.userEmail(t, userID); email != "" {
+		t.Fatalf("email = %q, an expired token must not set it", email)
+	}
+}
+
+func TestEmailVerificationEmailTaken(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	// Both users ask for the address, only the first one to confirm gets it
+	bobID, err := env.userService.CreateUser(ctx, "bob", testPassword, "shared@example.com")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	bobToken := mailedToken(t, env.mailer.next(t))
+
+	env.createUserWithEmail(t, "alice", "shared@example.com")
+
+	if err := env.userService.ConfirmEmail(ctx, bobToken); !errors.Is(err, ErrEmailAlreadyUsed) {
+		t.Fatalf("confirming a taken email: got %v, want ErrEmailAlreadyUsed", err)
+	}
+	if email := env.userEmail(t, bobID); email != "" {
+		t.Fatalf("email = %q, want none", email)
+	}
+}
+
+func TestEmailVerificationHourlyLimit(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+
+	for i := 0; i < maxEmailVerificationsPerHour; i++ {
+		if err := env.userService.UpdateUser(ctx, userID, "", "", "alice@example.com"); err != nil {
+			t.Fatalf("UpdateUser: %v", err)
+		}
+		env.mailer.next(t)
+	}
+
+	if err := env.userService.UpdateUser(ctx, userID, "", "", "alice@example.com"); !errors.Is(err, ErrTooManyEmailVerifications) {
+		t.Fatalf("got %v, want ErrTooManyEmailVerifications", err)
+	}
+	env.mailer.expectNone(t)
+}
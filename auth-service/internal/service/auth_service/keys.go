@@ -0,0 +1,405 @@
+package auth_service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"auth.service/internal/service"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+	AlgorithmHS256 = "HS256"
+)
+
+const (
+	rsaKeyBits         = 2048
+	keyFileExt         = ".pem"
+	keyRotationCheck   = time.Minute
+	privateKeyPEMBlock = "PRIVATE KEY"
+)
+
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// KeyStoreConfig configures how access tokens are signed
+type KeyStoreConfig struct {
+	Algorithm string        // EdDSA, RS256 or HS256
+	Dir       string        // Directory with PEM encoded PKCS#8 private keys, one key per file
+	Secret    []byte        // HMAC secret, used only with HS256
+	Rotation  time.Duration // Age after which a new signing key is generated, 0 disables rotation
+	Grace     time.Duration // How long a retired key still validates tokens
+}
+
+// KeyStoreConfigFromEnv reads the key store configuration from the environment.
+// The grace period is never shorter than the access token TTL, so rotation does not
+// invalidate tokens that were issued right before it
+func KeyStoreConfigFromEnv() KeyStoreConfig {
+	config := KeyStoreConfig{
+		Algorithm: getEnv("JWT_SIGNING_ALG", AlgorithmEdDSA),
+		Dir:       getEnv("JWT_KEYS_DIR", "keys"),
+		Secret:    []byte(os.Getenv("JWT_SECRET_KEY")),
+		Rotation:  parseDuration(getEnv("JWT_KEY_ROTATION", "720h")),
+		Grace:     parseDuration(getEnv("JWT_KEY_GRACE", "24h")),
+	}
+
+	if accessTTL := parseDuration(getEnv("ACCESS_TOKEN_TTL", "15m")); config.Grace < accessTTL {
+		config.Grace = accessTTL
+	}
+
+	return config
+}
+
+// signingKey is a key pair (or an HMAC secret) identified by kid
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	private   interface{}
+	public    crypto.PublicKey // nil for HMAC
+	createdAt time.Time
+}
+
+// KeyStore holds the keys that sign and verify access tokens. The newest key signs new tokens,
+// older keys keep validating tokens until the grace period after their retirement is over
+type KeyStore struct {
+	config KeyStoreConfig
+	mu     sync.RWMutex
+	keys   []*signingKey // Sorted by creation time, the last key is the active one
+}
+
+// NewKeyStore loads the keys from config.Dir, generating a signing key if there is no usable one
+func NewKeyStore(config KeyStoreConfig) (*KeyStore, error) {
+	op := "KeyStore.New"
+
+	s := &KeyStore{config: config}
+
+	switch config.Algorithm {
+	case AlgorithmHS256:
+		if len(config.Secret) == 0 {
+			return nil, fmt.Errorf("%s: JWT_SECRET_KEY is required for %s", op, AlgorithmHS256)
+		}
+
+		s.keys = []*signingKey{{
+			id:        hmacKeyID(config.Secret),
+			method:    jwt.SigningMethodHS256,
+			private:   config.Secret,
+			createdAt: time.Now(),
+		}}
+
+		return s, nil
+
+	case AlgorithmEdDSA, AlgorithmRS256:
+	default:
+		return nil, fmt.Errorf("%s: unsupported signing algorithm %q", op, config.Algorithm)
+	}
+
+	if err := os.MkdirAll(config.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys, err := loadKeys(config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	s.keys = keys
+
+	if s.needsRotation(time.Now()) {
+		if err := s.Rotate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	s.prune(time.Now())
+
+	return s, nil
+}
+
+// Rotate generates a new signing key. The previous key keeps validating tokens for the grace period
+func (s *KeyStore) Rotate() error {
+	op := "KeyStore.Rotate"
+
+	key, err := generateKey(s.config.Algorithm)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := saveKey(s.config.Dir, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.mu.Lock()
+	s.keys = append(s.keys, key)
+	s.mu.Unlock()
+
+	log.Printf("generated signing key %s (%s)", key.id, key.method.Alg())
+
+	return nil
+}
+
+// RunRotation rotates the signing key when it gets older than the rotation period
+// and removes keys whose grace period is over, until the context is cancelled
+func (s *KeyStore) RunRotation(ctx context.Context) {
+	if s.config.Algorithm == AlgorithmHS256 {
+		return
+	}
+
+	ticker := time.NewTicker(keyRotationCheck)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		if s.needsRotation(now) {
+			if err := s.Rotate(); err != nil {
+				log.Printf("failed to rotate signing key: %v", err)
+			}
+		}
+		s.prune(now)
+	}
+}
+
+// PublicKeys returns the public keys of all keys that still validate tokens
+func (s *KeyStore) PublicKeys() []service.PublicKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	var keys []service.PublicKey
+	for i, key := range s.keys {
+		if key.public == nil || !s.validAt(i, now) {
+			continue
+		}
+
+		keys = append(keys, service.PublicKey{
+			ID:        key.id,
+			Algorithm: key.method.Alg(),
+			Key:       key.public,
+		})
+	}
+
+	return keys
+}
+
+// signingKey returns the key that signs new tokens
+func (s *KeyStore) signingKey() *signingKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.keys[len(s.keys)-1]
+}
+
+// verificationKey returns the key with the given ID if it still validates tokens
+func (s *KeyStore) verificationKey(keyID string) (*signingKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	for i, key := range s.keys {
+		if key.id == keyID && s.validAt(i, now) {
+			return key, nil
+		}
+	}
+
+	return nil, ErrUnknownSigningKey
+}
+
+// keyFunc looks up the key that verifies a parsed token
+func (s *KeyStore) keyFunc(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+
+	key, err := s.verificationKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	if key.public != nil {
+		return key.public, nil
+	}
+
+	return key.private, nil
+}
+
+// validAt reports whether the i-th key validates tokens at now.
+// A key is retired when the next one is created and stays valid for the grace period after that.
+// Must be called with the lock held
+func (s *KeyStore) validAt(i int, now time.Time) bool {
+	if i == len(s.keys)-1 {
+		return true
+	}
+
+	retiredAt := s.keys[i+1].createdAt
+	return now.Before(retiredAt.Add(s.config.Grace))
+}
+
+func (s *KeyStore) needsRotation(now time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.keys) == 0 {
+		return true
+	}
+
+	active := s.keys[len(s.keys)-1]
+	if active.method.Alg() != s.config.Algorithm {
+		return true
+	}
+
+	return s.config.Rotation > 0 && now.Sub(active.createdAt) >= s.config.Rotation
+}
+
+// prune forgets keys whose grace period is over and removes their files
+func (s *KeyStore) prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.keys[:0]
+	for i, key := range s.keys {
+		if s.validAt(i, now) {
+			kept = append(kept, key)
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(s.config.Dir, key.id+keyFileExt)); err != nil && !os.IsNotExist(err) {
+			log.Printf("failed to remove expired signing key %s: %v", key.id, err)
+		}
+		log.Printf("signing key %s expired", key.id)
+	}
+	s.keys = kept
+}
+
+// loadKeys reads all PEM keys from dir sorted by file modification time
+func loadKeys(dir string) ([]*signingKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*signingKey
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExt {
+			continue
+		}
+
+		key, err := loadKey(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].createdAt.Before(keys[j].createdAt)
+	})
+
+	return keys, nil
+}
+
+func loadKey(path string) (*signingKey, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != privateKeyPEMBlock {
+		return nil, errors.New("expected a PKCS#8 PRIVATE KEY block")
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newSigningKey(private, info.ModTime())
+	if err != nil {
+		return nil, err
+	}
+
+	// The file name is the key ID, so keys provided by operators may use their own IDs
+	key.id = strings.TrimSuffix(filepath.Base(path), keyFileExt)
+
+	return key, nil
+}
+
+func generateKey(algorithm string) (*signingKey, error) {
+	var private interface{}
+	var err error
+
+	switch algorithm {
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newSigningKey(private, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key.public)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+	key.id = strings.ToLower(algorithm) + "-" + hex.EncodeToString(sum[:8])
+
+	return key, nil
+}
+
+func newSigningKey(private interface{}, createdAt time.Time) (*signingKey, error) {
+	switch k := private.(type) {
+	case ed25519.PrivateKey:
+		return &signingKey{method: jwt.SigningMethodEdDSA, private: k, public: k.Public(), createdAt: createdAt}, nil
+	case *rsa.PrivateKey:
+		return &signingKey{method: jwt.SigningMethodRS256, private: k, public: k.Public(), createdAt: createdAt}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", private)
+	}
+}
+
+func saveKey(dir string, key *signingKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return err
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMBlock, Bytes: der})
+	return os.WriteFile(filepath.Join(dir, key.id+keyFileExt), data, 0o600)
+}
@@ -0,0 +1,195 @@
+package auth_service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signTestToken signs an access token with the current signing key of the store
+func signTestToken(t *testing.T, s *KeyStore) string {
+	t.Helper()
+
+	key := s.signingKey()
+	token := jwt.NewWithClaims(key.method, &CustomClaims{
+		UserID: "user-1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	token.Header["kid"] = key.id
+
+	signed, err := token.SignedString(key.private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+// verifyTestToken checks the token the same way ValidateToken does
+func verifyTestToken(s *KeyStore, token string) error {
+	_, err := jwt.ParseWithClaims(token, &CustomClaims{}, s.keyFunc)
+	return err
+}
+
+// writeTestKey saves a new Ed25519 key as <dir>/<id>.pem with the given modification time
+func writeTestKey(t *testing.T, dir, id string, modTime time.Time) {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, id+keyFileExt)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMBlock, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeyStoreRotationGrace(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewKeyStore(KeyStoreConfig{Algorithm: AlgorithmEdDSA, Dir: dir, Grace: time.Hour})
+	if err != nil {
+		t.Fatalf("NewKeyStore: %v", err)
+	}
+
+	oldKeyID := s.signingKey().id
+	oldToken := signTestToken(t, s)
+
+	if err := s.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if s.signingKey().id == oldKeyID {
+		t.Fatal("new tokens must be signed with the new key after a rotation")
+	}
+	newToken := signTestToken(t, s)
+
+	// During the grace period both keys verify and both are published
+	if err := verifyTestToken(s, oldToken); err != nil {
+		t.Fatalf("token of the retired key during the grace period: %v", err)
+	}
+	if keys := s.PublicKeys(); len(keys) != 2 {
+		t.Fatalf("got %d public keys during the grace period, want 2", len(keys))
+	}
+
+	s.prune(time.Now().Add(2 * time.Hour))
+
+	if err := verifyTestToken(s, oldToken); !errors.Is(err, ErrUnknownSigningKey) {
+		t.Fatalf("token of a pruned key: got %v, want ErrUnknownSigningKey", err)
+	}
+	if err := verifyTestToken(s, newToken); err != nil {
+		t.Fatalf("token of the active key after pruning: %v", err)
+	}
+	if keys := s.PublicKeys(); len(keys) != 1 || keys[0].ID == oldKeyID {
+		t.Fatalf("public keys after pruning = %+v, want only the active key", keys)
+	}
+	if _, err := os.Stat(filepath.Join(dir, oldKeyID+keyFileExt)); !os.IsNotExist(err) {
+		t.Fatalf("the file of the pruned key must be removed, stat: %v", err)
+	}
+}
+
+func TestKeyStoreLoadsKeysFromDir(t *testing.T) {
+	now := time.Now()
+	oldCreated := now.Add(-2 * time.Hour).Truncate(time.Second)
+	newCreated := now.Add(-time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name       string
+		rotation   time.Duration
+		grace      time.Duration
+		wantSigner string // Empty means a newly generated key
+		wantKeys   []string
+	}{
+		{"newest file signs", 0, 2 * time.Hour, "operator-new", []string{"operator-old", "operator-new"}},
+		{"retired key past the grace period is dropped", 0, 30 * time.Minute, "operator-new", []string{"operator-new"}},
+		{"old active key is rotated on start", 30 * time.Minute, 2 * time.Hour, "", []string{"operator-old", "operator-new"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			// Written in reverse order, the creation time comes from the modification time
+			writeTestKey(t, dir, "operator-new", newCreated)
+			writeTestKey(t, dir, "operator-old", oldCreated)
+
+			s, err := NewKeyStore(KeyStoreConfig{Algorithm: AlgorithmEdDSA, Dir: dir, Rotation: tt.rotation, Grace: tt.grace})
+			if err != nil {
+				t.Fatalf("NewKeyStore: %v", err)
+			}
+
+			for _, id := range tt.wantKeys {
+				if _, err := s.verificationKey(id); err != nil {
+					t.Errorf("key %s must verify tokens: %v", id, err)
+				}
+			}
+			if len(tt.wantKeys) == 1 {
+				if _, err := s.verificationKey("operator-old"); !errors.Is(err, ErrUnknownSigningKey) {
+					t.Errorf("key past its grace period: got %v, want ErrUnknownSigningKey", err)
+				}
+			}
+
+			signer := s.signingKey()
+			if tt.wantSigner == "" {
+				if signer.id == "operator-new" || signer.id == "operator-old" {
+					t.Fatalf("signing key = %s, want a newly generated key", signer.id)
+				}
+				return
+			}
+			if signer.id != tt.wantSigner {
+				t.Fatalf("signing key = %s, want %s", signer.id, tt.wantSigner)
+			}
+			if !signer.createdAt.Equal(newCreated) {
+				t.Errorf("createdAt = %s, want the file modification time %s", signer.createdAt, newCreated)
+			}
+		})
+	}
+}
+
+func TestKeyStoreRejectsBrokenKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken"+keyFileExt), []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewKeyStore(KeyStoreConfig{Algorithm: AlgorithmEdDSA, Dir: dir}); err == nil {
+		t.Fatal("NewKeyStore must fail on a file that is not a PEM key")
+	}
+}
+
+func TestKeyStoreHS256RequiresSecret(t *testing.T) {
+	t.Setenv("JWT_SIGNING_ALG", AlgorithmHS256)
+	t.Setenv("JWT_SECRET_KEY", "")
+
+	if _, err := NewKeyStore(KeyStoreConfigFromEnv()); err == nil {
+		t.Fatal("switching to HS256 without JWT_SECRET_KEY must fail at startup")
+	}
+
+	t.Setenv("JWT_SECRET_KEY", "secret")
+	s, err := NewKeyStore(KeyStoreConfigFromEnv())
+	if err != nil {
+		t.Fatalf("NewKeyStore: %v", err)
+	}
+	if err := verifyTestToken(s, signTestToken(t, s)); err != nil {
+		t.Fatalf("HS256 token: %v", err)
+	}
+	if keys := s.PublicKeys(); len(keys) != 0 {
+		t.Fatalf("the HMAC secret must not be published, got %d public keys", len(keys))
+	}
+}
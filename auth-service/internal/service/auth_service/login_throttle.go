@@ -0,0 +1,193 @@
+package auth_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"auth.service/internal/repository"
+)
+
+const (
+	throttleScopeUsername = "username"
+	throttleScopeIP       = "ip"
+	maxThrottleSubject    = 255
+)
+
+var (
+	ErrTooManyAttempts = errors.New("Too many failed login attempts")
+	ErrAccountLocked   = errors.New("Account is locked")
+)
+
+// LoginThrottleConfig configures the backoff of failed logins and the account lockout
+type LoginThrottleConfig struct {
+	FreeAttempts     int           // Failures per username before logins are delayed
+	IPFreeAttempts   int           // Failures per client IP before logins are delayed, an IP may try many usernames
+	BaseDelay        time.Duration // Delay after the first failure over the free attempts, doubled on every next one
+	MaxDelay         time.Duration
+	LockoutThreshold int           // Failures per username that lock the account until an admin unlocks it, 0 disables the lockout
+	FailureWindow    time.Duration // Failures older than this are forgotten
+}
+
+// LoginThrottleConfigFromEnv reads the login throttling configuration from the environment
+func LoginThrottleConfigFromEnv() LoginThrottleConfig {
+	return LoginThrottleConfig{
+		FreeAttempts:     getIntEnv("LOGIN_FREE_ATTEMPTS", 3),
+		IPFreeAttempts:   getIntEnv("LOGIN_IP_FREE_ATTEMPTS", 20),
+		BaseDelay:        parseDuration(getEnv("LOGIN_BACKOFF_BASE", "1s")),
+		MaxDelay:         parseDuration(getEnv("LOGIN_BACKOFF_MAX", "15m")),
+		LockoutThreshold: getIntEnv("LOGIN_LOCKOUT_THRESHOLD", 10),
+		FailureWindow:    parseDuration(getEnv("LOGIN_FAILURE_WINDOW", "24h")),
+	}
+}
+
+// LoginThrottle slows down password guessing. Failed logins are counted per username and
+// per client IP: past the free attempts every next login waits exponentially longer, and
+// too many failures for a username lock it. Usernames are throttled the same way whether
+// the user exists or not, so the throttle does not reveal which usernames are taken
+type LoginThrottle struct {
+	repo   repository.LoginThrottleRepository
+	config LoginThrottleConfig
+	now    func() time.Time
+}
+
+func NewLoginThrottle(repo repository.LoginThrottleRepository, config LoginThrottleConfig) *LoginThrottle {
+	return &LoginThrottle{
+		repo:   repo,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Check returns ErrAccountLocked or ErrTooManyAttempts when a login for the username
+// from the IP must be refused without looking at the password. An empty IP is not throttled
+func (t *LoginThrottle) Check(ctx context.Context, username, ip string) error {
+	op := "LoginThrottle.Check"
+
+	username = throttleSubject(username)
+	now := t.now()
+
+	throttle, err := t.repo.LoginThrottle(ctx, throttleScopeUsername, username)
+	if err != nil && !errors.Is(err, repository.ErrThrottleNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if throttle != nil {
+		if throttle.LockedAt != nil {
+			return ErrAccountLocked
+		}
+		if throttle.BlockedUntil != nil && now.Before(*throttle.BlockedUntil) {
+			return ErrTooManyAttempts
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	throttle, err = t.repo.LoginThrottle(ctx, throttleScopeIP, ip)
+	if err != nil && !errors.Is(err, repository.ErrThrottleNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if throttle != nil && throttle.BlockedUntil != nil && now.Before(*throttle.BlockedUntil) {
+		return ErrTooManyAttempts
+	}
+
+	return nil
+}
+
+// Failure records a failed login for the username and the IP and delays the next ones
+func (t *LoginThrottle) Failure(ctx context.Context, username, ip string) error {
+	op := "LoginThrottle.Failure"
+
+	username = throttleSubject(username)
+	now := t.now()
+	resetBefore := now.Add(-t.config.FailureWindow)
+
+	failures, err := t.repo.AddFailure(ctx, throttleScopeUsername, username, now, resetBefore)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if t.config.LockoutThreshold > 0 && failures >= t.config.LockoutThreshold {
+		if err := t.repo.Lock(ctx, throttleScopeUsername, username, now); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	} else if delay := t.delay(failures, t.config.FreeAttempts); delay > 0 {
+		if err := t.repo.Block(ctx, throttleScopeUsername, username, now.Add(delay)); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if ip != "" {
+		failures, err := t.repo.AddFailure(ctx, throttleScopeIP, ip, now, resetBefore)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if delay := t.delay(failures, t.config.IPFreeAttempts); delay > 0 {
+			if err := t.repo.Block(ctx, throttleScopeIP, ip, now.Add(delay)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	if err := t.repo.DeleteStale(ctx, resetBefore); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Success forgets the failures of the username. Failures of the IP are kept,
+// so logging into an own account does not reset guessing at other accounts
+func (t *LoginThrottle) Success(ctx context.Context, username string) error {
+	if err := t.repo.Reset(ctx, throttleScopeUsername, throttleSubject(username)); err != nil {
+		return fmt.Errorf("LoginThrottle.Success: %w", err)
+	}
+
+	return nil
+}
+
+// Unlock lifts the lockout and the backoff of the username
+func (t *LoginThrottle) Unlock(ctx context.Context, username string) error {
+	if err := t.repo.Reset(ctx, throttleScopeUsername, throttleSubject(username)); err != nil {
+		return fmt.Errorf("LoginThrottle.Unlock: %w", err)
+	}
+
+	return nil
+}
+
+// delay is BaseDelay doubled for every failure over the free attempts, capped at MaxDelay
+func (t *LoginThrottle) delay(failures, freeAttempts int) time.Duration {
+	over := failures - freeAttempts
+	if over <= 0 {
+		return 0
+	}
+
+	delay := t.config.BaseDelay
+	for i := 1; i < over && delay < t.config.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, t.config.MaxDelay)
+}
+
+// throttleSubject keeps arbitrarily long usernames within the column, they can not be real usernames anyway
+func throttleSubject(username string) string {
+	if len(username) > maxThrottleSubject {
+		return hashSecret(username)
+	}
+
+	return username
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
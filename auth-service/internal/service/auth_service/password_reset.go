@@ -0,0 +1,190 @@
+package auth_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"auth.service/internal/mailer"
+	"auth.service/internal/repository"
+)
+
+// Reset emails per user per hour, more requests are silently dropped so nobody can flood a mailbox
+const maxResetsPerHour = 3
+
+var ErrInvalidResetToken = errors.New("Invalid or expired password reset token")
+
+// PasswordResetConfig configures the password reset emails
+type PasswordResetConfig struct {
+	TokenTTL time.Duration
+	// URL is the page that resets the password, the token is added as the token query parameter.
+	// Without it the email contains just the token
+	URL string
+}
+
+// PasswordResetConfigFromEnv reads the password reset configuration from the environment
+func PasswordResetConfigFromEnv() PasswordResetConfig {
+	return PasswordResetConfig{
+		TokenTTL: parseDuration(getEnv("PASSWORD_RESET_TTL", "1h")),
+		URL:      getEnv("PASSWORD_RESET_URL", ""),
+	}
+}
+
+// PasswordReset resets forgotten passwords with one-time tokens sent by email
+type PasswordReset struct {
+	repo        repository.PasswordResetRepository
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	mailer      mailer.Mailer
+	policy      *CredentialPolicy
+	throttle    *LoginThrottle
+	config      PasswordResetConfig
+	now         func() time.Time
+}
+
+func NewPasswordReset(repo repository.PasswordResetRepository, userRepo repository.UserRepository, sessionRepo repository.SessionRepository, mail mailer.Mailer, policy *CredentialPolicy, throttle *LoginThrottle, config PasswordResetConfig) *PasswordReset {
+	return &PasswordReset{
+		repo:        repo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		mailer:      mail,
+		policy:      policy,
+		throttle:    throttle,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// Request mails a reset token to the user with the email. Unknown emails, bots and requests
+// over the hourly limit are ignored without an error, and the email is sent in the background,
+// so neither the response nor its timing tells whether the email is registered
+func (r *PasswordReset) Request(ctx context.Context, email string) error {
+	op := "PasswordReset.Request"
+
+	user, err := r.userRepo.UserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if user.IsBot || user.Email == nil {
+		return nil
+	}
+
+	now := r.now()
+
+	recent, err := r.repo.CountResetTokensSince(ctx, user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if recent >= maxResetsPerHour {
+		log.Printf("password reset limit reached for user %s", user.ID)
+		return nil
+	}
+
+	token, err := newMailToken()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = r.repo.CreateResetToken(ctx, &repository.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashSecret(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(r.config.TokenTTL),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := r.repo.DeleteExpiredResetTokens(ctx, now); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	go sendMail(r.mailer, r.resetMessage(*user.Email, user.Username, token))
+
+	return nil
+}
+
+// Reset sets a new password with a mailed token. The token works once; on success every session
+// of the user ends, the other reset tokens stop working and a login lockout is lifted,
+// as the user has proven to own the email
+func (r *PasswordReset) Reset(ctx context.Context, token, newPassword string) error {
+	op := "PasswordReset.Reset"
+
+	resetToken, err := r.repo.ResetTokenByHash(ctx, hashSecret(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrResetNotFound) {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := r.now()
+	if resetToken.UsedAt != nil || !now.Before(resetToken.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	// The account may have been deleted after the token was mailed
+	user, err := r.userRepo.UserByID(ctx, resetToken.UserID)
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, repository.ErrUserNotFound) || (err == nil && user == nil) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The password is checked before the token is used up, so a rejected password can be replaced
+	if violations := r.policy.ValidatePassword(newPassword, user.Username); len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	used, err := r.repo.UseResetToken(ctx, resetToken.ID, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	user.PasswordHash, err = hashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := r.userRepo.UpdateUser(ctx, user); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Whoever knew the old password may be logged in somewhere
+	if err := r.sessionRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := r.repo.DeleteUserResetTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := r.throttle.Unlock(ctx, user.Username); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PasswordReset) resetMessage(email, username, token string) mailer.Message {
+	body := fmt.Sprintf("Hello, %s!\n\nSomeone asked to reset the password of your account. To choose a new password, %s\n"+
+		"It expires in %s and works once. You will be logged out on all devices.\n"+
+		"If it was not you, ignore this email, your password stays the same.\n",
+		username, mailAction(r.config.URL, token, "open the reset page"), r.config.TokenTTL)
+
+	return mailer.Message{
+		To:      email,
+		Subject: "Password reset",
+		Body:    body,
+	}
+}
@@ -0,0 +1,216 @@
+package auth_service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"auth.service/internal/repository"
+	"auth.service/internal/service"
+)
+
+const newTestPassword = "Battery-staple-2"
+
+// requestReset asks for a password reset and returns the mailed token
+func (env *testEnv) requestReset(t *testing.T, email string) string {
+	t.Helper()
+
+	if err := env.userService.RequestPasswordReset(context.Background(), email); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+
+	return mailedToken(t, env.mailer.next(t))
+}
+
+func TestPasswordResetMailsToken(t *testing.T) {
+	env := newTestEnv(t)
+	env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	// The email is normalized the same way as on registration
+	if err := env.userService.RequestPasswordReset(context.Background(), " Alice@Example.com "); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+
+	msg := env.mailer.next(t)
+	if msg.To != "alice@example.com" || msg.Subject != "Password reset" {
+		t.Fatalf("unexpected email %+v", msg)
+	}
+	if !strings.Contains(msg.Body, testResetURL+"?token=") {
+		t.Fatalf("the email must link to the reset page:\n%s", msg.Body)
+	}
+
+	if err := env.userService.ResetPassword(context.Background(), mailedToken(t, msg), newTestPassword); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+}
+
+func TestPasswordResetUnknownEmail(t *testing.T) {
+	env := newTestEnv(t)
+	env.createUser(t, "alice")
+
+	// Unknown emails get the same answer, so the response does not tell whether an email is registered
+	if err := env.userService.RequestPasswordReset(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	env.mailer.expectNone(t)
+}
+
+func TestPasswordResetHourlyLimit(t *testing.T) {
+	env := newTestEnv(t)
+	env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	for i := 0; i < maxResetsPerHour; i++ {
+		env.requestReset(t, "alice@example.com")
+	}
+
+	if err := env.userService.RequestPasswordReset(context.Background(), "alice@example.com"); err != nil {
+		t.Fatalf("requests over the limit must be dropped silently, got %v", err)
+	}
+	env.mailer.expectNone(t)
+
+	env.now = env.now.Add(time.Hour + time.Minute)
+	env.requestReset(t, "alice@example.com")
+}
+
+func TestPasswordResetTokenWorksOnce(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	token := env.requestReset(t, "alice@example.com")
+
+	if err := env.userService.ResetPassword(ctx, token, newTestPassword); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	if _, err := env.authService.Login(ctx, "alice", testPassword, service.ClientInfo{}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("login with the old password: got %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := env.authService.Login(ctx, "alice", newTestPassword, service.ClientInfo{}); err != nil {
+		t.Fatalf("login with the new password: %v", err)
+	}
+
+	if err := env.userService.ResetPassword(ctx, token, "Another-pass-3"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("second use of the token: got %v, want ErrInvalidResetToken", err)
+	}
+}
+
+func TestPasswordResetRevokesOtherTokens(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	first := env.requestReset(t, "alice@example.com")
+	second := env.requestReset(t, "alice@example.com")
+
+	if err := env.userService.ResetPassword(ctx, second, newTestPassword); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if err := env.userService.ResetPassword(ctx, first, "Another-pass-3"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("an older token after a reset: got %v, want ErrInvalidResetToken", err)
+	}
+}
+
+func TestPasswordResetTokenExpires(t *testing.T) {
+	env := newTestEnv(t)
+	env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	token := env.requestReset(t, "alice@example.com")
+	env.now = env.now.Add(time.Hour)
+
+	if err := env.userService.ResetPassword(context.Background(), token, newTestPassword); !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("expired token: got %v, want ErrInvalidResetToken", err)
+	}
+	env.login(t, "alice")
+}
+
+func TestPasswordResetRejectedPasswordKeepsToken(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	token := env.requestReset(t, "alice@example.com")
+
+	var validationErr *ValidationError
+	if err := env.userService.ResetPassword(ctx, token, "short"); !errors.As(err, &validationErr) {
+		t.Fatalf("weak password: got %v, want a ValidationError", err)
+	}
+	if err := env.userService.ResetPassword(ctx, token, newTestPassword); err != nil {
+		t.Fatalf("the token must still work after a rejected password: %v", err)
+	}
+}
+
+func TestPasswordResetRevokesSessions(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	// Two devices, each with its own refresh family; one of them has rotated its token already
+	laptop := env.login(t, "alice")
+	phone := env.login(t, "alice")
+	phone, err := env.authService.RefreshTokens(ctx, phone.RefreshToken, service.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.userService.ResetPassword(ctx, env.requestReset(t, "alice@example.com"), newTestPassword); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	for name, tokens := range map[string]*service.TokenPair{"laptop": laptop, "phone": phone} {
+		if _, err := env.authService.RefreshTokens(ctx, tokens.RefreshToken, service.ClientInfo{}); !errors.Is(err, ErrTokenNotFound) {
+			t.Errorf("refresh on the %s after a password reset: got %v, want ErrTokenNotFound", name, err)
+		}
+	}
+
+	sessions, err := env.sessions.ActiveByUserID(ctx, userID, env.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("got %d sessions after a password reset, want none", len(sessions))
+	}
+}
+
+func TestPasswordResetUnlocksAccount(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	for i := 0; i < 5; i++ {
+		env.authService.Login(ctx, "alice", "Wrong-password-1", service.ClientInfo{})
+	}
+	if _, err := env.authService.Login(ctx, "alice", testPassword, service.ClientInfo{}); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("got %v, want ErrAccountLocked", err)
+	}
+
+	if err := env.userService.ResetPassword(ctx, env.requestReset(t, "alice@example.com"), newTestPassword); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if _, err := env.authService.Login(ctx, "alice", newTestPassword, service.ClientInfo{}); err != nil {
+		t.Fatalf("login after the reset: %v", err)
+	}
+}
+
+// missingUserRepository finds no users, like a repository after the account was deleted
+type missingUserRepository struct {
+	repository.UserRepository
+}
+
+func (missingUserRepository) UserByID(ctx context.Context, id string) (*repository.User, error) {
+	return nil, nil
+}
+
+func TestPasswordResetMissingUser(t *testing.T) {
+	env := newTestEnv(t)
+	env.createUserWithEmail(t, "alice", "alice@example.com")
+
+	token := env.requestReset(t, "alice@example.com")
+	env.passwordReset.userRepo = missingUserRepository{env.users}
+
+	if err := env.userService.ResetPassword(context.Background(), token, newTestPassword); !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("token of a missing user: got %v, want ErrInvalidResetToken", err)
+	}
+}
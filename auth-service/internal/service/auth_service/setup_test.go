@@ -0,0 +1,174 @@
+package auth_service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"auth.service/internal/mailer"
+	"auth.service/internal/repository/repotest"
+	repo "auth.service/internal/repository/sqlite"
+	"github.com/jmoiron/sqlx"
+)
+
+const testPassword = "Correct-horse-1"
+
+// testEnv wires the services to an in-memory database. Components with a clock
+// read env.now, so tests move time by changing it
+type testEnv struct {
+	db                *sqlx.DB
+	now               time.Time
+	users             *repo.SqliteUserRepository
+	sessions          *repo.SqliteSessionRepository
+	mailer            *fakeMailer
+	twoFactor         *TwoFactor
+	throttle          *LoginThrottle
+	passwordReset     *PasswordReset
+	emailVerification *EmailVerification
+	authService       *AuthServiceImpl
+	userService       *UserServiceImpl
+}
+
+// testResetURL is the reset page, reset emails carry a link while confirmation emails carry the bare token
+const testResetURL = "https://chat.example/reset"
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	db := repotest.Sqlite(t)
+	env := &testEnv{
+		db:       db,
+		now:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		users:    repo.NewUserRepository(db),
+		sessions: repo.NewSessionRepository(db),
+		mailer:   &fakeMailer{sent: make(chan mailer.Message, 16)},
+	}
+	clock := func() time.Time { return env.now }
+	users := env.users
+
+	var err error
+	env.twoFactor, err = NewTwoFactor(repo.NewTwoFactorRepository(db), TwoFactorConfig{
+		Issuer:        "Test",
+		EncryptionKey: make([]byte, 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.twoFactor.now = clock
+
+	env.throttle = NewLoginThrottle(repo.NewLoginThrottleRepository(db), LoginThrottleConfig{
+		FreeAttempts:     100,
+		IPFreeAttempts:   100,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: 5,
+		FailureWindow:    time.Hour,
+	})
+	env.throttle.now = clock
+
+	policy, err := NewCredentialPolicy(CredentialPolicyConfig{
+		UsernameMinLength:  3,
+		UsernameMaxLength:  32,
+		PasswordMinLength:  8,
+		PasswordMinClasses: 2,
+		RejectUsername:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := NewKeyStore(KeyStoreConfig{Algorithm: AlgorithmHS256, Secret: []byte("test secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env.authService = NewAuthService(users, env.sessions, repo.NewTokenDenylistRepository(db), env.twoFactor, env.throttle, keys, 0, 0)
+	env.passwordReset = NewPasswordReset(repo.NewPasswordResetRepository(db), users, env.sessions, env.mailer, policy, env.throttle,
+		PasswordResetConfig{TokenTTL: time.Hour, URL: testResetURL})
+	env.passwordReset.now = clock
+
+	env.emailVerification = NewEmailVerification(repo.NewEmailVerificationRepository(db), users, env.mailer,
+		EmailVerificationConfig{TokenTTL: 24 * time.Hour})
+	env.emailVerification.now = clock
+
+	env.userService = NewUserService(users, env.twoFactor, env.throttle, policy, env.passwordReset, env.emailVerification, nil)
+
+	return env
+}
+
+// createUser registers a user with testPassword and returns its ID
+func (env *testEnv) createUser(t *testing.T, username string) string {
+	t.Helper()
+
+	userID, err := env.userService.CreateUser(context.Background(), username, testPassword, "")
+	if err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+
+	return userID
+}
+
+// createUserWithEmail registers a user and confirms the email with the mailed token
+func (env *testEnv) createUserWithEmail(t *testing.T, username, email string) string {
+	t.Helper()
+
+	userID, err := env.userService.CreateUser(context.Background(), username, testPassword, email)
+	if err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+
+	if err := env.userService.ConfirmEmail(context.Background(), mailedToken(t, env.mailer.next(t))); err != nil {
+		t.Fatalf("confirm email of %s: %v", username, err)
+	}
+
+	return userID
+}
+
+// fakeMailer keeps the sent messages. Emails are sent in the background, so tests wait for them with next
+type fakeMailer struct {
+	sent chan mailer.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+// next waits for the next sent message
+func (m *fakeMailer) next(t *testing.T) mailer.Message {
+	t.Helper()
+
+	select {
+	case msg := <-m.sent:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no email was sent")
+		return mailer.Message{}
+	}
+}
+
+// expectNone checks that no message is sent for a while
+func (m *fakeMailer) expectNone(t *testing.T) {
+	t.Helper()
+
+	select {
+	case msg := <-m.sent:
+		t.Fatalf("unexpected email %q to %s", msg.Subject, msg.To)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+var mailedTokenPattern = regexp.MustCompile(`token(?:=|:\s+)([A-Za-z0-9_-]+)`)
+
+// mailedToken extracts the token from a link or the bare token in an email
+func mailedToken(t *testing.T, msg mailer.Message) string {
+	t.Helper()
+
+	match := mailedTokenPattern.FindStringSubmatch(msg.Body)
+	if match == nil {
+		t.Fatalf("no token in email %q:\n%s", msg.Subject, msg.Body)
+	}
+
+	return match[1]
+}
@@ -0,0 +1,507 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"auth.service/internal/repository"
+	"auth.service/internal/service"
+)
+
+const (
+	totpSecretSize     = 20 // 160 bits, as recommended by RFC 4226
+	totpDigits         = 6
+	totpPeriod         = 30 * time.Second
+	totpSkew           = 1 // Accepted time steps before and after the current one
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	challengeTokenSize = 32
+	challengeTTL       = 5 * time.Minute
+	maxChallengeTries  = 5
+)
+
+// recoveryCodeAlphabet has no characters that are easy to confuse when typed from paper
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+var (
+	ErrTwoFactorUnavailable    = errors.New("Two-factor authentication is not configured")
+	ErrTwoFactorAlreadyEnabled = errors.New("Two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("Two-factor authentication is not enrolled")
+	ErrInvalidSecondFactor     = errors.New("Invalid second factor code")
+	ErrInvalidChallenge        = errors.New("Invalid or expired login challenge")
+)
+
+// TwoFactorConfig configures TOTP two-factor authentication
+type TwoFactorConfig struct {
+	Issuer        string // Shown by authenticator apps next to the account name
+	EncryptionKey []byte // AES-256 key that encrypts TOTP secrets at rest, 2FA is unavailable without it
+}
+
+// TwoFactorConfigFromEnv reads the 2FA configuration from the environment.
+// TOTP_ENCRYPTION_KEY is a base64 encoded 32-byte key
+func TwoFactorConfigFromEnv() (TwoFactorConfig, error) {
+	config := TwoFactorConfig{
+		Issuer: getEnv("TOTP_ISSUER", "Chatik"),
+	}
+
+	if encoded := getEnv("TOTP_ENCRYPTION_KEY", ""); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return TwoFactorConfig{}, errors.New("TOTP_ENCRYPTION_KEY must be a base64 encoded 32-byte key")
+		}
+		config.EncryptionKey = key
+	}
+
+	return config, nil
+}
+
+// TwoFactor manages TOTP enrollment, recovery codes and login challenges.
+// The clock is a field so that code verification can be checked at a fixed time
+type TwoFactor struct {
+	repo   repository.TwoFactorRepository
+	issuer string
+	aead   cipher.AEAD // nil when no encryption key is configured
+	now    func() time.Time
+}
+
+// NewTwoFactor creates the 2FA manager. Without an encryption key enrollment is refused,
+// but logins of users without 2FA keep working
+func NewTwoFactor(repo repository.TwoFactorRepository, config TwoFactorConfig) (*TwoFactor, error) {
+	t := &TwoFactor{
+		repo:   repo,
+		issuer: config.Issuer,
+		now:    time.Now,
+	}
+
+	if len(config.EncryptionKey) > 0 {
+		block, err := aes.NewCipher(config.EncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("TwoFactor.New: %w", err)
+		}
+
+		t.aead, err = cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("TwoFactor.New: %w", err)
+		}
+	}
+
+	return t, nil
+}
+
+// Enroll generates a new TOTP secret for the user, replacing an unconfirmed one.
+// 2FA is enabled only after Confirm
+func (t *TwoFactor) Enroll(ctx context.Context, userID, username string) (*service.TOTPEnrollment, error) {
+	op := "TwoFactor.Enroll"
+
+	if t.aead == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+
+	existing, err := t.repo.TOTPByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrTOTPNotFound) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if existing != nil && existing.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	encrypted, err := t.encrypt(userID, secret)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = t.repo.SaveTOTP(ctx, &repository.TOTP{
+		UserID:          userID,
+		SecretEncrypted: encrypted,
+		CreatedAt:       t.now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret)
+
+	return &service.TOTPEnrollment{
+		Secret:          encoded,
+		ProvisioningURI: t.provisioningURI(username, encoded),
+	}, nil
+}
+
+// Confirm enables 2FA after the user proves the authenticator works, and returns
+// one-time recovery codes. The codes are shown only once, only their hashes are stored
+func (t *TwoFactor) Confirm(ctx context.Context, userID, code string) ([]string, error) {
+	op := "TwoFactor.Confirm"
+
+	totp, err := t.repo.TOTPByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTOTPNotFound) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if totp.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if err := t.verifyTOTP(ctx, totp, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := t.now()
+	if err := t.repo.ReplaceRecoveryCodes(ctx, userID, hashes, now); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := t.repo.EnableTOTP(ctx, userID, now); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return codes, nil
+}
+
+// Disable turns 2FA off. It requires a current code or a recovery code,
+// so a stolen access token alone is not enough
+func (t *TwoFactor) Disable(ctx context.Context, userID, code string) error {
+	op := "TwoFactor.Disable"
+
+	enabled, err := t.Enabled(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !enabled {
+		return ErrTwoFactorNotEnrolled
+	}
+
+	if err := t.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	if err := t.repo.DeleteTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Enabled reports whether the user has confirmed 2FA
+func (t *TwoFactor) Enabled(ctx context.Context, userID string) (bool, error) {
+	totp, err := t.repo.TOTPByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTOTPNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return totp.Enabled, nil
+}
+
+// Verify checks a TOTP code or, failing that, a recovery code of a user with 2FA enabled.
+// Accepted codes can not be used again
+func (t *TwoFactor) Verify(ctx context.Context, userID, code string) error {
+	op := "TwoFactor.Verify"
+
+	totp, err := t.repo.TOTPByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTOTPNotFound) {
+			return ErrTwoFactorNotEnrolled
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !totp.Enabled {
+		return ErrTwoFactorNotEnrolled
+	}
+
+	if looksLikeTOTPCode(code) {
+		return t.verifyTOTP(ctx, totp, code)
+	}
+
+	used, err := t.repo.UseRecoveryCode(ctx, userID, hashSecret(normalizeRecoveryCode(code)), t.now())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !used {
+		return ErrInvalidSecondFactor
+	}
+
+	return nil
+}
+
+// StartChallenge creates a short-lived challenge for a login that passed the password check.
+// Only the hash of the returned token is stored
+func (t *TwoFactor) StartChallenge(ctx context.Context, userID, deviceName string) (string, time.Time, error) {
+	op := "TwoFactor.StartChallenge"
+
+	buf := make([]byte, challengeTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	now := t.now()
+	challenge := &repository.LoginChallenge{
+		UserID:     userID,
+		TokenHash:  hashSecret(token),
+		DeviceName: deviceName,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(challengeTTL),
+	}
+
+	if err := t.repo.CreateChallenge(ctx, challenge); err != nil {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := t.repo.DeleteExpiredChallenges(ctx, now); err != nil {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, challenge.ExpiresAt, nil
+}
+
+// Challenge returns the pending challenge of the token without consuming it
+func (t *TwoFactor) Challenge(ctx context.Context, token string) (*repository.LoginChallenge, error) {
+	challenge, err := t.repo.ChallengeByTokenHash(ctx, hashSecret(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrChallengeNotFound) {
+			return nil, ErrInvalidChallenge
+		}
+		return nil, fmt.Errorf("TwoFactor.Challenge: %w", err)
+	}
+
+	if !t.now().Before(challenge.ExpiresAt) {
+		return nil, ErrInvalidChallenge
+	}
+
+	return challenge, nil
+}
+
+// CompleteChallenge verifies the second factor for a challenge and consumes it.
+// After maxChallengeTries wrong codes the challenge is dropped and the login has to start over
+func (t *TwoFactor) CompleteChallenge(ctx context.Context, token, code string) (*repository.LoginChallenge, error) {
+	op := "TwoFactor.CompleteChallenge"
+
+	challenge, err := t.repo.ChallengeByTokenHash(ctx, hashSecret(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrChallengeNotFound) {
+			return nil, ErrInvalidChallenge
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !t.now().Before(challenge.ExpiresAt) {
+		_ = t.repo.DeleteChallenge(ctx, challenge.ID)
+		return nil, ErrInvalidChallenge
+	}
+
+	if err := t.Verify(ctx, challenge.UserID, code); err != nil {
+		if !errors.Is(err, ErrInvalidSecondFactor) {
+			return nil, err
+		}
+
+		attempts, attemptErr := t.repo.AddChallengeAttempt(ctx, challenge.ID)
+		if attemptErr != nil && !errors.Is(attemptErr, repository.ErrChallengeNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, attemptErr)
+		}
+		if attempts >= maxChallengeTries {
+			_ = t.repo.DeleteChallenge(ctx, challenge.ID)
+		}
+
+		return nil, err
+	}
+
+	if err := t.repo.DeleteChallenge(ctx, challenge.ID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenge, nil
+}
+
+// verifyTOTP checks the code against the current time step and its neighbours
+// and records the matching step, so the same code is rejected the second time
+func (t *TwoFactor) verifyTOTP(ctx context.Context, totp *repository.TOTP, code string) error {
+	op := "TwoFactor.verifyTOTP"
+
+	secret, err := t.decrypt(totp.UserID, totp.SecretEncrypted)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	step, ok := matchTOTP(secret, code, t.now())
+	if !ok {
+		return ErrInvalidSecondFactor
+	}
+
+	fresh, err := t.repo.UseTOTPStep(ctx, totp.UserID, step)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !fresh {
+		return ErrInvalidSecondFactor
+	}
+
+	return nil
+}
+
+func (t *TwoFactor) provisioningURI(username, secret string) string {
+	label := url.PathEscape(t.issuer) + ":" + url.PathEscape(username)
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", t.issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// encrypt seals the secret with AES-GCM. The user ID is authenticated data,
+// so a secret copied to another user's row does not decrypt
+func (t *TwoFactor) encrypt(userID string, secret []byte) (string, error) {
+	if t.aead == nil {
+		return "", ErrTwoFactorUnavailable
+	}
+
+	nonce := make([]byte, t.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := t.aead.Seal(nonce, nonce, secret, []byte(userID))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (t *TwoFactor) decrypt(userID, encrypted string) ([]byte, error) {
+	if t.aead == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < t.aead.NonceSize() {
+		return nil, errors.New("encrypted secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:t.aead.NonceSize()], sealed[t.aead.NonceSize():]
+	return t.aead.Open(nil, nonce, ciphertext, []byte(userID))
+}
+
+// matchTOTP returns the time step whose code matches, looking totpSkew steps around now
+func matchTOTP(secret []byte, code string, now time.Time) (int64, bool) {
+	current := now.Unix() / int64(totpPeriod.Seconds())
+
+	for offset := int64(-totpSkew); offset <= totpSkew; offset++ {
+		step := current + offset
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpCode computes the HOTP value (RFC 4226) for a time step, as TOTP (RFC 6238) does
+func totpCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+func looksLikeTOTPCode(code string) bool {
+	if len(code) != totpDigits {
+		return false
+	}
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// generateRecoveryCodes returns codes formatted as xxxxx-xxxxx and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := randomString(recoveryCodeAlphabet, recoveryCodeLength)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes = append(codes, raw[:recoveryCodeLength/2]+"-"+raw[recoveryCodeLength/2:])
+		hashes = append(hashes, hashSecret(raw))
+	}
+
+	return codes, hashes, nil
+}
+
+// randomString picks characters uniformly, rejecting bytes that would bias the choice
+func randomString(alphabet string, length int) (string, error) {
+	limit := 256 - 256%len(alphabet)
+	result := make([]byte, 0, length)
+	buf := make([]byte, 1)
+
+	for len(result) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		if int(buf[0]) >= limit {
+			continue
+		}
+		result = append(result, alphabet[int(buf[0])%len(alphabet)])
+	}
+
+	return string(result), nil
+}
+
+// normalizeRecoveryCode makes recovery codes case and separator insensitive
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// hashSecret hashes a high-entropy secret for storage and lookup
+func hashSecret(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
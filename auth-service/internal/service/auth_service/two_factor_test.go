@@ -0,0 +1,300 @@
+package auth_service
+
+import (
+	"context"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"testing"
+
+	"auth.service/internal/service"
+)
+
+// RFC 6238 appendix B, SHA-1 with the 20-byte ASCII secret; the RFC lists 8 digits, these are the last 6
+func TestTOTPCodeVectors(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		if code := totpCode(secret, tt.unix/30); code != tt.code {
+			t.Errorf("totpCode at %d = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+
+	secret, recoveryCodes := env.enableTwoFactor(t, userID, "alice")
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("got %d recovery codes, want %d", len(recoveryCodes), recoveryCodeCount)
+	}
+
+	result, err := env.authService.Login(ctx, "alice", testPassword, service.ClientInfo{DeviceName: "laptop"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if result.Tokens != nil || result.Challenge == nil {
+		t.Fatal("Login with 2FA enabled must return a challenge and no tokens")
+	}
+
+	if _, err := env.authService.VerifySecondFactor(ctx, result.Challenge.Token, wrongCode(secret, env.step()), service.ClientInfo{}); !errors.Is(err, ErrInvalidSecondFactor) {
+		t.Fatalf("wrong code: got %v, want ErrInvalidSecondFactor", err)
+	}
+
+	env.now = env.now.Add(totpPeriod)
+	tokens, err := env.authService.VerifySecondFactor(ctx, result.Challenge.Token, totpCode(secret, env.step()), service.ClientInfo{})
+	if err != nil {
+		t.Fatalf("VerifySecondFactor: %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" || tokens.UserID != userID {
+		t.Fatalf("unexpected tokens: %+v", tokens)
+	}
+
+	session, err := env.sessions.GetByRefreshToken(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.DeviceName != "laptop" {
+		t.Fatalf("the session must keep the device name given at login, got %q", session.DeviceName)
+	}
+
+	// The challenge is consumed by the successful verification
+	if _, err := env.authService.VerifySecondFactor(ctx, result.Challenge.Token, totpCode(secret, env.step()+1), service.ClientInfo{}); !errors.Is(err, ErrInvalidChallenge) {
+		t.Fatalf("reused challenge: got %v, want ErrInvalidChallenge", err)
+	}
+}
+
+func TestTwoFactorRejectsReusedTimeStep(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+	secret, _ := env.enableTwoFactor(t, userID, "alice")
+
+	// The code confirming the enrollment can not log in during the same step
+	if err := env.twoFactor.Verify(ctx, userID, totpCode(secret, env.step())); !errors.Is(err, ErrInvalidSecondFactor) {
+		t.Fatalf("code of the confirmed step: got %v, want ErrInvalidSecondFactor", err)
+	}
+
+	env.now = env.now.Add(totpPeriod)
+	code := totpCode(secret, env.step())
+	if err := env.twoFactor.Verify(ctx, userID, code); err != nil {
+		t.Fatalf("fresh code: %v", err)
+	}
+
+	// Still accepted by the skew window, but the step is used up
+	env.now = env.now.Add(totpPeriod)
+	if err := env.twoFactor.Verify(ctx, userID, code); !errors.Is(err, ErrInvalidSecondFactor) {
+		t.Fatalf("reused code: got %v, want ErrInvalidSecondFactor", err)
+	}
+
+	// Outside the skew window even an unused step is refused
+	if _, ok := matchTOTP(secret, totpCode(secret, env.step()-totpSkew-1), env.now); ok {
+		t.Skip("the old code happens to equal a code of the window")
+	}
+	if err := env.twoFactor.Verify(ctx, userID, totpCode(secret, env.step()-totpSkew-1)); !errors.Is(err, ErrInvalidSecondFactor) {
+		t.Fatalf("code outside the window: got %v, want ErrInvalidSecondFactor", err)
+	}
+}
+
+func TestRecoveryCodesAreSingleUse(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+	_, recoveryCodes := env.enableTwoFactor(t, userID, "alice")
+
+	// Codes are accepted in any case and without the separator
+	typed := strings.ToUpper(strings.ReplaceAll(recoveryCodes[0], "-", ""))
+	if err := env.twoFactor.Verify(ctx, userID, typed); err != nil {
+		t.Fatalf("recovery code: %v", err)
+	}
+	if err := env.twoFactor.Verify(ctx, userID, recoveryCodes[0]); !errors.Is(err, ErrInvalidSecondFactor) {
+		t.Fatalf("used recovery code: got %v, want ErrInvalidSecondFactor", err)
+	}
+
+	// A recovery code completes a login challenge as well
+	result, err := env.authService.Login(ctx, "alice", testPassword, service.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.authService.VerifySecondFactor(ctx, result.Challenge.Token, recoveryCodes[1], service.ClientInfo{}); err != nil {
+		t.Fatalf("login with a recovery code: %v", err)
+	}
+}
+
+func TestTwoFactorChallengeExpires(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+	secret, _ := env.enableTwoFactor(t, userID, "alice")
+
+	result, err := env.authService.Login(ctx, "alice", testPassword, service.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Challenge.ExpiresAt.Equal(env.now.Add(challengeTTL)) {
+		t.Fatalf("challenge expires at %v, want %v", result.Challenge.ExpiresAt, env.now.Add(challengeTTL))
+	}
+
+	env.now = env.now.Add(challengeTTL)
+	if _, err := env.authService.VerifySecondFactor(ctx, result.Challenge.Token, totpCode(secret, env.step()), service.ClientInfo{}); !errors.Is(err, ErrInvalidChallenge) {
+		t.Fatalf("expired challenge: got %v, want ErrInvalidChallenge", err)
+	}
+}
+
+func TestTwoFactorChallengeDroppedAfterMaxTries(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+	secret, _ := env.enableTwoFactor(t, userID, "alice")
+
+	result, err := env.authService.Login(ctx, "alice", testPassword, service.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env.now = env.now.Add(totpPeriod)
+	wrong := wrongCode(secret, env.step())
+	for i := 0; i < maxChallengeTries; i++ {
+		if _, err := env.twoFactor.CompleteChallenge(ctx, result.Challenge.Token, wrong); !errors.Is(err, ErrInvalidSecondFactor) {
+			t.Fatalf("try %d: got %v, want ErrInvalidSecondFactor", i+1, err)
+		}
+	}
+
+	if _, err := env.twoFactor.CompleteChallenge(ctx, result.Challenge.Token, totpCode(secret, env.step())); !errors.Is(err, ErrInvalidChallenge) {
+		t.Fatalf("after %d wrong codes: got %v, want ErrInvalidChallenge", maxChallengeTries, err)
+	}
+}
+
+func TestDisableTOTPWrongCodesLockAccount(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+	secret, _ := env.enableTwoFactor(t, userID, "alice")
+
+	env.now = env.now.Add(totpPeriod)
+	wrong := wrongCode(secret, env.step())
+	for i := 0; i < env.throttle.config.LockoutThreshold; i++ {
+		if err := env.userService.DisableTOTP(ctx, userID, wrong, service.ClientInfo{RemoteIP: "192.0.2.1"}); !errors.Is(err, ErrInvalidSecondFactor) {
+			t.Fatalf("try %d: got %v, want ErrInvalidSecondFactor", i+1, err)
+		}
+	}
+
+	// Even the right code is refused now, and so is a password login
+	if err := env.userService.DisableTOTP(ctx, userID, totpCode(secret, env.step()), service.ClientInfo{}); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("after the lockout threshold: got %v, want ErrAccountLocked", err)
+	}
+	if _, err := env.authService.Login(ctx, "alice", testPassword, service.ClientInfo{}); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("login after the lockout: got %v, want ErrAccountLocked", err)
+	}
+
+	enabled, err := env.twoFactor.Enabled(ctx, userID)
+	if err != nil || !enabled {
+		t.Fatalf("2FA must stay enabled, got %v, %v", enabled, err)
+	}
+}
+
+func TestConfirmTOTPRightCodeResetsFailures(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	userID := env.createUser(t, "alice")
+
+	enrollment, err := env.userService.EnrollTOTP(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := decodeSecret(t, enrollment.Secret)
+
+	wrong := wrongCode(secret, env.step())
+	for i := 0; i < env.throttle.config.LockoutThreshold-1; i++ {
+		if _, err := env.userService.ConfirmTOTP(ctx, userID, wrong, service.ClientInfo{}); !errors.Is(err, ErrInvalidSecondFactor) {
+			t.Fatalf("try %d: got %v, want ErrInvalidSecondFactor", i+1, err)
+		}
+	}
+
+	if _, err := env.userService.ConfirmTOTP(ctx, userID, totpCode(secret, env.step()), service.ClientInfo{}); err != nil {
+		t.Fatalf("ConfirmTOTP: %v", err)
+	}
+
+	// The failures before the right code are forgotten
+	if err := env.throttle.Failure(ctx, "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.throttle.Check(ctx, "alice", ""); err != nil {
+		t.Fatalf("one failure after a success: %v", err)
+	}
+}
+
+// enableTwoFactor enrolls and confirms 2FA at the current time and returns the raw secret and recovery codes
+func (env *testEnv) enableTwoFactor(t *testing.T, userID, username string) ([]byte, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	enrollment, err := env.userService.EnrollTOTP(ctx, userID)
+	if err != nil {
+		t.Fatalf("EnrollTOTP: %v", err)
+	}
+	if !strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/Test:"+username+"?") {
+		t.Fatalf("unexpected provisioning URI %s", enrollment.ProvisioningURI)
+	}
+
+	secret := decodeSecret(t, enrollment.Secret)
+
+	// Not enabled until confirmed
+	result, err := env.authService.Login(ctx, username, testPassword, service.ClientInfo{})
+	if err != nil || result.Tokens == nil {
+		t.Fatalf("login before confirmation must not ask for a code, got %+v, %v", result, err)
+	}
+
+	recoveryCodes, err := env.userService.ConfirmTOTP(ctx, userID, totpCode(secret, env.step()), service.ClientInfo{})
+	if err != nil {
+		t.Fatalf("ConfirmTOTP: %v", err)
+	}
+
+	return secret, recoveryCodes
+}
+
+// step is the TOTP time step of the test clock
+func (env *testEnv) step() int64 {
+	return env.now.Unix() / int64(totpPeriod.Seconds())
+}
+
+func decodeSecret(t *testing.T, encoded string) []byte {
+	t.Helper()
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+
+	return secret
+}
+
+// wrongCode returns a code that matches none of the steps accepted around step
+func wrongCode(secret []byte, step int64) string {
+	accepted := map[string]bool{}
+	for offset := int64(-totpSkew); offset <= totpSkew; offset++ {
+		accepted[totpCode(secret, step+offset)] = true
+	}
+
+	for _, code := range []string{"000000", "111111", "222222", "333333"} {
+		if !accepted[code] {
+			return code
+		}
+	}
+
+	return "444444"
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"sync"
 
 	"auth.service/internal/repository"
 	"auth.service/internal/service"
@@ -12,23 +15,52 @@ import (
 
 var (
 	ErrUserAlreadyExists  = errors.New("User already exists")
+	ErrEmailAlreadyUsed   = errors.New("Email is already used")
 	ErrUserNotFound       = errors.New("User not found")
 	ErrInvalidCredentials = errors.New("Invalid credentials")
+	ErrNotAdmin           = errors.New("Not an admin")
 )
 
 type UserServiceImpl struct {
-	userRepo repository.UserRepository
+	userRepo          repository.UserRepository
+	twoFactor         *TwoFactor
+	throttle          *LoginThrottle
+	policy            *CredentialPolicy
+	passwordReset     *PasswordReset
+	emailVerification *EmailVerification
+	adminIDs          []string
 }
 
-func NewUserService(userRepo repository.UserRepository) *UserServiceImpl {
+// NewUserService creates the user service. adminIDs are the users allowed to unlock accounts
+func NewUserService(userRepo repository.UserRepository, twoFactor *TwoFactor, throttle *LoginThrottle, policy *CredentialPolicy, passwordReset *PasswordReset, emailVerification *EmailVerification, adminIDs []string) *UserServiceImpl {
 	return &UserServiceImpl{
-		userRepo: userRepo,
+		userRepo:          userRepo,
+		twoFactor:         twoFactor,
+		throttle:          throttle,
+		policy:            policy,
+		passwordReset:     passwordReset,
+		emailVerification: emailVerification,
+		adminIDs:          adminIDs,
 	}
 }
 
-func (s *UserServiceImpl) CreateUser(ctx context.Context, username, password string) (string, error) {
+// CreateUser registers a user. A username, password or email breaking the credential policy
+// is refused with a *ValidationError listing every violation. The email is optional,
+// without it the password can not be reset. It is set once confirmed with the mailed token
+func (s *UserServiceImpl) CreateUser(ctx context.Context, username, password, email string) (string, error) {
 	op := "UserService.CreateUser"
 
+	email = normalizeEmail(email)
+
+	violations := s.policy.ValidateUsername(username)
+	violations = append(violations, s.policy.ValidatePassword(password, username)...)
+	if email != "" {
+		violations = append(violations, s.policy.ValidateEmail(email)...)
+	}
+	if len(violations) > 0 {
+		return "", &ValidationError{Violations: violations}
+	}
+
 	existingUser, err := s.userRepo.UserByUsername(ctx, username)
 	if err != nil {
 		switch {
@@ -57,6 +89,13 @@ func (s *UserServiceImpl) CreateUser(ctx context.Context, username, password str
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
+	// The user exists already, a failed confirmation email is repeated by setting the email again
+	if email != "" {
+		if err := s.emailVerification.Start(ctx, user, email); err != nil {
+			log.Printf("failed to start email verification for user %s: %v", user.ID, err)
+		}
+	}
+
 	return user.ID, nil
 }
 
@@ -75,10 +114,51 @@ func (s *UserServiceImpl) UserByID(ctx context.Context, userID string) (*service
 	return &service.User{
 		ID:       user.ID,
 		Username: user.Username,
+		IsBot:    user.IsBot,
+	}, nil
+}
+
+func (s *UserServiceImpl) UsersByIDs(ctx context.Context, userIDs []string) ([]*service.User, error) {
+	op := "UserService.UsersByIDs"
+
+	users, err := s.userRepo.UsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := make([]*service.User, 0, len(users))
+	for _, user := range users {
+		result = append(result, &service.User{
+			ID:       user.ID,
+			Username: user.Username,
+			IsBot:    user.IsBot,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *UserServiceImpl) UserByUsername(ctx context.Context, username string) (*service.User, error) {
+	op := "UserService.UserByUsername"
+
+	user, err := s.userRepo.UserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, service.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &service.User{
+		ID:       user.ID,
+		Username: user.Username,
+		IsBot:    user.IsBot,
 	}, nil
 }
 
-func (s *UserServiceImpl) UpdateUser(ctx context.Context, user_id, username, password string) error {
+// UpdateUser changes the non-empty ones of the username and the password. A new email
+// is mailed a confirmation token and replaces the current one only once confirmed
+func (s *UserServiceImpl) UpdateUser(ctx context.Context, user_id, username, password, email string) error {
 	op := "UserService.UpdateUser"
 
 	user, err := s.userRepo.UserByID(ctx, user_id)
@@ -90,6 +170,26 @@ func (s *UserServiceImpl) UpdateUser(ctx context.Context, user_id, username, pas
 		return ErrUserNotFound
 	}
 
+	// Existing usernames are kept as they are even if they predate the current rules
+	var violations []Violation
+	if username != "" && user.Username != username {
+		violations = append(violations, s.policy.ValidateUsername(username)...)
+	}
+	if password != "" {
+		passwordUsername := user.Username
+		if username != "" {
+			passwordUsername = username
+		}
+		violations = append(violations, s.policy.ValidatePassword(password, passwordUsername)...)
+	}
+	email = normalizeEmail(email)
+	if email != "" {
+		violations = append(violations, s.policy.ValidateEmail(email)...)
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
 	isChanged := false
 
 	if username != "" && user.Username != username {
@@ -115,6 +215,15 @@ func (s *UserServiceImpl) UpdateUser(ctx context.Context, user_id, username, pas
 		isChanged = true
 	}
 
+	if email != "" && (user.Email == nil || *user.Email != email) {
+		if err := s.emailVerification.Start(ctx, user, email); err != nil {
+			if errors.Is(err, ErrTooManyEmailVerifications) {
+				return err
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
 	if isChanged {
 		if err := s.userRepo.UpdateUser(ctx, user); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
@@ -134,11 +243,142 @@ func (s *UserServiceImpl) DeleteUser(ctx context.Context, userID string) error {
 	return nil
 }
 
+// EnrollTOTP starts two-factor enrollment. The secret is added to an authenticator app
+// and confirmed with ConfirmTOTP, until then logins do not ask for a code
+func (s *UserServiceImpl) EnrollTOTP(ctx context.Context, userID string) (*service.TOTPEnrollment, error) {
+	op := "UserService.EnrollTOTP"
+
+	user, err := s.userRepo.UserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	// Bots authenticate with API keys and never log in with a password
+	if user.IsBot {
+		return nil, ErrTwoFactorUnavailable
+	}
+
+	enrollment, err := s.twoFactor.Enroll(ctx, user.ID, user.Username)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorUnavailable) || errors.Is(err, ErrTwoFactorAlreadyEnabled) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return enrollment, nil
+}
+
+// ConfirmTOTP enables two-factor authentication and returns one-time recovery codes.
+// Wrong codes count as failed logins of the user
+func (s *UserServiceImpl) ConfirmTOTP(ctx context.Context, userID, code string, client service.ClientInfo) ([]string, error) {
+	var recoveryCodes []string
+
+	err := s.throttledSecondFactor(ctx, userID, client, func() error {
+		var err error
+		recoveryCodes, err = s.twoFactor.Confirm(ctx, userID, code)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableTOTP turns two-factor authentication off, it takes a current code or a recovery code.
+// Wrong codes count as failed logins, so a stolen access token does not allow guessing the code
+func (s *UserServiceImpl) DisableTOTP(ctx context.Context, userID, code string, client service.ClientInfo) error {
+	return s.throttledSecondFactor(ctx, userID, client, func() error {
+		return s.twoFactor.Disable(ctx, userID, code)
+	})
+}
+
+// throttledSecondFactor runs a check of a second factor code under the login throttle of the user:
+// it is refused while logins are delayed or the account is locked, and a wrong code is a failed login
+func (s *UserServiceImpl) throttledSecondFactor(ctx context.Context, userID string, client service.ClientInfo, check func() error) error {
+	op := "UserService.throttledSecondFactor"
+
+	user, err := s.userRepo.UserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.throttle.Check(ctx, user.Username, client.RemoteIP); err != nil {
+		if errors.Is(err, ErrAccountLocked) || errors.Is(err, ErrTooManyAttempts) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := check(); err != nil {
+		if errors.Is(err, ErrInvalidSecondFactor) {
+			if err := s.throttle.Failure(ctx, user.Username, client.RemoteIP); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		return err
+	}
+
+	if err := s.throttle.Success(ctx, user.Username); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UnlockUser lifts the lockout and the login backoff of the username. Only admins may do it;
+// the username does not have to exist, locks of unknown usernames are lifted the same way
+func (s *UserServiceImpl) UnlockUser(ctx context.Context, adminID, username string) error {
+	if !slices.Contains(s.adminIDs, adminID) {
+		return ErrNotAdmin
+	}
+
+	return s.throttle.Unlock(ctx, username)
+}
+
+// RequestPasswordReset mails a reset token to the owner of the email. It succeeds for unknown emails too,
+// so it can not be used to find out which emails are registered
+func (s *UserServiceImpl) RequestPasswordReset(ctx context.Context, email string) error {
+	return s.passwordReset.Request(ctx, email)
+}
+
+// ResetPassword sets a new password with a mailed reset token and ends all sessions of the user
+func (s *UserServiceImpl) ResetPassword(ctx context.Context, token, newPassword string) error {
+	return s.passwordReset.Reset(ctx, token, newPassword)
+}
+
+// ConfirmEmail sets the email a confirmation token was mailed to
+func (s *UserServiceImpl) ConfirmEmail(ctx context.Context, token string) error {
+	return s.emailVerification.Confirm(ctx, token)
+}
+
 func hashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(bytes), err
 }
 
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// dummyPasswordHash is compared against when there is no real hash to check,
+// it has the same cost as real hashes so the comparison takes as long
+func dummyPasswordHash() string {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = hashPassword("dummy password for unknown users")
+	})
+
+	return dummyHash
+}
+
 func checkPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"time"
 )
@@ -14,33 +15,129 @@ var (
 type User struct {
 	ID       string
 	Username string
+	IsBot    bool
+}
+
+type Bot struct {
+	ID       string
+	Username string
+	OwnerID  string
+	APIKey   *APIKey
+}
+
+type APIKey struct {
+	ID  string
+	Key string
 }
 
 type TokenPair struct {
-	UserID       string
-	AccessToken  string
-	RefreshToken string
+	UserID                string
+	AccessToken           string
+	RefreshToken          string
+	AccessTokenExpiresAt  time.Time
+	RefreshTokenExpiresAt time.Time
 }
 
 type TokenClaims struct {
+	ID        string // jti, empty for tokens issued before revocation support
 	UserID    string
 	Username  string
+	SessionID string // Session the token was issued to, empty for older tokens
+	ExpiresAt time.Time
+}
+
+// LoginResult is the outcome of a password check: tokens, or a challenge
+// when the user has two-factor authentication enabled
+type LoginResult struct {
+	Tokens    *TokenPair
+	Challenge *LoginChallenge
+}
+
+// LoginChallenge is completed with VerifySecondFactor before it expires
+type LoginChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TOTPEnrollment is a new TOTP secret waiting for confirmation with a code
+type TOTPEnrollment struct {
+	Secret          string // base32, for manual entry
+	ProvisioningURI string // otpauth:// URI, usually shown as a QR code
+}
+
+// ClientInfo describes the device a login or refresh request comes from
+type ClientInfo struct {
+	DeviceName string
+	UserAgent  string
+	IPAddress  string // RemoteIP without the port, stored with the session
+	// RemoteIP is the client address as seen by this service or the gateway in front of it.
+	// The client can not choose it, so it is used for login throttling
+	RemoteIP string
+}
+
+// Session is a login on a device, it survives refresh token rotation
+type Session struct {
+	ID         string
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+}
+
+// RevokedToken is an access token revoked before its expiry
+type RevokedToken struct {
+	JTI       string
 	ExpiresAt time.Time
 }
 
+// PublicKey is a key that verifies access tokens signed with the key ID
+type PublicKey struct {
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
 type UserService interface {
-	CreateUser(ctx context.Context, username, password string) (string, error)
+	CreateUser(ctx context.Context, username, password, email string) (string, error)
 	UserByID(ctx context.Context, userID string) (*User, error)
-	UpdateUser(ctx context.Context, user_id, username, password string) error
+	// UsersByIDs returns the users with the given IDs, unknown IDs are skipped
+	UsersByIDs(ctx context.Context, userIDs []string) ([]*User, error)
+	UserByUsername(ctx context.Context, username string) (*User, error)
+	UpdateUser(ctx context.Context, user_id, username, password, email string) error
 	DeleteUser(ctx context.Context, userID string) error
+	EnrollTOTP(ctx context.Context, userID string) (*TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID, code string, client ClientInfo) ([]string, error)
+	DisableTOTP(ctx context.Context, userID, code string, client ClientInfo) error
+	UnlockUser(ctx context.Context, adminID, username string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	ConfirmEmail(ctx context.Context, token string) error
 }
 
 type AuthService interface {
-	Login(ctx context.Context, username, password string) (*TokenPair, error)
-	RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Login(ctx context.Context, username, password string, client ClientInfo) (*LoginResult, error)
+	VerifySecondFactor(ctx context.Context, challengeToken, code string, client ClientInfo) (*TokenPair, error)
+	RefreshTokens(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error)
 	ValidateToken(ctx context.Context, accessToken string) (*TokenClaims, error)
+	PublicKeys(ctx context.Context) ([]PublicKey, error)
+	Logout(ctx context.Context, refreshToken, accessToken string) error
+	LogoutAll(ctx context.Context, userID, accessToken string) error
+	RevokedTokens(ctx context.Context, since time.Time) ([]RevokedToken, time.Time, error)
+	ListSessions(ctx context.Context, userID string) ([]Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID, accessToken string) error
 }
 
 type AccessService interface {
 	Check(ctx context.Context, accessToken string) (bool, string, error)
+	PublicKeys(ctx context.Context) ([]PublicKey, error)
+	RevokedTokens(ctx context.Context, since time.Time) ([]RevokedToken, time.Time, error)
+}
+
+type BotService interface {
+	CreateBot(ctx context.Context, ownerID, username string) (*Bot, error)
+	CreateAPIKey(ctx context.Context, ownerID, botID string) (*APIKey, error)
+	RevokeAPIKey(ctx context.Context, ownerID, keyID string) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*User, error)
 }
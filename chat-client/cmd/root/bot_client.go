@@ -0,0 +1,130 @@
+package root
+
+import (
+	"os"
+
+	"chat.client/internal/user_client"
+	"github.com/spf13/cobra"
+)
+
+var botCmd = &cobra.Command{
+	Use:   "bot",
+	Short: "manage bot accounts",
+}
+
+var botCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create a bot",
+	Long: `create a bot account owned by you.
+	The bot authenticates with an API key instead of login; pass it as --token to chat commands.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if username == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		bot, err := client.CreateBot(token, username)
+		if err != nil {
+			cmd.Printf("Failed to create bot: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Bot %s created with ID: %s\n", bot.GetUsername(), bot.GetUserId())
+		cmd.Printf("API key (shown only once): %s\n", bot.GetApiKey())
+		cmd.Printf("API key ID: %s\n", bot.GetApiKeyId())
+	},
+}
+
+var botKeyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "manage bot API keys",
+}
+
+var botKeyCreateCmd = &cobra.Command{
+	Use:   "create <bot-id>",
+	Short: "issue a new API key for a bot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		key, err := client.CreateBotAPIKey(token, args[0])
+		if err != nil {
+			cmd.Printf("Failed to create API key: %v\n", err)
+			return
+		}
+
+		cmd.Printf("API key (shown only once): %s\n", key.GetApiKey())
+		cmd.Printf("API key ID: %s\n", key.GetApiKeyId())
+	},
+}
+
+var botKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <key-id>",
+	Short: "revoke a bot API key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.RevokeBotAPIKey(token, args[0]); err != nil {
+			cmd.Printf("Failed to revoke API key: %v\n", err)
+			return
+		}
+
+		cmd.Println("API key revoked")
+	},
+}
+
+// newUserClient создает клиент сервиса аутентификации для команд, требующих токен.
+// Без --token используется сохраненная при входе сессия, истекший токен доступа обновляется
+func newUserClient(cmd *cobra.Command) (*user_client.UserClient, bool) {
+	authServiceAddr, ok := os.LookupEnv("CHAT_AUTH_SERVICE_ADDR")
+	if !ok {
+		cmd.Println("CHAT_AUTH_SERVICE_ADDR environment variable is not set")
+		return nil, false
+	}
+
+	client, err := user_client.NewUserClient(authServiceAddr)
+	if err != nil {
+		cmd.Printf("Failed to connect to auth service: %v\n", err)
+		return nil, false
+	}
+
+	if token == "" {
+		fresh, ok := savedSessionToken(cmd, client)
+		if !ok {
+			client.Close()
+			return nil, false
+		}
+		token = fresh
+	}
+
+	return client, true
+}
+
+func init() {
+	botCreateCmd.Flags().StringVarP(&username, "username", "u", "", "bot username")
+	botCreateCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	botKeyCreateCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+	botKeyRevokeCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	botKeyCmd.AddCommand(botKeyCreateCmd)
+	botKeyCmd.AddCommand(botKeyRevokeCmd)
+
+	botCmd.AddCommand(botCreateCmd)
+	botCmd.AddCommand(botKeyCmd)
+}
@@ -9,15 +9,19 @@ import (
 	"strings"
 	"syscall"
 
-	"chat.client/internal/chat_client"
 	pb "chat.service/api/proto"
 	"github.com/spf13/cobra"
 )
 
 var (
-	chatID   string
-	chatName string
-	token    string
+	chatID      string
+	chatName    string
+	token       string
+	publicChat  bool
+	channelChat bool
+	searchLimit int
+	markdown    bool
+	showIDs     bool
 )
 
 var connectCmd = &cobra.Command{
@@ -26,39 +30,27 @@ var connectCmd = &cobra.Command{
 	Long: `connect to a chat with the given chat ID.
 	It is written in Go and uses the Cobra library for command line parsing.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		var chatServiceAddr string
-
 		if chatID == "" && chatName == "" {
 			cmd.Help()
 			return
 		}
 
-		if token == "" {
-			cmd.Println("You must provide a token. Use login command to get a token.")
-			return
-		}
-
-		if addr, ok := os.LookupEnv("CHAT_SERVICE_ADDR"); !ok {
-			cmd.Println("CHAT_SERVICE_ADDR environment variable is not set")
-			return
-		} else {
-			chatServiceAddr = addr
-		}
-
-		client, err := chat_client.NewChatClient(chatServiceAddr, token)
-		if err != nil {
-			cmd.Printf("Failed to create chat client: %v\n", err)
+		// Сохраненная сессия обновляется по мере истечения токена доступа,
+		// поэтому долгое подключение не прерывается
+		client, ok := newChatClient(cmd)
+		if !ok {
 			return
 		}
 		defer client.Close()
 
 		// Если указано имя чата, но не указан ID, создаем новый чат
 		if chatID == "" && chatName != "" {
-			chatID, err = client.CreateChat(chatName)
+			createdID, err := client.CreateChat(chatName, pb.ChatVisibility_CHAT_VISIBILITY_PRIVATE, pb.ChatType_CHAT_TYPE_GROUP)
 			if err != nil {
 				cmd.Printf("Failed to create chat: %v\n", err)
 				return
 			}
+			chatID = createdID
 			cmd.Printf("Created new chat with ID: %s\n", chatID)
 		}
 
@@ -78,12 +70,26 @@ var connectCmd = &cobra.Command{
 		sigCh := make(chan os.Signal, 1)
 		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
+		// Последний опрос в чате, по которому голосует команда /vote
+		polls := &pollTracker{}
+
 		// Обработка входящих сообщений
 		client.ProcessChatMessages(
 			stream,
 			// Обработчик сообщений
 			func(message *pb.ChatMessage) {
-				fmt.Printf("%s: %s\n", message.GetUsername(), message.GetText())
+				polls.track(message)
+
+				if message.GetKind() == pb.MessageKind_MESSAGE_KIND_POLL && message.GetPoll() != nil {
+					if message.GetEvent() == pb.MessageEvent_MESSAGE_EVENT_UPDATED {
+						fmt.Printf("%s updated a poll:\n%s", message.GetUsername(), formatPoll(message.GetPoll()))
+					} else {
+						fmt.Printf("%s:\n%s", message.GetUsername(), formatPoll(message.GetPoll()))
+					}
+					return
+				}
+
+				fmt.Println(formatMessage(message))
 			},
 			// Обработчик ошибок
 			func(err error) {
@@ -107,10 +113,17 @@ var connectCmd = &cobra.Command{
 				// Удаляем символ новой строки в конце
 				input = strings.TrimSpace(input)
 
+				if input == "/vote" || strings.HasPrefix(input, "/vote ") {
+					handleVoteCommand(client, polls, input)
+					continue
+				}
+
 				if input != "" {
-					err = client.SendMessage(chatID, input)
+					res, err := client.SendMessage(chatID, input, messageFormat())
 					if err != nil {
 						fmt.Printf("Error sending message: %v\n", err)
+					} else if res.GetPendingReview() {
+						fmt.Println("Your message is held for review by chat admins")
 					}
 				}
 			}
@@ -128,47 +141,96 @@ var createChatCmd = &cobra.Command{
 	Long: `create a new chat with the given name.
 	It is written in Go and uses the Cobra library for command line parsing.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		var chatServiceAddr string
-
 		if chatName == "" {
 			cmd.Help()
 			return
 		}
 
-		if token == "" {
-			cmd.Println("You must provide a token. Use login command to get a token.")
+		client, ok := newChatClient(cmd)
+		if !ok {
 			return
 		}
+		defer client.Close()
 
-		if addr, ok := os.LookupEnv("CHAT_SERVICE_ADDR"); !ok {
-			cmd.Println("CHAT_SERVICE_ADDR environment variable is not set")
-			return
-		} else {
-			chatServiceAddr = addr
+		visibility := pb.ChatVisibility_CHAT_VISIBILITY_PRIVATE
+		if publicChat {
+			visibility = pb.ChatVisibility_CHAT_VISIBILITY_PUBLIC
+		}
+
+		chatType := pb.ChatType_CHAT_TYPE_GROUP
+		if channelChat {
+			chatType = pb.ChatType_CHAT_TYPE_CHANNEL
 		}
 
-		client, err := chat_client.NewChatClient(chatServiceAddr, token)
+		chatID, err := client.CreateChat(chatName, visibility, chatType)
 		if err != nil {
-			cmd.Printf("Failed to create chat client: %v\n", err)
+			cmd.Printf("Failed to create chat: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Chat created successfully with ID: %s\n", chatID)
+	},
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search [name prefix]",
+	Short: "search public chats",
+	Long: `search public chats whose name starts with the given prefix.
+	Without a prefix all public chats are listed.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var query string
+		if len(args) > 0 {
+			query = args[0]
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
 			return
 		}
 		defer client.Close()
 
-		chatID, err := client.CreateChat(chatName)
+		chats, err := client.SearchPublicChats(query, searchLimit)
 		if err != nil {
-			cmd.Printf("Failed to create chat: %v\n", err)
+			cmd.Printf("Failed to search chats: %v\n", err)
 			return
 		}
 
-		cmd.Printf("Chat created successfully with ID: %s\n", chatID)
+		if len(chats) == 0 {
+			cmd.Println("No public chats found")
+			return
+		}
+
+		for _, chat := range chats {
+			kind := "chat"
+			if chat.GetType() == pb.ChatType_CHAT_TYPE_CHANNEL {
+				kind = "channel"
+			}
+			cmd.Printf("%s\t%s\t(%s)\n", chat.GetChatId(), chat.GetName(), kind)
+		}
 	},
 }
 
+// messageFormat возвращает формат отправляемых сообщений в соответствии с флагом --markdown
+func messageFormat() pb.MessageFormat {
+	if markdown {
+		return pb.MessageFormat_MESSAGE_FORMAT_MARKDOWN
+	}
+	return pb.MessageFormat_MESSAGE_FORMAT_PLAIN
+}
+
 func init() {
 	connectCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
 	connectCmd.Flags().StringVarP(&chatName, "name", "n", "", "chat name")
 	connectCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+	connectCmd.Flags().BoolVarP(&markdown, "markdown", "m", false, "send messages as markdown: **bold**, `code`, [text](url)")
+	connectCmd.Flags().BoolVar(&showIDs, "show-ids", false, "show message IDs, e.g. to forward or delete messages")
 
 	createChatCmd.Flags().StringVarP(&chatName, "name", "n", "", "chat name")
 	createChatCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+	createChatCmd.Flags().BoolVar(&publicChat, "public", false, "make the chat public and discoverable")
+	createChatCmd.Flags().BoolVar(&channelChat, "channel", false, "create a channel where only admins can post")
+
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 20, "maximum number of chats to show")
+	searchCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
 }
@@ -0,0 +1,114 @@
+package root
+
+import (
+	"fmt"
+	"strings"
+
+	pb "chat.service/api/proto"
+	"github.com/spf13/cobra"
+)
+
+var commandsCmd = &cobra.Command{
+	Use:   "commands",
+	Short: "list slash commands available in a chat",
+	Long: `list slash commands available in a chat: server built-ins and commands registered by bots.
+	Type a command in a connect session to run it, start a message with // to send a literal slash.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		commands, err := client.ListCommands(chatID)
+		if err != nil {
+			cmd.Printf("Failed to list commands: %v\n", err)
+			return
+		}
+
+		for _, command := range commands {
+			owner := "built-in"
+			if command.GetBotId() != "" {
+				owner = "bot " + command.GetBotId()
+			}
+			cmd.Printf("%s\t%s\t(%s)\n", command.GetUsage(), command.GetDescription(), owner)
+		}
+	},
+}
+
+// formatMessage форматирует текстовое сообщение чата для вывода в консоль
+func formatMessage(message *pb.ChatMessage) string {
+	var line string
+	switch message.GetKind() {
+	case pb.MessageKind_MESSAGE_KIND_ACTION:
+		line = fmt.Sprintf("* %s %s", message.GetUsername(), message.GetText())
+	case pb.MessageKind_MESSAGE_KIND_SYSTEM:
+		line = fmt.Sprintf("-- %s --", message.GetText())
+	default:
+		line = fmt.Sprintf("%s: %s", message.GetUsername(), renderEntities(message.GetText(), message.GetEntities()))
+	}
+
+	// Удаленное сообщение и пересланная копия удаленного сообщения приходят без текста
+	if message.GetDeleted() || message.GetForwardedFrom().GetDeleted() {
+		line = fmt.Sprintf("%s: [message deleted]", message.GetUsername())
+	}
+
+	if forwarded := message.GetForwardedFrom(); forwarded != nil {
+		line = fmt.Sprintf("[forwarded from %s] %s", forwarded.GetUsername(), line)
+	}
+
+	if message.GetEvent() == pb.MessageEvent_MESSAGE_EVENT_DELETED {
+		line = fmt.Sprintf("-- message %s was deleted --", message.GetMessageId())
+	}
+
+	// Вызов команды приходит только боту, которому она принадлежит
+	if message.GetEvent() == pb.MessageEvent_MESSAGE_EVENT_COMMAND && message.GetCommand() != nil {
+		line = fmt.Sprintf("%s invoked /%s %s (invocation %s)",
+			message.GetUsername(),
+			message.GetCommand().GetName(),
+			message.GetCommand().GetArgs(),
+			message.GetCommand().GetInvocationId(),
+		)
+	}
+
+	if message.GetEphemeral() {
+		line = "(only you) " + line
+	}
+
+	// Превью ссылок приходят отдельным обновлением уже показанного сообщения
+	if message.GetEvent() == pb.MessageEvent_MESSAGE_EVENT_UPDATED && len(message.GetPreviews()) > 0 {
+		return strings.TrimPrefix(formatPreviews(message.GetPreviews()), "\n")
+	}
+
+	if showIDs && message.GetMessageId() != "" {
+		line = fmt.Sprintf("[%s] %s", message.GetMessageId(), line)
+	}
+
+	return line + formatPreviews(message.GetPreviews())
+}
+
+// formatPreviews форматирует превью ссылок сообщения, каждое превью начинается с новой строки
+func formatPreviews(previews []*pb.LinkPreview) string {
+	var b strings.Builder
+	for _, preview := range previews {
+		b.WriteString("\n  > ")
+		b.WriteString(preview.GetTitle())
+		if preview.GetDescription() != "" {
+			b.WriteString(" - ")
+			b.WriteString(preview.GetDescription())
+		}
+		b.WriteString("\n    ")
+		b.WriteString(preview.GetUrl())
+	}
+	return b.String()
+}
+
+func init() {
+	commandsCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	commandsCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+}
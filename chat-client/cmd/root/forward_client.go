@@ -0,0 +1,67 @@
+package root
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var forwardCmd = &cobra.Command{
+	Use:   "forward <message-id>",
+	Short: "forward a message to another chat",
+	Long: `forward a message to the chat given by --id. You must be a member of both chats.
+	Use connect --show-ids to see message IDs.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		message, err := client.ForwardMessage(args[0], chatID)
+		if err != nil {
+			cmd.Printf("Failed to forward message: %v\n", err)
+			return
+		}
+
+		if message.GetPendingReview() {
+			cmd.Println("Forwarded message is held for review by chat admins")
+			return
+		}
+
+		cmd.Printf("Message forwarded with ID: %s\n", message.GetMessageId())
+	},
+}
+
+var deleteMessageCmd = &cobra.Command{
+	Use:   "delete <message-id>",
+	Short: "delete a message",
+	Long: `delete a message. Allowed for the message author and chat admins.
+	Forwarded copies of the message are shown as deleted.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.DeleteMessage(args[0]); err != nil {
+			cmd.Printf("Failed to delete message: %v\n", err)
+			return
+		}
+
+		cmd.Println("Message deleted")
+	},
+}
+
+func init() {
+	forwardCmd.Flags().StringVarP(&chatID, "id", "i", "", "target chat ID")
+	forwardCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	deleteMessageCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+}
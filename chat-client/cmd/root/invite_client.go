@@ -0,0 +1,172 @@
+package root
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"chat.client/internal/chat_client"
+	pb "chat.service/api/proto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	inviteTTL     time.Duration
+	inviteMaxUses int
+	inviteRole    string
+	inviteID      string
+)
+
+var inviteCmd = &cobra.Command{
+	Use:   "invite",
+	Short: "manage chat invites",
+	Long: `manage invite links for a chat.
+	Only chat admins can create and revoke invites.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var inviteCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create an invite code for a chat",
+	Long: `create an invite code for the chat with the given ID.
+	The code is shown only once, share it with the users you want to invite.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		var role pb.ChatRole
+		switch strings.ToLower(inviteRole) {
+		case "", "member":
+			role = pb.ChatRole_CHAT_ROLE_MEMBER
+		case "admin":
+			role = pb.ChatRole_CHAT_ROLE_ADMIN
+		default:
+			cmd.Printf("Unknown role: %s (expected member or admin)\n", inviteRole)
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		invite, err := client.CreateInvite(chatID, inviteTTL, inviteMaxUses, role)
+		if err != nil {
+			cmd.Printf("Failed to create invite: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Invite created with ID: %s\n", invite.GetInviteId())
+		cmd.Printf("Code: %s\n", invite.GetCode())
+		cmd.Printf("Expires at: %s\n", invite.GetExpiresAt().AsTime().Local().Format(time.RFC1123))
+	},
+}
+
+var inviteRevokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "revoke an invite",
+	Long:  `revoke the invite with the given ID so it can no longer be used.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if inviteID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.RevokeInvite(inviteID); err != nil {
+			cmd.Printf("Failed to revoke invite: %v\n", err)
+			return
+		}
+
+		cmd.Println("Invite revoked successfully")
+	},
+}
+
+var joinCmd = &cobra.Command{
+	Use:   "join [code]",
+	Short: "join a chat by invite code or a public chat by ID",
+	Long: `join a chat using an invite code received from one of its admins,
+	or join a public chat by its ID with the --id flag.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 && chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if len(args) == 0 {
+			chat, err := client.JoinChat(chatID)
+			if err != nil {
+				cmd.Printf("Failed to join chat: %v\n", err)
+				return
+			}
+
+			cmd.Printf("Joined chat %s with ID: %s\n", chat.GetName(), chat.GetChatId())
+			return
+		}
+
+		joinedChatID, err := client.JoinByInvite(args[0])
+		if err != nil {
+			cmd.Printf("Failed to join chat: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Joined chat with ID: %s\n", joinedChatID)
+	},
+}
+
+// newChatClient создает клиент сервиса чатов по адресу из окружения и токену из флагов
+// или сохраненной при входе сессии
+func newChatClient(cmd *cobra.Command) (*chat_client.ChatClient, bool) {
+	chatServiceAddr, ok := os.LookupEnv("CHAT_SERVICE_ADDR")
+	if !ok {
+		cmd.Println("CHAT_SERVICE_ADDR environment variable is not set")
+		return nil, false
+	}
+
+	tokens, ok := chatTokenSource(cmd)
+	if !ok {
+		return nil, false
+	}
+
+	client, err := chat_client.NewChatClientWithTokenSource(chatServiceAddr, tokens)
+	if err != nil {
+		cmd.Printf("Failed to create chat client: %v\n", err)
+		return nil, false
+	}
+
+	return client, true
+}
+
+func init() {
+	inviteCreateCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	inviteCreateCmd.Flags().DurationVar(&inviteTTL, "ttl", 24*time.Hour, "how long the invite stays valid")
+	inviteCreateCmd.Flags().IntVar(&inviteMaxUses, "max-uses", 0, "maximum number of uses (0 - unlimited)")
+	inviteCreateCmd.Flags().StringVar(&inviteRole, "role", "member", "role granted to joined users (member or admin)")
+	inviteCreateCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	inviteRevokeCmd.Flags().StringVar(&inviteID, "invite", "", "invite ID")
+	inviteRevokeCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	joinCmd.Flags().StringVarP(&chatID, "id", "i", "", "public chat ID")
+	joinCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	inviteCmd.AddCommand(inviteCreateCmd)
+	inviteCmd.AddCommand(inviteRevokeCmd)
+}
@@ -0,0 +1,93 @@
+package root
+
+import (
+	"os"
+	"strings"
+	"unicode/utf16"
+
+	pb "chat.service/api/proto"
+)
+
+// ANSI последовательности для оформления разметки
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiUnderline = "\x1b[4m"
+	ansiCyan      = "\x1b[36m"
+	ansiYellow    = "\x1b[33m"
+)
+
+// renderEntities оформляет текст сообщения ANSI стилями по фрагментам разметки.
+// Смещения фрагментов задаются в единицах UTF-16. При заданной переменной NO_COLOR текст выводится без стилей
+func renderEntities(text string, entities []*pb.MessageEntity) string {
+	if len(entities) == 0 || os.Getenv("NO_COLOR") != "" {
+		return text
+	}
+
+	units := utf16.Encode([]rune(text))
+
+	// Позиции, в которых начинаются и заканчиваются фрагменты, и адреса ссылок после их текста
+	boundaries := make(map[int]bool)
+	linkSuffixes := make(map[int]string)
+	for _, entity := range entities {
+		start := int(entity.GetOffset())
+		end := start + int(entity.GetLength())
+		if start < 0 || end > len(units) || start >= end {
+			continue
+		}
+
+		boundaries[start] = true
+		boundaries[end] = true
+		if entity.GetType() == pb.MessageEntityType_MESSAGE_ENTITY_TYPE_LINK && entity.GetUrl() != string(utf16.Decode(units[start:end])) {
+			linkSuffixes[end] += " (" + entity.GetUrl() + ")"
+		}
+	}
+
+	var b strings.Builder
+	last := 0
+	for i := 0; i <= len(units); i++ {
+		if !boundaries[i] {
+			continue
+		}
+
+		b.WriteString(string(utf16.Decode(units[last:i])))
+		last = i
+
+		// На каждой границе сбрасываем стиль и заново включаем стили фрагментов, покрывающих позицию
+		b.WriteString(ansiReset)
+		b.WriteString(linkSuffixes[i])
+		b.WriteString(activeStyles(entities, i))
+	}
+	b.WriteString(string(utf16.Decode(units[last:])))
+
+	return b.String()
+}
+
+// activeStyles возвращает стили фрагментов, покрывающих позицию pos
+func activeStyles(entities []*pb.MessageEntity, pos int) string {
+	var styles string
+	for _, entity := range entities {
+		start := int(entity.GetOffset())
+		end := start + int(entity.GetLength())
+		if start <= pos && pos < end {
+			styles += entityStyle(entity.GetType())
+		}
+	}
+	return styles
+}
+
+// entityStyle возвращает ANSI стиль для типа фрагмента разметки
+func entityStyle(entityType pb.MessageEntityType) string {
+	switch entityType {
+	case pb.MessageEntityType_MESSAGE_ENTITY_TYPE_BOLD:
+		return ansiBold
+	case pb.MessageEntityType_MESSAGE_ENTITY_TYPE_CODE:
+		return ansiCyan
+	case pb.MessageEntityType_MESSAGE_ENTITY_TYPE_LINK:
+		return ansiUnderline
+	case pb.MessageEntityType_MESSAGE_ENTITY_TYPE_MENTION:
+		return ansiYellow
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,275 @@
+package root
+
+import (
+	"time"
+
+	pb "chat.service/api/proto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	restrictReason string
+	memberMuteFor  time.Duration
+	reportsLimit   int
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report <message-id>",
+	Short: "report a message to chat admins",
+	Long: `report a message to chat admins with a reason.
+	Use connect --show-ids to see message IDs.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if restrictReason == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if _, err := client.ReportMessage(args[0], restrictReason); err != nil {
+			cmd.Printf("Failed to report message: %v\n", err)
+			return
+		}
+
+		cmd.Println("Message reported")
+	},
+}
+
+var reportsCmd = &cobra.Command{
+	Use:   "reports",
+	Short: "list reported messages",
+	Long:  `list the latest reports on chat messages. Only chat admins can see reports.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		reports, err := client.ListReports(chatID, reportsLimit)
+		if err != nil {
+			cmd.Printf("Failed to list reports: %v\n", err)
+			return
+		}
+
+		if len(reports) == 0 {
+			cmd.Println("No reports")
+			return
+		}
+
+		for _, report := range reports {
+			cmd.Printf("%s\t%s\t%s: %s\t(%s)\n",
+				report.GetCreatedAt().AsTime().Local().Format(time.RFC1123),
+				report.GetMessageId(),
+				report.GetAuthorUsername(),
+				report.GetMessageText(),
+				report.GetReason(),
+			)
+		}
+	},
+}
+
+var memberCmd = &cobra.Command{
+	Use:   "member",
+	Short: "ban and mute chat members",
+	Long: `ban and mute chat members. Only chat admins can restrict members.
+	A banned user is removed from the chat and cannot join again, a muted user cannot post until the mute expires.`,
+}
+
+var memberBanCmd = &cobra.Command{
+	Use:   "ban <username>",
+	Short: "ban a user from the chat",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if _, err := client.BanUser(chatID, args[0], restrictReason); err != nil {
+			cmd.Printf("Failed to ban user: %v\n", err)
+			return
+		}
+
+		cmd.Printf("User %s banned\n", args[0])
+	},
+}
+
+var memberMuteCmd = &cobra.Command{
+	Use:   "mute <username>",
+	Short: "temporarily forbid a user to post",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" || memberMuteFor <= 0 {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		restriction, err := client.MuteUser(chatID, args[0], restrictReason, memberMuteFor)
+		if err != nil {
+			cmd.Printf("Failed to mute user: %v\n", err)
+			return
+		}
+
+		cmd.Printf("User %s muted until %s\n", args[0], restriction.GetExpiresAt().AsTime().Local().Format(time.RFC1123))
+	},
+}
+
+var memberUnbanCmd = &cobra.Command{
+	Use:   "unban <username>",
+	Short: "lift a ban",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		liftRestriction(cmd, args[0], pb.RestrictionKind_RESTRICTION_KIND_BAN)
+	},
+}
+
+var memberUnmuteCmd = &cobra.Command{
+	Use:   "unmute <username>",
+	Short: "lift a mute before it expires",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		liftRestriction(cmd, args[0], pb.RestrictionKind_RESTRICTION_KIND_MUTE)
+	},
+}
+
+var memberListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list active bans and mutes",
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		restrictions, err := client.ListRestrictions(chatID)
+		if err != nil {
+			cmd.Printf("Failed to list restrictions: %v\n", err)
+			return
+		}
+
+		if len(restrictions) == 0 {
+			cmd.Println("No active restrictions")
+			return
+		}
+
+		for _, restriction := range restrictions {
+			until := "permanent"
+			if restriction.GetExpiresAt() != nil {
+				until = "until " + restriction.GetExpiresAt().AsTime().Local().Format(time.RFC1123)
+			}
+
+			kind := "ban"
+			if restriction.GetKind() == pb.RestrictionKind_RESTRICTION_KIND_MUTE {
+				kind = "mute"
+			}
+
+			cmd.Printf("%s\t%s\t%s\t%s\n", restriction.GetUsername(), kind, until, restriction.GetReason())
+		}
+	},
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "show the chat moderation log",
+	Long:  `show who reported, banned, muted or lifted restrictions in the chat. Only chat admins can see the log.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		entries, err := client.GetAuditLog(chatID, reportsLimit)
+		if err != nil {
+			cmd.Printf("Failed to get moderation log: %v\n", err)
+			return
+		}
+
+		for _, entry := range entries {
+			cmd.Printf("%s\t%s\t%s\t%s\t%s\n",
+				entry.GetCreatedAt().AsTime().Local().Format(time.RFC1123),
+				entry.GetActorUsername(),
+				entry.GetAction(),
+				entry.GetTargetUsername(),
+				entry.GetDetails(),
+			)
+		}
+	},
+}
+
+// liftRestriction снимает ограничение пользователя указанного вида
+func liftRestriction(cmd *cobra.Command, username string, kind pb.RestrictionKind) {
+	if chatID == "" {
+		cmd.Help()
+		return
+	}
+
+	client, ok := newChatClient(cmd)
+	if !ok {
+		return
+	}
+	defer client.Close()
+
+	if err := client.LiftRestriction(chatID, username, kind); err != nil {
+		cmd.Printf("Failed to lift restriction: %v\n", err)
+		return
+	}
+
+	cmd.Printf("Restriction lifted for %s\n", username)
+}
+
+func init() {
+	reportCmd.Flags().StringVarP(&restrictReason, "reason", "r", "", "why the message should be reviewed")
+	reportCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	reportsCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	reportsCmd.Flags().IntVarP(&reportsLimit, "limit", "l", 50, "number of reports to show")
+	reportsCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	auditCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	auditCmd.Flags().IntVarP(&reportsLimit, "limit", "l", 50, "number of entries to show")
+	auditCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	for _, command := range []*cobra.Command{memberBanCmd, memberMuteCmd, memberUnbanCmd, memberUnmuteCmd, memberListCmd} {
+		command.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+		command.Flags().StringVarP(&token, "token", "t", "", "auth token")
+		memberCmd.AddCommand(command)
+	}
+	memberBanCmd.Flags().StringVarP(&restrictReason, "reason", "r", "", "reason shown in the moderation log")
+	memberMuteCmd.Flags().StringVarP(&restrictReason, "reason", "r", "", "reason shown in the moderation log")
+	memberMuteCmd.Flags().DurationVar(&memberMuteFor, "for", 0, "mute duration, e.g. 30m or 24h (up to 720h)")
+}
@@ -0,0 +1,112 @@
+package root
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var moderationCmd = &cobra.Command{
+	Use:   "moderation",
+	Short: "review messages held by moderation filters",
+	Long: `review messages held by the server moderation filters.
+	Held messages are published only after a chat admin approves them.`,
+}
+
+var moderationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list messages waiting for review",
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		messages, err := client.ListFlaggedMessages(chatID)
+		if err != nil {
+			cmd.Printf("Failed to list held messages: %v\n", err)
+			return
+		}
+
+		if len(messages) == 0 {
+			cmd.Println("No messages waiting for review")
+			return
+		}
+
+		for _, message := range messages {
+			text := message.GetText()
+			if forwarded := message.GetForwardedFrom(); forwarded != nil {
+				text = fmt.Sprintf("[forwarded from %s] %s", forwarded.GetUsername(), text)
+			}
+
+			cmd.Printf("%s\t%s\t%s: %s\t(%s)\n",
+				message.GetFlaggedMessageId(),
+				message.GetCreatedAt().AsTime().Local().Format(time.RFC1123),
+				message.GetUsername(),
+				text,
+				message.GetReason(),
+			)
+		}
+	},
+}
+
+var moderationApproveCmd = &cobra.Command{
+	Use:   "approve <flagged-message-id>",
+	Short: "publish a held message",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		message, err := client.ApproveFlaggedMessage(args[0])
+		if err != nil {
+			cmd.Printf("Failed to approve message: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Message approved and published with ID: %s\n", message.GetMessageId())
+	},
+}
+
+var moderationRemoveCmd = &cobra.Command{
+	Use:   "remove <flagged-message-id>",
+	Short: "discard a held message",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.RemoveFlaggedMessage(args[0]); err != nil {
+			cmd.Printf("Failed to remove message: %v\n", err)
+			return
+		}
+
+		cmd.Println("Message removed")
+	},
+}
+
+func init() {
+	moderationListCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	moderationListCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	moderationApproveCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	moderationRemoveCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	moderationCmd.AddCommand(moderationListCmd)
+	moderationCmd.AddCommand(moderationApproveCmd)
+	moderationCmd.AddCommand(moderationRemoveCmd)
+}
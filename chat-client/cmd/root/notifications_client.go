@@ -0,0 +1,73 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	pb "chat.service/api/proto"
+	"github.com/spf13/cobra"
+)
+
+var notificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "stream notifications from all chats",
+	Long: `stream new messages, mentions and invites from all your chats over one connection.
+	Muted and hidden chats are skipped, chats with the mentions notify level report mentions only.
+	Press Ctrl+C to stop.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		stream, err := client.StreamNotifications(ctx)
+		if err != nil {
+			cmd.Printf("Failed to subscribe to notifications: %v\n", err)
+			return
+		}
+
+		cmd.Println("Listening for notifications. Press Ctrl+C to exit.")
+
+		for {
+			notification, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() == nil {
+					cmd.Printf("Error receiving notification: %v\n", err)
+				}
+				return
+			}
+
+			cmd.Println(formatNotification(notification))
+		}
+	},
+}
+
+// formatNotification форматирует уведомление в одну строку с временем и чатом
+func formatNotification(notification *pb.Notification) string {
+	chat := notification.GetChatName()
+	if chat == "" {
+		chat = notification.GetChatId()
+	}
+
+	prefix := fmt.Sprintf("[%s] %s", notification.GetCreatedAt().AsTime().Local().Format("15:04"), chat)
+
+	switch notification.GetKind() {
+	case pb.NotificationKind_NOTIFICATION_KIND_MENTION:
+		return fmt.Sprintf("%s (mention) %s", prefix, formatMessage(notification.GetMessage()))
+	case pb.NotificationKind_NOTIFICATION_KIND_INVITE:
+		return fmt.Sprintf("%s: %s added you to the chat (%s)", prefix, notification.GetActorUsername(), notification.GetChatId())
+	default:
+		return fmt.Sprintf("%s %s", prefix, formatMessage(notification.GetMessage()))
+	}
+}
+
+func init() {
+	notificationsCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+}
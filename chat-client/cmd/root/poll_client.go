@@ -0,0 +1,180 @@
+package root
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chat.client/internal/chat_client"
+	pb "chat.service/api/proto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pollQuestion  string
+	pollOptions   []string
+	pollMultiple  bool
+	pollAnonymous bool
+	pollClosesIn  time.Duration
+)
+
+var pollCmd = &cobra.Command{
+	Use:   "poll",
+	Short: "create a poll",
+	Long: `create a poll in the chat. Repeat --option for every answer.
+	Chat members can vote from the connect session with /vote <number>.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" || pollQuestion == "" || len(pollOptions) < 2 {
+			cmd.Help()
+			return
+		}
+
+		var closesAt *time.Time
+		if pollClosesIn > 0 {
+			at := time.Now().Add(pollClosesIn)
+			closesAt = &at
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		message, err := client.CreatePoll(chatID, pollQuestion, pollOptions, pollMultiple, pollAnonymous, closesAt)
+		if err != nil {
+			cmd.Printf("Failed to create poll: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Poll created with ID: %s\n", message.GetPoll().GetPollId())
+	},
+}
+
+var pollCloseCmd = &cobra.Command{
+	Use:   "close <poll-id>",
+	Short: "close a poll",
+	Long:  `close a poll before its deadline. Allowed for the poll author and chat admins.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		poll, err := client.ClosePoll(args[0])
+		if err != nil {
+			cmd.Printf("Failed to close poll: %v\n", err)
+			return
+		}
+
+		fmt.Print(formatPoll(poll))
+	},
+}
+
+// pollTracker запоминает последний опрос, полученный в сессии connect, чтобы по нему можно было голосовать командой /vote
+type pollTracker struct {
+	mu     sync.Mutex
+	pollID string
+}
+
+func (t *pollTracker) track(message *pb.ChatMessage) {
+	if message.GetKind() != pb.MessageKind_MESSAGE_KIND_POLL || message.GetPoll() == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Обновления результатов старых опросов не должны перебивать последний опубликованный опрос
+	if message.GetEvent() != pb.MessageEvent_MESSAGE_EVENT_UPDATED || t.pollID == "" {
+		t.pollID = message.GetPoll().GetPollId()
+	}
+}
+
+func (t *pollTracker) last() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.pollID
+}
+
+// handleVoteCommand обрабатывает ввод вида "/vote 2" или "/vote 1 3" для последнего опроса
+func handleVoteCommand(client *chat_client.ChatClient, polls *pollTracker, input string) {
+	fields := strings.Fields(strings.TrimPrefix(input, "/vote"))
+	if len(fields) == 0 {
+		fmt.Println("Usage: /vote <option number> [option number...]")
+		return
+	}
+
+	pollID := polls.last()
+	if pollID == "" {
+		fmt.Println("There is no poll to vote in")
+		return
+	}
+
+	indexes := make([]int32, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 {
+			fmt.Printf("Invalid option number: %s\n", field)
+			return
+		}
+		indexes = append(indexes, int32(n-1))
+	}
+
+	if _, err := client.Vote(pollID, indexes); err != nil {
+		fmt.Printf("Failed to vote: %v\n", err)
+	}
+}
+
+// formatPoll отображает опрос с пронумерованными вариантами ответа и текущими результатами
+func formatPoll(poll *pb.Poll) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[poll] %s", poll.GetQuestion())
+	var flags []string
+	if poll.GetMultipleChoice() {
+		flags = append(flags, "multiple choice")
+	}
+	if poll.GetAnonymous() {
+		flags = append(flags, "anonymous")
+	}
+	if poll.GetClosed() {
+		flags = append(flags, "closed")
+	} else if poll.GetClosesAt() != nil {
+		flags = append(flags, "closes "+poll.GetClosesAt().AsTime().Local().Format(time.RFC1123))
+	}
+	if len(flags) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(flags, ", "))
+	}
+	b.WriteString("\n")
+
+	for _, option := range poll.GetOptions() {
+		percent := 0
+		if poll.GetTotalVoters() > 0 {
+			percent = int(option.GetVotes() * 100 / poll.GetTotalVoters())
+		}
+		fmt.Fprintf(&b, "  %d. %s - %d (%d%%)\n", option.GetIndex()+1, option.GetText(), option.GetVotes(), percent)
+	}
+	fmt.Fprintf(&b, "  voters: %d\n", poll.GetTotalVoters())
+
+	return b.String()
+}
+
+func init() {
+	pollCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	pollCmd.Flags().StringVarP(&pollQuestion, "question", "q", "", "poll question")
+	pollCmd.Flags().StringArrayVarP(&pollOptions, "option", "o", nil, "answer option (repeat for every option)")
+	pollCmd.Flags().BoolVar(&pollMultiple, "multiple", false, "allow choosing several options")
+	pollCmd.Flags().BoolVar(&pollAnonymous, "anonymous", false, "hide who voted for what")
+	pollCmd.Flags().DurationVar(&pollClosesIn, "closes-in", 0, "close the poll automatically after this duration")
+	pollCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	pollCloseCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	pollCmd.AddCommand(pollCloseCmd)
+}
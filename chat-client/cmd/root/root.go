@@ -21,8 +21,35 @@ var rootCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(registerCmd)
 	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+	rootCmd.AddCommand(sessionsCmd)
+	rootCmd.AddCommand(twoFactorCmd)
+	rootCmd.AddCommand(unlockCmd)
+	rootCmd.AddCommand(confirmEmailCmd)
+	rootCmd.AddCommand(forgotPasswordCmd)
+	rootCmd.AddCommand(resetPasswordCmd)
 	rootCmd.AddCommand(connectCmd)
 	rootCmd.AddCommand(createChatCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(inviteCmd)
+	rootCmd.AddCommand(joinCmd)
+	rootCmd.AddCommand(muteCmd)
+	rootCmd.AddCommand(settingsCmd)
+	rootCmd.AddCommand(unreadCmd)
+	rootCmd.AddCommand(notificationsCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(scheduledCmd)
+	rootCmd.AddCommand(pollCmd)
+	rootCmd.AddCommand(botCmd)
+	rootCmd.AddCommand(webhookCmd)
+	rootCmd.AddCommand(commandsCmd)
+	rootCmd.AddCommand(forwardCmd)
+	rootCmd.AddCommand(deleteMessageCmd)
+	rootCmd.AddCommand(moderationCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(reportsCmd)
+	rootCmd.AddCommand(memberCmd)
+	rootCmd.AddCommand(auditCmd)
 }
 
 func Execute() error {
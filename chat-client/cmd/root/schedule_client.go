@@ -0,0 +1,119 @@
+package root
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleIn time.Duration
+	scheduleAt string
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule <text>",
+	Short: "schedule a message",
+	Long: `schedule a message to be sent to the chat later.
+	Use --in for a delay or --at for an exact time in RFC3339 format.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" || (scheduleIn == 0 && scheduleAt == "") {
+			cmd.Help()
+			return
+		}
+
+		sendAt := time.Now().Add(scheduleIn)
+		if scheduleAt != "" {
+			at, err := time.Parse(time.RFC3339, scheduleAt)
+			if err != nil {
+				cmd.Printf("Invalid time %q, expected RFC3339 format (e.g. 2025-06-01T09:00:00+03:00)\n", scheduleAt)
+				return
+			}
+			sendAt = at
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		scheduled, err := client.ScheduleMessage(chatID, strings.Join(args, " "), sendAt)
+		if err != nil {
+			cmd.Printf("Failed to schedule message: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Message %s scheduled for %s\n", scheduled.GetScheduledMessageId(), scheduled.GetSendAt().AsTime().Local().Format(time.RFC1123))
+	},
+}
+
+var scheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "list scheduled messages",
+	Long:  `list your messages that are waiting to be sent, optionally only for one chat.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		messages, err := client.ListScheduledMessages(chatID)
+		if err != nil {
+			cmd.Printf("Failed to list scheduled messages: %v\n", err)
+			return
+		}
+
+		if len(messages) == 0 {
+			cmd.Println("No scheduled messages")
+			return
+		}
+
+		for _, message := range messages {
+			cmd.Printf("%s\t%s\t%s\t%s\n",
+				message.GetScheduledMessageId(),
+				message.GetChatId(),
+				message.GetSendAt().AsTime().Local().Format(time.RFC1123),
+				message.GetText(),
+			)
+		}
+	},
+}
+
+var scheduledCancelCmd = &cobra.Command{
+	Use:   "cancel <id>",
+	Short: "cancel a scheduled message",
+	Long:  `cancel a scheduled message that has not been sent yet.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.CancelScheduledMessage(args[0]); err != nil {
+			cmd.Printf("Failed to cancel scheduled message: %v\n", err)
+			return
+		}
+
+		cmd.Println("Scheduled message cancelled")
+	},
+}
+
+func init() {
+	scheduleCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	scheduleCmd.Flags().DurationVar(&scheduleIn, "in", 0, "send the message after this delay")
+	scheduleCmd.Flags().StringVar(&scheduleAt, "at", "", "send the message at this time (RFC3339)")
+	scheduleCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	scheduledCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	scheduledCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	scheduledCancelCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	scheduledCmd.AddCommand(scheduledCancelCmd)
+}
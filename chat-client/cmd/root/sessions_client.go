@@ -0,0 +1,76 @@
+package root
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "list devices you are logged in on",
+	Long: `list active sessions: device name, IP address and when each session was last used.
+	The session of the current token is marked with *.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		sessions, err := client.ListSessions(token)
+		if err != nil {
+			cmd.Printf("Failed to list sessions: %v\n", err)
+			return
+		}
+
+		if len(sessions) == 0 {
+			cmd.Println("No active sessions")
+			return
+		}
+
+		for _, session := range sessions {
+			marker := " "
+			if session.GetCurrent() {
+				marker = "*"
+			}
+
+			cmd.Printf("%s %s\t%s\t%s\tlast used %s\n",
+				marker,
+				session.GetSessionId(),
+				session.GetDeviceName(),
+				session.GetIpAddress(),
+				session.GetLastUsedAt().AsTime().Local().Format(time.RFC1123),
+			)
+		}
+	},
+}
+
+var sessionsRevokeCmd = &cobra.Command{
+	Use:   "revoke <session-id>",
+	Short: "log out a device",
+	Long: `end a session: its refresh token stops working and the device has to log in again.
+	Access tokens already issued to the device stay valid until they expire.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.RevokeSession(token, args[0]); err != nil {
+			cmd.Printf("Failed to revoke session: %v\n", err)
+			return
+		}
+
+		cmd.Println("Session revoked")
+	},
+}
+
+func init() {
+	sessionsCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+	sessionsRevokeCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	sessionsCmd.AddCommand(sessionsRevokeCmd)
+}
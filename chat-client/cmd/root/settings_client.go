@@ -0,0 +1,150 @@
+package root
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	pb "chat.service/api/proto"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	muteFor     time.Duration
+	notifyLevel string
+	hideChat    bool
+	unhideChat  bool
+)
+
+var muteCmd = &cobra.Command{
+	Use:   "mute",
+	Short: "mute a chat",
+	Long: `mute notifications from the chat with the given ID for a period of time.
+	Use --for 0 to unmute the chat.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		settings, err := client.UpdateChatSettings(&pb.UpdateChatSettingsRequest{
+			ChatId:     chatID,
+			MutedUntil: timestamppb.New(time.Now().Add(muteFor)),
+		})
+		if err != nil {
+			cmd.Printf("Failed to mute chat: %v\n", err)
+			return
+		}
+
+		if settings.GetMutedUntil() == nil {
+			cmd.Println("Chat unmuted")
+			return
+		}
+
+		cmd.Printf("Chat muted until %s\n", settings.GetMutedUntil().AsTime().Local().Format(time.RFC1123))
+	},
+}
+
+var settingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "change personal chat settings",
+	Long: `change notification level and visibility of the chat with the given ID.
+	Only the provided settings are changed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		req := &pb.UpdateChatSettingsRequest{ChatId: chatID}
+
+		switch strings.ToLower(notifyLevel) {
+		case "":
+		case "all":
+			req.NotifyLevel = pb.NotifyLevel_NOTIFY_LEVEL_ALL
+		case "mentions":
+			req.NotifyLevel = pb.NotifyLevel_NOTIFY_LEVEL_MENTIONS
+		default:
+			cmd.Printf("Unknown notify level: %s (expected all or mentions)\n", notifyLevel)
+			return
+		}
+
+		switch {
+		case hideChat && unhideChat:
+			cmd.Println("Use either --hide or --unhide")
+			return
+		case hideChat || unhideChat:
+			req.Hidden = &hideChat
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		settings, err := client.UpdateChatSettings(req)
+		if err != nil {
+			cmd.Printf("Failed to update chat settings: %v\n", err)
+			return
+		}
+
+		level := "all"
+		if settings.GetNotifyLevel() == pb.NotifyLevel_NOTIFY_LEVEL_MENTIONS {
+			level = "mentions"
+		}
+		cmd.Printf("Notify: %s, hidden: %t\n", level, settings.GetHidden())
+	},
+}
+
+var unreadCmd = &cobra.Command{
+	Use:   "unread",
+	Short: "show unread messages counters",
+	Long:  `show unread messages and mentions counters for all your chats.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		counters, err := client.GetUnreadCounters()
+		if err != nil {
+			cmd.Printf("Failed to get unread counters: %v\n", err)
+			return
+		}
+
+		for _, counter := range counters {
+			if counter.GetUnreadCount() == 0 && counter.GetMentionCount() == 0 {
+				continue
+			}
+
+			line := fmt.Sprintf("%s\t%d unread, %d mentions", counter.GetChatId(), counter.GetUnreadCount(), counter.GetMentionCount())
+			if counter.GetMuted() {
+				line += " (muted)"
+			}
+			cmd.Println(line)
+		}
+	},
+}
+
+func init() {
+	muteCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	muteCmd.Flags().DurationVar(&muteFor, "for", 8*time.Hour, "how long to mute the chat (0 to unmute)")
+	muteCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	settingsCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	settingsCmd.Flags().StringVar(&notifyLevel, "notify", "", "notify about all messages or mentions only (all or mentions)")
+	settingsCmd.Flags().BoolVar(&hideChat, "hide", false, "hide the chat")
+	settingsCmd.Flags().BoolVar(&unhideChat, "unhide", false, "show the hidden chat again")
+	settingsCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	unreadCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+}
@@ -0,0 +1,93 @@
+package root
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var twoFactorCmd = &cobra.Command{
+	Use:   "2fa",
+	Short: "manage two-factor authentication",
+	Long: `enable or disable two-factor authentication with an authenticator app (TOTP).
+	When it is enabled, login asks for a code from the app after the password.`,
+}
+
+var twoFactorEnrollCmd = &cobra.Command{
+	Use:   "enroll",
+	Short: "start enabling two-factor authentication",
+	Long: `generate a secret for an authenticator app. Add it to the app by the secret or the otpauth:// URI
+	and finish with "2fa confirm <code>".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		enrollment, err := client.EnrollTOTP(token)
+		if err != nil {
+			cmd.Printf("Failed to enroll two-factor authentication: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Secret: %s\n", enrollment.GetSecret())
+		cmd.Printf("URI: %s\n", enrollment.GetProvisioningUri())
+		cmd.Println("Add the secret to your authenticator app and run: chatik 2fa confirm <code>")
+	},
+}
+
+var twoFactorConfirmCmd = &cobra.Command{
+	Use:   "confirm <code>",
+	Short: "enable two-factor authentication",
+	Long: `enable two-factor authentication with a code from the authenticator app.
+	The printed recovery codes are shown only once, each of them can replace a code one time.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		recoveryCodes, err := client.ConfirmTOTP(token, args[0])
+		if err != nil {
+			cmd.Printf("Failed to enable two-factor authentication: %v\n", err)
+			return
+		}
+
+		cmd.Println("Two-factor authentication enabled. Recovery codes, store them somewhere safe:")
+		for _, code := range recoveryCodes {
+			cmd.Printf("  %s\n", code)
+		}
+	},
+}
+
+var twoFactorDisableCmd = &cobra.Command{
+	Use:   "disable <code>",
+	Short: "disable two-factor authentication",
+	Long:  `disable two-factor authentication. Takes a code from the authenticator app or a recovery code.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.DisableTOTP(token, args[0]); err != nil {
+			cmd.Printf("Failed to disable two-factor authentication: %v\n", err)
+			return
+		}
+
+		cmd.Println("Two-factor authentication disabled")
+	},
+}
+
+func init() {
+	twoFactorEnrollCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+	twoFactorConfirmCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+	twoFactorDisableCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	twoFactorCmd.AddCommand(twoFactorEnrollCmd)
+	twoFactorCmd.AddCommand(twoFactorConfirmCmd)
+	twoFactorCmd.AddCommand(twoFactorDisableCmd)
+}
@@ -1,21 +1,34 @@
 package root
 
 import (
+	"bufio"
+	"context"
 	"os"
+	"strings"
 
+	"chat.client/internal/chat_client"
 	"chat.client/internal/user_client"
 	"github.com/spf13/cobra"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
 )
 
 var (
-	username string
-	password string
+	username     string
+	password     string
+	refreshToken string
+	logoutAll    bool
+	deviceName   string
+	totpCode     string
+	email        string
 )
 
 var registerCmd = &cobra.Command{
 	Use:   "register",
 	Short: "register a new user",
 	Long: `register a new user with the given username and password.
+	The email is optional, it is needed to reset a forgotten password.
+	It takes effect after confirm-email with the token mailed to it.
 	It is written in Go and uses the Cobra library for command line parsing.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var authServiceAddr string
@@ -38,9 +51,10 @@ var registerCmd = &cobra.Command{
 			return
 		}
 
-		err = client.Register(username, password)
+		err = client.Register(username, password, email)
 		if err != nil {
 			cmd.Printf("Ошибка при регистрации пользователя: %v\n", err)
+			printFieldViolations(cmd, err)
 			return
 		}
 
@@ -51,6 +65,9 @@ var registerCmd = &cobra.Command{
 		}
 
 		cmd.Println("User registered successfully")
+		if email != "" {
+			cmd.Println("Confirm the email with confirm-email and the token sent to it")
+		}
 	},
 }
 
@@ -61,7 +78,6 @@ var loginCmd = &cobra.Command{
 	It is written in Go and uses the Cobra library for command line parsing.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var authServiceAddr string
-		var token string
 
 		if username == "" || password == "" {
 			cmd.Help()
@@ -81,26 +97,345 @@ var loginCmd = &cobra.Command{
 			return
 		}
 
-		token, err = client.Login(username, password)
+		session, challenge, err := client.Login(username, password, deviceName)
 		if err != nil {
 			cmd.Printf("Ошибка при входе в систему: %v\n", err)
 			return
 		}
 
+		// Включена двухфакторная аутентификация: вход завершается кодом
+		if challenge != nil {
+			code := totpCode
+			if code == "" {
+				code = promptSecondFactorCode(cmd)
+			}
+
+			session, err = client.VerifySecondFactor(challenge.Token, code)
+			if err != nil {
+				client.Close()
+				cmd.Printf("Failed to verify two-factor code: %v\n", err)
+				return
+			}
+		}
+
 		err = client.Close()
 		if err != nil {
 			cmd.Help()
 			return
 		}
 
-		cmd.Printf("User logged in successfully, token: %s\n", token)
+		cmd.Printf("User logged in successfully, token: %s\n", session.AccessToken)
+		cmd.Printf("Refresh token: %s\n", session.RefreshToken)
+
+		// Сохраняем сессию, чтобы команды без --token обновляли токен доступа сами
+		path, err := user_client.SessionPath()
+		if err == nil {
+			err = user_client.SaveSession(path, session)
+		}
+		if err != nil {
+			cmd.Printf("Failed to save session: %v\n", err)
+			return
+		}
+		cmd.Printf("Session saved to %s\n", path)
+	},
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "log out of a session",
+	Long: `log out of the session of the given refresh token. The access token passed with --token is revoked as well.
+	Without --refresh-token the session saved by login is ended and removed.
+	With --all every session of the token owner is ended.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Без явных токенов используем сессию, сохраненную командой login
+		path, _ := user_client.SessionPath()
+		var saved *user_client.Session
+		if refreshToken == "" && token == "" && path != "" {
+			saved, _ = user_client.LoadSession(path)
+		}
+
+		if logoutAll {
+			client, ok := newUserClient(cmd)
+			if !ok {
+				return
+			}
+			defer client.Close()
+
+			if err := client.LogoutAll(token); err != nil {
+				cmd.Printf("Failed to log out: %v\n", err)
+				return
+			}
+
+			removeSavedSession(cmd, path, saved)
+			cmd.Println("Logged out of all sessions")
+			return
+		}
+
+		if saved != nil {
+			refreshToken = saved.RefreshToken
+			token = saved.AccessToken
+		}
+
+		if refreshToken == "" {
+			cmd.Help()
+			return
+		}
+
+		authServiceAddr, ok := os.LookupEnv("CHAT_AUTH_SERVICE_ADDR")
+		if !ok {
+			cmd.Println("CHAT_AUTH_SERVICE_ADDR environment variable is not set")
+			return
+		}
+
+		client, err := user_client.NewUserClient(authServiceAddr)
+		if err != nil {
+			cmd.Printf("Failed to connect to auth service: %v\n", err)
+			return
+		}
+		defer client.Close()
+
+		if err := client.Logout(token, refreshToken); err != nil {
+			cmd.Printf("Failed to log out: %v\n", err)
+			return
+		}
+
+		removeSavedSession(cmd, path, saved)
+		cmd.Println("Logged out")
 	},
 }
 
+var unlockCmd = &cobra.Command{
+	Use:   "unlock <username>",
+	Short: "unlock a user after too many failed logins",
+	Long: `lift the lockout and the login delay of a user locked after too many failed logins.
+	Only admins of the auth service can do it.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.UnlockUser(token, args[0]); err != nil {
+			cmd.Printf("Failed to unlock user: %v\n", err)
+			return
+		}
+
+		cmd.Printf("User %s unlocked\n", args[0])
+	},
+}
+
+var forgotPasswordCmd = &cobra.Command{
+	Use:   "forgot-password <email>",
+	Short: "request a password reset email",
+	Long: `send a password reset token to the email of a user.
+	The answer is the same whether the email is registered or not.
+	Set the new password with reset-password.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newAnonymousUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.RequestPasswordReset(args[0]); err != nil {
+			cmd.Printf("Failed to request password reset: %v\n", err)
+			return
+		}
+
+		cmd.Println("If the email is registered, a password reset token has been sent to it")
+	},
+}
+
+var confirmEmailCmd = &cobra.Command{
+	Use:   "confirm-email <confirmation-token>",
+	Short: "confirm an email with the mailed token",
+	Long: `confirm the email given at registration with the token from the confirmation email.
+	Until then the email can not be used to reset the password.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newAnonymousUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.ConfirmEmail(args[0]); err != nil {
+			cmd.Printf("Failed to confirm email: %v\n", err)
+			return
+		}
+
+		cmd.Println("Email confirmed")
+	},
+}
+
+var resetPasswordCmd = &cobra.Command{
+	Use:   "reset-password <reset-token>",
+	Short: "set a new password with a reset token",
+	Long: `set a new password with the token from the password reset email.
+	The token works once, and all sessions of the user are ended.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if password == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newAnonymousUserClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.ResetPassword(args[0], password); err != nil {
+			cmd.Printf("Failed to reset password: %v\n", err)
+			printFieldViolations(cmd, err)
+			return
+		}
+
+		cmd.Println("Password changed, log in again on all devices")
+	},
+}
+
+// newAnonymousUserClient подключается к сервису аутентификации без токена, для команд до входа в систему
+func newAnonymousUserClient(cmd *cobra.Command) (*user_client.UserClient, bool) {
+	authServiceAddr, ok := os.LookupEnv("CHAT_AUTH_SERVICE_ADDR")
+	if !ok {
+		cmd.Println("CHAT_AUTH_SERVICE_ADDR environment variable is not set")
+		return nil, false
+	}
+
+	client, err := user_client.NewUserClient(authServiceAddr)
+	if err != nil {
+		cmd.Printf("Failed to connect to auth service: %v\n", err)
+		return nil, false
+	}
+
+	return client, true
+}
+
+// printFieldViolations выводит по строке на каждое нарушенное правило из деталей ошибки сервера
+func printFieldViolations(cmd *cobra.Command, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return
+	}
+
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+
+		for _, violation := range badRequest.GetFieldViolations() {
+			cmd.Printf("  %s: %s\n", violation.GetField(), violation.GetDescription())
+		}
+	}
+}
+
+// promptSecondFactorCode запрашивает код двухфакторной аутентификации в терминале
+func promptSecondFactorCode(cmd *cobra.Command) string {
+	cmd.Print("Two-factor code (or recovery code): ")
+
+	line, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// removeSavedSession удаляет файл сессии, если выход выполнен из сохраненной сессии
+func removeSavedSession(cmd *cobra.Command, path string, saved *user_client.Session) {
+	if saved == nil {
+		return
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		cmd.Printf("Failed to remove saved session: %v\n", err)
+	}
+}
+
+// savedSessionToken возвращает токен доступа сохраненной при входе сессии, обновляя его при необходимости
+func savedSessionToken(cmd *cobra.Command, client *user_client.UserClient) (string, bool) {
+	path, err := user_client.SessionPath()
+	if err != nil {
+		cmd.Println("You must provide a token. Use login command to get a token.")
+		return "", false
+	}
+
+	session, err := user_client.LoadSession(path)
+	if err != nil {
+		cmd.Println("You must provide a token. Use login command to get a token.")
+		return "", false
+	}
+
+	fresh, err := user_client.NewTokenSource(client, session, path).Token(context.Background())
+	if err != nil {
+		cmd.Printf("Failed to refresh session: %v\n", err)
+		return "", false
+	}
+
+	return fresh, true
+}
+
+// chatTokenSource возвращает токен из флага --token, а без него - сохраненную при входе сессию,
+// которая обновляется refresh-токеном по мере истечения токена доступа
+func chatTokenSource(cmd *cobra.Command) (chat_client.TokenSource, bool) {
+	if token != "" {
+		return chat_client.StaticToken(token), true
+	}
+
+	path, err := user_client.SessionPath()
+	if err != nil {
+		cmd.Println("You must provide a token. Use login command to get a token.")
+		return nil, false
+	}
+
+	session, err := user_client.LoadSession(path)
+	if err != nil {
+		cmd.Println("You must provide a token. Use login command to get a token.")
+		return nil, false
+	}
+
+	authServiceAddr, ok := os.LookupEnv("CHAT_AUTH_SERVICE_ADDR")
+	if !ok {
+		cmd.Println("CHAT_AUTH_SERVICE_ADDR environment variable is not set")
+		return nil, false
+	}
+
+	client, err := user_client.NewUserClient(authServiceAddr)
+	if err != nil {
+		cmd.Printf("Failed to connect to auth service: %v\n", err)
+		return nil, false
+	}
+
+	return user_client.NewTokenSource(client, session, path), true
+}
+
+// defaultDeviceName возвращает имя устройства по умолчанию: имя хоста
+func defaultDeviceName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "chatik"
+	}
+
+	return "chatik on " + hostname
+}
+
 func init() {
 	registerCmd.Flags().StringVarP(&username, "username", "u", "", "username")
 	registerCmd.Flags().StringVarP(&password, "password", "p", "", "password")
+	registerCmd.Flags().StringVarP(&email, "email", "e", "", "email for password reset, optional")
 
 	loginCmd.Flags().StringVarP(&username, "username", "u", "", "username")
 	loginCmd.Flags().StringVarP(&password, "password", "p", "", "password")
+	loginCmd.Flags().StringVar(&deviceName, "device", defaultDeviceName(), "device name shown in the session list")
+	loginCmd.Flags().StringVar(&totpCode, "code", "", "two-factor code or recovery code, asked for if needed and not given")
+
+	logoutCmd.Flags().StringVarP(&refreshToken, "refresh-token", "r", "", "refresh token of the session")
+	logoutCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+	logoutCmd.Flags().BoolVar(&logoutAll, "all", false, "log out of all sessions")
+
+	unlockCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	resetPasswordCmd.Flags().StringVarP(&password, "password", "p", "", "new password")
 }
@@ -0,0 +1,266 @@
+package root
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	webhookURL      string
+	webhookName     string
+	deliveriesLimit int
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "manage outgoing chat webhooks",
+}
+
+var webhookCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create an outgoing webhook",
+	Long: `create an outgoing webhook: every new chat message is POSTed as signed JSON to the URL.
+	Only chat admins can manage webhooks.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" || webhookURL == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		webhook, err := client.CreateWebhook(chatID, webhookURL)
+		if err != nil {
+			cmd.Printf("Failed to create webhook: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Webhook created with ID: %s\n", webhook.GetWebhookId())
+		cmd.Printf("Signing secret (shown only once): %s\n", webhook.GetSecret())
+	},
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list chat webhooks",
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		webhooks, err := client.ListWebhooks(chatID)
+		if err != nil {
+			cmd.Printf("Failed to list webhooks: %v\n", err)
+			return
+		}
+
+		if len(webhooks) == 0 {
+			cmd.Println("No webhooks")
+			return
+		}
+
+		for _, webhook := range webhooks {
+			cmd.Printf("%s\t%s\t%s\n",
+				webhook.GetWebhookId(),
+				webhook.GetUrl(),
+				webhook.GetCreatedAt().AsTime().Local().Format(time.RFC1123),
+			)
+		}
+	},
+}
+
+var webhookDeleteCmd = &cobra.Command{
+	Use:   "delete <webhook-id>",
+	Short: "delete a webhook",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.DeleteWebhook(args[0]); err != nil {
+			cmd.Printf("Failed to delete webhook: %v\n", err)
+			return
+		}
+
+		cmd.Println("Webhook deleted")
+	},
+}
+
+var webhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries <webhook-id>",
+	Short: "show the webhook delivery log",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		deliveries, err := client.ListWebhookDeliveries(args[0], deliveriesLimit)
+		if err != nil {
+			cmd.Printf("Failed to get delivery log: %v\n", err)
+			return
+		}
+
+		if len(deliveries) == 0 {
+			cmd.Println("No deliveries yet")
+			return
+		}
+
+		for _, delivery := range deliveries {
+			result := "ok"
+			if !delivery.GetSuccess() {
+				result = "failed: " + delivery.GetError()
+			}
+
+			cmd.Printf("%s\tmessage %s\tattempt %d\tstatus %d\t%dms\t%s\n",
+				delivery.GetCreatedAt().AsTime().Local().Format(time.RFC1123),
+				delivery.GetMessageId(),
+				delivery.GetAttempt(),
+				delivery.GetStatusCode(),
+				delivery.GetDurationMs(),
+				result,
+			)
+		}
+	},
+}
+
+var webhookIncomingCmd = &cobra.Command{
+	Use:   "incoming",
+	Short: "manage incoming chat webhooks",
+	Long: `manage incoming webhooks: secret URLs that let scripts post messages to the chat
+	with a plain HTTP request, e.g. curl -d '{"text": "..."}' <url>.`,
+}
+
+var webhookIncomingCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create an incoming webhook",
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		webhook, err := client.CreateIncomingWebhook(chatID, webhookName)
+		if err != nil {
+			cmd.Printf("Failed to create incoming webhook: %v\n", err)
+			return
+		}
+
+		cmd.Printf("Incoming webhook created with ID: %s\n", webhook.GetIncomingWebhookId())
+		cmd.Printf("Messages are posted by bot account: %s\n", webhook.GetBotId())
+		cmd.Printf("Post messages to (shown only once): <chat-service HTTP address>%s\n", webhook.GetPath())
+	},
+}
+
+var webhookIncomingListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list incoming webhooks",
+	Run: func(cmd *cobra.Command, args []string) {
+		if chatID == "" {
+			cmd.Help()
+			return
+		}
+
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		webhooks, err := client.ListIncomingWebhooks(chatID)
+		if err != nil {
+			cmd.Printf("Failed to list incoming webhooks: %v\n", err)
+			return
+		}
+
+		if len(webhooks) == 0 {
+			cmd.Println("No incoming webhooks")
+			return
+		}
+
+		for _, webhook := range webhooks {
+			cmd.Printf("%s\t%s\t%s\t%s\n",
+				webhook.GetIncomingWebhookId(),
+				webhook.GetName(),
+				webhook.GetBotId(),
+				webhook.GetCreatedAt().AsTime().Local().Format(time.RFC1123),
+			)
+		}
+	},
+}
+
+var webhookIncomingRevokeCmd = &cobra.Command{
+	Use:   "revoke <webhook-id>",
+	Short: "revoke an incoming webhook",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client, ok := newChatClient(cmd)
+		if !ok {
+			return
+		}
+		defer client.Close()
+
+		if err := client.RevokeIncomingWebhook(args[0]); err != nil {
+			cmd.Printf("Failed to revoke incoming webhook: %v\n", err)
+			return
+		}
+
+		cmd.Println("Incoming webhook revoked")
+	},
+}
+
+func init() {
+	webhookCreateCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	webhookCreateCmd.Flags().StringVar(&webhookURL, "url", "", "URL that receives new messages")
+	webhookCreateCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	webhookListCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	webhookListCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	webhookDeleteCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	webhookDeliveriesCmd.Flags().IntVarP(&deliveriesLimit, "limit", "n", 20, "number of entries to show")
+	webhookDeliveriesCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	webhookIncomingCreateCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	webhookIncomingCreateCmd.Flags().StringVarP(&webhookName, "name", "n", "", "default sender name for posted messages")
+	webhookIncomingCreateCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	webhookIncomingListCmd.Flags().StringVarP(&chatID, "id", "i", "", "chat ID")
+	webhookIncomingListCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	webhookIncomingRevokeCmd.Flags().StringVarP(&token, "token", "t", "", "auth token")
+
+	webhookIncomingCmd.AddCommand(webhookIncomingCreateCmd)
+	webhookIncomingCmd.AddCommand(webhookIncomingListCmd)
+	webhookIncomingCmd.AddCommand(webhookIncomingRevokeCmd)
+
+	webhookCmd.AddCommand(webhookCreateCmd)
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookDeleteCmd)
+	webhookCmd.AddCommand(webhookDeliveriesCmd)
+	webhookCmd.AddCommand(webhookIncomingCmd)
+}
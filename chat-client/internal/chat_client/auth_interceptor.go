@@ -7,9 +7,28 @@ import (
 	"google.golang.org/grpc/metadata"
 )
 
+// TokenSource выдает токен доступа для очередного запроса
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken источник, всегда возвращающий один и тот же токен
+type StaticToken string
+
+// Token возвращает токен
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
 // AuthInterceptor создает новый перехватчик для добавления токена аутентификации
-func AuthInterceptor(token string) grpc.UnaryClientInterceptor {
+func AuthInterceptor(tokens TokenSource) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		// Токен запрашивается для каждого вызова, поэтому обновленный токен используется сразу
+		token, err := tokens.Token(ctx)
+		if err != nil {
+			return err
+		}
+
 		// Создаем новый контекст с метаданными, содержащими токен
 		md := metadata.New(map[string]string{
 			"authorization": "Bearer " + token,
@@ -22,8 +41,13 @@ func AuthInterceptor(token string) grpc.UnaryClientInterceptor {
 }
 
 // StreamAuthInterceptor создает новый перехватчик для добавления токена аутентификации в стримы
-func StreamAuthInterceptor(token string) grpc.StreamClientInterceptor {
+func StreamAuthInterceptor(tokens TokenSource) grpc.StreamClientInterceptor {
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		token, err := tokens.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+
 		// Создаем новый контекст с метаданными, содержащими токен
 		md := metadata.New(map[string]string{
 			"authorization": "Bearer " + token,
@@ -2,29 +2,38 @@ package chat_client
 
 import (
 	"context"
+	"io"
+	"time"
 
 	pb "chat.service/api/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type ChatClient struct {
 	chatClient pb.ChatServiceClient
 	conn       *grpc.ClientConn
-	token      string
+	tokens     TokenSource
 }
 
 func NewChatClient(chatServiceAddr string, token string) (*ChatClient, error) {
+	return NewChatClientWithTokenSource(chatServiceAddr, StaticToken(token))
+}
+
+// NewChatClientWithTokenSource создает клиент, берущий токен доступа из источника перед каждым вызовом
+func NewChatClientWithTokenSource(chatServiceAddr string, tokens TokenSource) (*ChatClient, error) {
 	// Создаем перехватчики для аутентификации
-	authInterceptor := AuthInterceptor(token)
-	streamAuthInterceptor := StreamAuthInterceptor(token)
+	authInterceptor := AuthInterceptor(tokens)
+	streamAuthInterceptor := StreamAuthInterceptor(tokens)
 
-	// Устанавливаем соединение с перехватчиками
+	// Устанавливаем соединение с перехватчиками. Повтор выполняется после аутентификации,
+	// поэтому каждая попытка отправляется с токеном
 	conn, err := grpc.NewClient(
 		chatServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(authInterceptor),
-		grpc.WithStreamInterceptor(streamAuthInterceptor),
+		grpc.WithChainUnaryInterceptor(authInterceptor, RetryInterceptor()),
+		grpc.WithChainStreamInterceptor(streamAuthInterceptor, StreamRetryInterceptor()),
 	)
 	if err != nil {
 		return nil, err
@@ -35,17 +44,24 @@ func NewChatClient(chatServiceAddr string, token string) (*ChatClient, error) {
 	return &ChatClient{
 		chatClient: chatClient,
 		conn:       conn,
-		token:      token,
+		tokens:     tokens,
 	}, nil
 }
 
 func (c *ChatClient) Close() error {
+	// Источник токенов может держать собственное соединение, например с сервисом аутентификации
+	if closer, ok := c.tokens.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
 	return c.conn.Close()
 }
 
-func (c *ChatClient) CreateChat(name string) (string, error) {
+func (c *ChatClient) CreateChat(name string, visibility pb.ChatVisibility, chatType pb.ChatType) (string, error) {
 	res, err := c.chatClient.CreateChat(context.Background(), &pb.CreateChatRequest{
-		Name: name,
+		Name:       name,
+		Visibility: visibility,
+		Type:       chatType,
 	})
 
 	if err != nil {
@@ -91,11 +107,355 @@ func (c *ChatClient) ProcessChatMessages(stream pb.ChatService_ConnectChatClient
 	}()
 }
 
-func (c *ChatClient) SendMessage(chatID, text string) error {
-	_, err := c.chatClient.SendMessage(context.Background(), &pb.SendMessageRequest{
+func (c *ChatClient) SendMessage(chatID, text string, format pb.MessageFormat) (*pb.SendMessageResponse, error) {
+	return c.chatClient.SendMessage(context.Background(), &pb.SendMessageRequest{
 		ChatId: chatID,
 		Text:   text,
+		Format: format,
+	})
+}
+
+// SearchPublicChats ищет публичные чаты по началу названия
+func (c *ChatClient) SearchPublicChats(query string, limit int) ([]*pb.ChatInfo, error) {
+	res, err := c.chatClient.SearchPublicChats(context.Background(), &pb.SearchPublicChatsRequest{
+		Query: query,
+		Limit: int32(limit),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetChats(), nil
+}
+
+// JoinChat вступает в публичный чат
+func (c *ChatClient) JoinChat(chatID string) (*pb.ChatInfo, error) {
+	res, err := c.chatClient.JoinChat(context.Background(), &pb.JoinChatRequest{
+		ChatId: chatID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetChat(), nil
+}
+
+// CreateInvite создает приглашение в чат и возвращает ответ сервера с кодом приглашения
+func (c *ChatClient) CreateInvite(chatID string, ttl time.Duration, maxUses int, role pb.ChatRole) (*pb.CreateInviteResponse, error) {
+	req := &pb.CreateInviteRequest{
+		ChatId:      chatID,
+		MaxUses:     int32(maxUses),
+		DefaultRole: role,
+	}
+	if ttl > 0 {
+		req.ExpiresAt = timestamppb.New(time.Now().Add(ttl))
+	}
+
+	return c.chatClient.CreateInvite(context.Background(), req)
+}
+
+// RevokeInvite отзывает приглашение в чат
+func (c *ChatClient) RevokeInvite(inviteID string) error {
+	_, err := c.chatClient.RevokeInvite(context.Background(), &pb.RevokeInviteRequest{
+		InviteId: inviteID,
 	})
 
 	return err
 }
+
+// JoinByInvite вступает в чат по коду приглашения и возвращает ID чата
+func (c *ChatClient) JoinByInvite(code string) (string, error) {
+	res, err := c.chatClient.JoinByInvite(context.Background(), &pb.JoinByInviteRequest{
+		Code: code,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return res.GetChatId(), nil
+}
+
+// UpdateChatSettings изменяет персональные настройки чата
+func (c *ChatClient) UpdateChatSettings(req *pb.UpdateChatSettingsRequest) (*pb.ChatSettings, error) {
+	return c.chatClient.UpdateChatSettings(context.Background(), req)
+}
+
+// GetUnreadCounters возвращает счетчики непрочитанных сообщений по всем чатам
+func (c *ChatClient) GetUnreadCounters() ([]*pb.UnreadCounter, error) {
+	res, err := c.chatClient.GetUnreadCounters(context.Background(), &pb.GetUnreadCountersRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetCounters(), nil
+}
+
+// StreamNotifications подписывается на уведомления по всем чатам пользователя
+func (c *ChatClient) StreamNotifications(ctx context.Context) (pb.ChatService_StreamNotificationsClient, error) {
+	return c.chatClient.StreamNotifications(ctx, &pb.StreamNotificationsRequest{})
+}
+
+// ScheduleMessage планирует отправку сообщения в чат в указанное время
+func (c *ChatClient) ScheduleMessage(chatID, text string, sendAt time.Time) (*pb.ScheduledMessage, error) {
+	return c.chatClient.ScheduleMessage(context.Background(), &pb.ScheduleMessageRequest{
+		ChatId: chatID,
+		Text:   text,
+		SendAt: timestamppb.New(sendAt),
+	})
+}
+
+// ListScheduledMessages возвращает ожидающие отправки сообщения, при пустом chatID - по всем чатам
+func (c *ChatClient) ListScheduledMessages(chatID string) ([]*pb.ScheduledMessage, error) {
+	res, err := c.chatClient.ListScheduledMessages(context.Background(), &pb.ListScheduledMessagesRequest{
+		ChatId: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetMessages(), nil
+}
+
+// CancelScheduledMessage отменяет отложенное сообщение
+func (c *ChatClient) CancelScheduledMessage(id string) error {
+	_, err := c.chatClient.CancelScheduledMessage(context.Background(), &pb.CancelScheduledMessageRequest{
+		ScheduledMessageId: id,
+	})
+
+	return err
+}
+
+// CreatePoll публикует в чате опрос. closesAt может быть nil для опроса без срока
+func (c *ChatClient) CreatePoll(chatID, question string, options []string, multipleChoice, anonymous bool, closesAt *time.Time) (*pb.ChatMessage, error) {
+	req := &pb.CreatePollRequest{
+		ChatId:         chatID,
+		Question:       question,
+		Options:        options,
+		MultipleChoice: multipleChoice,
+		Anonymous:      anonymous,
+	}
+	if closesAt != nil {
+		req.ClosesAt = timestamppb.New(*closesAt)
+	}
+
+	return c.chatClient.CreatePoll(context.Background(), req)
+}
+
+// Vote голосует в опросе за варианты с указанными индексами (начиная с 0)
+func (c *ChatClient) Vote(pollID string, optionIndexes []int32) (*pb.Poll, error) {
+	return c.chatClient.Vote(context.Background(), &pb.VoteRequest{
+		PollId:        pollID,
+		OptionIndexes: optionIndexes,
+	})
+}
+
+// ClosePoll досрочно закрывает опрос
+func (c *ChatClient) ClosePoll(pollID string) (*pb.Poll, error) {
+	return c.chatClient.ClosePoll(context.Background(), &pb.ClosePollRequest{
+		PollId: pollID,
+	})
+}
+
+// ForwardMessage пересылает сообщение в другой чат
+func (c *ChatClient) ForwardMessage(messageID, targetChatID string) (*pb.ChatMessage, error) {
+	return c.chatClient.ForwardMessage(context.Background(), &pb.ForwardMessageRequest{
+		SourceMessageId: messageID,
+		TargetChatId:    targetChatID,
+	})
+}
+
+// DeleteMessage удаляет сообщение
+func (c *ChatClient) DeleteMessage(messageID string) error {
+	_, err := c.chatClient.DeleteMessage(context.Background(), &pb.DeleteMessageRequest{
+		MessageId: messageID,
+	})
+	return err
+}
+
+// ListFlaggedMessages возвращает сообщения чата, ожидающие проверки модератором
+func (c *ChatClient) ListFlaggedMessages(chatID string) ([]*pb.FlaggedMessage, error) {
+	res, err := c.chatClient.ListFlaggedMessages(context.Background(), &pb.ListFlaggedMessagesRequest{
+		ChatId: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetMessages(), nil
+}
+
+// ApproveFlaggedMessage одобряет сообщение из очереди модерации
+func (c *ChatClient) ApproveFlaggedMessage(flaggedID string) (*pb.ChatMessage, error) {
+	return c.chatClient.ApproveFlaggedMessage(context.Background(), &pb.ApproveFlaggedMessageRequest{
+		FlaggedMessageId: flaggedID,
+	})
+}
+
+// RemoveFlaggedMessage удаляет сообщение из очереди модерации
+func (c *ChatClient) RemoveFlaggedMessage(flaggedID string) error {
+	_, err := c.chatClient.RemoveFlaggedMessage(context.Background(), &pb.RemoveFlaggedMessageRequest{
+		FlaggedMessageId: flaggedID,
+	})
+	return err
+}
+
+// ReportMessage отправляет жалобу на сообщение
+func (c *ChatClient) ReportMessage(messageID, reason string) (*pb.ReportMessageResponse, error) {
+	return c.chatClient.ReportMessage(context.Background(), &pb.ReportMessageRequest{
+		MessageId: messageID,
+		Reason:    reason,
+	})
+}
+
+// ListReports возвращает жалобы на сообщения чата
+func (c *ChatClient) ListReports(chatID string, limit int) ([]*pb.MessageReport, error) {
+	res, err := c.chatClient.ListReports(context.Background(), &pb.ListReportsRequest{
+		ChatId: chatID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetReports(), nil
+}
+
+// BanUser блокирует пользователя в чате
+func (c *ChatClient) BanUser(chatID, username, reason string) (*pb.ChatRestriction, error) {
+	return c.chatClient.BanUser(context.Background(), &pb.BanUserRequest{
+		ChatId:   chatID,
+		Username: username,
+		Reason:   reason,
+	})
+}
+
+// MuteUser временно запрещает пользователю писать в чат
+func (c *ChatClient) MuteUser(chatID, username, reason string, duration time.Duration) (*pb.ChatRestriction, error) {
+	return c.chatClient.MuteUser(context.Background(), &pb.MuteUserRequest{
+		ChatId:          chatID,
+		Username:        username,
+		Reason:          reason,
+		DurationSeconds: int64(duration / time.Second),
+	})
+}
+
+// LiftRestriction снимает блокировку или запрет писать
+func (c *ChatClient) LiftRestriction(chatID, username string, kind pb.RestrictionKind) error {
+	_, err := c.chatClient.LiftRestriction(context.Background(), &pb.LiftRestrictionRequest{
+		ChatId:   chatID,
+		Username: username,
+		Kind:     kind,
+	})
+	return err
+}
+
+// ListRestrictions возвращает действующие ограничения участников чата
+func (c *ChatClient) ListRestrictions(chatID string) ([]*pb.ChatRestriction, error) {
+	res, err := c.chatClient.ListRestrictions(context.Background(), &pb.ListRestrictionsRequest{
+		ChatId: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetRestrictions(), nil
+}
+
+// GetAuditLog возвращает журнал модерации чата
+func (c *ChatClient) GetAuditLog(chatID string, limit int) ([]*pb.AuditEntry, error) {
+	res, err := c.chatClient.GetAuditLog(context.Background(), &pb.GetAuditLogRequest{
+		ChatId: chatID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetEntries(), nil
+}
+
+// CreateWebhook создает исходящий вебхук чата
+func (c *ChatClient) CreateWebhook(chatID, url string) (*pb.Webhook, error) {
+	return c.chatClient.CreateWebhook(context.Background(), &pb.CreateWebhookRequest{
+		ChatId: chatID,
+		Url:    url,
+	})
+}
+
+// ListWebhooks возвращает вебхуки чата
+func (c *ChatClient) ListWebhooks(chatID string) ([]*pb.Webhook, error) {
+	res, err := c.chatClient.ListWebhooks(context.Background(), &pb.ListWebhooksRequest{
+		ChatId: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetWebhooks(), nil
+}
+
+// DeleteWebhook удаляет вебхук
+func (c *ChatClient) DeleteWebhook(webhookID string) error {
+	_, err := c.chatClient.DeleteWebhook(context.Background(), &pb.DeleteWebhookRequest{
+		WebhookId: webhookID,
+	})
+
+	return err
+}
+
+// ListWebhookDeliveries возвращает журнал доставки вебхука
+func (c *ChatClient) ListWebhookDeliveries(webhookID string, limit int) ([]*pb.WebhookDelivery, error) {
+	res, err := c.chatClient.ListWebhookDeliveries(context.Background(), &pb.ListWebhookDeliveriesRequest{
+		WebhookId: webhookID,
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetDeliveries(), nil
+}
+
+// CreateIncomingWebhook создает входящий вебхук чата
+func (c *ChatClient) CreateIncomingWebhook(chatID, name string) (*pb.IncomingWebhook, error) {
+	return c.chatClient.CreateIncomingWebhook(context.Background(), &pb.CreateIncomingWebhookRequest{
+		ChatId: chatID,
+		Name:   name,
+	})
+}
+
+// ListIncomingWebhooks возвращает действующие входящие вебхуки чата
+func (c *ChatClient) ListIncomingWebhooks(chatID string) ([]*pb.IncomingWebhook, error) {
+	res, err := c.chatClient.ListIncomingWebhooks(context.Background(), &pb.ListIncomingWebhooksRequest{
+		ChatId: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetWebhooks(), nil
+}
+
+// RevokeIncomingWebhook отзывает входящий вебхук
+func (c *ChatClient) RevokeIncomingWebhook(webhookID string) error {
+	_, err := c.chatClient.RevokeIncomingWebhook(context.Background(), &pb.RevokeIncomingWebhookRequest{
+		IncomingWebhookId: webhookID,
+	})
+
+	return err
+}
+
+// ListCommands возвращает slash-команды, доступные в чате
+func (c *ChatClient) ListCommands(chatID string) ([]*pb.CommandInfo, error) {
+	res, err := c.chatClient.ListCommands(context.Background(), &pb.ListCommandsRequest{
+		ChatId: chatID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetCommands(), nil
+}
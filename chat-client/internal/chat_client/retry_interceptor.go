@@ -0,0 +1,130 @@
+package chat_client
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// retryAfterKey ключ трейлера, в котором сервер передает время ожидания в секундах
+	retryAfterKey = "retry-after"
+	// maxRateLimitRetries сколько раз повторяется запрос, отклоненный ограничением частоты
+	maxRateLimitRetries = 3
+	// maxRetryWait максимальное время ожидания перед повтором
+	maxRetryWait = 30 * time.Second
+)
+
+// RetryInterceptor создает перехватчик, который повторяет запросы, отклоненные сервером
+// из-за превышения лимита запросов. Время ожидания берется из трейлера retry-after,
+// а если сервер его не передал, растет экспоненциально
+func RetryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		for attempt := 0; ; attempt++ {
+			var trailer metadata.MD
+			err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...)
+			if status.Code(err) != codes.ResourceExhausted || attempt == maxRateLimitRetries {
+				return err
+			}
+
+			if waitErr := waitRetry(ctx, trailer, attempt); waitErr != nil {
+				return err
+			}
+		}
+	}
+}
+
+// StreamRetryInterceptor создает перехватчик, который переоткрывает серверные стримы,
+// отклоненные ограничением частоты до получения первого сообщения
+func StreamRetryInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil || desc.ClientStreams {
+			return stream, err
+		}
+
+		return &retryClientStream{
+			ClientStream: stream,
+			ctx:          ctx,
+			open: func() (grpc.ClientStream, error) {
+				return streamer(ctx, desc, cc, method, opts...)
+			},
+		}, nil
+	}
+}
+
+// retryClientStream запоминает запрос серверного стрима, чтобы повторить его,
+// если сервер отклонил стрим из-за превышения лимита. Сгенерированный код отправляет запрос
+// серверного стрима до первого чтения, поэтому синхронизация не требуется
+type retryClientStream struct {
+	grpc.ClientStream
+	ctx      context.Context
+	open     func() (grpc.ClientStream, error)
+	request  interface{}
+	received bool
+}
+
+func (s *retryClientStream) SendMsg(m interface{}) error {
+	s.request = m
+
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *retryClientStream) RecvMsg(m interface{}) error {
+	for attempt := 0; ; attempt++ {
+		err := s.ClientStream.RecvMsg(m)
+		if err == nil {
+			s.received = true
+			return nil
+		}
+
+		if s.received || s.request == nil || status.Code(err) != codes.ResourceExhausted || attempt == maxRateLimitRetries {
+			return err
+		}
+
+		if waitErr := waitRetry(s.ctx, s.ClientStream.Trailer(), attempt); waitErr != nil {
+			return err
+		}
+
+		// Переоткрываем стрим с тем же запросом
+		stream, openErr := s.open()
+		if openErr != nil {
+			return openErr
+		}
+		if sendErr := stream.SendMsg(s.request); sendErr != nil {
+			return sendErr
+		}
+		if closeErr := stream.CloseSend(); closeErr != nil {
+			return closeErr
+		}
+		s.ClientStream = stream
+	}
+}
+
+// waitRetry ждет перед повтором запроса время из трейлера retry-after
+func waitRetry(ctx context.Context, trailer metadata.MD, attempt int) error {
+	wait := time.Second << attempt
+	if values := trailer.Get(retryAfterKey); len(values) > 0 {
+		if seconds, err := strconv.Atoi(values[0]); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+	}
+	if wait > maxRetryWait {
+		wait = maxRetryWait
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package user_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// refreshMargin за сколько до истечения токена доступа он обновляется заранее,
+// чтобы запрос не ушел с токеном, истекающим в пути
+const refreshMargin = time.Minute
+
+// Блокировка файла сессии на время обновления токенов
+const (
+	lockRetryInterval = 50 * time.Millisecond
+	// staleLockAge после этого времени блокировка считается брошенной завершившимся процессом
+	staleLockAge = 30 * time.Second
+)
+
+// ErrSessionExpired срок действия refresh-токена истек, нужно войти заново
+var ErrSessionExpired = errors.New("сессия истекла, выполните login")
+
+// Session токены сессии пользователя и сроки их действия
+type Session struct {
+	AccessToken           string    `json:"access_token"`
+	RefreshToken          string    `json:"refresh_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// SessionPath возвращает путь к файлу сохраненной сессии: CHATIK_SESSION_FILE
+// или chatik/session.json в пользовательском каталоге настроек
+func SessionPath() (string, error) {
+	if path, ok := os.LookupEnv("CHATIK_SESSION_FILE"); ok {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "chatik", "session.json"), nil
+}
+
+// LoadSession читает сессию из файла
+func LoadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &Session{}
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// SaveSession сохраняет сессию в файл, доступный только владельцу
+func SaveSession(path string, session *Session) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Пишем во временный файл и переименовываем, чтобы другие процессы не прочитали файл наполовину
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// lockSession блокирует файл сессии для других процессов клиента и возвращает функцию снятия блокировки.
+// Блокировкой служит файл рядом с сессией, созданный с O_EXCL
+func lockSession(ctx context.Context, path string) (func(), error) {
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o700); err != nil {
+		return nil, err
+	}
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lockPath)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// TokenSource выдает действующий токен доступа, при необходимости обновляя его refresh-токеном.
+// Обновленная сессия сохраняется в файл, так как прежний refresh-токен после обновления недействителен
+type TokenSource struct {
+	client  *UserClient
+	path    string
+	mu      sync.Mutex
+	session *Session
+}
+
+// NewTokenSource создает источник токенов для сессии. Если path пуст, сессия не сохраняется
+func NewTokenSource(client *UserClient, session *Session, path string) *TokenSource {
+	return &TokenSource{
+		client:  client,
+		path:    path,
+		session: session,
+	}
+}
+
+// Close закрывает клиент сервиса аутентификации, которым обновляется сессия
+func (s *TokenSource) Close() error {
+	return s.client.Close()
+}
+
+// Token возвращает токен доступа, обновляя сессию, если токен истек или скоро истечет.
+// Несколько процессов клиента делят один файл сессии, поэтому обновление выполняется под блокировкой
+// файла: если другой процесс уже обновил токены, используется сохраненная им сессия, иначе
+// повторное обновление прежним refresh-токеном отозвало бы всю сессию
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session.fresh() {
+		return s.session.AccessToken, nil
+	}
+
+	if s.path != "" {
+		unlock, err := lockSession(ctx, s.path)
+		if err != nil {
+			return "", err
+		}
+		defer unlock()
+
+		if saved, err := LoadSession(s.path); err == nil && saved.RefreshToken != s.session.RefreshToken {
+			s.session = saved
+			if s.session.fresh() {
+				return s.session.AccessToken, nil
+			}
+		}
+	}
+
+	if !s.session.RefreshTokenExpiresAt.IsZero() && time.Now().After(s.session.RefreshTokenExpiresAt) {
+		return "", ErrSessionExpired
+	}
+
+	session, err := s.client.Refresh(ctx, s.session.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	s.session = session
+
+	if s.path != "" {
+		if err := SaveSession(s.path, session); err != nil {
+			return "", err
+		}
+	}
+
+	return session.AccessToken, nil
+}
+
+// fresh сообщает, что токен доступа можно использовать без обновления.
+// Без известного срока действия токен используется как есть
+func (s *Session) fresh() bool {
+	return s.AccessTokenExpiresAt.IsZero() || time.Until(s.AccessTokenExpiresAt) > refreshMargin
+}
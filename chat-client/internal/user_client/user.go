@@ -2,16 +2,20 @@ package user_client
 
 import (
 	"context"
+	"time"
 
 	authpb "auth.service/api/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type UserClient struct {
 	userClient   authpb.UserServiceClient
 	authClient   authpb.AuthServiceClient
 	accessClient authpb.AccessServiceClient
+	botClient    authpb.BotServiceClient
 	conn         *grpc.ClientConn
 }
 
@@ -24,11 +28,13 @@ func NewUserClient(authServiceAddr string) (*UserClient, error) {
 	accessClient := authpb.NewAccessServiceClient(conn)
 	userClient := authpb.NewUserServiceClient(conn)
 	authClient := authpb.NewAuthServiceClient(conn)
+	botClient := authpb.NewBotServiceClient(conn)
 
 	return &UserClient{
 		userClient:   userClient,
 		authClient:   authClient,
 		accessClient: accessClient,
+		botClient:    botClient,
 		conn:         conn,
 	}, nil
 }
@@ -37,24 +43,224 @@ func (c *UserClient) Close() error {
 	return c.conn.Close()
 }
 
-func (c *UserClient) Register(username, password string) error {
+// Register регистрирует пользователя. Email необязателен, без него нельзя восстановить пароль;
+// он начинает действовать после ConfirmEmail
+func (c *UserClient) Register(username, password, email string) error {
 	_, err := c.userClient.CreateUser(context.Background(), &authpb.CreateUserRequest{
 		Username: username,
 		Password: password,
+		Email:    email,
 	})
 
 	return err
 }
 
-func (c *UserClient) Login(username, password string) (string, error) {
+// ConfirmEmail подтверждает email токеном из письма, до этого адрес не используется
+func (c *UserClient) ConfirmEmail(confirmationToken string) error {
+	_, err := c.userClient.ConfirmEmail(context.Background(), &authpb.ConfirmEmailRequest{
+		Token: confirmationToken,
+	})
+
+	return err
+}
+
+// RequestPasswordReset просит отправить токен сброса пароля на email. Сервер отвечает одинаково
+// для известных и неизвестных адресов
+func (c *UserClient) RequestPasswordReset(email string) error {
+	_, err := c.userClient.RequestPasswordReset(context.Background(), &authpb.RequestPasswordResetRequest{
+		Email: email,
+	})
+
+	return err
+}
+
+// ResetPassword задает новый пароль по токену из письма, все сессии пользователя завершаются
+func (c *UserClient) ResetPassword(resetToken, newPassword string) error {
+	_, err := c.userClient.ResetPassword(context.Background(), &authpb.ResetPasswordRequest{
+		Token:       resetToken,
+		NewPassword: newPassword,
+	})
+
+	return err
+}
+
+// LoginChallenge - запрос второго фактора при входе пользователя с включенной 2FA
+type LoginChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// Login входит в систему и возвращает токены новой сессии. Если у пользователя включена
+// двухфакторная аутентификация, вместо сессии возвращается запрос кода, вход завершает VerifySecondFactor
+func (c *UserClient) Login(username, password, deviceName string) (*Session, *LoginChallenge, error) {
 	res, err := c.authClient.Login(context.Background(), &authpb.LoginRequest{
-		Username: username,
-		Password: password,
+		Username:   username,
+		Password:   password,
+		DeviceName: deviceName,
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.SecondFactorRequired {
+		return nil, &LoginChallenge{
+			Token:     res.ChallengeToken,
+			ExpiresAt: timeOrZero(res.ChallengeExpiresAt),
+		}, nil
+	}
+
+	return sessionFromLogin(res), nil, nil
+}
+
+// VerifySecondFactor завершает вход кодом из приложения-аутентификатора или кодом восстановления
+func (c *UserClient) VerifySecondFactor(challengeToken, code string) (*Session, error) {
+	res, err := c.authClient.VerifySecondFactor(context.Background(), &authpb.VerifySecondFactorRequest{
+		ChallengeToken: challengeToken,
+		Code:           code,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sessionFromLogin(res), nil
+}
+
+func sessionFromLogin(res *authpb.LoginResponse) *Session {
+	return &Session{
+		AccessToken:           res.AccessToken,
+		RefreshToken:          res.RefreshToken,
+		AccessTokenExpiresAt:  timeOrZero(res.AccessTokenExpiresAt),
+		RefreshTokenExpiresAt: timeOrZero(res.RefreshTokenExpiresAt),
+	}
+}
+
+// EnrollTOTP начинает подключение двухфакторной аутентификации владельца токена
+func (c *UserClient) EnrollTOTP(token string) (*authpb.EnrollTOTPResponse, error) {
+	return c.userClient.EnrollTOTP(withToken(token), &authpb.EnrollTOTPRequest{})
+}
 
+// ConfirmTOTP включает двухфакторную аутентификацию и возвращает коды восстановления
+func (c *UserClient) ConfirmTOTP(token, code string) ([]string, error) {
+	res, err := c.userClient.ConfirmTOTP(withToken(token), &authpb.ConfirmTOTPRequest{
+		Code: code,
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return res.AccessToken, nil
+	return res.RecoveryCodes, nil
+}
+
+// DisableTOTP отключает двухфакторную аутентификацию
+func (c *UserClient) DisableTOTP(token, code string) error {
+	_, err := c.userClient.DisableTOTP(withToken(token), &authpb.DisableTOTPRequest{
+		Code: code,
+	})
+
+	return err
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов. Переданный refresh-токен
+// после этого недействителен, дальше нужно использовать токен из возвращенной сессии
+func (c *UserClient) Refresh(ctx context.Context, refreshToken string) (*Session, error) {
+	res, err := c.authClient.GetAccessToken(ctx, &authpb.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		AccessToken:           res.AccessToken,
+		RefreshToken:          res.RefreshToken,
+		AccessTokenExpiresAt:  timeOrZero(res.AccessTokenExpiresAt),
+		RefreshTokenExpiresAt: timeOrZero(res.RefreshTokenExpiresAt),
+	}, nil
+}
+
+// Logout завершает сессию refresh-токена. Если передан токен доступа, он отзывается
+func (c *UserClient) Logout(token, refreshToken string) error {
+	ctx := context.Background()
+	if token != "" {
+		ctx = withToken(token)
+	}
+
+	_, err := c.authClient.Logout(ctx, &authpb.LogoutRequest{
+		RefreshToken: refreshToken,
+	})
+
+	return err
+}
+
+// LogoutAll завершает все сессии владельца токена и отзывает сам токен
+func (c *UserClient) LogoutAll(token string) error {
+	_, err := c.authClient.LogoutAll(withToken(token), &authpb.LogoutAllRequest{})
+
+	return err
+}
+
+// timeOrZero преобразует время из ответа, отсутствующее время остается нулевым
+func timeOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return ts.AsTime()
+}
+
+// withToken добавляет токен доступа пользователя в метаданные запроса
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// ListSessions возвращает активные сессии владельца токена
+func (c *UserClient) ListSessions(token string) ([]*authpb.Session, error) {
+	res, err := c.authClient.ListSessions(withToken(token), &authpb.ListSessionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Sessions, nil
+}
+
+// RevokeSession завершает сессию владельца токена
+func (c *UserClient) RevokeSession(token, sessionID string) error {
+	_, err := c.authClient.RevokeSession(withToken(token), &authpb.RevokeSessionRequest{
+		SessionId: sessionID,
+	})
+
+	return err
+}
+
+// UnlockUser снимает блокировку входа после неудачных попыток, доступно только администраторам
+func (c *UserClient) UnlockUser(token, username string) error {
+	_, err := c.userClient.UnlockUser(withToken(token), &authpb.UnlockUserRequest{
+		Username: username,
+	})
+
+	return err
+}
+
+// CreateBot создает бота от имени владельца токена и возвращает его первый API-ключ
+func (c *UserClient) CreateBot(token, username string) (*authpb.CreateBotResponse, error) {
+	return c.botClient.CreateBot(withToken(token), &authpb.CreateBotRequest{
+		Username: username,
+	})
+}
+
+// CreateBotAPIKey выпускает дополнительный API-ключ бота
+func (c *UserClient) CreateBotAPIKey(token, botID string) (*authpb.BotAPIKeyResponse, error) {
+	return c.botClient.CreateBotAPIKey(withToken(token), &authpb.CreateBotAPIKeyRequest{
+		BotId: botID,
+	})
+}
+
+// RevokeBotAPIKey отзывает API-ключ бота
+func (c *UserClient) RevokeBotAPIKey(token, keyID string) error {
+	_, err := c.botClient.RevokeBotAPIKey(withToken(token), &authpb.RevokeBotAPIKeyRequest{
+		ApiKeyId: keyID,
+	})
+
+	return err
 }
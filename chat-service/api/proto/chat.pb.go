@@ -24,29 +24,5161 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Тип сообщения
+type MessageKind int32
+
+const (
+	MessageKind_MESSAGE_KIND_UNSPECIFIED MessageKind = 0
+	MessageKind_MESSAGE_KIND_TEXT        MessageKind = 1
+	MessageKind_MESSAGE_KIND_POLL        MessageKind = 2
+	MessageKind_MESSAGE_KIND_ACTION      MessageKind = 3 // Действие пользователя, команда /me
+	MessageKind_MESSAGE_KIND_SYSTEM      MessageKind = 4 // Служебное сообщение: смена темы, добавление и выход участников
+)
+
+// Enum value maps for MessageKind.
+var (
+	MessageKind_name = map[int32]string{
+		0: "MESSAGE_KIND_UNSPECIFIED",
+		1: "MESSAGE_KIND_TEXT",
+		2: "MESSAGE_KIND_POLL",
+		3: "MESSAGE_KIND_ACTION",
+		4: "MESSAGE_KIND_SYSTEM",
+	}
+	MessageKind_value = map[string]int32{
+		"MESSAGE_KIND_UNSPECIFIED": 0,
+		"MESSAGE_KIND_TEXT":        1,
+		"MESSAGE_KIND_POLL":        2,
+		"MESSAGE_KIND_ACTION":      3,
+		"MESSAGE_KIND_SYSTEM":      4,
+	}
+)
+
+func (x MessageKind) Enum() *MessageKind {
+	p := new(MessageKind)
+	*p = x
+	return p
+}
+
+func (x MessageKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[0].Descriptor()
+}
+
+func (MessageKind) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[0]
+}
+
+func (x MessageKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MessageKind.Descriptor instead.
+func (MessageKind) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{0}
+}
+
+// Событие, с которым сообщение пришло в стрим ConnectChat
+type MessageEvent int32
+
+const (
+	MessageEvent_MESSAGE_EVENT_UNSPECIFIED MessageEvent = 0
+	MessageEvent_MESSAGE_EVENT_NEW         MessageEvent = 1 // Новое сообщение (в том числе из истории)
+	MessageEvent_MESSAGE_EVENT_UPDATED     MessageEvent = 2 // Обновление ранее отправленного сообщения, например результатов опроса
+	MessageEvent_MESSAGE_EVENT_COMMAND     MessageEvent = 3 // Вызов slash-команды, принадлежащей боту; приходит только в стрим этого бота
+	MessageEvent_MESSAGE_EVENT_DELETED     MessageEvent = 4 // Сообщение удалено, приходит с тем же message_id и deleted = true
+)
+
+// Enum value maps for MessageEvent.
+var (
+	MessageEvent_name = map[int32]string{
+		0: "MESSAGE_EVENT_UNSPECIFIED",
+		1: "MESSAGE_EVENT_NEW",
+		2: "MESSAGE_EVENT_UPDATED",
+		3: "MESSAGE_EVENT_COMMAND",
+		4: "MESSAGE_EVENT_DELETED",
+	}
+	MessageEvent_value = map[string]int32{
+		"MESSAGE_EVENT_UNSPECIFIED": 0,
+		"MESSAGE_EVENT_NEW":         1,
+		"MESSAGE_EVENT_UPDATED":     2,
+		"MESSAGE_EVENT_COMMAND":     3,
+		"MESSAGE_EVENT_DELETED":     4,
+	}
+)
+
+func (x MessageEvent) Enum() *MessageEvent {
+	p := new(MessageEvent)
+	*p = x
+	return p
+}
+
+func (x MessageEvent) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageEvent) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[1].Descriptor()
+}
+
+func (MessageEvent) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[1]
+}
+
+func (x MessageEvent) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MessageEvent.Descriptor instead.
+func (MessageEvent) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{1}
+}
+
+// Формат текста отправляемого сообщения
+type MessageFormat int32
+
+const (
+	MessageFormat_MESSAGE_FORMAT_PLAIN    MessageFormat = 0 // Текст отправляется как есть
+	MessageFormat_MESSAGE_FORMAT_MARKDOWN MessageFormat = 1 // **жирный**, `код`, [ссылка](https://...), @упоминание; \ экранирует разметку
+)
+
+// Enum value maps for MessageFormat.
+var (
+	MessageFormat_name = map[int32]string{
+		0: "MESSAGE_FORMAT_PLAIN",
+		1: "MESSAGE_FORMAT_MARKDOWN",
+	}
+	MessageFormat_value = map[string]int32{
+		"MESSAGE_FORMAT_PLAIN":    0,
+		"MESSAGE_FORMAT_MARKDOWN": 1,
+	}
+)
+
+func (x MessageFormat) Enum() *MessageFormat {
+	p := new(MessageFormat)
+	*p = x
+	return p
+}
+
+func (x MessageFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[2].Descriptor()
+}
+
+func (MessageFormat) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[2]
+}
+
+func (x MessageFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MessageFormat.Descriptor instead.
+func (MessageFormat) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{2}
+}
+
+// Тип фрагмента разметки
+type MessageEntityType int32
+
+const (
+	MessageEntityType_MESSAGE_ENTITY_TYPE_UNSPECIFIED MessageEntityType = 0
+	MessageEntityType_MESSAGE_ENTITY_TYPE_BOLD        MessageEntityType = 1
+	MessageEntityType_MESSAGE_ENTITY_TYPE_CODE        MessageEntityType = 2
+	MessageEntityType_MESSAGE_ENTITY_TYPE_LINK        MessageEntityType = 3
+	MessageEntityType_MESSAGE_ENTITY_TYPE_MENTION     MessageEntityType = 4
+)
+
+// Enum value maps for MessageEntityType.
+var (
+	MessageEntityType_name = map[int32]string{
+		0: "MESSAGE_ENTITY_TYPE_UNSPECIFIED",
+		1: "MESSAGE_ENTITY_TYPE_BOLD",
+		2: "MESSAGE_ENTITY_TYPE_CODE",
+		3: "MESSAGE_ENTITY_TYPE_LINK",
+		4: "MESSAGE_ENTITY_TYPE_MENTION",
+	}
+	MessageEntityType_value = map[string]int32{
+		"MESSAGE_ENTITY_TYPE_UNSPECIFIED": 0,
+		"MESSAGE_ENTITY_TYPE_BOLD":        1,
+		"MESSAGE_ENTITY_TYPE_CODE":        2,
+		"MESSAGE_ENTITY_TYPE_LINK":        3,
+		"MESSAGE_ENTITY_TYPE_MENTION":     4,
+	}
+)
+
+func (x MessageEntityType) Enum() *MessageEntityType {
+	p := new(MessageEntityType)
+	*p = x
+	return p
+}
+
+func (x MessageEntityType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageEntityType) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[3].Descriptor()
+}
+
+func (MessageEntityType) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[3]
+}
+
+func (x MessageEntityType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MessageEntityType.Descriptor instead.
+func (MessageEntityType) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{3}
+}
+
+// Роль участника в чате
+type ChatRole int32
+
+const (
+	ChatRole_CHAT_ROLE_UNSPECIFIED ChatRole = 0
+	ChatRole_CHAT_ROLE_MEMBER      ChatRole = 1
+	ChatRole_CHAT_ROLE_ADMIN       ChatRole = 2
+)
+
+// Enum value maps for ChatRole.
+var (
+	ChatRole_name = map[int32]string{
+		0: "CHAT_ROLE_UNSPECIFIED",
+		1: "CHAT_ROLE_MEMBER",
+		2: "CHAT_ROLE_ADMIN",
+	}
+	ChatRole_value = map[string]int32{
+		"CHAT_ROLE_UNSPECIFIED": 0,
+		"CHAT_ROLE_MEMBER":      1,
+		"CHAT_ROLE_ADMIN":       2,
+	}
+)
+
+func (x ChatRole) Enum() *ChatRole {
+	p := new(ChatRole)
+	*p = x
+	return p
+}
+
+func (x ChatRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChatRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[4].Descriptor()
+}
+
+func (ChatRole) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[4]
+}
+
+func (x ChatRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChatRole.Descriptor instead.
+func (ChatRole) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{4}
+}
+
+// Видимость чата
+type ChatVisibility int32
+
+const (
+	ChatVisibility_CHAT_VISIBILITY_UNSPECIFIED ChatVisibility = 0
+	ChatVisibility_CHAT_VISIBILITY_PRIVATE     ChatVisibility = 1 // Вступить можно только по приглашению
+	ChatVisibility_CHAT_VISIBILITY_PUBLIC      ChatVisibility = 2 // Чат доступен в поиске и для самостоятельного вступления
+)
+
+// Enum value maps for ChatVisibility.
+var (
+	ChatVisibility_name = map[int32]string{
+		0: "CHAT_VISIBILITY_UNSPECIFIED",
+		1: "CHAT_VISIBILITY_PRIVATE",
+		2: "CHAT_VISIBILITY_PUBLIC",
+	}
+	ChatVisibility_value = map[string]int32{
+		"CHAT_VISIBILITY_UNSPECIFIED": 0,
+		"CHAT_VISIBILITY_PRIVATE":     1,
+		"CHAT_VISIBILITY_PUBLIC":      2,
+	}
+)
+
+func (x ChatVisibility) Enum() *ChatVisibility {
+	p := new(ChatVisibility)
+	*p = x
+	return p
+}
+
+func (x ChatVisibility) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChatVisibility) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[5].Descriptor()
+}
+
+func (ChatVisibility) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[5]
+}
+
+func (x ChatVisibility) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChatVisibility.Descriptor instead.
+func (ChatVisibility) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{5}
+}
+
+// Тип чата
+type ChatType int32
+
+const (
+	ChatType_CHAT_TYPE_UNSPECIFIED ChatType = 0
+	ChatType_CHAT_TYPE_GROUP       ChatType = 1 // Писать могут все участники
+	ChatType_CHAT_TYPE_CHANNEL     ChatType = 2 // Писать могут только администраторы, остальные читают
+)
+
+// Enum value maps for ChatType.
+var (
+	ChatType_name = map[int32]string{
+		0: "CHAT_TYPE_UNSPECIFIED",
+		1: "CHAT_TYPE_GROUP",
+		2: "CHAT_TYPE_CHANNEL",
+	}
+	ChatType_value = map[string]int32{
+		"CHAT_TYPE_UNSPECIFIED": 0,
+		"CHAT_TYPE_GROUP":       1,
+		"CHAT_TYPE_CHANNEL":     2,
+	}
+)
+
+func (x ChatType) Enum() *ChatType {
+	p := new(ChatType)
+	*p = x
+	return p
+}
+
+func (x ChatType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChatType) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[6].Descriptor()
+}
+
+func (ChatType) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[6]
+}
+
+func (x ChatType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChatType.Descriptor instead.
+func (ChatType) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{6}
+}
+
+// Уровень уведомлений о сообщениях чата
+type NotifyLevel int32
+
+const (
+	NotifyLevel_NOTIFY_LEVEL_UNSPECIFIED NotifyLevel = 0
+	NotifyLevel_NOTIFY_LEVEL_ALL         NotifyLevel = 1 // Уведомлять обо всех сообщениях
+	NotifyLevel_NOTIFY_LEVEL_MENTIONS    NotifyLevel = 2 // Уведомлять только об упоминаниях
+)
+
+// Enum value maps for NotifyLevel.
+var (
+	NotifyLevel_name = map[int32]string{
+		0: "NOTIFY_LEVEL_UNSPECIFIED",
+		1: "NOTIFY_LEVEL_ALL",
+		2: "NOTIFY_LEVEL_MENTIONS",
+	}
+	NotifyLevel_value = map[string]int32{
+		"NOTIFY_LEVEL_UNSPECIFIED": 0,
+		"NOTIFY_LEVEL_ALL":         1,
+		"NOTIFY_LEVEL_MENTIONS":    2,
+	}
+)
+
+func (x NotifyLevel) Enum() *NotifyLevel {
+	p := new(NotifyLevel)
+	*p = x
+	return p
+}
+
+func (x NotifyLevel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotifyLevel) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[7].Descriptor()
+}
+
+func (NotifyLevel) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[7]
+}
+
+func (x NotifyLevel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotifyLevel.Descriptor instead.
+func (NotifyLevel) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{7}
+}
+
+// Тип уведомления
+type NotificationKind int32
+
+const (
+	NotificationKind_NOTIFICATION_KIND_UNSPECIFIED NotificationKind = 0
+	NotificationKind_NOTIFICATION_KIND_MESSAGE     NotificationKind = 1 // Новое сообщение в чате
+	NotificationKind_NOTIFICATION_KIND_MENTION     NotificationKind = 2 // Сообщение с упоминанием пользователя
+	NotificationKind_NOTIFICATION_KIND_INVITE      NotificationKind = 3 // Пользователя добавили в чат
+)
+
+// Enum value maps for NotificationKind.
+var (
+	NotificationKind_name = map[int32]string{
+		0: "NOTIFICATION_KIND_UNSPECIFIED",
+		1: "NOTIFICATION_KIND_MESSAGE",
+		2: "NOTIFICATION_KIND_MENTION",
+		3: "NOTIFICATION_KIND_INVITE",
+	}
+	NotificationKind_value = map[string]int32{
+		"NOTIFICATION_KIND_UNSPECIFIED": 0,
+		"NOTIFICATION_KIND_MESSAGE":     1,
+		"NOTIFICATION_KIND_MENTION":     2,
+		"NOTIFICATION_KIND_INVITE":      3,
+	}
+)
+
+func (x NotificationKind) Enum() *NotificationKind {
+	p := new(NotificationKind)
+	*p = x
+	return p
+}
+
+func (x NotificationKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[8].Descriptor()
+}
+
+func (NotificationKind) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[8]
+}
+
+func (x NotificationKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationKind.Descriptor instead.
+func (NotificationKind) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{8}
+}
+
+// Вид ограничения участника чата
+type RestrictionKind int32
+
+const (
+	RestrictionKind_RESTRICTION_KIND_UNSPECIFIED RestrictionKind = 0
+	RestrictionKind_RESTRICTION_KIND_BAN         RestrictionKind = 1 // Пользователь исключен из чата и не может вступить снова
+	RestrictionKind_RESTRICTION_KIND_MUTE        RestrictionKind = 2 // Пользователь временно не может писать в чат
+)
+
+// Enum value maps for RestrictionKind.
+var (
+	RestrictionKind_name = map[int32]string{
+		0: "RESTRICTION_KIND_UNSPECIFIED",
+		1: "RESTRICTION_KIND_BAN",
+		2: "RESTRICTION_KIND_MUTE",
+	}
+	RestrictionKind_value = map[string]int32{
+		"RESTRICTION_KIND_UNSPECIFIED": 0,
+		"RESTRICTION_KIND_BAN":         1,
+		"RESTRICTION_KIND_MUTE":        2,
+	}
+)
+
+func (x RestrictionKind) Enum() *RestrictionKind {
+	p := new(RestrictionKind)
+	*p = x
+	return p
+}
+
+func (x RestrictionKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RestrictionKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_proto_enumTypes[9].Descriptor()
+}
+
+func (RestrictionKind) Type() protoreflect.EnumType {
+	return &file_chat_proto_enumTypes[9]
+}
+
+func (x RestrictionKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RestrictionKind.Descriptor instead.
+func (RestrictionKind) EnumDescriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{9}
+}
+
 type CreateChatRequest struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
-	Name               string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                                         // Необязательное имя чата
+	Name               string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                                         // Необязательное имя чата (обязательно для публичных чатов)
 	ParticipantUserIds []string               `protobuf:"bytes,2,rep,name=participant_user_ids,json=participantUserIds,proto3" json:"participant_user_ids,omitempty"` // ID других пользователей для добавления в чат
+	Visibility         ChatVisibility         `protobuf:"varint,3,opt,name=visibility,proto3,enum=chat.ChatVisibility" json:"visibility,omitempty"`                   // По умолчанию чат приватный
+	Type               ChatType               `protobuf:"varint,4,opt,name=type,proto3,enum=chat.ChatType" json:"type,omitempty"`                                     // По умолчанию обычный групповой чат
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
 
-func (x *CreateChatRequest) Reset() {
-	*x = CreateChatRequest{}
-	mi := &file_chat_proto_msgTypes[0]
+func (x *CreateChatRequest) Reset() {
+	*x = CreateChatRequest{}
+	mi := &file_chat_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatRequest) ProtoMessage() {}
+
+func (x *CreateChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatRequest.ProtoReflect.Descriptor instead.
+func (*CreateChatRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateChatRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateChatRequest) GetParticipantUserIds() []string {
+	if x != nil {
+		return x.ParticipantUserIds
+	}
+	return nil
+}
+
+func (x *CreateChatRequest) GetVisibility() ChatVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return ChatVisibility_CHAT_VISIBILITY_UNSPECIFIED
+}
+
+func (x *CreateChatRequest) GetType() ChatType {
+	if x != nil {
+		return x.Type
+	}
+	return ChatType_CHAT_TYPE_UNSPECIFIED
+}
+
+type CreateChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"` // ID созданного чата
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatResponse) Reset() {
+	*x = CreateChatResponse{}
+	mi := &file_chat_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatResponse) ProtoMessage() {}
+
+func (x *CreateChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatResponse.ProtoReflect.Descriptor instead.
+func (*CreateChatResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateChatResponse) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type ConnectChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"` // К какому чату подключиться
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConnectChatRequest) Reset() {
+	*x = ConnectChatRequest{}
+	mi := &file_chat_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectChatRequest) ProtoMessage() {}
+
+func (x *ConnectChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectChatRequest.ProtoReflect.Descriptor instead.
+func (*ConnectChatRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConnectChatRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+// Сообщение в чате (используется в стриме ConnectChat и для SendMessage)
+type ChatMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // ID отправителя
+	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`           // Имя отправителя (для удобства отображения)
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Event         MessageEvent           `protobuf:"varint,7,opt,name=event,proto3,enum=chat.MessageEvent" json:"event,omitempty"`
+	Kind          MessageKind            `protobuf:"varint,8,opt,name=kind,proto3,enum=chat.MessageKind" json:"kind,omitempty"`
+	Poll          *Poll                  `protobuf:"bytes,9,opt,name=poll,proto3" json:"poll,omitempty"`                                          // Заполнено для сообщений типа MESSAGE_KIND_POLL
+	Ephemeral     bool                   `protobuf:"varint,10,opt,name=ephemeral,proto3" json:"ephemeral,omitempty"`                              // Сообщение видно только получателю и не сохраняется в истории
+	Command       *CommandInvocation     `protobuf:"bytes,11,opt,name=command,proto3" json:"command,omitempty"`                                   // Заполнено для событий MESSAGE_EVENT_COMMAND
+	Entities      []*MessageEntity       `protobuf:"bytes,12,rep,name=entities,proto3" json:"entities,omitempty"`                                 // Разметка текста, заполнена для сообщений в формате markdown
+	Previews      []*LinkPreview         `protobuf:"bytes,13,rep,name=previews,proto3" json:"previews,omitempty"`                                 // Превью ссылок из текста; приходят отдельным событием MESSAGE_EVENT_UPDATED, когда готовы
+	ForwardedFrom *ForwardedFrom         `protobuf:"bytes,14,opt,name=forwarded_from,json=forwardedFrom,proto3" json:"forwarded_from,omitempty"`  // Заполнено для пересланных сообщений
+	Deleted       bool                   `protobuf:"varint,15,opt,name=deleted,proto3" json:"deleted,omitempty"`                                  // Сообщение удалено, текст не возвращается
+	PendingReview bool                   `protobuf:"varint,16,opt,name=pending_review,json=pendingReview,proto3" json:"pending_review,omitempty"` // Сообщение отмечено модерацией и будет опубликовано после одобрения администратором
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_chat_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChatMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetEvent() MessageEvent {
+	if x != nil {
+		return x.Event
+	}
+	return MessageEvent_MESSAGE_EVENT_UNSPECIFIED
+}
+
+func (x *ChatMessage) GetKind() MessageKind {
+	if x != nil {
+		return x.Kind
+	}
+	return MessageKind_MESSAGE_KIND_UNSPECIFIED
+}
+
+func (x *ChatMessage) GetPoll() *Poll {
+	if x != nil {
+		return x.Poll
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetEphemeral() bool {
+	if x != nil {
+		return x.Ephemeral
+	}
+	return false
+}
+
+func (x *ChatMessage) GetCommand() *CommandInvocation {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetEntities() []*MessageEntity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetPreviews() []*LinkPreview {
+	if x != nil {
+		return x.Previews
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetForwardedFrom() *ForwardedFrom {
+	if x != nil {
+		return x.ForwardedFrom
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *ChatMessage) GetPendingReview() bool {
+	if x != nil {
+		return x.PendingReview
+	}
+	return false
+}
+
+// Источник пересланного сообщения: исходные автор и чат
+type ForwardedFrom struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Deleted       bool                   `protobuf:"varint,5,opt,name=deleted,proto3" json:"deleted,omitempty"` // Исходное сообщение удалено, текст пересланного сообщения не возвращается
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForwardedFrom) Reset() {
+	*x = ForwardedFrom{}
+	mi := &file_chat_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardedFrom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardedFrom) ProtoMessage() {}
+
+func (x *ForwardedFrom) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardedFrom.ProtoReflect.Descriptor instead.
+func (*ForwardedFrom) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ForwardedFrom) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ForwardedFrom) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ForwardedFrom) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ForwardedFrom) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ForwardedFrom) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+// Превью ссылки из текста сообщения
+type LinkPreview struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ImageUrl      string                 `protobuf:"bytes,4,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkPreview) Reset() {
+	*x = LinkPreview{}
+	mi := &file_chat_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkPreview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkPreview) ProtoMessage() {}
+
+func (x *LinkPreview) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkPreview.ProtoReflect.Descriptor instead.
+func (*LinkPreview) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LinkPreview) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *LinkPreview) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *LinkPreview) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *LinkPreview) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+// Фрагмент разметки текста. Смещение и длина считаются в единицах UTF-16
+type MessageEntity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          MessageEntityType      `protobuf:"varint,1,opt,name=type,proto3,enum=chat.MessageEntityType" json:"type,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length        int32                  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"` // Адрес ссылки для MESSAGE_ENTITY_TYPE_LINK
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageEntity) Reset() {
+	*x = MessageEntity{}
+	mi := &file_chat_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageEntity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageEntity) ProtoMessage() {}
+
+func (x *MessageEntity) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageEntity.ProtoReflect.Descriptor instead.
+func (*MessageEntity) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MessageEntity) GetType() MessageEntityType {
+	if x != nil {
+		return x.Type
+	}
+	return MessageEntityType_MESSAGE_ENTITY_TYPE_UNSPECIFIED
+}
+
+func (x *MessageEntity) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *MessageEntity) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *MessageEntity) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// Вызов slash-команды бота
+type CommandInvocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InvocationId  string                 `protobuf:"bytes,1,opt,name=invocation_id,json=invocationId,proto3" json:"invocation_id,omitempty"` // Передается в RespondToCommand
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                     // Имя команды без "/"
+	Args          string                 `protobuf:"bytes,3,opt,name=args,proto3" json:"args,omitempty"`                                     // Текст после имени команды
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandInvocation) Reset() {
+	*x = CommandInvocation{}
+	mi := &file_chat_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandInvocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandInvocation) ProtoMessage() {}
+
+func (x *CommandInvocation) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandInvocation.ProtoReflect.Descriptor instead.
+func (*CommandInvocation) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CommandInvocation) GetInvocationId() string {
+	if x != nil {
+		return x.InvocationId
+	}
+	return ""
+}
+
+func (x *CommandInvocation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CommandInvocation) GetArgs() string {
+	if x != nil {
+		return x.Args
+	}
+	return ""
+}
+
+// Вариант ответа в опросе
+type PollOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // Номер варианта, начиная с 0
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Votes         int32                  `protobuf:"varint,3,opt,name=votes,proto3" json:"votes,omitempty"`
+	VoterIds      []string               `protobuf:"bytes,4,rep,name=voter_ids,json=voterIds,proto3" json:"voter_ids,omitempty"` // Не заполняется для анонимных опросов
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PollOption) Reset() {
+	*x = PollOption{}
+	mi := &file_chat_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PollOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PollOption) ProtoMessage() {}
+
+func (x *PollOption) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PollOption.ProtoReflect.Descriptor instead.
+func (*PollOption) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PollOption) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *PollOption) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *PollOption) GetVotes() int32 {
+	if x != nil {
+		return x.Votes
+	}
+	return 0
+}
+
+func (x *PollOption) GetVoterIds() []string {
+	if x != nil {
+		return x.VoterIds
+	}
+	return nil
+}
+
+// Опрос с текущими результатами
+type Poll struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PollId         string                 `protobuf:"bytes,1,opt,name=poll_id,json=pollId,proto3" json:"poll_id,omitempty"` // Совпадает с ID сообщения опроса
+	Question       string                 `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
+	Options        []*PollOption          `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty"`
+	MultipleChoice bool                   `protobuf:"varint,4,opt,name=multiple_choice,json=multipleChoice,proto3" json:"multiple_choice,omitempty"`
+	Anonymous      bool                   `protobuf:"varint,5,opt,name=anonymous,proto3" json:"anonymous,omitempty"`
+	ClosesAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=closes_at,json=closesAt,proto3" json:"closes_at,omitempty"` // Не заполнено для опросов без срока
+	Closed         bool                   `protobuf:"varint,7,opt,name=closed,proto3" json:"closed,omitempty"`
+	TotalVoters    int32                  `protobuf:"varint,8,opt,name=total_voters,json=totalVoters,proto3" json:"total_voters,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Poll) Reset() {
+	*x = Poll{}
+	mi := &file_chat_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Poll) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Poll) ProtoMessage() {}
+
+func (x *Poll) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Poll.ProtoReflect.Descriptor instead.
+func (*Poll) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Poll) GetPollId() string {
+	if x != nil {
+		return x.PollId
+	}
+	return ""
+}
+
+func (x *Poll) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *Poll) GetOptions() []*PollOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Poll) GetMultipleChoice() bool {
+	if x != nil {
+		return x.MultipleChoice
+	}
+	return false
+}
+
+func (x *Poll) GetAnonymous() bool {
+	if x != nil {
+		return x.Anonymous
+	}
+	return false
+}
+
+func (x *Poll) GetClosesAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosesAt
+	}
+	return nil
+}
+
+func (x *Poll) GetClosed() bool {
+	if x != nil {
+		return x.Closed
+	}
+	return false
+}
+
+func (x *Poll) GetTotalVoters() int32 {
+	if x != nil {
+		return x.TotalVoters
+	}
+	return 0
+}
+
+type CreatePollRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChatId         string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Question       string                 `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
+	Options        []string               `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty"`
+	MultipleChoice bool                   `protobuf:"varint,4,opt,name=multiple_choice,json=multipleChoice,proto3" json:"multiple_choice,omitempty"`
+	Anonymous      bool                   `protobuf:"varint,5,opt,name=anonymous,proto3" json:"anonymous,omitempty"`
+	ClosesAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=closes_at,json=closesAt,proto3" json:"closes_at,omitempty"` // Необязательное время автоматического закрытия
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreatePollRequest) Reset() {
+	*x = CreatePollRequest{}
+	mi := &file_chat_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePollRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePollRequest) ProtoMessage() {}
+
+func (x *CreatePollRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePollRequest.ProtoReflect.Descriptor instead.
+func (*CreatePollRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreatePollRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *CreatePollRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *CreatePollRequest) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *CreatePollRequest) GetMultipleChoice() bool {
+	if x != nil {
+		return x.MultipleChoice
+	}
+	return false
+}
+
+func (x *CreatePollRequest) GetAnonymous() bool {
+	if x != nil {
+		return x.Anonymous
+	}
+	return false
+}
+
+func (x *CreatePollRequest) GetClosesAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosesAt
+	}
+	return nil
+}
+
+type VoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PollId        string                 `protobuf:"bytes,1,opt,name=poll_id,json=pollId,proto3" json:"poll_id,omitempty"`
+	OptionIndexes []int32                `protobuf:"varint,2,rep,packed,name=option_indexes,json=optionIndexes,proto3" json:"option_indexes,omitempty"` // Для опросов с одним вариантом ответа - ровно один индекс
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoteRequest) Reset() {
+	*x = VoteRequest{}
+	mi := &file_chat_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteRequest) ProtoMessage() {}
+
+func (x *VoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteRequest.ProtoReflect.Descriptor instead.
+func (*VoteRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *VoteRequest) GetPollId() string {
+	if x != nil {
+		return x.PollId
+	}
+	return ""
+}
+
+func (x *VoteRequest) GetOptionIndexes() []int32 {
+	if x != nil {
+		return x.OptionIndexes
+	}
+	return nil
+}
+
+type ClosePollRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PollId        string                 `protobuf:"bytes,1,opt,name=poll_id,json=pollId,proto3" json:"poll_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClosePollRequest) Reset() {
+	*x = ClosePollRequest{}
+	mi := &file_chat_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClosePollRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClosePollRequest) ProtoMessage() {}
+
+func (x *ClosePollRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClosePollRequest.ProtoReflect.Descriptor instead.
+func (*ClosePollRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClosePollRequest) GetPollId() string {
+	if x != nil {
+		return x.PollId
+	}
+	return ""
+}
+
+type SendMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Format        MessageFormat          `protobuf:"varint,3,opt,name=format,proto3,enum=chat.MessageFormat" json:"format,omitempty"` // user_id отправителя будет взят из аутентификационного контекста (interceptor)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMessageRequest) Reset() {
+	*x = SendMessageRequest{}
+	mi := &file_chat_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageRequest) ProtoMessage() {}
+
+func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
+func (*SendMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SendMessageRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetFormat() MessageFormat {
+	if x != nil {
+		return x.Format
+	}
+	return MessageFormat_MESSAGE_FORMAT_PLAIN
+}
+
+type SendMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`              // ID отправленного сообщения
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                               // Время отправки на сервере
+	PendingReview bool                   `protobuf:"varint,3,opt,name=pending_review,json=pendingReview,proto3" json:"pending_review,omitempty"` // Сообщение отмечено модерацией и будет опубликовано после одобрения администратором
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMessageResponse) Reset() {
+	*x = SendMessageResponse{}
+	mi := &file_chat_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageResponse) ProtoMessage() {}
+
+func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendMessageResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SendMessageResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *SendMessageResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SendMessageResponse) GetPendingReview() bool {
+	if x != nil {
+		return x.PendingReview
+	}
+	return false
+}
+
+type ScheduleMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	SendAt        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"` // Время отправки, должно быть в будущем
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleMessageRequest) Reset() {
+	*x = ScheduleMessageRequest{}
+	mi := &file_chat_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMessageRequest) ProtoMessage() {}
+
+func (x *ScheduleMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMessageRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ScheduleMessageRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ScheduleMessageRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ScheduleMessageRequest) GetSendAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SendAt
+	}
+	return nil
+}
+
+// Отложенное сообщение
+type ScheduledMessage struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ScheduledMessageId string                 `protobuf:"bytes,1,opt,name=scheduled_message_id,json=scheduledMessageId,proto3" json:"scheduled_message_id,omitempty"`
+	ChatId             string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Text               string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	SendAt             *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ScheduledMessage) Reset() {
+	*x = ScheduledMessage{}
+	mi := &file_chat_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledMessage) ProtoMessage() {}
+
+func (x *ScheduledMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledMessage.ProtoReflect.Descriptor instead.
+func (*ScheduledMessage) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ScheduledMessage) GetScheduledMessageId() string {
+	if x != nil {
+		return x.ScheduledMessageId
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetSendAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SendAt
+	}
+	return nil
+}
+
+func (x *ScheduledMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListScheduledMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"` // Необязательный фильтр по чату
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledMessagesRequest) Reset() {
+	*x = ListScheduledMessagesRequest{}
+	mi := &file_chat_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledMessagesRequest) ProtoMessage() {}
+
+func (x *ListScheduledMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledMessagesRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListScheduledMessagesRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type ListScheduledMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ScheduledMessage    `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledMessagesResponse) Reset() {
+	*x = ListScheduledMessagesResponse{}
+	mi := &file_chat_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledMessagesResponse) ProtoMessage() {}
+
+func (x *ListScheduledMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledMessagesResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListScheduledMessagesResponse) GetMessages() []*ScheduledMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type CancelScheduledMessageRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ScheduledMessageId string                 `protobuf:"bytes,1,opt,name=scheduled_message_id,json=scheduledMessageId,proto3" json:"scheduled_message_id,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CancelScheduledMessageRequest) Reset() {
+	*x = CancelScheduledMessageRequest{}
+	mi := &file_chat_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledMessageRequest) ProtoMessage() {}
+
+func (x *CancelScheduledMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledMessageRequest.ProtoReflect.Descriptor instead.
+func (*CancelScheduledMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CancelScheduledMessageRequest) GetScheduledMessageId() string {
+	if x != nil {
+		return x.ScheduledMessageId
+	}
+	return ""
+}
+
+type CancelScheduledMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledMessageResponse) Reset() {
+	*x = CancelScheduledMessageResponse{}
+	mi := &file_chat_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledMessageResponse) ProtoMessage() {}
+
+func (x *CancelScheduledMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledMessageResponse.ProtoReflect.Descriptor instead.
+func (*CancelScheduledMessageResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{20}
+}
+
+// Краткая информация о чате
+type ChatInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Visibility    ChatVisibility         `protobuf:"varint,3,opt,name=visibility,proto3,enum=chat.ChatVisibility" json:"visibility,omitempty"`
+	Type          ChatType               `protobuf:"varint,4,opt,name=type,proto3,enum=chat.ChatType" json:"type,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Topic         string                 `protobuf:"bytes,6,opt,name=topic,proto3" json:"topic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatInfo) Reset() {
+	*x = ChatInfo{}
+	mi := &file_chat_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatInfo) ProtoMessage() {}
+
+func (x *ChatInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatInfo.ProtoReflect.Descriptor instead.
+func (*ChatInfo) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ChatInfo) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ChatInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChatInfo) GetVisibility() ChatVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return ChatVisibility_CHAT_VISIBILITY_UNSPECIFIED
+}
+
+func (x *ChatInfo) GetType() ChatType {
+	if x != nil {
+		return x.Type
+	}
+	return ChatType_CHAT_TYPE_UNSPECIFIED
+}
+
+func (x *ChatInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ChatInfo) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+type SearchPublicChatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`  // Начало названия чата
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // По умолчанию 20
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPublicChatsRequest) Reset() {
+	*x = SearchPublicChatsRequest{}
+	mi := &file_chat_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPublicChatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPublicChatsRequest) ProtoMessage() {}
+
+func (x *SearchPublicChatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPublicChatsRequest.ProtoReflect.Descriptor instead.
+func (*SearchPublicChatsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SearchPublicChatsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchPublicChatsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchPublicChatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chats         []*ChatInfo            `protobuf:"bytes,1,rep,name=chats,proto3" json:"chats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPublicChatsResponse) Reset() {
+	*x = SearchPublicChatsResponse{}
+	mi := &file_chat_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPublicChatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPublicChatsResponse) ProtoMessage() {}
+
+func (x *SearchPublicChatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPublicChatsResponse.ProtoReflect.Descriptor instead.
+func (*SearchPublicChatsResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SearchPublicChatsResponse) GetChats() []*ChatInfo {
+	if x != nil {
+		return x.Chats
+	}
+	return nil
+}
+
+type JoinChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinChatRequest) Reset() {
+	*x = JoinChatRequest{}
+	mi := &file_chat_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinChatRequest) ProtoMessage() {}
+
+func (x *JoinChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinChatRequest.ProtoReflect.Descriptor instead.
+func (*JoinChatRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *JoinChatRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type JoinChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chat          *ChatInfo              `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinChatResponse) Reset() {
+	*x = JoinChatResponse{}
+	mi := &file_chat_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinChatResponse) ProtoMessage() {}
+
+func (x *JoinChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinChatResponse.ProtoReflect.Descriptor instead.
+func (*JoinChatResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *JoinChatResponse) GetChat() *ChatInfo {
+	if x != nil {
+		return x.Chat
+	}
+	return nil
+}
+
+type UpdateChatSettingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MutedUntil    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=muted_until,json=mutedUntil,proto3" json:"muted_until,omitempty"`                           // Время в прошлом снимает заглушение, отсутствие - без изменений
+	NotifyLevel   NotifyLevel            `protobuf:"varint,3,opt,name=notify_level,json=notifyLevel,proto3,enum=chat.NotifyLevel" json:"notify_level,omitempty"` // UNSPECIFIED - без изменений
+	Hidden        *bool                  `protobuf:"varint,4,opt,name=hidden,proto3,oneof" json:"hidden,omitempty"`                                              // Отсутствие - без изменений
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateChatSettingsRequest) Reset() {
+	*x = UpdateChatSettingsRequest{}
+	mi := &file_chat_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateChatSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateChatSettingsRequest) ProtoMessage() {}
+
+func (x *UpdateChatSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateChatSettingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateChatSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *UpdateChatSettingsRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *UpdateChatSettingsRequest) GetMutedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MutedUntil
+	}
+	return nil
+}
+
+func (x *UpdateChatSettingsRequest) GetNotifyLevel() NotifyLevel {
+	if x != nil {
+		return x.NotifyLevel
+	}
+	return NotifyLevel_NOTIFY_LEVEL_UNSPECIFIED
+}
+
+func (x *UpdateChatSettingsRequest) GetHidden() bool {
+	if x != nil && x.Hidden != nil {
+		return *x.Hidden
+	}
+	return false
+}
+
+// Персональные настройки чата участника
+type ChatSettings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MutedUntil    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=muted_until,json=mutedUntil,proto3" json:"muted_until,omitempty"` // Не заполнено, если чат не заглушен
+	NotifyLevel   NotifyLevel            `protobuf:"varint,3,opt,name=notify_level,json=notifyLevel,proto3,enum=chat.NotifyLevel" json:"notify_level,omitempty"`
+	Hidden        bool                   `protobuf:"varint,4,opt,name=hidden,proto3" json:"hidden,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatSettings) Reset() {
+	*x = ChatSettings{}
+	mi := &file_chat_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatSettings) ProtoMessage() {}
+
+func (x *ChatSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatSettings.ProtoReflect.Descriptor instead.
+func (*ChatSettings) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ChatSettings) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ChatSettings) GetMutedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MutedUntil
+	}
+	return nil
+}
+
+func (x *ChatSettings) GetNotifyLevel() NotifyLevel {
+	if x != nil {
+		return x.NotifyLevel
+	}
+	return NotifyLevel_NOTIFY_LEVEL_UNSPECIFIED
+}
+
+func (x *ChatSettings) GetHidden() bool {
+	if x != nil {
+		return x.Hidden
+	}
+	return false
+}
+
+type GetUnreadCountersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUnreadCountersRequest) Reset() {
+	*x = GetUnreadCountersRequest{}
+	mi := &file_chat_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUnreadCountersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUnreadCountersRequest) ProtoMessage() {}
+
+func (x *GetUnreadCountersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUnreadCountersRequest.ProtoReflect.Descriptor instead.
+func (*GetUnreadCountersRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{28}
+}
+
+type UnreadCounter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UnreadCount   int32                  `protobuf:"varint,2,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	MentionCount  int32                  `protobuf:"varint,3,opt,name=mention_count,json=mentionCount,proto3" json:"mention_count,omitempty"`
+	Muted         bool                   `protobuf:"varint,4,opt,name=muted,proto3" json:"muted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnreadCounter) Reset() {
+	*x = UnreadCounter{}
+	mi := &file_chat_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnreadCounter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnreadCounter) ProtoMessage() {}
+
+func (x *UnreadCounter) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnreadCounter.ProtoReflect.Descriptor instead.
+func (*UnreadCounter) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UnreadCounter) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *UnreadCounter) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *UnreadCounter) GetMentionCount() int32 {
+	if x != nil {
+		return x.MentionCount
+	}
+	return 0
+}
+
+func (x *UnreadCounter) GetMuted() bool {
+	if x != nil {
+		return x.Muted
+	}
+	return false
+}
+
+type GetUnreadCountersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Counters      []*UnreadCounter       `protobuf:"bytes,1,rep,name=counters,proto3" json:"counters,omitempty"` // Скрытые чаты не возвращаются
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUnreadCountersResponse) Reset() {
+	*x = GetUnreadCountersResponse{}
+	mi := &file_chat_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUnreadCountersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUnreadCountersResponse) ProtoMessage() {}
+
+func (x *GetUnreadCountersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUnreadCountersResponse.ProtoReflect.Descriptor instead.
+func (*GetUnreadCountersResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetUnreadCountersResponse) GetCounters() []*UnreadCounter {
+	if x != nil {
+		return x.Counters
+	}
+	return nil
+}
+
+type MarkChatReadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkChatReadRequest) Reset() {
+	*x = MarkChatReadRequest{}
+	mi := &file_chat_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkChatReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkChatReadRequest) ProtoMessage() {}
+
+func (x *MarkChatReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkChatReadRequest.ProtoReflect.Descriptor instead.
+func (*MarkChatReadRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MarkChatReadRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type MarkChatReadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkChatReadResponse) Reset() {
+	*x = MarkChatReadResponse{}
+	mi := &file_chat_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkChatReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkChatReadResponse) ProtoMessage() {}
+
+func (x *MarkChatReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkChatReadResponse.ProtoReflect.Descriptor instead.
+func (*MarkChatReadResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{32}
+}
+
+type StreamNotificationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamNotificationsRequest) Reset() {
+	*x = StreamNotificationsRequest{}
+	mi := &file_chat_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamNotificationsRequest) ProtoMessage() {}
+
+func (x *StreamNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*StreamNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{33}
+}
+
+// Уведомление стрима StreamNotifications
+type Notification struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          NotificationKind       `protobuf:"varint,1,opt,name=kind,proto3,enum=chat.NotificationKind" json:"kind,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	ChatName      string                 `protobuf:"bytes,3,opt,name=chat_name,json=chatName,proto3" json:"chat_name,omitempty"`
+	Message       *ChatMessage           `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`                                  // Сообщение для MESSAGE и MENTION
+	ActorUsername string                 `protobuf:"bytes,5,opt,name=actor_username,json=actorUsername,proto3" json:"actor_username,omitempty"` // Кто отправил сообщение или добавил пользователя в чат
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Notification) Reset() {
+	*x = Notification{}
+	mi := &file_chat_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Notification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Notification) ProtoMessage() {}
+
+func (x *Notification) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Notification.ProtoReflect.Descriptor instead.
+func (*Notification) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Notification) GetKind() NotificationKind {
+	if x != nil {
+		return x.Kind
+	}
+	return NotificationKind_NOTIFICATION_KIND_UNSPECIFIED
+}
+
+func (x *Notification) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *Notification) GetChatName() string {
+	if x != nil {
+		return x.ChatName
+	}
+	return ""
+}
+
+func (x *Notification) GetMessage() *ChatMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *Notification) GetActorUsername() string {
+	if x != nil {
+		return x.ActorUsername
+	}
+	return ""
+}
+
+func (x *Notification) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateInviteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                           // Если не указано, используется срок по умолчанию
+	MaxUses       int32                  `protobuf:"varint,3,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`                                // 0 - без ограничения количества использований
+	DefaultRole   ChatRole               `protobuf:"varint,4,opt,name=default_role,json=defaultRole,proto3,enum=chat.ChatRole" json:"default_role,omitempty"` // Роль, выдаваемая вступившему (по умолчанию участник)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateInviteRequest) Reset() {
+	*x = CreateInviteRequest{}
+	mi := &file_chat_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateInviteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateInviteRequest) ProtoMessage() {}
+
+func (x *CreateInviteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateInviteRequest.ProtoReflect.Descriptor instead.
+func (*CreateInviteRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CreateInviteRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *CreateInviteRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CreateInviteRequest) GetMaxUses() int32 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *CreateInviteRequest) GetDefaultRole() ChatRole {
+	if x != nil {
+		return x.DefaultRole
+	}
+	return ChatRole_CHAT_ROLE_UNSPECIFIED
+}
+
+type CreateInviteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InviteId      string                 `protobuf:"bytes,1,opt,name=invite_id,json=inviteId,proto3" json:"invite_id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"` // Код приглашения, возвращается только один раз
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateInviteResponse) Reset() {
+	*x = CreateInviteResponse{}
+	mi := &file_chat_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateInviteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateInviteResponse) ProtoMessage() {}
+
+func (x *CreateInviteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateInviteResponse.ProtoReflect.Descriptor instead.
+func (*CreateInviteResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CreateInviteResponse) GetInviteId() string {
+	if x != nil {
+		return x.InviteId
+	}
+	return ""
+}
+
+func (x *CreateInviteResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CreateInviteResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type RevokeInviteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InviteId      string                 `protobuf:"bytes,1,opt,name=invite_id,json=inviteId,proto3" json:"invite_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeInviteRequest) Reset() {
+	*x = RevokeInviteRequest{}
+	mi := &file_chat_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeInviteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeInviteRequest) ProtoMessage() {}
+
+func (x *RevokeInviteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeInviteRequest.ProtoReflect.Descriptor instead.
+func (*RevokeInviteRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RevokeInviteRequest) GetInviteId() string {
+	if x != nil {
+		return x.InviteId
+	}
+	return ""
+}
+
+type RevokeInviteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeInviteResponse) Reset() {
+	*x = RevokeInviteResponse{}
+	mi := &file_chat_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeInviteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeInviteResponse) ProtoMessage() {}
+
+func (x *RevokeInviteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeInviteResponse.ProtoReflect.Descriptor instead.
+func (*RevokeInviteResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{38}
+}
+
+type JoinByInviteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinByInviteRequest) Reset() {
+	*x = JoinByInviteRequest{}
+	mi := &file_chat_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinByInviteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinByInviteRequest) ProtoMessage() {}
+
+func (x *JoinByInviteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinByInviteRequest.ProtoReflect.Descriptor instead.
+func (*JoinByInviteRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *JoinByInviteRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type JoinByInviteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Role          ChatRole               `protobuf:"varint,2,opt,name=role,proto3,enum=chat.ChatRole" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinByInviteResponse) Reset() {
+	*x = JoinByInviteResponse{}
+	mi := &file_chat_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinByInviteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinByInviteResponse) ProtoMessage() {}
+
+func (x *JoinByInviteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinByInviteResponse.ProtoReflect.Descriptor instead.
+func (*JoinByInviteResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *JoinByInviteResponse) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *JoinByInviteResponse) GetRole() ChatRole {
+	if x != nil {
+		return x.Role
+	}
+	return ChatRole_CHAT_ROLE_UNSPECIFIED
+}
+
+// Исходящий вебхук: каждое новое сообщение чата отправляется POST-запросом с подписанным JSON на url
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Secret        string                 `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"` // Секрет для проверки HMAC-подписи, возвращается только при создании вебхука
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_chat_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Webhook) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *Webhook) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"` // http:// или https:// адрес получателя
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_chat_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CreateWebhookRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_chat_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListWebhooksRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_chat_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_chat_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *DeleteWebhookRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+type DeleteWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_chat_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{46}
+}
+
+// Попытка доставки сообщения на вебхук
+type WebhookDelivery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId    string                 `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Attempt       int32                  `protobuf:"varint,4,opt,name=attempt,proto3" json:"attempt,omitempty"`                         // Номер попытки, начиная с 1
+	StatusCode    int32                  `protobuf:"varint,5,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"` // HTTP статус ответа, 0 если ответ не получен
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Success       bool                   `protobuf:"varint,7,opt,name=success,proto3" json:"success,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,8,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_chat_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *WebhookDelivery) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *WebhookDelivery) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListWebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // По умолчанию 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_chat_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"` // От новых к старым
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_chat_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+// Входящий вебхук: POST /hooks/<token> с JSON {"text": "...", "username": "...", "format": "markdown"}
+// публикует сообщение в чат от имени бот-аккаунта вебхука
+type IncomingWebhook struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IncomingWebhookId string                 `protobuf:"bytes,1,opt,name=incoming_webhook_id,json=incomingWebhookId,proto3" json:"incoming_webhook_id,omitempty"`
+	ChatId            string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Name              string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`   // Имя отправителя по умолчанию
+	Token             string                 `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"` // Секретный токен, возвращается только при создании
+	Path              string                 `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"`   // Путь HTTP эндпоинта с токеном, возвращается только при создании
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	BotId             string                 `protobuf:"bytes,7,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"` // Бот-аккаунт, от имени которого вебхук пишет в чат
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *IncomingWebhook) Reset() {
+	*x = IncomingWebhook{}
+	mi := &file_chat_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncomingWebhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncomingWebhook) ProtoMessage() {}
+
+func (x *IncomingWebhook) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncomingWebhook.ProtoReflect.Descriptor instead.
+func (*IncomingWebhook) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *IncomingWebhook) GetIncomingWebhookId() string {
+	if x != nil {
+		return x.IncomingWebhookId
+	}
+	return ""
+}
+
+func (x *IncomingWebhook) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *IncomingWebhook) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IncomingWebhook) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *IncomingWebhook) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *IncomingWebhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *IncomingWebhook) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+type CreateIncomingWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateIncomingWebhookRequest) Reset() {
+	*x = CreateIncomingWebhookRequest{}
+	mi := &file_chat_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateIncomingWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateIncomingWebhookRequest) ProtoMessage() {}
+
+func (x *CreateIncomingWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateIncomingWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateIncomingWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *CreateIncomingWebhookRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *CreateIncomingWebhookRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ListIncomingWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIncomingWebhooksRequest) Reset() {
+	*x = ListIncomingWebhooksRequest{}
+	mi := &file_chat_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncomingWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncomingWebhooksRequest) ProtoMessage() {}
+
+func (x *ListIncomingWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncomingWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListIncomingWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListIncomingWebhooksRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type ListIncomingWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*IncomingWebhook     `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIncomingWebhooksResponse) Reset() {
+	*x = ListIncomingWebhooksResponse{}
+	mi := &file_chat_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncomingWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncomingWebhooksResponse) ProtoMessage() {}
+
+func (x *ListIncomingWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncomingWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListIncomingWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListIncomingWebhooksResponse) GetWebhooks() []*IncomingWebhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type RevokeIncomingWebhookRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IncomingWebhookId string                 `protobuf:"bytes,1,opt,name=incoming_webhook_id,json=incomingWebhookId,proto3" json:"incoming_webhook_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RevokeIncomingWebhookRequest) Reset() {
+	*x = RevokeIncomingWebhookRequest{}
+	mi := &file_chat_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeIncomingWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeIncomingWebhookRequest) ProtoMessage() {}
+
+func (x *RevokeIncomingWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeIncomingWebhookRequest.ProtoReflect.Descriptor instead.
+func (*RevokeIncomingWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *RevokeIncomingWebhookRequest) GetIncomingWebhookId() string {
+	if x != nil {
+		return x.IncomingWebhookId
+	}
+	return ""
+}
+
+type RevokeIncomingWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeIncomingWebhookResponse) Reset() {
+	*x = RevokeIncomingWebhookResponse{}
+	mi := &file_chat_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeIncomingWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeIncomingWebhookResponse) ProtoMessage() {}
+
+func (x *RevokeIncomingWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeIncomingWebhookResponse.ProtoReflect.Descriptor instead.
+func (*RevokeIncomingWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{55}
+}
+
+type RegisterCommandRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // Латинские буквы в нижнем регистре, цифры и "_", без "/"
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterCommandRequest) Reset() {
+	*x = RegisterCommandRequest{}
+	mi := &file_chat_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterCommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterCommandRequest) ProtoMessage() {}
+
+func (x *RegisterCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterCommandRequest.ProtoReflect.Descriptor instead.
+func (*RegisterCommandRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *RegisterCommandRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *RegisterCommandRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisterCommandRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type RegisterCommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterCommandResponse) Reset() {
+	*x = RegisterCommandResponse{}
+	mi := &file_chat_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterCommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterCommandResponse) ProtoMessage() {}
+
+func (x *RegisterCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterCommandResponse.ProtoReflect.Descriptor instead.
+func (*RegisterCommandResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{57}
+}
+
+type UnregisterCommandRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterCommandRequest) Reset() {
+	*x = UnregisterCommandRequest{}
+	mi := &file_chat_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterCommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterCommandRequest) ProtoMessage() {}
+
+func (x *UnregisterCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterCommandRequest.ProtoReflect.Descriptor instead.
+func (*UnregisterCommandRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *UnregisterCommandRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *UnregisterCommandRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type UnregisterCommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterCommandResponse) Reset() {
+	*x = UnregisterCommandResponse{}
+	mi := &file_chat_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterCommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterCommandResponse) ProtoMessage() {}
+
+func (x *UnregisterCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterCommandResponse.ProtoReflect.Descriptor instead.
+func (*UnregisterCommandResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{59}
+}
+
+type ListCommandsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommandsRequest) Reset() {
+	*x = ListCommandsRequest{}
+	mi := &file_chat_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommandsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommandsRequest) ProtoMessage() {}
+
+func (x *ListCommandsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommandsRequest.ProtoReflect.Descriptor instead.
+func (*ListCommandsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ListCommandsRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type CommandInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Usage         string                 `protobuf:"bytes,2,opt,name=usage,proto3" json:"usage,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	BotId         string                 `protobuf:"bytes,4,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"` // Пусто для встроенных команд
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandInfo) Reset() {
+	*x = CommandInfo{}
+	mi := &file_chat_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandInfo) ProtoMessage() {}
+
+func (x *CommandInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandInfo.ProtoReflect.Descriptor instead.
+func (*CommandInfo) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *CommandInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CommandInfo) GetUsage() string {
+	if x != nil {
+		return x.Usage
+	}
+	return ""
+}
+
+func (x *CommandInfo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CommandInfo) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+type ListCommandsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commands      []*CommandInfo         `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommandsResponse) Reset() {
+	*x = ListCommandsResponse{}
+	mi := &file_chat_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommandsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommandsResponse) ProtoMessage() {}
+
+func (x *ListCommandsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommandsResponse.ProtoReflect.Descriptor instead.
+func (*ListCommandsResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ListCommandsResponse) GetCommands() []*CommandInfo {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+type RespondToCommandRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InvocationId  string                 `protobuf:"bytes,1,opt,name=invocation_id,json=invocationId,proto3" json:"invocation_id,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Ephemeral     bool                   `protobuf:"varint,3,opt,name=ephemeral,proto3" json:"ephemeral,omitempty"` // true - ответ увидит только вызвавший команду пользователь
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondToCommandRequest) Reset() {
+	*x = RespondToCommandRequest{}
+	mi := &file_chat_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondToCommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondToCommandRequest) ProtoMessage() {}
+
+func (x *RespondToCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondToCommandRequest.ProtoReflect.Descriptor instead.
+func (*RespondToCommandRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *RespondToCommandRequest) GetInvocationId() string {
+	if x != nil {
+		return x.InvocationId
+	}
+	return ""
+}
+
+func (x *RespondToCommandRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *RespondToCommandRequest) GetEphemeral() bool {
+	if x != nil {
+		return x.Ephemeral
+	}
+	return false
+}
+
+type RespondToCommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`              // Пусто для эфемерных ответов
+	PendingReview bool                   `protobuf:"varint,2,opt,name=pending_review,json=pendingReview,proto3" json:"pending_review,omitempty"` // Ответ отмечен модерацией и появится в чате после одобрения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondToCommandResponse) Reset() {
+	*x = RespondToCommandResponse{}
+	mi := &file_chat_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondToCommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondToCommandResponse) ProtoMessage() {}
+
+func (x *RespondToCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondToCommandResponse.ProtoReflect.Descriptor instead.
+func (*RespondToCommandResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *RespondToCommandResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *RespondToCommandResponse) GetPendingReview() bool {
+	if x != nil {
+		return x.PendingReview
+	}
+	return false
+}
+
+type ForwardMessageRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SourceMessageId string                 `protobuf:"bytes,1,opt,name=source_message_id,json=sourceMessageId,proto3" json:"source_message_id,omitempty"`
+	TargetChatId    string                 `protobuf:"bytes,2,opt,name=target_chat_id,json=targetChatId,proto3" json:"target_chat_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ForwardMessageRequest) Reset() {
+	*x = ForwardMessageRequest{}
+	mi := &file_chat_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardMessageRequest) ProtoMessage() {}
+
+func (x *ForwardMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardMessageRequest.ProtoReflect.Descriptor instead.
+func (*ForwardMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ForwardMessageRequest) GetSourceMessageId() string {
+	if x != nil {
+		return x.SourceMessageId
+	}
+	return ""
+}
+
+func (x *ForwardMessageRequest) GetTargetChatId() string {
+	if x != nil {
+		return x.TargetChatId
+	}
+	return ""
+}
+
+type DeleteMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMessageRequest) Reset() {
+	*x = DeleteMessageRequest{}
+	mi := &file_chat_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessageRequest) ProtoMessage() {}
+
+func (x *DeleteMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMessageRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *DeleteMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type DeleteMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMessageResponse) Reset() {
+	*x = DeleteMessageResponse{}
+	mi := &file_chat_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessageResponse) ProtoMessage() {}
+
+func (x *DeleteMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMessageResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMessageResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{67}
+}
+
+// Сообщение, отмеченное фильтрами модерации
+type FlaggedMessage struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FlaggedMessageId string                 `protobuf:"bytes,1,opt,name=flagged_message_id,json=flaggedMessageId,proto3" json:"flagged_message_id,omitempty"`
+	ChatId           string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId           string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username         string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Text             string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	Reason           string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"` // Причины, по которым фильтры отметили сообщение
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ForwardedFrom    *ForwardedFrom         `protobuf:"bytes,8,opt,name=forwarded_from,json=forwardedFrom,proto3" json:"forwarded_from,omitempty"` // Заполнено для пересланных сообщений
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *FlaggedMessage) Reset() {
+	*x = FlaggedMessage{}
+	mi := &file_chat_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlaggedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlaggedMessage) ProtoMessage() {}
+
+func (x *FlaggedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlaggedMessage.ProtoReflect.Descriptor instead.
+func (*FlaggedMessage) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *FlaggedMessage) GetFlaggedMessageId() string {
+	if x != nil {
+		return x.FlaggedMessageId
+	}
+	return ""
+}
+
+func (x *FlaggedMessage) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *FlaggedMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *FlaggedMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *FlaggedMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *FlaggedMessage) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FlaggedMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *FlaggedMessage) GetForwardedFrom() *ForwardedFrom {
+	if x != nil {
+		return x.ForwardedFrom
+	}
+	return nil
+}
+
+type ListFlaggedMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFlaggedMessagesRequest) Reset() {
+	*x = ListFlaggedMessagesRequest{}
+	mi := &file_chat_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFlaggedMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFlaggedMessagesRequest) ProtoMessage() {}
+
+func (x *ListFlaggedMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFlaggedMessagesRequest.ProtoReflect.Descriptor instead.
+func (*ListFlaggedMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ListFlaggedMessagesRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type ListFlaggedMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*FlaggedMessage      `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFlaggedMessagesResponse) Reset() {
+	*x = ListFlaggedMessagesResponse{}
+	mi := &file_chat_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFlaggedMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFlaggedMessagesResponse) ProtoMessage() {}
+
+func (x *ListFlaggedMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFlaggedMessagesResponse.ProtoReflect.Descriptor instead.
+func (*ListFlaggedMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ListFlaggedMessagesResponse) GetMessages() []*FlaggedMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type ApproveFlaggedMessageRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FlaggedMessageId string                 `protobuf:"bytes,1,opt,name=flagged_message_id,json=flaggedMessageId,proto3" json:"flagged_message_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ApproveFlaggedMessageRequest) Reset() {
+	*x = ApproveFlaggedMessageRequest{}
+	mi := &file_chat_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveFlaggedMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveFlaggedMessageRequest) ProtoMessage() {}
+
+func (x *ApproveFlaggedMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveFlaggedMessageRequest.ProtoReflect.Descriptor instead.
+func (*ApproveFlaggedMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ApproveFlaggedMessageRequest) GetFlaggedMessageId() string {
+	if x != nil {
+		return x.FlaggedMessageId
+	}
+	return ""
+}
+
+type RemoveFlaggedMessageRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FlaggedMessageId string                 `protobuf:"bytes,1,opt,name=flagged_message_id,json=flaggedMessageId,proto3" json:"flagged_message_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RemoveFlaggedMessageRequest) Reset() {
+	*x = RemoveFlaggedMessageRequest{}
+	mi := &file_chat_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveFlaggedMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveFlaggedMessageRequest) ProtoMessage() {}
+
+func (x *RemoveFlaggedMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveFlaggedMessageRequest.ProtoReflect.Descriptor instead.
+func (*RemoveFlaggedMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *RemoveFlaggedMessageRequest) GetFlaggedMessageId() string {
+	if x != nil {
+		return x.FlaggedMessageId
+	}
+	return ""
+}
+
+type RemoveFlaggedMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveFlaggedMessageResponse) Reset() {
+	*x = RemoveFlaggedMessageResponse{}
+	mi := &file_chat_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveFlaggedMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveFlaggedMessageResponse) ProtoMessage() {}
+
+func (x *RemoveFlaggedMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveFlaggedMessageResponse.ProtoReflect.Descriptor instead.
+func (*RemoveFlaggedMessageResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{73}
+}
+
+type ChatRestriction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RestrictionId string                 `protobuf:"bytes,1,opt,name=restriction_id,json=restrictionId,proto3" json:"restriction_id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Kind          RestrictionKind        `protobuf:"varint,5,opt,name=kind,proto3,enum=chat.RestrictionKind" json:"kind,omitempty"`
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"` // ID администратора, установившего ограничение
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Не задано для бессрочных ограничений
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatRestriction) Reset() {
+	*x = ChatRestriction{}
+	mi := &file_chat_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatRestriction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatRestriction) ProtoMessage() {}
+
+func (x *ChatRestriction) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatRestriction.ProtoReflect.Descriptor instead.
+func (*ChatRestriction) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ChatRestriction) GetRestrictionId() string {
+	if x != nil {
+		return x.RestrictionId
+	}
+	return ""
+}
+
+func (x *ChatRestriction) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ChatRestriction) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ChatRestriction) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ChatRestriction) GetKind() RestrictionKind {
+	if x != nil {
+		return x.Kind
+	}
+	return RestrictionKind_RESTRICTION_KIND_UNSPECIFIED
+}
+
+func (x *ChatRestriction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ChatRestriction) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *ChatRestriction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ChatRestriction) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type ReportMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportMessageRequest) Reset() {
+	*x = ReportMessageRequest{}
+	mi := &file_chat_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportMessageRequest) ProtoMessage() {}
+
+func (x *ReportMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportMessageRequest.ProtoReflect.Descriptor instead.
+func (*ReportMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ReportMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ReportMessageRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ReportMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReportId      string                 `protobuf:"bytes,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportMessageResponse) Reset() {
+	*x = ReportMessageResponse{}
+	mi := &file_chat_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportMessageResponse) ProtoMessage() {}
+
+func (x *ReportMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportMessageResponse.ProtoReflect.Descriptor instead.
+func (*ReportMessageResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ReportMessageResponse) GetReportId() string {
+	if x != nil {
+		return x.ReportId
+	}
+	return ""
+}
+
+type MessageReport struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ReportId       string                 `protobuf:"bytes,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	ChatId         string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MessageId      string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ReporterId     string                 `protobuf:"bytes,4,opt,name=reporter_id,json=reporterId,proto3" json:"reporter_id,omitempty"`
+	Reason         string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	AuthorId       string                 `protobuf:"bytes,7,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	AuthorUsername string                 `protobuf:"bytes,8,opt,name=author_username,json=authorUsername,proto3" json:"author_username,omitempty"`
+	MessageText    string                 `protobuf:"bytes,9,opt,name=message_text,json=messageText,proto3" json:"message_text,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MessageReport) Reset() {
+	*x = MessageReport{}
+	mi := &file_chat_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageReport) ProtoMessage() {}
+
+func (x *MessageReport) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageReport.ProtoReflect.Descriptor instead.
+func (*MessageReport) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *MessageReport) GetReportId() string {
+	if x != nil {
+		return x.ReportId
+	}
+	return ""
+}
+
+func (x *MessageReport) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *MessageReport) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *MessageReport) GetReporterId() string {
+	if x != nil {
+		return x.ReporterId
+	}
+	return ""
+}
+
+func (x *MessageReport) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MessageReport) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *MessageReport) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *MessageReport) GetAuthorUsername() string {
+	if x != nil {
+		return x.AuthorUsername
+	}
+	return ""
+}
+
+func (x *MessageReport) GetMessageText() string {
+	if x != nil {
+		return x.MessageText
+	}
+	return ""
+}
+
+type ListReportsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // По умолчанию 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsRequest) Reset() {
+	*x = ListReportsRequest{}
+	mi := &file_chat_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsRequest) ProtoMessage() {}
+
+func (x *ListReportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsRequest.ProtoReflect.Descriptor instead.
+func (*ListReportsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *ListReportsRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ListReportsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListReportsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reports       []*MessageReport       `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsResponse) Reset() {
+	*x = ListReportsResponse{}
+	mi := &file_chat_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsResponse) ProtoMessage() {}
+
+func (x *ListReportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsResponse.ProtoReflect.Descriptor instead.
+func (*ListReportsResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *ListReportsResponse) GetReports() []*MessageReport {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+type BanUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BanUserRequest) Reset() {
+	*x = BanUserRequest{}
+	mi := &file_chat_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BanUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BanUserRequest) ProtoMessage() {}
+
+func (x *BanUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BanUserRequest.ProtoReflect.Descriptor instead.
+func (*BanUserRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *BanUserRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *BanUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *BanUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type MuteUserRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ChatId          string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Username        string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Reason          string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	DurationSeconds int64                  `protobuf:"varint,4,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"` // Не больше 30 дней
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MuteUserRequest) Reset() {
+	*x = MuteUserRequest{}
+	mi := &file_chat_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatRequest) String() string {
+func (x *MuteUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatRequest) ProtoMessage() {}
+func (*MuteUserRequest) ProtoMessage() {}
 
-func (x *CreateChatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_proto_msgTypes[0]
+func (x *MuteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,47 +5189,63 @@ func (x *CreateChatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatRequest.ProtoReflect.Descriptor instead.
-func (*CreateChatRequest) Descriptor() ([]byte, []int) {
-	return file_chat_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use MuteUserRequest.ProtoReflect.Descriptor instead.
+func (*MuteUserRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{81}
 }
 
-func (x *CreateChatRequest) GetName() string {
+func (x *MuteUserRequest) GetChatId() string {
 	if x != nil {
-		return x.Name
+		return x.ChatId
 	}
 	return ""
 }
 
-func (x *CreateChatRequest) GetParticipantUserIds() []string {
+func (x *MuteUserRequest) GetUsername() string {
 	if x != nil {
-		return x.ParticipantUserIds
+		return x.Username
 	}
-	return nil
+	return ""
 }
 
-type CreateChatResponse struct {
+func (x *MuteUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MuteUserRequest) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type LiftRestrictionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"` // ID созданного чата
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Kind          RestrictionKind        `protobuf:"varint,3,opt,name=kind,proto3,enum=chat.RestrictionKind" json:"kind,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateChatResponse) Reset() {
-	*x = CreateChatResponse{}
-	mi := &file_chat_proto_msgTypes[1]
+func (x *LiftRestrictionRequest) Reset() {
+	*x = LiftRestrictionRequest{}
+	mi := &file_chat_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatResponse) String() string {
+func (x *LiftRestrictionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatResponse) ProtoMessage() {}
+func (*LiftRestrictionRequest) ProtoMessage() {}
 
-func (x *CreateChatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_proto_msgTypes[1]
+func (x *LiftRestrictionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -108,40 +5256,53 @@ func (x *CreateChatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatResponse.ProtoReflect.Descriptor instead.
-func (*CreateChatResponse) Descriptor() ([]byte, []int) {
-	return file_chat_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use LiftRestrictionRequest.ProtoReflect.Descriptor instead.
+func (*LiftRestrictionRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *CreateChatResponse) GetChatId() string {
+func (x *LiftRestrictionRequest) GetChatId() string {
 	if x != nil {
 		return x.ChatId
 	}
 	return ""
 }
 
-type ConnectChatRequest struct {
+func (x *LiftRestrictionRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *LiftRestrictionRequest) GetKind() RestrictionKind {
+	if x != nil {
+		return x.Kind
+	}
+	return RestrictionKind_RESTRICTION_KIND_UNSPECIFIED
+}
+
+type LiftRestrictionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"` // К какому чату подключиться
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ConnectChatRequest) Reset() {
-	*x = ConnectChatRequest{}
-	mi := &file_chat_proto_msgTypes[2]
+func (x *LiftRestrictionResponse) Reset() {
+	*x = LiftRestrictionResponse{}
+	mi := &file_chat_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConnectChatRequest) String() string {
+func (x *LiftRestrictionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConnectChatRequest) ProtoMessage() {}
+func (*LiftRestrictionResponse) ProtoMessage() {}
 
-func (x *ConnectChatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_proto_msgTypes[2]
+func (x *LiftRestrictionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -152,46 +5313,77 @@ func (x *ConnectChatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConnectChatRequest.ProtoReflect.Descriptor instead.
-func (*ConnectChatRequest) Descriptor() ([]byte, []int) {
-	return file_chat_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use LiftRestrictionResponse.ProtoReflect.Descriptor instead.
+func (*LiftRestrictionResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{83}
 }
 
-func (x *ConnectChatRequest) GetChatId() string {
+type ListRestrictionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRestrictionsRequest) Reset() {
+	*x = ListRestrictionsRequest{}
+	mi := &file_chat_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRestrictionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRestrictionsRequest) ProtoMessage() {}
+
+func (x *ListRestrictionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRestrictionsRequest.ProtoReflect.Descriptor instead.
+func (*ListRestrictionsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *ListRestrictionsRequest) GetChatId() string {
 	if x != nil {
 		return x.ChatId
 	}
 	return ""
 }
 
-// Сообщение в чате (используется в стриме ConnectChat и для SendMessage)
-type ChatMessage struct {
+type ListRestrictionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // ID отправителя
-	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`           // Имя отправителя (для удобства отображения)
-	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Restrictions  []*ChatRestriction     `protobuf:"bytes,1,rep,name=restrictions,proto3" json:"restrictions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatMessage) Reset() {
-	*x = ChatMessage{}
-	mi := &file_chat_proto_msgTypes[3]
+func (x *ListRestrictionsResponse) Reset() {
+	*x = ListRestrictionsResponse{}
+	mi := &file_chat_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatMessage) String() string {
+func (x *ListRestrictionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatMessage) ProtoMessage() {}
+func (*ListRestrictionsResponse) ProtoMessage() {}
 
-func (x *ChatMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_proto_msgTypes[3]
+func (x *ListRestrictionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -202,76 +5394,158 @@ func (x *ChatMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
-func (*ChatMessage) Descriptor() ([]byte, []int) {
-	return file_chat_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ListRestrictionsResponse.ProtoReflect.Descriptor instead.
+func (*ListRestrictionsResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *ChatMessage) GetMessageId() string {
+func (x *ListRestrictionsResponse) GetRestrictions() []*ChatRestriction {
 	if x != nil {
-		return x.MessageId
+		return x.Restrictions
+	}
+	return nil
+}
+
+// Запись журнала модерации чата
+type AuditEntry struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EntryId         string                 `protobuf:"bytes,1,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
+	ChatId          string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	ActorId         string                 `protobuf:"bytes,3,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorUsername   string                 `protobuf:"bytes,4,opt,name=actor_username,json=actorUsername,proto3" json:"actor_username,omitempty"`
+	Action          string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"` // report, ban, unban, mute, unmute
+	TargetUserId    string                 `protobuf:"bytes,6,opt,name=target_user_id,json=targetUserId,proto3" json:"target_user_id,omitempty"`
+	TargetUsername  string                 `protobuf:"bytes,7,opt,name=target_username,json=targetUsername,proto3" json:"target_username,omitempty"`
+	TargetMessageId string                 `protobuf:"bytes,8,opt,name=target_message_id,json=targetMessageId,proto3" json:"target_message_id,omitempty"`
+	Details         string                 `protobuf:"bytes,9,opt,name=details,proto3" json:"details,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_chat_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *AuditEntry) GetEntryId() string {
+	if x != nil {
+		return x.EntryId
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetChatId() string {
+func (x *AuditEntry) GetChatId() string {
 	if x != nil {
 		return x.ChatId
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetUserId() string {
+func (x *AuditEntry) GetActorId() string {
 	if x != nil {
-		return x.UserId
+		return x.ActorId
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetUsername() string {
+func (x *AuditEntry) GetActorUsername() string {
 	if x != nil {
-		return x.Username
+		return x.ActorUsername
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetText() string {
+func (x *AuditEntry) GetAction() string {
 	if x != nil {
-		return x.Text
+		return x.Action
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetTimestamp() *timestamppb.Timestamp {
+func (x *AuditEntry) GetTargetUserId() string {
 	if x != nil {
-		return x.Timestamp
+		return x.TargetUserId
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetTargetUsername() string {
+	if x != nil {
+		return x.TargetUsername
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetTargetMessageId() string {
+	if x != nil {
+		return x.TargetMessageId
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
 	}
 	return nil
 }
 
-type SendMessageRequest struct {
+type GetAuditLogRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
-	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"` // user_id отправителя будет взят из аутентификационного контекста (interceptor)
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // По умолчанию 50
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendMessageRequest) Reset() {
-	*x = SendMessageRequest{}
-	mi := &file_chat_proto_msgTypes[4]
+func (x *GetAuditLogRequest) Reset() {
+	*x = GetAuditLogRequest{}
+	mi := &file_chat_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendMessageRequest) String() string {
+func (x *GetAuditLogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendMessageRequest) ProtoMessage() {}
+func (*GetAuditLogRequest) ProtoMessage() {}
 
-func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_proto_msgTypes[4]
+func (x *GetAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -282,48 +5556,47 @@ func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
-func (*SendMessageRequest) Descriptor() ([]byte, []int) {
-	return file_chat_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use GetAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*GetAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *SendMessageRequest) GetChatId() string {
+func (x *GetAuditLogRequest) GetChatId() string {
 	if x != nil {
 		return x.ChatId
 	}
 	return ""
 }
 
-func (x *SendMessageRequest) GetText() string {
+func (x *GetAuditLogRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Text
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-type SendMessageResponse struct {
+type GetAuditLogResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // ID отправленного сообщения
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                  // Время отправки на сервере
+	Entries       []*AuditEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendMessageResponse) Reset() {
-	*x = SendMessageResponse{}
-	mi := &file_chat_proto_msgTypes[5]
+func (x *GetAuditLogResponse) Reset() {
+	*x = GetAuditLogResponse{}
+	mi := &file_chat_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendMessageResponse) String() string {
+func (x *GetAuditLogResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendMessageResponse) ProtoMessage() {}
+func (*GetAuditLogResponse) ProtoMessage() {}
 
-func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_proto_msgTypes[5]
+func (x *GetAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,21 +5607,14 @@ func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
-func (*SendMessageResponse) Descriptor() ([]byte, []int) {
-	return file_chat_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *SendMessageResponse) GetMessageId() string {
-	if x != nil {
-		return x.MessageId
-	}
-	return ""
+// Deprecated: Use GetAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*GetAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *SendMessageResponse) GetTimestamp() *timestamppb.Timestamp {
+func (x *GetAuditLogResponse) GetEntries() []*AuditEntry {
 	if x != nil {
-		return x.Timestamp
+		return x.Entries
 	}
 	return nil
 }
@@ -358,14 +5624,18 @@ var File_chat_proto protoreflect.FileDescriptor
 const file_chat_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"chat.proto\x12\x04chat\x1a\x1fgoogle/protobuf/timestamp.proto\"Y\n" +
+	"chat.proto\x12\x04chat\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb3\x01\n" +
 	"\x11CreateChatRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x120\n" +
-	"\x14participant_user_ids\x18\x02 \x03(\tR\x12participantUserIds\"-\n" +
+	"\x14participant_user_ids\x18\x02 \x03(\tR\x12participantUserIds\x124\n" +
+	"\n" +
+	"visibility\x18\x03 \x01(\x0e2\x14.chat.ChatVisibilityR\n" +
+	"visibility\x12\"\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x0e.chat.ChatTypeR\x04type\"-\n" +
 	"\x12CreateChatResponse\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\"-\n" +
 	"\x12ConnectChatRequest\x12\x17\n" +
-	"\achat_id\x18\x01 \x01(\tR\x06chatId\"\xc8\x01\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"\xe7\x04\n" +
 	"\vChatMessage\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
@@ -373,19 +5643,445 @@ const file_chat_proto_rawDesc = "" +
 	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
 	"\busername\x18\x04 \x01(\tR\busername\x12\x12\n" +
 	"\x04text\x18\x05 \x01(\tR\x04text\x128\n" +
-	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"A\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12(\n" +
+	"\x05event\x18\a \x01(\x0e2\x12.chat.MessageEventR\x05event\x12%\n" +
+	"\x04kind\x18\b \x01(\x0e2\x11.chat.MessageKindR\x04kind\x12\x1e\n" +
+	"\x04poll\x18\t \x01(\v2\n" +
+	".chat.PollR\x04poll\x12\x1c\n" +
+	"\tephemeral\x18\n" +
+	" \x01(\bR\tephemeral\x121\n" +
+	"\acommand\x18\v \x01(\v2\x17.chat.CommandInvocationR\acommand\x12/\n" +
+	"\bentities\x18\f \x03(\v2\x13.chat.MessageEntityR\bentities\x12-\n" +
+	"\bpreviews\x18\r \x03(\v2\x11.chat.LinkPreviewR\bpreviews\x12:\n" +
+	"\x0eforwarded_from\x18\x0e \x01(\v2\x13.chat.ForwardedFromR\rforwardedFrom\x12\x18\n" +
+	"\adeleted\x18\x0f \x01(\bR\adeleted\x12%\n" +
+	"\x0epending_review\x18\x10 \x01(\bR\rpendingReview\"\x96\x01\n" +
+	"\rForwardedFrom\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x04 \x01(\tR\busername\x12\x18\n" +
+	"\adeleted\x18\x05 \x01(\bR\adeleted\"t\n" +
+	"\vLinkPreview\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\timage_url\x18\x04 \x01(\tR\bimageUrl\"~\n" +
+	"\rMessageEntity\x12+\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x17.chat.MessageEntityTypeR\x04type\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x05R\x06length\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\"`\n" +
+	"\x11CommandInvocation\x12#\n" +
+	"\rinvocation_id\x18\x01 \x01(\tR\finvocationId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04args\x18\x03 \x01(\tR\x04args\"i\n" +
+	"\n" +
+	"PollOption\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x14\n" +
+	"\x05votes\x18\x03 \x01(\x05R\x05votes\x12\x1b\n" +
+	"\tvoter_ids\x18\x04 \x03(\tR\bvoterIds\"\xa2\x02\n" +
+	"\x04Poll\x12\x17\n" +
+	"\apoll_id\x18\x01 \x01(\tR\x06pollId\x12\x1a\n" +
+	"\bquestion\x18\x02 \x01(\tR\bquestion\x12*\n" +
+	"\aoptions\x18\x03 \x03(\v2\x10.chat.PollOptionR\aoptions\x12'\n" +
+	"\x0fmultiple_choice\x18\x04 \x01(\bR\x0emultipleChoice\x12\x1c\n" +
+	"\tanonymous\x18\x05 \x01(\bR\tanonymous\x127\n" +
+	"\tcloses_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bclosesAt\x12\x16\n" +
+	"\x06closed\x18\a \x01(\bR\x06closed\x12!\n" +
+	"\ftotal_voters\x18\b \x01(\x05R\vtotalVoters\"\xe2\x01\n" +
+	"\x11CreatePollRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x1a\n" +
+	"\bquestion\x18\x02 \x01(\tR\bquestion\x12\x18\n" +
+	"\aoptions\x18\x03 \x03(\tR\aoptions\x12'\n" +
+	"\x0fmultiple_choice\x18\x04 \x01(\bR\x0emultipleChoice\x12\x1c\n" +
+	"\tanonymous\x18\x05 \x01(\bR\tanonymous\x127\n" +
+	"\tcloses_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bclosesAt\"M\n" +
+	"\vVoteRequest\x12\x17\n" +
+	"\apoll_id\x18\x01 \x01(\tR\x06pollId\x12%\n" +
+	"\x0eoption_indexes\x18\x02 \x03(\x05R\roptionIndexes\"+\n" +
+	"\x10ClosePollRequest\x12\x17\n" +
+	"\apoll_id\x18\x01 \x01(\tR\x06pollId\"n\n" +
 	"\x12SendMessageRequest\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
-	"\x04text\x18\x02 \x01(\tR\x04text\"n\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12+\n" +
+	"\x06format\x18\x03 \x01(\x0e2\x13.chat.MessageFormatR\x06format\"\x95\x01\n" +
 	"\x13SendMessageResponse\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x128\n" +
-	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp2\xd0\x01\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12%\n" +
+	"\x0epending_review\x18\x03 \x01(\bR\rpendingReview\"z\n" +
+	"\x16ScheduleMessageRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x123\n" +
+	"\asend_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x06sendAt\"\xe1\x01\n" +
+	"\x10ScheduledMessage\x120\n" +
+	"\x14scheduled_message_id\x18\x01 \x01(\tR\x12scheduledMessageId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x123\n" +
+	"\asend_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06sendAt\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"7\n" +
+	"\x1cListScheduledMessagesRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"S\n" +
+	"\x1dListScheduledMessagesResponse\x122\n" +
+	"\bmessages\x18\x01 \x03(\v2\x16.chat.ScheduledMessageR\bmessages\"Q\n" +
+	"\x1dCancelScheduledMessageRequest\x120\n" +
+	"\x14scheduled_message_id\x18\x01 \x01(\tR\x12scheduledMessageId\" \n" +
+	"\x1eCancelScheduledMessageResponse\"\xe2\x01\n" +
+	"\bChatInfo\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x124\n" +
+	"\n" +
+	"visibility\x18\x03 \x01(\x0e2\x14.chat.ChatVisibilityR\n" +
+	"visibility\x12\"\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x0e.chat.ChatTypeR\x04type\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x14\n" +
+	"\x05topic\x18\x06 \x01(\tR\x05topic\"F\n" +
+	"\x18SearchPublicChatsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"A\n" +
+	"\x19SearchPublicChatsResponse\x12$\n" +
+	"\x05chats\x18\x01 \x03(\v2\x0e.chat.ChatInfoR\x05chats\"*\n" +
+	"\x0fJoinChatRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"6\n" +
+	"\x10JoinChatResponse\x12\"\n" +
+	"\x04chat\x18\x01 \x01(\v2\x0e.chat.ChatInfoR\x04chat\"\xcf\x01\n" +
+	"\x19UpdateChatSettingsRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12;\n" +
+	"\vmuted_until\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"mutedUntil\x124\n" +
+	"\fnotify_level\x18\x03 \x01(\x0e2\x11.chat.NotifyLevelR\vnotifyLevel\x12\x1b\n" +
+	"\x06hidden\x18\x04 \x01(\bH\x00R\x06hidden\x88\x01\x01B\t\n" +
+	"\a_hidden\"\xb2\x01\n" +
+	"\fChatSettings\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12;\n" +
+	"\vmuted_until\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"mutedUntil\x124\n" +
+	"\fnotify_level\x18\x03 \x01(\x0e2\x11.chat.NotifyLevelR\vnotifyLevel\x12\x16\n" +
+	"\x06hidden\x18\x04 \x01(\bR\x06hidden\"\x1a\n" +
+	"\x18GetUnreadCountersRequest\"\x86\x01\n" +
+	"\rUnreadCounter\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12!\n" +
+	"\funread_count\x18\x02 \x01(\x05R\vunreadCount\x12#\n" +
+	"\rmention_count\x18\x03 \x01(\x05R\fmentionCount\x12\x14\n" +
+	"\x05muted\x18\x04 \x01(\bR\x05muted\"L\n" +
+	"\x19GetUnreadCountersResponse\x12/\n" +
+	"\bcounters\x18\x01 \x03(\v2\x13.chat.UnreadCounterR\bcounters\".\n" +
+	"\x13MarkChatReadRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"\x16\n" +
+	"\x14MarkChatReadResponse\"\x1c\n" +
+	"\x1aStreamNotificationsRequest\"\xff\x01\n" +
+	"\fNotification\x12*\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x16.chat.NotificationKindR\x04kind\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x1b\n" +
+	"\tchat_name\x18\x03 \x01(\tR\bchatName\x12+\n" +
+	"\amessage\x18\x04 \x01(\v2\x11.chat.ChatMessageR\amessage\x12%\n" +
+	"\x0eactor_username\x18\x05 \x01(\tR\ractorUsername\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb7\x01\n" +
+	"\x13CreateInviteRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x19\n" +
+	"\bmax_uses\x18\x03 \x01(\x05R\amaxUses\x121\n" +
+	"\fdefault_role\x18\x04 \x01(\x0e2\x0e.chat.ChatRoleR\vdefaultRole\"\x82\x01\n" +
+	"\x14CreateInviteResponse\x12\x1b\n" +
+	"\tinvite_id\x18\x01 \x01(\tR\binviteId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"2\n" +
+	"\x13RevokeInviteRequest\x12\x1b\n" +
+	"\tinvite_id\x18\x01 \x01(\tR\binviteId\"\x16\n" +
+	"\x14RevokeInviteResponse\")\n" +
+	"\x13JoinByInviteRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"S\n" +
+	"\x14JoinByInviteResponse\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\"\n" +
+	"\x04role\x18\x02 \x01(\x0e2\x0e.chat.ChatRoleR\x04role\"\xa6\x01\n" +
+	"\aWebhook\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\tR\x06secret\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"A\n" +
+	"\x14CreateWebhookRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\".\n" +
+	"\x13ListWebhooksRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"A\n" +
+	"\x14ListWebhooksResponse\x12)\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\r.chat.WebhookR\bwebhooks\"5\n" +
+	"\x14DeleteWebhookRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\"\x17\n" +
+	"\x15DeleteWebhookResponse\"\xb7\x02\n" +
+	"\x0fWebhookDelivery\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\tR\n" +
+	"deliveryId\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\x12\x18\n" +
+	"\aattempt\x18\x04 \x01(\x05R\aattempt\x12\x1f\n" +
+	"\vstatus_code\x18\x05 \x01(\x05R\n" +
+	"statusCode\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x18\n" +
+	"\asuccess\x18\a \x01(\bR\asuccess\x12\x1f\n" +
+	"\vduration_ms\x18\b \x01(\x03R\n" +
+	"durationMs\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"S\n" +
+	"\x1cListWebhookDeliveriesRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"V\n" +
+	"\x1dListWebhookDeliveriesResponse\x125\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x15.chat.WebhookDeliveryR\n" +
+	"deliveries\"\xea\x01\n" +
+	"\x0fIncomingWebhook\x12.\n" +
+	"\x13incoming_webhook_id\x18\x01 \x01(\tR\x11incomingWebhookId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05token\x18\x04 \x01(\tR\x05token\x12\x12\n" +
+	"\x04path\x18\x05 \x01(\tR\x04path\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x15\n" +
+	"\x06bot_id\x18\a \x01(\tR\x05botId\"K\n" +
+	"\x1cCreateIncomingWebhookRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"6\n" +
+	"\x1bListIncomingWebhooksRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"Q\n" +
+	"\x1cListIncomingWebhooksResponse\x121\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x15.chat.IncomingWebhookR\bwebhooks\"N\n" +
+	"\x1cRevokeIncomingWebhookRequest\x12.\n" +
+	"\x13incoming_webhook_id\x18\x01 \x01(\tR\x11incomingWebhookId\"\x1f\n" +
+	"\x1dRevokeIncomingWebhookResponse\"g\n" +
+	"\x16RegisterCommandRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"\x19\n" +
+	"\x17RegisterCommandResponse\"G\n" +
+	"\x18UnregisterCommandRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x1b\n" +
+	"\x19UnregisterCommandResponse\".\n" +
+	"\x13ListCommandsRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"p\n" +
+	"\vCommandInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05usage\x18\x02 \x01(\tR\x05usage\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x15\n" +
+	"\x06bot_id\x18\x04 \x01(\tR\x05botId\"E\n" +
+	"\x14ListCommandsResponse\x12-\n" +
+	"\bcommands\x18\x01 \x03(\v2\x11.chat.CommandInfoR\bcommands\"p\n" +
+	"\x17RespondToCommandRequest\x12#\n" +
+	"\rinvocation_id\x18\x01 \x01(\tR\finvocationId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1c\n" +
+	"\tephemeral\x18\x03 \x01(\bR\tephemeral\"`\n" +
+	"\x18RespondToCommandResponse\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12%\n" +
+	"\x0epending_review\x18\x02 \x01(\bR\rpendingReview\"i\n" +
+	"\x15ForwardMessageRequest\x12*\n" +
+	"\x11source_message_id\x18\x01 \x01(\tR\x0fsourceMessageId\x12$\n" +
+	"\x0etarget_chat_id\x18\x02 \x01(\tR\ftargetChatId\"5\n" +
+	"\x14DeleteMessageRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\"\x17\n" +
+	"\x15DeleteMessageResponse\"\xaf\x02\n" +
+	"\x0eFlaggedMessage\x12,\n" +
+	"\x12flagged_message_id\x18\x01 \x01(\tR\x10flaggedMessageId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x04 \x01(\tR\busername\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12:\n" +
+	"\x0eforwarded_from\x18\b \x01(\v2\x13.chat.ForwardedFromR\rforwardedFrom\"5\n" +
+	"\x1aListFlaggedMessagesRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"O\n" +
+	"\x1bListFlaggedMessagesResponse\x120\n" +
+	"\bmessages\x18\x01 \x03(\v2\x14.chat.FlaggedMessageR\bmessages\"L\n" +
+	"\x1cApproveFlaggedMessageRequest\x12,\n" +
+	"\x12flagged_message_id\x18\x01 \x01(\tR\x10flaggedMessageId\"K\n" +
+	"\x1bRemoveFlaggedMessageRequest\x12,\n" +
+	"\x12flagged_message_id\x18\x01 \x01(\tR\x10flaggedMessageId\"\x1e\n" +
+	"\x1cRemoveFlaggedMessageResponse\"\xde\x02\n" +
+	"\x0fChatRestriction\x12%\n" +
+	"\x0erestriction_id\x18\x01 \x01(\tR\rrestrictionId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x04 \x01(\tR\busername\x12)\n" +
+	"\x04kind\x18\x05 \x01(\x0e2\x15.chat.RestrictionKindR\x04kind\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\a \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"M\n" +
+	"\x14ReportMessageRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"4\n" +
+	"\x15ReportMessageResponse\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\tR\breportId\"\xc1\x02\n" +
+	"\rMessageReport\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\tR\breportId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\x12\x1f\n" +
+	"\vreporter_id\x18\x04 \x01(\tR\n" +
+	"reporterId\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1b\n" +
+	"\tauthor_id\x18\a \x01(\tR\bauthorId\x12'\n" +
+	"\x0fauthor_username\x18\b \x01(\tR\x0eauthorUsername\x12!\n" +
+	"\fmessage_text\x18\t \x01(\tR\vmessageText\"C\n" +
+	"\x12ListReportsRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"D\n" +
+	"\x13ListReportsResponse\x12-\n" +
+	"\areports\x18\x01 \x03(\v2\x13.chat.MessageReportR\areports\"]\n" +
+	"\x0eBanUserRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x89\x01\n" +
+	"\x0fMuteUserRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12)\n" +
+	"\x10duration_seconds\x18\x04 \x01(\x03R\x0fdurationSeconds\"x\n" +
+	"\x16LiftRestrictionRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12)\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x15.chat.RestrictionKindR\x04kind\"\x19\n" +
+	"\x17LiftRestrictionResponse\"2\n" +
+	"\x17ListRestrictionsRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"U\n" +
+	"\x18ListRestrictionsResponse\x129\n" +
+	"\frestrictions\x18\x01 \x03(\v2\x15.chat.ChatRestrictionR\frestrictions\"\xea\x02\n" +
+	"\n" +
+	"AuditEntry\x12\x19\n" +
+	"\bentry_id\x18\x01 \x01(\tR\aentryId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x19\n" +
+	"\bactor_id\x18\x03 \x01(\tR\aactorId\x12%\n" +
+	"\x0eactor_username\x18\x04 \x01(\tR\ractorUsername\x12\x16\n" +
+	"\x06action\x18\x05 \x01(\tR\x06action\x12$\n" +
+	"\x0etarget_user_id\x18\x06 \x01(\tR\ftargetUserId\x12'\n" +
+	"\x0ftarget_username\x18\a \x01(\tR\x0etargetUsername\x12*\n" +
+	"\x11target_message_id\x18\b \x01(\tR\x0ftargetMessageId\x12\x18\n" +
+	"\adetails\x18\t \x01(\tR\adetails\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"C\n" +
+	"\x12GetAuditLogRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"A\n" +
+	"\x13GetAuditLogResponse\x12*\n" +
+	"\aentries\x18\x01 \x03(\v2\x10.chat.AuditEntryR\aentries*\x8b\x01\n" +
+	"\vMessageKind\x12\x1c\n" +
+	"\x18MESSAGE_KIND_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11MESSAGE_KIND_TEXT\x10\x01\x12\x15\n" +
+	"\x11MESSAGE_KIND_POLL\x10\x02\x12\x17\n" +
+	"\x13MESSAGE_KIND_ACTION\x10\x03\x12\x17\n" +
+	"\x13MESSAGE_KIND_SYSTEM\x10\x04*\x95\x01\n" +
+	"\fMessageEvent\x12\x1d\n" +
+	"\x19MESSAGE_EVENT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11MESSAGE_EVENT_NEW\x10\x01\x12\x19\n" +
+	"\x15MESSAGE_EVENT_UPDATED\x10\x02\x12\x19\n" +
+	"\x15MESSAGE_EVENT_COMMAND\x10\x03\x12\x19\n" +
+	"\x15MESSAGE_EVENT_DELETED\x10\x04*F\n" +
+	"\rMessageFormat\x12\x18\n" +
+	"\x14MESSAGE_FORMAT_PLAIN\x10\x00\x12\x1b\n" +
+	"\x17MESSAGE_FORMAT_MARKDOWN\x10\x01*\xb3\x01\n" +
+	"\x11MessageEntityType\x12#\n" +
+	"\x1fMESSAGE_ENTITY_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18MESSAGE_ENTITY_TYPE_BOLD\x10\x01\x12\x1c\n" +
+	"\x18MESSAGE_ENTITY_TYPE_CODE\x10\x02\x12\x1c\n" +
+	"\x18MESSAGE_ENTITY_TYPE_LINK\x10\x03\x12\x1f\n" +
+	"\x1bMESSAGE_ENTITY_TYPE_MENTION\x10\x04*P\n" +
+	"\bChatRole\x12\x19\n" +
+	"\x15CHAT_ROLE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10CHAT_ROLE_MEMBER\x10\x01\x12\x13\n" +
+	"\x0fCHAT_ROLE_ADMIN\x10\x02*j\n" +
+	"\x0eChatVisibility\x12\x1f\n" +
+	"\x1bCHAT_VISIBILITY_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17CHAT_VISIBILITY_PRIVATE\x10\x01\x12\x1a\n" +
+	"\x16CHAT_VISIBILITY_PUBLIC\x10\x02*Q\n" +
+	"\bChatType\x12\x19\n" +
+	"\x15CHAT_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fCHAT_TYPE_GROUP\x10\x01\x12\x15\n" +
+	"\x11CHAT_TYPE_CHANNEL\x10\x02*\\\n" +
+	"\vNotifyLevel\x12\x1c\n" +
+	"\x18NOTIFY_LEVEL_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10NOTIFY_LEVEL_ALL\x10\x01\x12\x19\n" +
+	"\x15NOTIFY_LEVEL_MENTIONS\x10\x02*\x91\x01\n" +
+	"\x10NotificationKind\x12!\n" +
+	"\x1dNOTIFICATION_KIND_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19NOTIFICATION_KIND_MESSAGE\x10\x01\x12\x1d\n" +
+	"\x19NOTIFICATION_KIND_MENTION\x10\x02\x12\x1c\n" +
+	"\x18NOTIFICATION_KIND_INVITE\x10\x03*h\n" +
+	"\x0fRestrictionKind\x12 \n" +
+	"\x1cRESTRICTION_KIND_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14RESTRICTION_KIND_BAN\x10\x01\x12\x19\n" +
+	"\x15RESTRICTION_KIND_MUTE\x10\x022\x91\x18\n" +
 	"\vChatService\x12?\n" +
 	"\n" +
 	"CreateChat\x12\x17.chat.CreateChatRequest\x1a\x18.chat.CreateChatResponse\x12<\n" +
 	"\vConnectChat\x12\x18.chat.ConnectChatRequest\x1a\x11.chat.ChatMessage0\x01\x12B\n" +
-	"\vSendMessage\x12\x18.chat.SendMessageRequest\x1a\x19.chat.SendMessageResponseB Z\x1echat.service/api/proto;chat_v1b\x06proto3"
+	"\vSendMessage\x12\x18.chat.SendMessageRequest\x1a\x19.chat.SendMessageResponse\x128\n" +
+	"\n" +
+	"CreatePoll\x12\x17.chat.CreatePollRequest\x1a\x11.chat.ChatMessage\x12%\n" +
+	"\x04Vote\x12\x11.chat.VoteRequest\x1a\n" +
+	".chat.Poll\x12/\n" +
+	"\tClosePoll\x12\x16.chat.ClosePollRequest\x1a\n" +
+	".chat.Poll\x12G\n" +
+	"\x0fScheduleMessage\x12\x1c.chat.ScheduleMessageRequest\x1a\x16.chat.ScheduledMessage\x12`\n" +
+	"\x15ListScheduledMessages\x12\".chat.ListScheduledMessagesRequest\x1a#.chat.ListScheduledMessagesResponse\x12c\n" +
+	"\x16CancelScheduledMessage\x12#.chat.CancelScheduledMessageRequest\x1a$.chat.CancelScheduledMessageResponse\x12E\n" +
+	"\fCreateInvite\x12\x19.chat.CreateInviteRequest\x1a\x1a.chat.CreateInviteResponse\x12E\n" +
+	"\fRevokeInvite\x12\x19.chat.RevokeInviteRequest\x1a\x1a.chat.RevokeInviteResponse\x12E\n" +
+	"\fJoinByInvite\x12\x19.chat.JoinByInviteRequest\x1a\x1a.chat.JoinByInviteResponse\x12T\n" +
+	"\x11SearchPublicChats\x12\x1e.chat.SearchPublicChatsRequest\x1a\x1f.chat.SearchPublicChatsResponse\x129\n" +
+	"\bJoinChat\x12\x15.chat.JoinChatRequest\x1a\x16.chat.JoinChatResponse\x12I\n" +
+	"\x12UpdateChatSettings\x12\x1f.chat.UpdateChatSettingsRequest\x1a\x12.chat.ChatSettings\x12T\n" +
+	"\x11GetUnreadCounters\x12\x1e.chat.GetUnreadCountersRequest\x1a\x1f.chat.GetUnreadCountersResponse\x12E\n" +
+	"\fMarkChatRead\x12\x19.chat.MarkChatReadRequest\x1a\x1a.chat.MarkChatReadResponse\x12M\n" +
+	"\x13StreamNotifications\x12 .chat.StreamNotificationsRequest\x1a\x12.chat.Notification0\x01\x12:\n" +
+	"\rCreateWebhook\x12\x1a.chat.CreateWebhookRequest\x1a\r.chat.Webhook\x12E\n" +
+	"\fListWebhooks\x12\x19.chat.ListWebhooksRequest\x1a\x1a.chat.ListWebhooksResponse\x12H\n" +
+	"\rDeleteWebhook\x12\x1a.chat.DeleteWebhookRequest\x1a\x1b.chat.DeleteWebhookResponse\x12`\n" +
+	"\x15ListWebhookDeliveries\x12\".chat.ListWebhookDeliveriesRequest\x1a#.chat.ListWebhookDeliveriesResponse\x12R\n" +
+	"\x15CreateIncomingWebhook\x12\".chat.CreateIncomingWebhookRequest\x1a\x15.chat.IncomingWebhook\x12]\n" +
+	"\x14ListIncomingWebhooks\x12!.chat.ListIncomingWebhooksRequest\x1a\".chat.ListIncomingWebhooksResponse\x12`\n" +
+	"\x15RevokeIncomingWebhook\x12\".chat.RevokeIncomingWebhookRequest\x1a#.chat.RevokeIncomingWebhookResponse\x12N\n" +
+	"\x0fRegisterCommand\x12\x1c.chat.RegisterCommandRequest\x1a\x1d.chat.RegisterCommandResponse\x12T\n" +
+	"\x11UnregisterCommand\x12\x1e.chat.UnregisterCommandRequest\x1a\x1f.chat.UnregisterCommandResponse\x12E\n" +
+	"\fListCommands\x12\x19.chat.ListCommandsRequest\x1a\x1a.chat.ListCommandsResponse\x12Q\n" +
+	"\x10RespondToCommand\x12\x1d.chat.RespondToCommandRequest\x1a\x1e.chat.RespondToCommandResponse\x12@\n" +
+	"\x0eForwardMessage\x12\x1b.chat.ForwardMessageRequest\x1a\x11.chat.ChatMessage\x12H\n" +
+	"\rDeleteMessage\x12\x1a.chat.DeleteMessageRequest\x1a\x1b.chat.DeleteMessageResponse\x12Z\n" +
+	"\x13ListFlaggedMessages\x12 .chat.ListFlaggedMessagesRequest\x1a!.chat.ListFlaggedMessagesResponse\x12N\n" +
+	"\x15ApproveFlaggedMessage\x12\".chat.ApproveFlaggedMessageRequest\x1a\x11.chat.ChatMessage\x12]\n" +
+	"\x14RemoveFlaggedMessage\x12!.chat.RemoveFlaggedMessageRequest\x1a\".chat.RemoveFlaggedMessageResponse\x12H\n" +
+	"\rReportMessage\x12\x1a.chat.ReportMessageRequest\x1a\x1b.chat.ReportMessageResponse\x12B\n" +
+	"\vListReports\x12\x18.chat.ListReportsRequest\x1a\x19.chat.ListReportsResponse\x126\n" +
+	"\aBanUser\x12\x14.chat.BanUserRequest\x1a\x15.chat.ChatRestriction\x128\n" +
+	"\bMuteUser\x12\x15.chat.MuteUserRequest\x1a\x15.chat.ChatRestriction\x12N\n" +
+	"\x0fLiftRestriction\x12\x1c.chat.LiftRestrictionRequest\x1a\x1d.chat.LiftRestrictionResponse\x12Q\n" +
+	"\x10ListRestrictions\x12\x1d.chat.ListRestrictionsRequest\x1a\x1e.chat.ListRestrictionsResponse\x12B\n" +
+	"\vGetAuditLog\x12\x18.chat.GetAuditLogRequest\x1a\x19.chat.GetAuditLogResponseB Z\x1echat.service/api/proto;chat_v1b\x06proto3"
 
 var (
 	file_chat_proto_rawDescOnce sync.Once
@@ -399,30 +6095,254 @@ func file_chat_proto_rawDescGZIP() []byte {
 	return file_chat_proto_rawDescData
 }
 
-var file_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_chat_proto_enumTypes = make([]protoimpl.EnumInfo, 10)
+var file_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 89)
 var file_chat_proto_goTypes = []any{
-	(*CreateChatRequest)(nil),     // 0: chat.CreateChatRequest
-	(*CreateChatResponse)(nil),    // 1: chat.CreateChatResponse
-	(*ConnectChatRequest)(nil),    // 2: chat.ConnectChatRequest
-	(*ChatMessage)(nil),           // 3: chat.ChatMessage
-	(*SendMessageRequest)(nil),    // 4: chat.SendMessageRequest
-	(*SendMessageResponse)(nil),   // 5: chat.SendMessageResponse
-	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(MessageKind)(0),                       // 0: chat.MessageKind
+	(MessageEvent)(0),                      // 1: chat.MessageEvent
+	(MessageFormat)(0),                     // 2: chat.MessageFormat
+	(MessageEntityType)(0),                 // 3: chat.MessageEntityType
+	(ChatRole)(0),                          // 4: chat.ChatRole
+	(ChatVisibility)(0),                    // 5: chat.ChatVisibility
+	(ChatType)(0),                          // 6: chat.ChatType
+	(NotifyLevel)(0),                       // 7: chat.NotifyLevel
+	(NotificationKind)(0),                  // 8: chat.NotificationKind
+	(RestrictionKind)(0),                   // 9: chat.RestrictionKind
+	(*CreateChatRequest)(nil),              // 10: chat.CreateChatRequest
+	(*CreateChatResponse)(nil),             // 11: chat.CreateChatResponse
+	(*ConnectChatRequest)(nil),             // 12: chat.ConnectChatRequest
+	(*ChatMessage)(nil),                    // 13: chat.ChatMessage
+	(*ForwardedFrom)(nil),                  // 14: chat.ForwardedFrom
+	(*LinkPreview)(nil),                    // 15: chat.LinkPreview
+	(*MessageEntity)(nil),                  // 16: chat.MessageEntity
+	(*CommandInvocation)(nil),              // 17: chat.CommandInvocation
+	(*PollOption)(nil),                     // 18: chat.PollOption
+	(*Poll)(nil),                           // 19: chat.Poll
+	(*CreatePollRequest)(nil),              // 20: chat.CreatePollRequest
+	(*VoteRequest)(nil),                    // 21: chat.VoteRequest
+	(*ClosePollRequest)(nil),               // 22: chat.ClosePollRequest
+	(*SendMessageRequest)(nil),             // 23: chat.SendMessageRequest
+	(*SendMessageResponse)(nil),            // 24: chat.SendMessageResponse
+	(*ScheduleMessageRequest)(nil),         // 25: chat.ScheduleMessageRequest
+	(*ScheduledMessage)(nil),               // 26: chat.ScheduledMessage
+	(*ListScheduledMessagesRequest)(nil),   // 27: chat.ListScheduledMessagesRequest
+	(*ListScheduledMessagesResponse)(nil),  // 28: chat.ListScheduledMessagesResponse
+	(*CancelScheduledMessageRequest)(nil),  // 29: chat.CancelScheduledMessageRequest
+	(*CancelScheduledMessageResponse)(nil), // 30: chat.CancelScheduledMessageResponse
+	(*ChatInfo)(nil),                       // 31: chat.ChatInfo
+	(*SearchPublicChatsRequest)(nil),       // 32: chat.SearchPublicChatsRequest
+	(*SearchPublicChatsResponse)(nil),      // 33: chat.SearchPublicChatsResponse
+	(*JoinChatRequest)(nil),                // 34: chat.JoinChatRequest
+	(*JoinChatResponse)(nil),               // 35: chat.JoinChatResponse
+	(*UpdateChatSettingsRequest)(nil),      // 36: chat.UpdateChatSettingsRequest
+	(*ChatSettings)(nil),                   // 37: chat.ChatSettings
+	(*GetUnreadCountersRequest)(nil),       // 38: chat.GetUnreadCountersRequest
+	(*UnreadCounter)(nil),                  // 39: chat.UnreadCounter
+	(*GetUnreadCountersResponse)(nil),      // 40: chat.GetUnreadCountersResponse
+	(*MarkChatReadRequest)(nil),            // 41: chat.MarkChatReadRequest
+	(*MarkChatReadResponse)(nil),           // 42: chat.MarkChatReadResponse
+	(*StreamNotificationsRequest)(nil),     // 43: chat.StreamNotificationsRequest
+	(*Notification)(nil),                   // 44: chat.Notification
+	(*CreateInviteRequest)(nil),            // 45: chat.CreateInviteRequest
+	(*CreateInviteResponse)(nil),           // 46: chat.CreateInviteResponse
+	(*RevokeInviteRequest)(nil),            // 47: chat.RevokeInviteRequest
+	(*RevokeInviteResponse)(nil),           // 48: chat.RevokeInviteResponse
+	(*JoinByInviteRequest)(nil),            // 49: chat.JoinByInviteRequest
+	(*JoinByInviteResponse)(nil),           // 50: chat.JoinByInviteResponse
+	(*Webhook)(nil),                        // 51: chat.Webhook
+	(*CreateWebhookRequest)(nil),           // 52: chat.CreateWebhookRequest
+	(*ListWebhooksRequest)(nil),            // 53: chat.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),           // 54: chat.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),           // 55: chat.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),          // 56: chat.DeleteWebhookResponse
+	(*WebhookDelivery)(nil),                // 57: chat.WebhookDelivery
+	(*ListWebhookDeliveriesRequest)(nil),   // 58: chat.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil),  // 59: chat.ListWebhookDeliveriesResponse
+	(*IncomingWebhook)(nil),                // 60: chat.IncomingWebhook
+	(*CreateIncomingWebhookRequest)(nil),   // 61: chat.CreateIncomingWebhookRequest
+	(*ListIncomingWebhooksRequest)(nil),    // 62: chat.ListIncomingWebhooksRequest
+	(*ListIncomingWebhooksResponse)(nil),   // 63: chat.ListIncomingWebhooksResponse
+	(*RevokeIncomingWebhookRequest)(nil),   // 64: chat.RevokeIncomingWebhookRequest
+	(*RevokeIncomingWebhookResponse)(nil),  // 65: chat.RevokeIncomingWebhookResponse
+	(*RegisterCommandRequest)(nil),         // 66: chat.RegisterCommandRequest
+	(*RegisterCommandResponse)(nil),        // 67: chat.RegisterCommandResponse
+	(*UnregisterCommandRequest)(nil),       // 68: chat.UnregisterCommandRequest
+	(*UnregisterCommandResponse)(nil),      // 69: chat.UnregisterCommandResponse
+	(*ListCommandsRequest)(nil),            // 70: chat.ListCommandsRequest
+	(*CommandInfo)(nil),                    // 71: chat.CommandInfo
+	(*ListCommandsResponse)(nil),           // 72: chat.ListCommandsResponse
+	(*RespondToCommandRequest)(nil),        // 73: chat.RespondToCommandRequest
+	(*RespondToCommandResponse)(nil),       // 74: chat.RespondToCommandResponse
+	(*ForwardMessageRequest)(nil),          // 75: chat.ForwardMessageRequest
+	(*DeleteMessageRequest)(nil),           // 76: chat.DeleteMessageRequest
+	(*DeleteMessageResponse)(nil),          // 77: chat.DeleteMessageResponse
+	(*FlaggedMessage)(nil),                 // 78: chat.FlaggedMessage
+	(*ListFlaggedMessagesRequest)(nil),     // 79: chat.ListFlaggedMessagesRequest
+	(*ListFlaggedMessagesResponse)(nil),    // 80: chat.ListFlaggedMessagesResponse
+	(*ApproveFlaggedMessageRequest)(nil),   // 81: chat.ApproveFlaggedMessageRequest
+	(*RemoveFlaggedMessageRequest)(nil),    // 82: chat.RemoveFlaggedMessageRequest
+	(*RemoveFlaggedMessageResponse)(nil),   // 83: chat.RemoveFlaggedMessageResponse
+	(*ChatRestriction)(nil),                // 84: chat.ChatRestriction
+	(*ReportMessageRequest)(nil),           // 85: chat.ReportMessageRequest
+	(*ReportMessageResponse)(nil),          // 86: chat.ReportMessageResponse
+	(*MessageReport)(nil),                  // 87: chat.MessageReport
+	(*ListReportsRequest)(nil),             // 88: chat.ListReportsRequest
+	(*ListReportsResponse)(nil),            // 89: chat.ListReportsResponse
+	(*BanUserRequest)(nil),                 // 90: chat.BanUserRequest
+	(*MuteUserRequest)(nil),                // 91: chat.MuteUserRequest
+	(*LiftRestrictionRequest)(nil),         // 92: chat.LiftRestrictionRequest
+	(*LiftRestrictionResponse)(nil),        // 93: chat.LiftRestrictionResponse
+	(*ListRestrictionsRequest)(nil),        // 94: chat.ListRestrictionsRequest
+	(*ListRestrictionsResponse)(nil),       // 95: chat.ListRestrictionsResponse
+	(*AuditEntry)(nil),                     // 96: chat.AuditEntry
+	(*GetAuditLogRequest)(nil),             // 97: chat.GetAuditLogRequest
+	(*GetAuditLogResponse)(nil),            // 98: chat.GetAuditLogResponse
+	(*timestamppb.Timestamp)(nil),          // 99: google.protobuf.Timestamp
 }
 var file_chat_proto_depIdxs = []int32{
-	6, // 0: chat.ChatMessage.timestamp:type_name -> google.protobuf.Timestamp
-	6, // 1: chat.SendMessageResponse.timestamp:type_name -> google.protobuf.Timestamp
-	0, // 2: chat.ChatService.CreateChat:input_type -> chat.CreateChatRequest
-	2, // 3: chat.ChatService.ConnectChat:input_type -> chat.ConnectChatRequest
-	4, // 4: chat.ChatService.SendMessage:input_type -> chat.SendMessageRequest
-	1, // 5: chat.ChatService.CreateChat:output_type -> chat.CreateChatResponse
-	3, // 6: chat.ChatService.ConnectChat:output_type -> chat.ChatMessage
-	5, // 7: chat.ChatService.SendMessage:output_type -> chat.SendMessageResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	5,  // 0: chat.CreateChatRequest.visibility:type_name -> chat.ChatVisibility
+	6,  // 1: chat.CreateChatRequest.type:type_name -> chat.ChatType
+	99, // 2: chat.ChatMessage.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 3: chat.ChatMessage.event:type_name -> chat.MessageEvent
+	0,  // 4: chat.ChatMessage.kind:type_name -> chat.MessageKind
+	19, // 5: chat.ChatMessage.poll:type_name -> chat.Poll
+	17, // 6: chat.ChatMessage.command:type_name -> chat.CommandInvocation
+	16, // 7: chat.ChatMessage.entities:type_name -> chat.MessageEntity
+	15, // 8: chat.ChatMessage.previews:type_name -> chat.LinkPreview
+	14, // 9: chat.ChatMessage.forwarded_from:type_name -> chat.ForwardedFrom
+	3,  // 10: chat.MessageEntity.type:type_name -> chat.MessageEntityType
+	18, // 11: chat.Poll.options:type_name -> chat.PollOption
+	99, // 12: chat.Poll.closes_at:type_name -> google.protobuf.Timestamp
+	99, // 13: chat.CreatePollRequest.closes_at:type_name -> google.protobuf.Timestamp
+	2,  // 14: chat.SendMessageRequest.format:type_name -> chat.MessageFormat
+	99, // 15: chat.SendMessageResponse.timestamp:type_name -> google.protobuf.Timestamp
+	99, // 16: chat.ScheduleMessageRequest.send_at:type_name -> google.protobuf.Timestamp
+	99, // 17: chat.ScheduledMessage.send_at:type_name -> google.protobuf.Timestamp
+	99, // 18: chat.ScheduledMessage.created_at:type_name -> google.protobuf.Timestamp
+	26, // 19: chat.ListScheduledMessagesResponse.messages:type_name -> chat.ScheduledMessage
+	5,  // 20: chat.ChatInfo.visibility:type_name -> chat.ChatVisibility
+	6,  // 21: chat.ChatInfo.type:type_name -> chat.ChatType
+	99, // 22: chat.ChatInfo.created_at:type_name -> google.protobuf.Timestamp
+	31, // 23: chat.SearchPublicChatsResponse.chats:type_name -> chat.ChatInfo
+	31, // 24: chat.JoinChatResponse.chat:type_name -> chat.ChatInfo
+	99, // 25: chat.UpdateChatSettingsRequest.muted_until:type_name -> google.protobuf.Timestamp
+	7,  // 26: chat.UpdateChatSettingsRequest.notify_level:type_name -> chat.NotifyLevel
+	99, // 27: chat.ChatSettings.muted_until:type_name -> google.protobuf.Timestamp
+	7,  // 28: chat.ChatSettings.notify_level:type_name -> chat.NotifyLevel
+	39, // 29: chat.GetUnreadCountersResponse.counters:type_name -> chat.UnreadCounter
+	8,  // 30: chat.Notification.kind:type_name -> chat.NotificationKind
+	13, // 31: chat.Notification.message:type_name -> chat.ChatMessage
+	99, // 32: chat.Notification.created_at:type_name -> google.protobuf.Timestamp
+	99, // 33: chat.CreateInviteRequest.expires_at:type_name -> google.protobuf.Timestamp
+	4,  // 34: chat.CreateInviteRequest.default_role:type_name -> chat.ChatRole
+	99, // 35: chat.CreateInviteResponse.expires_at:type_name -> google.protobuf.Timestamp
+	4,  // 36: chat.JoinByInviteResponse.role:type_name -> chat.ChatRole
+	99, // 37: chat.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	51, // 38: chat.ListWebhooksResponse.webhooks:type_name -> chat.Webhook
+	99, // 39: chat.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	57, // 40: chat.ListWebhookDeliveriesResponse.deliveries:type_name -> chat.WebhookDelivery
+	99, // 41: chat.IncomingWebhook.created_at:type_name -> google.protobuf.Timestamp
+	60, // 42: chat.ListIncomingWebhooksResponse.webhooks:type_name -> chat.IncomingWebhook
+	71, // 43: chat.ListCommandsResponse.commands:type_name -> chat.CommandInfo
+	99, // 44: chat.FlaggedMessage.created_at:type_name -> google.protobuf.Timestamp
+	14, // 45: chat.FlaggedMessage.forwarded_from:type_name -> chat.ForwardedFrom
+	78, // 46: chat.ListFlaggedMessagesResponse.messages:type_name -> chat.FlaggedMessage
+	9,  // 47: chat.ChatRestriction.kind:type_name -> chat.RestrictionKind
+	99, // 48: chat.ChatRestriction.created_at:type_name -> google.protobuf.Timestamp
+	99, // 49: chat.ChatRestriction.expires_at:type_name -> google.protobuf.Timestamp
+	99, // 50: chat.MessageReport.created_at:type_name -> google.protobuf.Timestamp
+	87, // 51: chat.ListReportsResponse.reports:type_name -> chat.MessageReport
+	9,  // 52: chat.LiftRestrictionRequest.kind:type_name -> chat.RestrictionKind
+	84, // 53: chat.ListRestrictionsResponse.restrictions:type_name -> chat.ChatRestriction
+	99, // 54: chat.AuditEntry.created_at:type_name -> google.protobuf.Timestamp
+	96, // 55: chat.GetAuditLogResponse.entries:type_name -> chat.AuditEntry
+	10, // 56: chat.ChatService.CreateChat:input_type -> chat.CreateChatRequest
+	12, // 57: chat.ChatService.ConnectChat:input_type -> chat.ConnectChatRequest
+	23, // 58: chat.ChatService.SendMessage:input_type -> chat.SendMessageRequest
+	20, // 59: chat.ChatService.CreatePoll:input_type -> chat.CreatePollRequest
+	21, // 60: chat.ChatService.Vote:input_type -> chat.VoteRequest
+	22, // 61: chat.ChatService.ClosePoll:input_type -> chat.ClosePollRequest
+	25, // 62: chat.ChatService.ScheduleMessage:input_type -> chat.ScheduleMessageRequest
+	27, // 63: chat.ChatService.ListScheduledMessages:input_type -> chat.ListScheduledMessagesRequest
+	29, // 64: chat.ChatService.CancelScheduledMessage:input_type -> chat.CancelScheduledMessageRequest
+	45, // 65: chat.ChatService.CreateInvite:input_type -> chat.CreateInviteRequest
+	47, // 66: chat.ChatService.RevokeInvite:input_type -> chat.RevokeInviteRequest
+	49, // 67: chat.ChatService.JoinByInvite:input_type -> chat.JoinByInviteRequest
+	32, // 68: chat.ChatService.SearchPublicChats:input_type -> chat.SearchPublicChatsRequest
+	34, // 69: chat.ChatService.JoinChat:input_type -> chat.JoinChatRequest
+	36, // 70: chat.ChatService.UpdateChatSettings:input_type -> chat.UpdateChatSettingsRequest
+	38, // 71: chat.ChatService.GetUnreadCounters:input_type -> chat.GetUnreadCountersRequest
+	41, // 72: chat.ChatService.MarkChatRead:input_type -> chat.MarkChatReadRequest
+	43, // 73: chat.ChatService.StreamNotifications:input_type -> chat.StreamNotificationsRequest
+	52, // 74: chat.ChatService.CreateWebhook:input_type -> chat.CreateWebhookRequest
+	53, // 75: chat.ChatService.ListWebhooks:input_type -> chat.ListWebhooksRequest
+	55, // 76: chat.ChatService.DeleteWebhook:input_type -> chat.DeleteWebhookRequest
+	58, // 77: chat.ChatService.ListWebhookDeliveries:input_type -> chat.ListWebhookDeliveriesRequest
+	61, // 78: chat.ChatService.CreateIncomingWebhook:input_type -> chat.CreateIncomingWebhookRequest
+	62, // 79: chat.ChatService.ListIncomingWebhooks:input_type -> chat.ListIncomingWebhooksRequest
+	64, // 80: chat.ChatService.RevokeIncomingWebhook:input_type -> chat.RevokeIncomingWebhookRequest
+	66, // 81: chat.ChatService.RegisterCommand:input_type -> chat.RegisterCommandRequest
+	68, // 82: chat.ChatService.UnregisterCommand:input_type -> chat.UnregisterCommandRequest
+	70, // 83: chat.ChatService.ListCommands:input_type -> chat.ListCommandsRequest
+	73, // 84: chat.ChatService.RespondToCommand:input_type -> chat.RespondToCommandRequest
+	75, // 85: chat.ChatService.ForwardMessage:input_type -> chat.ForwardMessageRequest
+	76, // 86: chat.ChatService.DeleteMessage:input_type -> chat.DeleteMessageRequest
+	79, // 87: chat.ChatService.ListFlaggedMessages:input_type -> chat.ListFlaggedMessagesRequest
+	81, // 88: chat.ChatService.ApproveFlaggedMessage:input_type -> chat.ApproveFlaggedMessageRequest
+	82, // 89: chat.ChatService.RemoveFlaggedMessage:input_type -> chat.RemoveFlaggedMessageRequest
+	85, // 90: chat.ChatService.ReportMessage:input_type -> chat.ReportMessageRequest
+	88, // 91: chat.ChatService.ListReports:input_type -> chat.ListReportsRequest
+	90, // 92: chat.ChatService.BanUser:input_type -> chat.BanUserRequest
+	91, // 93: chat.ChatService.MuteUser:input_type -> chat.MuteUserRequest
+	92, // 94: chat.ChatService.LiftRestriction:input_type -> chat.LiftRestrictionRequest
+	94, // 95: chat.ChatService.ListRestrictions:input_type -> chat.ListRestrictionsRequest
+	97, // 96: chat.ChatService.GetAuditLog:input_type -> chat.GetAuditLogRequest
+	11, // 97: chat.ChatService.CreateChat:output_type -> chat.CreateChatResponse
+	13, // 98: chat.ChatService.ConnectChat:output_type -> chat.ChatMessage
+	24, // 99: chat.ChatService.SendMessage:output_type -> chat.SendMessageResponse
+	13, // 100: chat.ChatService.CreatePoll:output_type -> chat.ChatMessage
+	19, // 101: chat.ChatService.Vote:output_type -> chat.Poll
+	19, // 102: chat.ChatService.ClosePoll:output_type -> chat.Poll
+	26, // 103: chat.ChatService.ScheduleMessage:output_type -> chat.ScheduledMessage
+	28, // 104: chat.ChatService.ListScheduledMessages:output_type -> chat.ListScheduledMessagesResponse
+	30, // 105: chat.ChatService.CancelScheduledMessage:output_type -> chat.CancelScheduledMessageResponse
+	46, // 106: chat.ChatService.CreateInvite:output_type -> chat.CreateInviteResponse
+	48, // 107: chat.ChatService.RevokeInvite:output_type -> chat.RevokeInviteResponse
+	50, // 108: chat.ChatService.JoinByInvite:output_type -> chat.JoinByInviteResponse
+	33, // 109: chat.ChatService.SearchPublicChats:output_type -> chat.SearchPublicChatsResponse
+	35, // 110: chat.ChatService.JoinChat:output_type -> chat.JoinChatResponse
+	37, // 111: chat.ChatService.UpdateChatSettings:output_type -> chat.ChatSettings
+	40, // 112: chat.ChatService.GetUnreadCounters:output_type -> chat.GetUnreadCountersResponse
+	42, // 113: chat.ChatService.MarkChatRead:output_type -> chat.MarkChatReadResponse
+	44, // 114: chat.ChatService.StreamNotifications:output_type -> chat.Notification
+	51, // 115: chat.ChatService.CreateWebhook:output_type -> chat.Webhook
+	54, // 116: chat.ChatService.ListWebhooks:output_type -> chat.ListWebhooksResponse
+	56, // 117: chat.ChatService.DeleteWebhook:output_type -> chat.DeleteWebhookResponse
+	59, // 118: chat.ChatService.ListWebhookDeliveries:output_type -> chat.ListWebhookDeliveriesResponse
+	60, // 119: chat.ChatService.CreateIncomingWebhook:output_type -> chat.IncomingWebhook
+	63, // 120: chat.ChatService.ListIncomingWebhooks:output_type -> chat.ListIncomingWebhooksResponse
+	65, // 121: chat.ChatService.RevokeIncomingWebhook:output_type -> chat.RevokeIncomingWebhookResponse
+	67, // 122: chat.ChatService.RegisterCommand:output_type -> chat.RegisterCommandResponse
+	69, // 123: chat.ChatService.UnregisterCommand:output_type -> chat.UnregisterCommandResponse
+	72, // 124: chat.ChatService.ListCommands:output_type -> chat.ListCommandsResponse
+	74, // 125: chat.ChatService.RespondToCommand:output_type -> chat.RespondToCommandResponse
+	13, // 126: chat.ChatService.ForwardMessage:output_type -> chat.ChatMessage
+	77, // 127: chat.ChatService.DeleteMessage:output_type -> chat.DeleteMessageResponse
+	80, // 128: chat.ChatService.ListFlaggedMessages:output_type -> chat.ListFlaggedMessagesResponse
+	13, // 129: chat.ChatService.ApproveFlaggedMessage:output_type -> chat.ChatMessage
+	83, // 130: chat.ChatService.RemoveFlaggedMessage:output_type -> chat.RemoveFlaggedMessageResponse
+	86, // 131: chat.ChatService.ReportMessage:output_type -> chat.ReportMessageResponse
+	89, // 132: chat.ChatService.ListReports:output_type -> chat.ListReportsResponse
+	84, // 133: chat.ChatService.BanUser:output_type -> chat.ChatRestriction
+	84, // 134: chat.ChatService.MuteUser:output_type -> chat.ChatRestriction
+	93, // 135: chat.ChatService.LiftRestriction:output_type -> chat.LiftRestrictionResponse
+	95, // 136: chat.ChatService.ListRestrictions:output_type -> chat.ListRestrictionsResponse
+	98, // 137: chat.ChatService.GetAuditLog:output_type -> chat.GetAuditLogResponse
+	97, // [97:138] is the sub-list for method output_type
+	56, // [56:97] is the sub-list for method input_type
+	56, // [56:56] is the sub-list for extension type_name
+	56, // [56:56] is the sub-list for extension extendee
+	0,  // [0:56] is the sub-list for field type_name
 }
 
 func init() { file_chat_proto_init() }
@@ -430,18 +6350,20 @@ func file_chat_proto_init() {
 	if File_chat_proto != nil {
 		return
 	}
+	file_chat_proto_msgTypes[26].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chat_proto_rawDesc), len(file_chat_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      10,
+			NumMessages:   89,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_chat_proto_goTypes,
 		DependencyIndexes: file_chat_proto_depIdxs,
+		EnumInfos:         file_chat_proto_enumTypes,
 		MessageInfos:      file_chat_proto_msgTypes,
 	}.Build()
 	File_chat_proto = out.File
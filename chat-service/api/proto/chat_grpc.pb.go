@@ -21,9 +21,47 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ChatService_CreateChat_FullMethodName  = "/chat.ChatService/CreateChat"
-	ChatService_ConnectChat_FullMethodName = "/chat.ChatService/ConnectChat"
-	ChatService_SendMessage_FullMethodName = "/chat.ChatService/SendMessage"
+	ChatService_CreateChat_FullMethodName             = "/chat.ChatService/CreateChat"
+	ChatService_ConnectChat_FullMethodName            = "/chat.ChatService/ConnectChat"
+	ChatService_SendMessage_FullMethodName            = "/chat.ChatService/SendMessage"
+	ChatService_CreatePoll_FullMethodName             = "/chat.ChatService/CreatePoll"
+	ChatService_Vote_FullMethodName                   = "/chat.ChatService/Vote"
+	ChatService_ClosePoll_FullMethodName              = "/chat.ChatService/ClosePoll"
+	ChatService_ScheduleMessage_FullMethodName        = "/chat.ChatService/ScheduleMessage"
+	ChatService_ListScheduledMessages_FullMethodName  = "/chat.ChatService/ListScheduledMessages"
+	ChatService_CancelScheduledMessage_FullMethodName = "/chat.ChatService/CancelScheduledMessage"
+	ChatService_CreateInvite_FullMethodName           = "/chat.ChatService/CreateInvite"
+	ChatService_RevokeInvite_FullMethodName           = "/chat.ChatService/RevokeInvite"
+	ChatService_JoinByInvite_FullMethodName           = "/chat.ChatService/JoinByInvite"
+	ChatService_SearchPublicChats_FullMethodName      = "/chat.ChatService/SearchPublicChats"
+	ChatService_JoinChat_FullMethodName               = "/chat.ChatService/JoinChat"
+	ChatService_UpdateChatSettings_FullMethodName     = "/chat.ChatService/UpdateChatSettings"
+	ChatService_GetUnreadCounters_FullMethodName      = "/chat.ChatService/GetUnreadCounters"
+	ChatService_MarkChatRead_FullMethodName           = "/chat.ChatService/MarkChatRead"
+	ChatService_StreamNotifications_FullMethodName    = "/chat.ChatService/StreamNotifications"
+	ChatService_CreateWebhook_FullMethodName          = "/chat.ChatService/CreateWebhook"
+	ChatService_ListWebhooks_FullMethodName           = "/chat.ChatService/ListWebhooks"
+	ChatService_DeleteWebhook_FullMethodName          = "/chat.ChatService/DeleteWebhook"
+	ChatService_ListWebhookDeliveries_FullMethodName  = "/chat.ChatService/ListWebhookDeliveries"
+	ChatService_CreateIncomingWebhook_FullMethodName  = "/chat.ChatService/CreateIncomingWebhook"
+	ChatService_ListIncomingWebhooks_FullMethodName   = "/chat.ChatService/ListIncomingWebhooks"
+	ChatService_RevokeIncomingWebhook_FullMethodName  = "/chat.ChatService/RevokeIncomingWebhook"
+	ChatService_RegisterCommand_FullMethodName        = "/chat.ChatService/RegisterCommand"
+	ChatService_UnregisterCommand_FullMethodName      = "/chat.ChatService/UnregisterCommand"
+	ChatService_ListCommands_FullMethodName           = "/chat.ChatService/ListCommands"
+	ChatService_RespondToCommand_FullMethodName       = "/chat.ChatService/RespondToCommand"
+	ChatService_ForwardMessage_FullMethodName         = "/chat.ChatService/ForwardMessage"
+	ChatService_DeleteMessage_FullMethodName          = "/chat.ChatService/DeleteMessage"
+	ChatService_ListFlaggedMessages_FullMethodName    = "/chat.ChatService/ListFlaggedMessages"
+	ChatService_ApproveFlaggedMessage_FullMethodName  = "/chat.ChatService/ApproveFlaggedMessage"
+	ChatService_RemoveFlaggedMessage_FullMethodName   = "/chat.ChatService/RemoveFlaggedMessage"
+	ChatService_ReportMessage_FullMethodName          = "/chat.ChatService/ReportMessage"
+	ChatService_ListReports_FullMethodName            = "/chat.ChatService/ListReports"
+	ChatService_BanUser_FullMethodName                = "/chat.ChatService/BanUser"
+	ChatService_MuteUser_FullMethodName               = "/chat.ChatService/MuteUser"
+	ChatService_LiftRestriction_FullMethodName        = "/chat.ChatService/LiftRestriction"
+	ChatService_ListRestrictions_FullMethodName       = "/chat.ChatService/ListRestrictions"
+	ChatService_GetAuditLog_FullMethodName            = "/chat.ChatService/GetAuditLog"
 )
 
 // ChatServiceClient is the client API for ChatService service.
@@ -40,6 +78,83 @@ type ChatServiceClient interface {
 	ConnectChat(ctx context.Context, in *ConnectChatRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatMessage], error)
 	// Отправка сообщения в чат
 	SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse, error)
+	// Создание опроса в чате
+	CreatePoll(ctx context.Context, in *CreatePollRequest, opts ...grpc.CallOption) (*ChatMessage, error)
+	// Голосование в опросе. Повторный голос заменяет предыдущий
+	Vote(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*Poll, error)
+	// Досрочное закрытие опроса (автор опроса или администратор чата)
+	ClosePoll(ctx context.Context, in *ClosePollRequest, opts ...grpc.CallOption) (*Poll, error)
+	// Отложенная отправка сообщения в указанное время
+	ScheduleMessage(ctx context.Context, in *ScheduleMessageRequest, opts ...grpc.CallOption) (*ScheduledMessage, error)
+	// Список ожидающих отправки отложенных сообщений пользователя
+	ListScheduledMessages(ctx context.Context, in *ListScheduledMessagesRequest, opts ...grpc.CallOption) (*ListScheduledMessagesResponse, error)
+	// Отмена отложенного сообщения
+	CancelScheduledMessage(ctx context.Context, in *CancelScheduledMessageRequest, opts ...grpc.CallOption) (*CancelScheduledMessageResponse, error)
+	// Создание пригласительной ссылки (только для администраторов чата)
+	CreateInvite(ctx context.Context, in *CreateInviteRequest, opts ...grpc.CallOption) (*CreateInviteResponse, error)
+	// Отзыв пригласительной ссылки (только для администраторов чата)
+	RevokeInvite(ctx context.Context, in *RevokeInviteRequest, opts ...grpc.CallOption) (*RevokeInviteResponse, error)
+	// Вступление в чат по коду приглашения
+	JoinByInvite(ctx context.Context, in *JoinByInviteRequest, opts ...grpc.CallOption) (*JoinByInviteResponse, error)
+	// Поиск публичных чатов по началу названия
+	SearchPublicChats(ctx context.Context, in *SearchPublicChatsRequest, opts ...grpc.CallOption) (*SearchPublicChatsResponse, error)
+	// Самостоятельное вступление в публичный чат
+	JoinChat(ctx context.Context, in *JoinChatRequest, opts ...grpc.CallOption) (*JoinChatResponse, error)
+	// Изменение персональных настроек чата (заглушение, уведомления, скрытие)
+	UpdateChatSettings(ctx context.Context, in *UpdateChatSettingsRequest, opts ...grpc.CallOption) (*ChatSettings, error)
+	// Счетчики непрочитанных сообщений и упоминаний по всем чатам пользователя
+	GetUnreadCounters(ctx context.Context, in *GetUnreadCountersRequest, opts ...grpc.CallOption) (*GetUnreadCountersResponse, error)
+	// Отметка чата как прочитанного
+	MarkChatRead(ctx context.Context, in *MarkChatReadRequest, opts ...grpc.CallOption) (*MarkChatReadResponse, error)
+	// Уведомления о новых сообщениях, упоминаниях и приглашениях по всем чатам пользователя
+	// в одном серверном стриме, с учетом персональных настроек уведомлений
+	StreamNotifications(ctx context.Context, in *StreamNotificationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Notification], error)
+	// Создание исходящего вебхука чата (только для администраторов чата)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	// Список вебхуков чата (только для администраторов чата)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	// Удаление вебхука (только для администраторов чата)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	// Журнал попыток доставки вебхука (только для администраторов чата)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	// Создание входящего вебхука: секретный URL для публикации сообщений в чат по HTTP (только для администраторов чата)
+	CreateIncomingWebhook(ctx context.Context, in *CreateIncomingWebhookRequest, opts ...grpc.CallOption) (*IncomingWebhook, error)
+	// Список действующих входящих вебхуков чата (только для администраторов чата)
+	ListIncomingWebhooks(ctx context.Context, in *ListIncomingWebhooksRequest, opts ...grpc.CallOption) (*ListIncomingWebhooksResponse, error)
+	// Отзыв входящего вебхука (только для администраторов чата)
+	RevokeIncomingWebhook(ctx context.Context, in *RevokeIncomingWebhookRequest, opts ...grpc.CallOption) (*RevokeIncomingWebhookResponse, error)
+	// Регистрация slash-команды бота в чате (только для ботов-участников чата)
+	RegisterCommand(ctx context.Context, in *RegisterCommandRequest, opts ...grpc.CallOption) (*RegisterCommandResponse, error)
+	// Удаление slash-команды бота
+	UnregisterCommand(ctx context.Context, in *UnregisterCommandRequest, opts ...grpc.CallOption) (*UnregisterCommandResponse, error)
+	// Список доступных в чате slash-команд: встроенных и команд ботов
+	ListCommands(ctx context.Context, in *ListCommandsRequest, opts ...grpc.CallOption) (*ListCommandsResponse, error)
+	// Ответ бота на вызов команды: в чат или только вызвавшему пользователю
+	RespondToCommand(ctx context.Context, in *RespondToCommandRequest, opts ...grpc.CallOption) (*RespondToCommandResponse, error)
+	// Пересылка сообщения в другой чат; вызывающий должен состоять в обоих чатах
+	ForwardMessage(ctx context.Context, in *ForwardMessageRequest, opts ...grpc.CallOption) (*ChatMessage, error)
+	// Удаление сообщения автором или администратором чата
+	DeleteMessage(ctx context.Context, in *DeleteMessageRequest, opts ...grpc.CallOption) (*DeleteMessageResponse, error)
+	// Сообщения чата, отмеченные фильтрами модерации и ожидающие проверки. Доступно администраторам чата
+	ListFlaggedMessages(ctx context.Context, in *ListFlaggedMessagesRequest, opts ...grpc.CallOption) (*ListFlaggedMessagesResponse, error)
+	// Одобрение сообщения из очереди модерации, сообщение публикуется в чат от имени автора
+	ApproveFlaggedMessage(ctx context.Context, in *ApproveFlaggedMessageRequest, opts ...grpc.CallOption) (*ChatMessage, error)
+	// Удаление сообщения из очереди модерации без публикации
+	RemoveFlaggedMessage(ctx context.Context, in *RemoveFlaggedMessageRequest, opts ...grpc.CallOption) (*RemoveFlaggedMessageResponse, error)
+	// Жалоба участника чата на сообщение
+	ReportMessage(ctx context.Context, in *ReportMessageRequest, opts ...grpc.CallOption) (*ReportMessageResponse, error)
+	// Последние жалобы на сообщения чата. Доступно администраторам чата
+	ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error)
+	// Блокировка пользователя: пользователь исключается из чата и не может вступить снова
+	BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*ChatRestriction, error)
+	// Временный запрет писать в чат, снимается автоматически по истечении срока
+	MuteUser(ctx context.Context, in *MuteUserRequest, opts ...grpc.CallOption) (*ChatRestriction, error)
+	// Досрочное снятие блокировки или запрета писать
+	LiftRestriction(ctx context.Context, in *LiftRestrictionRequest, opts ...grpc.CallOption) (*LiftRestrictionResponse, error)
+	// Действующие ограничения участников чата. Доступно администраторам чата
+	ListRestrictions(ctx context.Context, in *ListRestrictionsRequest, opts ...grpc.CallOption) (*ListRestrictionsResponse, error)
+	// Журнал модерации чата: жалобы, блокировки и запреты писать. Доступно администраторам чата
+	GetAuditLog(ctx context.Context, in *GetAuditLogRequest, opts ...grpc.CallOption) (*GetAuditLogResponse, error)
 }
 
 type chatServiceClient struct {
@@ -89,6 +204,395 @@ func (c *chatServiceClient) SendMessage(ctx context.Context, in *SendMessageRequ
 	return out, nil
 }
 
+func (c *chatServiceClient) CreatePoll(ctx context.Context, in *CreatePollRequest, opts ...grpc.CallOption) (*ChatMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChatMessage)
+	err := c.cc.Invoke(ctx, ChatService_CreatePoll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) Vote(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*Poll, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Poll)
+	err := c.cc.Invoke(ctx, ChatService_Vote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ClosePoll(ctx context.Context, in *ClosePollRequest, opts ...grpc.CallOption) (*Poll, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Poll)
+	err := c.cc.Invoke(ctx, ChatService_ClosePoll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ScheduleMessage(ctx context.Context, in *ScheduleMessageRequest, opts ...grpc.CallOption) (*ScheduledMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduledMessage)
+	err := c.cc.Invoke(ctx, ChatService_ScheduleMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListScheduledMessages(ctx context.Context, in *ListScheduledMessagesRequest, opts ...grpc.CallOption) (*ListScheduledMessagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScheduledMessagesResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListScheduledMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) CancelScheduledMessage(ctx context.Context, in *CancelScheduledMessageRequest, opts ...grpc.CallOption) (*CancelScheduledMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelScheduledMessageResponse)
+	err := c.cc.Invoke(ctx, ChatService_CancelScheduledMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) CreateInvite(ctx context.Context, in *CreateInviteRequest, opts ...grpc.CallOption) (*CreateInviteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateInviteResponse)
+	err := c.cc.Invoke(ctx, ChatService_CreateInvite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) RevokeInvite(ctx context.Context, in *RevokeInviteRequest, opts ...grpc.CallOption) (*RevokeInviteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeInviteResponse)
+	err := c.cc.Invoke(ctx, ChatService_RevokeInvite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) JoinByInvite(ctx context.Context, in *JoinByInviteRequest, opts ...grpc.CallOption) (*JoinByInviteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JoinByInviteResponse)
+	err := c.cc.Invoke(ctx, ChatService_JoinByInvite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) SearchPublicChats(ctx context.Context, in *SearchPublicChatsRequest, opts ...grpc.CallOption) (*SearchPublicChatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchPublicChatsResponse)
+	err := c.cc.Invoke(ctx, ChatService_SearchPublicChats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) JoinChat(ctx context.Context, in *JoinChatRequest, opts ...grpc.CallOption) (*JoinChatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JoinChatResponse)
+	err := c.cc.Invoke(ctx, ChatService_JoinChat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) UpdateChatSettings(ctx context.Context, in *UpdateChatSettingsRequest, opts ...grpc.CallOption) (*ChatSettings, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChatSettings)
+	err := c.cc.Invoke(ctx, ChatService_UpdateChatSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetUnreadCounters(ctx context.Context, in *GetUnreadCountersRequest, opts ...grpc.CallOption) (*GetUnreadCountersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUnreadCountersResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetUnreadCounters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) MarkChatRead(ctx context.Context, in *MarkChatReadRequest, opts ...grpc.CallOption) (*MarkChatReadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkChatReadResponse)
+	err := c.cc.Invoke(ctx, ChatService_MarkChatRead_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) StreamNotifications(ctx context.Context, in *StreamNotificationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Notification], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChatService_ServiceDesc.Streams[1], ChatService_StreamNotifications_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamNotificationsRequest, Notification]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_StreamNotificationsClient = grpc.ServerStreamingClient[Notification]
+
+func (c *chatServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, ChatService_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, ChatService_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) CreateIncomingWebhook(ctx context.Context, in *CreateIncomingWebhookRequest, opts ...grpc.CallOption) (*IncomingWebhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IncomingWebhook)
+	err := c.cc.Invoke(ctx, ChatService_CreateIncomingWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListIncomingWebhooks(ctx context.Context, in *ListIncomingWebhooksRequest, opts ...grpc.CallOption) (*ListIncomingWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIncomingWebhooksResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListIncomingWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) RevokeIncomingWebhook(ctx context.Context, in *RevokeIncomingWebhookRequest, opts ...grpc.CallOption) (*RevokeIncomingWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeIncomingWebhookResponse)
+	err := c.cc.Invoke(ctx, ChatService_RevokeIncomingWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) RegisterCommand(ctx context.Context, in *RegisterCommandRequest, opts ...grpc.CallOption) (*RegisterCommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterCommandResponse)
+	err := c.cc.Invoke(ctx, ChatService_RegisterCommand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) UnregisterCommand(ctx context.Context, in *UnregisterCommandRequest, opts ...grpc.CallOption) (*UnregisterCommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnregisterCommandResponse)
+	err := c.cc.Invoke(ctx, ChatService_UnregisterCommand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListCommands(ctx context.Context, in *ListCommandsRequest, opts ...grpc.CallOption) (*ListCommandsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCommandsResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListCommands_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) RespondToCommand(ctx context.Context, in *RespondToCommandRequest, opts ...grpc.CallOption) (*RespondToCommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RespondToCommandResponse)
+	err := c.cc.Invoke(ctx, ChatService_RespondToCommand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ForwardMessage(ctx context.Context, in *ForwardMessageRequest, opts ...grpc.CallOption) (*ChatMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChatMessage)
+	err := c.cc.Invoke(ctx, ChatService_ForwardMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) DeleteMessage(ctx context.Context, in *DeleteMessageRequest, opts ...grpc.CallOption) (*DeleteMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteMessageResponse)
+	err := c.cc.Invoke(ctx, ChatService_DeleteMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListFlaggedMessages(ctx context.Context, in *ListFlaggedMessagesRequest, opts ...grpc.CallOption) (*ListFlaggedMessagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFlaggedMessagesResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListFlaggedMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ApproveFlaggedMessage(ctx context.Context, in *ApproveFlaggedMessageRequest, opts ...grpc.CallOption) (*ChatMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChatMessage)
+	err := c.cc.Invoke(ctx, ChatService_ApproveFlaggedMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) RemoveFlaggedMessage(ctx context.Context, in *RemoveFlaggedMessageRequest, opts ...grpc.CallOption) (*RemoveFlaggedMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveFlaggedMessageResponse)
+	err := c.cc.Invoke(ctx, ChatService_RemoveFlaggedMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ReportMessage(ctx context.Context, in *ReportMessageRequest, opts ...grpc.CallOption) (*ReportMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportMessageResponse)
+	err := c.cc.Invoke(ctx, ChatService_ReportMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReportsResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListReports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*ChatRestriction, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChatRestriction)
+	err := c.cc.Invoke(ctx, ChatService_BanUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) MuteUser(ctx context.Context, in *MuteUserRequest, opts ...grpc.CallOption) (*ChatRestriction, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChatRestriction)
+	err := c.cc.Invoke(ctx, ChatService_MuteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) LiftRestriction(ctx context.Context, in *LiftRestrictionRequest, opts ...grpc.CallOption) (*LiftRestrictionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LiftRestrictionResponse)
+	err := c.cc.Invoke(ctx, ChatService_LiftRestriction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListRestrictions(ctx context.Context, in *ListRestrictionsRequest, opts ...grpc.CallOption) (*ListRestrictionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRestrictionsResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListRestrictions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetAuditLog(ctx context.Context, in *GetAuditLogRequest, opts ...grpc.CallOption) (*GetAuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAuditLogResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatServiceServer is the server API for ChatService service.
 // All implementations must embed UnimplementedChatServiceServer
 // for forward compatibility.
@@ -103,89 +607,957 @@ type ChatServiceServer interface {
 	ConnectChat(*ConnectChatRequest, grpc.ServerStreamingServer[ChatMessage]) error
 	// Отправка сообщения в чат
 	SendMessage(context.Context, *SendMessageRequest) (*SendMessageResponse, error)
+	// Создание опроса в чате
+	CreatePoll(context.Context, *CreatePollRequest) (*ChatMessage, error)
+	// Голосование в опросе. Повторный голос заменяет предыдущий
+	Vote(context.Context, *VoteRequest) (*Poll, error)
+	// Досрочное закрытие опроса (автор опроса или администратор чата)
+	ClosePoll(context.Context, *ClosePollRequest) (*Poll, error)
+	// Отложенная отправка сообщения в указанное время
+	ScheduleMessage(context.Context, *ScheduleMessageRequest) (*ScheduledMessage, error)
+	// Список ожидающих отправки отложенных сообщений пользователя
+	ListScheduledMessages(context.Context, *ListScheduledMessagesRequest) (*ListScheduledMessagesResponse, error)
+	// Отмена отложенного сообщения
+	CancelScheduledMessage(context.Context, *CancelScheduledMessageRequest) (*CancelScheduledMessageResponse, error)
+	// Создание пригласительной ссылки (только для администраторов чата)
+	CreateInvite(context.Context, *CreateInviteRequest) (*CreateInviteResponse, error)
+	// Отзыв пригласительной ссылки (только для администраторов чата)
+	RevokeInvite(context.Context, *RevokeInviteRequest) (*RevokeInviteResponse, error)
+	// Вступление в чат по коду приглашения
+	JoinByInvite(context.Context, *JoinByInviteRequest) (*JoinByInviteResponse, error)
+	// Поиск публичных чатов по началу названия
+	SearchPublicChats(context.Context, *SearchPublicChatsRequest) (*SearchPublicChatsResponse, error)
+	// Самостоятельное вступление в публичный чат
+	JoinChat(context.Context, *JoinChatRequest) (*JoinChatResponse, error)
+	// Изменение персональных настроек чата (заглушение, уведомления, скрытие)
+	UpdateChatSettings(context.Context, *UpdateChatSettingsRequest) (*ChatSettings, error)
+	// Счетчики непрочитанных сообщений и упоминаний по всем чатам пользователя
+	GetUnreadCounters(context.Context, *GetUnreadCountersRequest) (*GetUnreadCountersResponse, error)
+	// Отметка чата как прочитанного
+	MarkChatRead(context.Context, *MarkChatReadRequest) (*MarkChatReadResponse, error)
+	// Уведомления о новых сообщениях, упоминаниях и приглашениях по всем чатам пользователя
+	// в одном серверном стриме, с учетом персональных настроек уведомлений
+	StreamNotifications(*StreamNotificationsRequest, grpc.ServerStreamingServer[Notification]) error
+	// Создание исходящего вебхука чата (только для администраторов чата)
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error)
+	// Список вебхуков чата (только для администраторов чата)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	// Удаление вебхука (только для администраторов чата)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	// Журнал попыток доставки вебхука (только для администраторов чата)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	// Создание входящего вебхука: секретный URL для публикации сообщений в чат по HTTP (только для администраторов чата)
+	CreateIncomingWebhook(context.Context, *CreateIncomingWebhookRequest) (*IncomingWebhook, error)
+	// Список действующих входящих вебхуков чата (только для администраторов чата)
+	ListIncomingWebhooks(context.Context, *ListIncomingWebhooksRequest) (*ListIncomingWebhooksResponse, error)
+	// Отзыв входящего вебхука (только для администраторов чата)
+	RevokeIncomingWebhook(context.Context, *RevokeIncomingWebhookRequest) (*RevokeIncomingWebhookResponse, error)
+	// Регистрация slash-команды бота в чате (только для ботов-участников чата)
+	RegisterCommand(context.Context, *RegisterCommandRequest) (*RegisterCommandResponse, error)
+	// Удаление slash-команды бота
+	UnregisterCommand(context.Context, *UnregisterCommandRequest) (*UnregisterCommandResponse, error)
+	// Список доступных в чате slash-команд: встроенных и команд ботов
+	ListCommands(context.Context, *ListCommandsRequest) (*ListCommandsResponse, error)
+	// Ответ бота на вызов команды: в чат или только вызвавшему пользователю
+	RespondToCommand(context.Context, *RespondToCommandRequest) (*RespondToCommandResponse, error)
+	// Пересылка сообщения в другой чат; вызывающий должен состоять в обоих чатах
+	ForwardMessage(context.Context, *ForwardMessageRequest) (*ChatMessage, error)
+	// Удаление сообщения автором или администратором чата
+	DeleteMessage(context.Context, *DeleteMessageRequest) (*DeleteMessageResponse, error)
+	// Сообщения чата, отмеченные фильтрами модерации и ожидающие проверки. Доступно администраторам чата
+	ListFlaggedMessages(context.Context, *ListFlaggedMessagesRequest) (*ListFlaggedMessagesResponse, error)
+	// Одобрение сообщения из очереди модерации, сообщение публикуется в чат от имени автора
+	ApproveFlaggedMessage(context.Context, *ApproveFlaggedMessageRequest) (*ChatMessage, error)
+	// Удаление сообщения из очереди модерации без публикации
+	RemoveFlaggedMessage(context.Context, *RemoveFlaggedMessageRequest) (*RemoveFlaggedMessageResponse, error)
+	// Жалоба участника чата на сообщение
+	ReportMessage(context.Context, *ReportMessageRequest) (*ReportMessageResponse, error)
+	// Последние жалобы на сообщения чата. Доступно администраторам чата
+	ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error)
+	// Блокировка пользователя: пользователь исключается из чата и не может вступить снова
+	BanUser(context.Context, *BanUserRequest) (*ChatRestriction, error)
+	// Временный запрет писать в чат, снимается автоматически по истечении срока
+	MuteUser(context.Context, *MuteUserRequest) (*ChatRestriction, error)
+	// Досрочное снятие блокировки или запрета писать
+	LiftRestriction(context.Context, *LiftRestrictionRequest) (*LiftRestrictionResponse, error)
+	// Действующие ограничения участников чата. Доступно администраторам чата
+	ListRestrictions(context.Context, *ListRestrictionsRequest) (*ListRestrictionsResponse, error)
+	// Журнал модерации чата: жалобы, блокировки и запреты писать. Доступно администраторам чата
+	GetAuditLog(context.Context, *GetAuditLogRequest) (*GetAuditLogResponse, error)
 	mustEmbedUnimplementedChatServiceServer()
 }
 
-// UnimplementedChatServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedChatServiceServer struct{}
+// UnimplementedChatServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedChatServiceServer struct{}
+
+func (UnimplementedChatServiceServer) CreateChat(context.Context, *CreateChatRequest) (*CreateChatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateChat not implemented")
+}
+func (UnimplementedChatServiceServer) ConnectChat(*ConnectChatRequest, grpc.ServerStreamingServer[ChatMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method ConnectChat not implemented")
+}
+func (UnimplementedChatServiceServer) SendMessage(context.Context, *SendMessageRequest) (*SendMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
+}
+func (UnimplementedChatServiceServer) CreatePoll(context.Context, *CreatePollRequest) (*ChatMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePoll not implemented")
+}
+func (UnimplementedChatServiceServer) Vote(context.Context, *VoteRequest) (*Poll, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Vote not implemented")
+}
+func (UnimplementedChatServiceServer) ClosePoll(context.Context, *ClosePollRequest) (*Poll, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClosePoll not implemented")
+}
+func (UnimplementedChatServiceServer) ScheduleMessage(context.Context, *ScheduleMessageRequest) (*ScheduledMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleMessage not implemented")
+}
+func (UnimplementedChatServiceServer) ListScheduledMessages(context.Context, *ListScheduledMessagesRequest) (*ListScheduledMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduledMessages not implemented")
+}
+func (UnimplementedChatServiceServer) CancelScheduledMessage(context.Context, *CancelScheduledMessageRequest) (*CancelScheduledMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScheduledMessage not implemented")
+}
+func (UnimplementedChatServiceServer) CreateInvite(context.Context, *CreateInviteRequest) (*CreateInviteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInvite not implemented")
+}
+func (UnimplementedChatServiceServer) RevokeInvite(context.Context, *RevokeInviteRequest) (*RevokeInviteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeInvite not implemented")
+}
+func (UnimplementedChatServiceServer) JoinByInvite(context.Context, *JoinByInviteRequest) (*JoinByInviteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinByInvite not implemented")
+}
+func (UnimplementedChatServiceServer) SearchPublicChats(context.Context, *SearchPublicChatsRequest) (*SearchPublicChatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchPublicChats not implemented")
+}
+func (UnimplementedChatServiceServer) JoinChat(context.Context, *JoinChatRequest) (*JoinChatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinChat not implemented")
+}
+func (UnimplementedChatServiceServer) UpdateChatSettings(context.Context, *UpdateChatSettingsRequest) (*ChatSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateChatSettings not implemented")
+}
+func (UnimplementedChatServiceServer) GetUnreadCounters(context.Context, *GetUnreadCountersRequest) (*GetUnreadCountersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUnreadCounters not implemented")
+}
+func (UnimplementedChatServiceServer) MarkChatRead(context.Context, *MarkChatReadRequest) (*MarkChatReadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkChatRead not implemented")
+}
+func (UnimplementedChatServiceServer) StreamNotifications(*StreamNotificationsRequest, grpc.ServerStreamingServer[Notification]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamNotifications not implemented")
+}
+func (UnimplementedChatServiceServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedChatServiceServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedChatServiceServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedChatServiceServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedChatServiceServer) CreateIncomingWebhook(context.Context, *CreateIncomingWebhookRequest) (*IncomingWebhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIncomingWebhook not implemented")
+}
+func (UnimplementedChatServiceServer) ListIncomingWebhooks(context.Context, *ListIncomingWebhooksRequest) (*ListIncomingWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIncomingWebhooks not implemented")
+}
+func (UnimplementedChatServiceServer) RevokeIncomingWebhook(context.Context, *RevokeIncomingWebhookRequest) (*RevokeIncomingWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeIncomingWebhook not implemented")
+}
+func (UnimplementedChatServiceServer) RegisterCommand(context.Context, *RegisterCommandRequest) (*RegisterCommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterCommand not implemented")
+}
+func (UnimplementedChatServiceServer) UnregisterCommand(context.Context, *UnregisterCommandRequest) (*UnregisterCommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterCommand not implemented")
+}
+func (UnimplementedChatServiceServer) ListCommands(context.Context, *ListCommandsRequest) (*ListCommandsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCommands not implemented")
+}
+func (UnimplementedChatServiceServer) RespondToCommand(context.Context, *RespondToCommandRequest) (*RespondToCommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondToCommand not implemented")
+}
+func (UnimplementedChatServiceServer) ForwardMessage(context.Context, *ForwardMessageRequest) (*ChatMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForwardMessage not implemented")
+}
+func (UnimplementedChatServiceServer) DeleteMessage(context.Context, *DeleteMessageRequest) (*DeleteMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMessage not implemented")
+}
+func (UnimplementedChatServiceServer) ListFlaggedMessages(context.Context, *ListFlaggedMessagesRequest) (*ListFlaggedMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFlaggedMessages not implemented")
+}
+func (UnimplementedChatServiceServer) ApproveFlaggedMessage(context.Context, *ApproveFlaggedMessageRequest) (*ChatMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveFlaggedMessage not implemented")
+}
+func (UnimplementedChatServiceServer) RemoveFlaggedMessage(context.Context, *RemoveFlaggedMessageRequest) (*RemoveFlaggedMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveFlaggedMessage not implemented")
+}
+func (UnimplementedChatServiceServer) ReportMessage(context.Context, *ReportMessageRequest) (*ReportMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportMessage not implemented")
+}
+func (UnimplementedChatServiceServer) ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReports not implemented")
+}
+func (UnimplementedChatServiceServer) BanUser(context.Context, *BanUserRequest) (*ChatRestriction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanUser not implemented")
+}
+func (UnimplementedChatServiceServer) MuteUser(context.Context, *MuteUserRequest) (*ChatRestriction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MuteUser not implemented")
+}
+func (UnimplementedChatServiceServer) LiftRestriction(context.Context, *LiftRestrictionRequest) (*LiftRestrictionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LiftRestriction not implemented")
+}
+func (UnimplementedChatServiceServer) ListRestrictions(context.Context, *ListRestrictionsRequest) (*ListRestrictionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRestrictions not implemented")
+}
+func (UnimplementedChatServiceServer) GetAuditLog(context.Context, *GetAuditLogRequest) (*GetAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuditLog not implemented")
+}
+func (UnimplementedChatServiceServer) mustEmbedUnimplementedChatServiceServer() {}
+func (UnimplementedChatServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeChatServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChatServiceServer will
+// result in compilation errors.
+type UnsafeChatServiceServer interface {
+	mustEmbedUnimplementedChatServiceServer()
+}
+
+func RegisterChatServiceServer(s grpc.ServiceRegistrar, srv ChatServiceServer) {
+	// If the following call pancis, it indicates UnimplementedChatServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ChatService_ServiceDesc, srv)
+}
+
+func _ChatService_CreateChat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateChatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CreateChat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CreateChat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CreateChat(ctx, req.(*CreateChatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ConnectChat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ConnectChatRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServiceServer).ConnectChat(m, &grpc.GenericServerStream[ConnectChatRequest, ChatMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_ConnectChatServer = grpc.ServerStreamingServer[ChatMessage]
+
+func _ChatService_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).SendMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_SendMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).SendMessage(ctx, req.(*SendMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_CreatePoll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CreatePoll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CreatePoll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CreatePoll(ctx, req.(*CreatePollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_Vote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).Vote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_Vote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).Vote(ctx, req.(*VoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ClosePoll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClosePollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ClosePoll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ClosePoll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ClosePoll(ctx, req.(*ClosePollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ScheduleMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ScheduleMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ScheduleMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ScheduleMessage(ctx, req.(*ScheduleMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListScheduledMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScheduledMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListScheduledMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListScheduledMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListScheduledMessages(ctx, req.(*ListScheduledMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_CancelScheduledMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScheduledMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CancelScheduledMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CancelScheduledMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CancelScheduledMessage(ctx, req.(*CancelScheduledMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_CreateInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CreateInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CreateInvite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CreateInvite(ctx, req.(*CreateInviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_RevokeInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeInviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).RevokeInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_RevokeInvite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).RevokeInvite(ctx, req.(*RevokeInviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_JoinByInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinByInviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).JoinByInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_JoinByInvite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).JoinByInvite(ctx, req.(*JoinByInviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_SearchPublicChats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchPublicChatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).SearchPublicChats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_SearchPublicChats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).SearchPublicChats(ctx, req.(*SearchPublicChatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_JoinChat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinChatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).JoinChat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_JoinChat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).JoinChat(ctx, req.(*JoinChatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_UpdateChatSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateChatSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).UpdateChatSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_UpdateChatSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).UpdateChatSettings(ctx, req.(*UpdateChatSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetUnreadCounters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUnreadCountersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetUnreadCounters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetUnreadCounters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetUnreadCounters(ctx, req.(*GetUnreadCountersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_MarkChatRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkChatReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).MarkChatRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_MarkChatRead_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).MarkChatRead(ctx, req.(*MarkChatReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_StreamNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServiceServer).StreamNotifications(m, &grpc.GenericServerStream[StreamNotificationsRequest, Notification]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_StreamNotificationsServer = grpc.ServerStreamingServer[Notification]
+
+func _ChatService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_CreateIncomingWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIncomingWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CreateIncomingWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CreateIncomingWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CreateIncomingWebhook(ctx, req.(*CreateIncomingWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListIncomingWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIncomingWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListIncomingWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListIncomingWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListIncomingWebhooks(ctx, req.(*ListIncomingWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_RevokeIncomingWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeIncomingWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).RevokeIncomingWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_RevokeIncomingWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).RevokeIncomingWebhook(ctx, req.(*RevokeIncomingWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_RegisterCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).RegisterCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_RegisterCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).RegisterCommand(ctx, req.(*RegisterCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_UnregisterCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).UnregisterCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_UnregisterCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).UnregisterCommand(ctx, req.(*UnregisterCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommandsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListCommands_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListCommands(ctx, req.(*ListCommandsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_RespondToCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondToCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).RespondToCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_RespondToCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).RespondToCommand(ctx, req.(*RespondToCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-func (UnimplementedChatServiceServer) CreateChat(context.Context, *CreateChatRequest) (*CreateChatResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateChat not implemented")
+func _ChatService_ForwardMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ForwardMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ForwardMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ForwardMessage(ctx, req.(*ForwardMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChatServiceServer) ConnectChat(*ConnectChatRequest, grpc.ServerStreamingServer[ChatMessage]) error {
-	return status.Errorf(codes.Unimplemented, "method ConnectChat not implemented")
+
+func _ChatService_DeleteMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).DeleteMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_DeleteMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).DeleteMessage(ctx, req.(*DeleteMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChatServiceServer) SendMessage(context.Context, *SendMessageRequest) (*SendMessageResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
+
+func _ChatService_ListFlaggedMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFlaggedMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListFlaggedMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListFlaggedMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListFlaggedMessages(ctx, req.(*ListFlaggedMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChatServiceServer) mustEmbedUnimplementedChatServiceServer() {}
-func (UnimplementedChatServiceServer) testEmbeddedByValue()                     {}
 
-// UnsafeChatServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ChatServiceServer will
-// result in compilation errors.
-type UnsafeChatServiceServer interface {
-	mustEmbedUnimplementedChatServiceServer()
+func _ChatService_ApproveFlaggedMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveFlaggedMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ApproveFlaggedMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ApproveFlaggedMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ApproveFlaggedMessage(ctx, req.(*ApproveFlaggedMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterChatServiceServer(s grpc.ServiceRegistrar, srv ChatServiceServer) {
-	// If the following call pancis, it indicates UnimplementedChatServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _ChatService_RemoveFlaggedMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveFlaggedMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&ChatService_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(ChatServiceServer).RemoveFlaggedMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_RemoveFlaggedMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).RemoveFlaggedMessage(ctx, req.(*RemoveFlaggedMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ChatService_CreateChat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateChatRequest)
+func _ChatService_ReportMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatServiceServer).CreateChat(ctx, in)
+		return srv.(ChatServiceServer).ReportMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChatService_CreateChat_FullMethodName,
+		FullMethod: ChatService_ReportMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatServiceServer).CreateChat(ctx, req.(*CreateChatRequest))
+		return srv.(ChatServiceServer).ReportMessage(ctx, req.(*ReportMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChatService_ConnectChat_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ConnectChatRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _ChatService_ListReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(ChatServiceServer).ConnectChat(m, &grpc.GenericServerStream[ConnectChatRequest, ChatMessage]{ServerStream: stream})
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListReports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListReports(ctx, req.(*ListReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type ChatService_ConnectChatServer = grpc.ServerStreamingServer[ChatMessage]
+func _ChatService_BanUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).BanUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_BanUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).BanUser(ctx, req.(*BanUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-func _ChatService_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendMessageRequest)
+func _ChatService_MuteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MuteUserRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatServiceServer).SendMessage(ctx, in)
+		return srv.(ChatServiceServer).MuteUser(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChatService_SendMessage_FullMethodName,
+		FullMethod: ChatService_MuteUser_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatServiceServer).SendMessage(ctx, req.(*SendMessageRequest))
+		return srv.(ChatServiceServer).MuteUser(ctx, req.(*MuteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_LiftRestriction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LiftRestrictionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).LiftRestriction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_LiftRestriction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).LiftRestriction(ctx, req.(*LiftRestrictionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListRestrictions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRestrictionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListRestrictions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListRestrictions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListRestrictions(ctx, req.(*ListRestrictionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetAuditLog(ctx, req.(*GetAuditLogRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -205,6 +1577,154 @@ var ChatService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendMessage",
 			Handler:    _ChatService_SendMessage_Handler,
 		},
+		{
+			MethodName: "CreatePoll",
+			Handler:    _ChatService_CreatePoll_Handler,
+		},
+		{
+			MethodName: "Vote",
+			Handler:    _ChatService_Vote_Handler,
+		},
+		{
+			MethodName: "ClosePoll",
+			Handler:    _ChatService_ClosePoll_Handler,
+		},
+		{
+			MethodName: "ScheduleMessage",
+			Handler:    _ChatService_ScheduleMessage_Handler,
+		},
+		{
+			MethodName: "ListScheduledMessages",
+			Handler:    _ChatService_ListScheduledMessages_Handler,
+		},
+		{
+			MethodName: "CancelScheduledMessage",
+			Handler:    _ChatService_CancelScheduledMessage_Handler,
+		},
+		{
+			MethodName: "CreateInvite",
+			Handler:    _ChatService_CreateInvite_Handler,
+		},
+		{
+			MethodName: "RevokeInvite",
+			Handler:    _ChatService_RevokeInvite_Handler,
+		},
+		{
+			MethodName: "JoinByInvite",
+			Handler:    _ChatService_JoinByInvite_Handler,
+		},
+		{
+			MethodName: "SearchPublicChats",
+			Handler:    _ChatService_SearchPublicChats_Handler,
+		},
+		{
+			MethodName: "JoinChat",
+			Handler:    _ChatService_JoinChat_Handler,
+		},
+		{
+			MethodName: "UpdateChatSettings",
+			Handler:    _ChatService_UpdateChatSettings_Handler,
+		},
+		{
+			MethodName: "GetUnreadCounters",
+			Handler:    _ChatService_GetUnreadCounters_Handler,
+		},
+		{
+			MethodName: "MarkChatRead",
+			Handler:    _ChatService_MarkChatRead_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _ChatService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _ChatService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _ChatService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _ChatService_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "CreateIncomingWebhook",
+			Handler:    _ChatService_CreateIncomingWebhook_Handler,
+		},
+		{
+			MethodName: "ListIncomingWebhooks",
+			Handler:    _ChatService_ListIncomingWebhooks_Handler,
+		},
+		{
+			MethodName: "RevokeIncomingWebhook",
+			Handler:    _ChatService_RevokeIncomingWebhook_Handler,
+		},
+		{
+			MethodName: "RegisterCommand",
+			Handler:    _ChatService_RegisterCommand_Handler,
+		},
+		{
+			MethodName: "UnregisterCommand",
+			Handler:    _ChatService_UnregisterCommand_Handler,
+		},
+		{
+			MethodName: "ListCommands",
+			Handler:    _ChatService_ListCommands_Handler,
+		},
+		{
+			MethodName: "RespondToCommand",
+			Handler:    _ChatService_RespondToCommand_Handler,
+		},
+		{
+			MethodName: "ForwardMessage",
+			Handler:    _ChatService_ForwardMessage_Handler,
+		},
+		{
+			MethodName: "DeleteMessage",
+			Handler:    _ChatService_DeleteMessage_Handler,
+		},
+		{
+			MethodName: "ListFlaggedMessages",
+			Handler:    _ChatService_ListFlaggedMessages_Handler,
+		},
+		{
+			MethodName: "ApproveFlaggedMessage",
+			Handler:    _ChatService_ApproveFlaggedMessage_Handler,
+		},
+		{
+			MethodName: "RemoveFlaggedMessage",
+			Handler:    _ChatService_RemoveFlaggedMessage_Handler,
+		},
+		{
+			MethodName: "ReportMessage",
+			Handler:    _ChatService_ReportMessage_Handler,
+		},
+		{
+			MethodName: "ListReports",
+			Handler:    _ChatService_ListReports_Handler,
+		},
+		{
+			MethodName: "BanUser",
+			Handler:    _ChatService_BanUser_Handler,
+		},
+		{
+			MethodName: "MuteUser",
+			Handler:    _ChatService_MuteUser_Handler,
+		},
+		{
+			MethodName: "LiftRestriction",
+			Handler:    _ChatService_LiftRestriction_Handler,
+		},
+		{
+			MethodName: "ListRestrictions",
+			Handler:    _ChatService_ListRestrictions_Handler,
+		},
+		{
+			MethodName: "GetAuditLog",
+			Handler:    _ChatService_GetAuditLog_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -212,6 +1732,11 @@ var ChatService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ChatService_ConnectChat_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamNotifications",
+			Handler:       _ChatService_StreamNotifications_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "chat.proto",
 }
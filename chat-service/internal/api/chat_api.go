@@ -2,9 +2,12 @@ package api
 
 import (
 	"context"
+	"errors"
 	"log"
+	"time"
 
 	pb "chat.service/api/proto"
+	"chat.service/internal/models"
 	"chat.service/internal/service/chat_service"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -51,10 +54,15 @@ func (h *ChatServiceHandler) CreateChat(ctx context.Context, req *pb.CreateChatR
 	}
 
 	// Создаем чат
-	chatID, err := h.chatService.CreateChat(ctx, req.Name, userID, req.ParticipantUserIds)
+	chatID, err := h.chatService.CreateChat(ctx, req.Name, userID, req.ParticipantUserIds, visibilityFromProto(req.Visibility), chatKindFromProto(req.Type))
 	if err != nil {
 		log.Printf("Ошибка при создании чата: %v", err)
-		return nil, status.Error(codes.Internal, "ошибка при создании чата")
+		switch err {
+		case chat_service.ErrInvalidChat:
+			return nil, status.Error(codes.InvalidArgument, "для публичного чата необходимо указать название")
+		default:
+			return nil, status.Error(codes.Internal, "ошибка при создании чата")
+		}
 	}
 
 	return &pb.CreateChatResponse{
@@ -74,19 +82,18 @@ func (h *ChatServiceHandler) ConnectChat(req *pb.ConnectChatRequest, stream pb.C
 	messages, err := h.chatService.GetChatMessages(stream.Context(), req.ChatId, userID, 50, 0)
 	if err != nil {
 		log.Printf("Ошибка при получении сообщений чата: %v", err)
+		switch err {
+		case chat_service.ErrUserBanned:
+			return status.Error(codes.PermissionDenied, err.Error())
+		case chat_service.ErrUserNotInChat:
+			return status.Error(codes.PermissionDenied, "пользователь не является участником чата")
+		}
 		return status.Error(codes.Internal, "ошибка при получении сообщений чата")
 	}
 
 	// Отправляем последние сообщения клиенту
 	for _, msg := range messages {
-		pbMsg := &pb.ChatMessage{
-			MessageId: msg.ID,
-			ChatId:    msg.ChatID,
-			UserId:    msg.UserID,
-			Username:  msg.Username,
-			Text:      msg.Text,
-			Timestamp: timestamppb.New(msg.CreatedAt),
-		}
+		pbMsg := messageToProto(msg)
 
 		if err := stream.Send(pbMsg); err != nil {
 			log.Printf("Ошибка при отправке сообщения клиенту: %v", err)
@@ -98,12 +105,22 @@ func (h *ChatServiceHandler) ConnectChat(req *pb.ConnectChatRequest, stream pb.C
 	messageChan, subscriptionID, err := h.chatService.SubscribeToChat(stream.Context(), req.ChatId, userID)
 	if err != nil {
 		log.Printf("Ошибка при подписке на обновления чата: %v", err)
+		switch err {
+		case chat_service.ErrUserBanned:
+			return status.Error(codes.PermissionDenied, err.Error())
+		case chat_service.ErrUserNotInChat:
+			return status.Error(codes.PermissionDenied, "пользователь не является участником чата")
+		}
 		return status.Error(codes.Internal, "ошибка при подписке на обновления чата")
 	}
 
 	// Обеспечиваем отписку при завершении соединения
 	defer h.chatService.UnsubscribeFromChat(req.ChatId, subscriptionID)
 
+	// Сообщения, полученные через открытый стрим, считаются прочитанными
+	h.markRead(req.ChatId, userID)
+	defer h.markRead(req.ChatId, userID)
+
 	// Запускаем горутину для отправки новых сообщений клиенту
 	for {
 		select {
@@ -114,14 +131,7 @@ func (h *ChatServiceHandler) ConnectChat(req *pb.ConnectChatRequest, stream pb.C
 			}
 
 			// Конвертируем сообщение в protobuf формат
-			pbMsg := &pb.ChatMessage{
-				MessageId: message.ID,
-				ChatId:    message.ChatID,
-				UserId:    message.UserID,
-				Username:  message.Username,
-				Text:      message.Text,
-				Timestamp: timestamppb.New(message.CreatedAt),
-			}
+			pbMsg := messageToProto(message)
 
 			// Отправляем сообщение клиенту
 			if err := stream.Send(pbMsg); err != nil {
@@ -136,6 +146,13 @@ func (h *ChatServiceHandler) ConnectChat(req *pb.ConnectChatRequest, stream pb.C
 	}
 }
 
+// markRead отмечает чат прочитанным, не прерывая работу стрима при ошибке
+func (h *ChatServiceHandler) markRead(chatID, userID string) {
+	if err := h.chatService.MarkChatRead(context.Background(), chatID, userID); err != nil {
+		log.Printf("Ошибка при отметке чата %s прочитанным: %v", chatID, err)
+	}
+}
+
 // SendMessage отправляет сообщение в чат
 func (h *ChatServiceHandler) SendMessage(ctx context.Context, req *pb.SendMessageRequest) (*pb.SendMessageResponse, error) {
 	// Получаем ID пользователя из контекста
@@ -145,21 +162,1354 @@ func (h *ChatServiceHandler) SendMessage(ctx context.Context, req *pb.SendMessag
 	}
 
 	// Отправляем сообщение
-	messageID, timestamp, err := h.chatService.SendMessage(ctx, req.ChatId, userID, req.Text)
+	message, err := h.chatService.SendMessage(ctx, req.ChatId, userID, req.Text, formatFromProto(req.Format))
 	if err != nil {
 		log.Printf("Ошибка при отправке сообщения: %v", err)
+		if errors.Is(err, chat_service.ErrMessageRejected) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
 		switch err {
 		case chat_service.ErrUserNotInChat:
 			return nil, status.Error(codes.PermissionDenied, "пользователь не является участником чата")
+		case chat_service.ErrChannelReadOnly:
+			return nil, status.Error(codes.PermissionDenied, "в канале могут писать только администраторы")
+		case chat_service.ErrUserBanned, chat_service.ErrUserMuted:
+			return nil, status.Error(codes.PermissionDenied, err.Error())
 		case chat_service.ErrChatNotFound:
 			return nil, status.Error(codes.NotFound, "чат не найден")
+		case chat_service.ErrMessageTooLong, chat_service.ErrInvalidMarkup:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
 		default:
-			return nil, status.Error(codes.Internal, "ошибка при отправке сообщения")
+			// Ошибки выполнения slash-команд
+			return nil, commandError(err, "ошибка при отправке сообщения")
 		}
 	}
 
 	return &pb.SendMessageResponse{
-		MessageId: messageID,
-		Timestamp: timestamppb.New(timestamp),
+		MessageId:     message.ID,
+		Timestamp:     timestamppb.New(message.CreatedAt),
+		PendingReview: message.PendingReview,
 	}, nil
 }
+
+// CreatePoll публикует в чате сообщение с опросом
+func (h *ChatServiceHandler) CreatePoll(ctx context.Context, req *pb.CreatePollRequest) (*pb.ChatMessage, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := chat_service.PollParams{
+		Question:       req.Question,
+		Options:        req.Options,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+	}
+	if req.ClosesAt != nil {
+		closesAt := req.ClosesAt.AsTime()
+		params.ClosesAt = &closesAt
+	}
+
+	message, err := h.chatService.CreatePoll(ctx, req.ChatId, userID, params)
+	if err != nil {
+		log.Printf("Ошибка при создании опроса: %v", err)
+		if errors.Is(err, chat_service.ErrMessageRejected) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, pollError(err)
+	}
+
+	return messageToProto(message), nil
+}
+
+// Vote учитывает голос пользователя в опросе
+func (h *ChatServiceHandler) Vote(ctx context.Context, req *pb.VoteRequest) (*pb.Poll, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	optionIndexes := make([]int, 0, len(req.OptionIndexes))
+	for _, idx := range req.OptionIndexes {
+		optionIndexes = append(optionIndexes, int(idx))
+	}
+
+	poll, err := h.chatService.Vote(ctx, req.PollId, userID, optionIndexes)
+	if err != nil {
+		log.Printf("Ошибка при голосовании в опросе: %v", err)
+		return nil, pollError(err)
+	}
+
+	return pollToProto(poll), nil
+}
+
+// ClosePoll досрочно закрывает опрос
+func (h *ChatServiceHandler) ClosePoll(ctx context.Context, req *pb.ClosePollRequest) (*pb.Poll, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := h.chatService.ClosePoll(ctx, req.PollId, userID)
+	if err != nil {
+		log.Printf("Ошибка при закрытии опроса: %v", err)
+		return nil, pollError(err)
+	}
+
+	return pollToProto(poll), nil
+}
+
+// ForwardMessage пересылает сообщение в другой чат
+func (h *ChatServiceHandler) ForwardMessage(ctx context.Context, req *pb.ForwardMessageRequest) (*pb.ChatMessage, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := h.chatService.ForwardMessage(ctx, req.SourceMessageId, req.TargetChatId, userID)
+	if err != nil {
+		log.Printf("Ошибка при пересылке сообщения: %v", err)
+		if errors.Is(err, chat_service.ErrMessageRejected) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, messageError(err, "ошибка при пересылке сообщения")
+	}
+
+	pbMsg := messageToProto(message)
+	pbMsg.PendingReview = message.PendingReview
+
+	return pbMsg, nil
+}
+
+// DeleteMessage удаляет сообщение
+func (h *ChatServiceHandler) DeleteMessage(ctx context.Context, req *pb.DeleteMessageRequest) (*pb.DeleteMessageResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.chatService.DeleteMessage(ctx, req.MessageId, userID); err != nil {
+		log.Printf("Ошибка при удалении сообщения: %v", err)
+		return nil, messageError(err, "ошибка при удалении сообщения")
+	}
+
+	return &pb.DeleteMessageResponse{}, nil
+}
+
+// ListFlaggedMessages возвращает сообщения чата, ожидающие проверки модератором
+func (h *ChatServiceHandler) ListFlaggedMessages(ctx context.Context, req *pb.ListFlaggedMessagesRequest) (*pb.ListFlaggedMessagesResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := h.chatService.ListFlaggedMessages(ctx, req.ChatId, userID)
+	if err != nil {
+		log.Printf("Ошибка при получении очереди модерации: %v", err)
+		return nil, moderationError(err, "ошибка при получении очереди модерации")
+	}
+
+	response := &pb.ListFlaggedMessagesResponse{}
+	for _, message := range messages {
+		response.Messages = append(response.Messages, flaggedToProto(message))
+	}
+
+	return response, nil
+}
+
+// ApproveFlaggedMessage одобряет сообщение из очереди модерации
+func (h *ChatServiceHandler) ApproveFlaggedMessage(ctx context.Context, req *pb.ApproveFlaggedMessageRequest) (*pb.ChatMessage, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := h.chatService.ApproveFlaggedMessage(ctx, req.FlaggedMessageId, userID)
+	if err != nil {
+		log.Printf("Ошибка при одобрении сообщения: %v", err)
+		return nil, moderationError(err, "ошибка при одобрении сообщения")
+	}
+
+	return messageToProto(message), nil
+}
+
+// RemoveFlaggedMessage удаляет сообщение из очереди модерации без публикации
+func (h *ChatServiceHandler) RemoveFlaggedMessage(ctx context.Context, req *pb.RemoveFlaggedMessageRequest) (*pb.RemoveFlaggedMessageResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.chatService.RemoveFlaggedMessage(ctx, req.FlaggedMessageId, userID); err != nil {
+		log.Printf("Ошибка при удалении сообщения из очереди модерации: %v", err)
+		return nil, moderationError(err, "ошибка при удалении сообщения из очереди модерации")
+	}
+
+	return &pb.RemoveFlaggedMessageResponse{}, nil
+}
+
+// ReportMessage сохраняет жалобу на сообщение
+func (h *ChatServiceHandler) ReportMessage(ctx context.Context, req *pb.ReportMessageRequest) (*pb.ReportMessageResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := h.chatService.ReportMessage(ctx, req.MessageId, userID, req.Reason)
+	if err != nil {
+		log.Printf("Ошибка при отправке жалобы: %v", err)
+		return nil, restrictionError(err, "ошибка при отправке жалобы")
+	}
+
+	return &pb.ReportMessageResponse{ReportId: report.ID}, nil
+}
+
+// ListReports возвращает жалобы на сообщения чата
+func (h *ChatServiceHandler) ListReports(ctx context.Context, req *pb.ListReportsRequest) (*pb.ListReportsResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := h.chatService.ListReports(ctx, req.ChatId, userID, int(req.Limit))
+	if err != nil {
+		log.Printf("Ошибка при получении жалоб: %v", err)
+		return nil, restrictionError(err, "ошибка при получении жалоб")
+	}
+
+	response := &pb.ListReportsResponse{}
+	for _, report := range reports {
+		response.Reports = append(response.Reports, &pb.MessageReport{
+			ReportId:       report.ID,
+			ChatId:         report.ChatID,
+			MessageId:      report.MessageID,
+			ReporterId:     report.ReporterID,
+			Reason:         report.Reason,
+			CreatedAt:      timestamppb.New(report.CreatedAt),
+			AuthorId:       report.AuthorID,
+			AuthorUsername: report.AuthorUsername,
+			MessageText:    report.MessageText,
+		})
+	}
+
+	return response, nil
+}
+
+// BanUser блокирует пользователя в чате
+func (h *ChatServiceHandler) BanUser(ctx context.Context, req *pb.BanUserRequest) (*pb.ChatRestriction, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	restriction, err := h.chatService.BanUser(ctx, req.ChatId, userID, req.Username, req.Reason)
+	if err != nil {
+		log.Printf("Ошибка при блокировке пользователя: %v", err)
+		return nil, restrictionError(err, "ошибка при блокировке пользователя")
+	}
+
+	return restrictionToProto(restriction), nil
+}
+
+// MuteUser временно запрещает пользователю писать в чат
+func (h *ChatServiceHandler) MuteUser(ctx context.Context, req *pb.MuteUserRequest) (*pb.ChatRestriction, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(req.DurationSeconds) * time.Second
+	restriction, err := h.chatService.MuteUser(ctx, req.ChatId, userID, req.Username, req.Reason, duration)
+	if err != nil {
+		log.Printf("Ошибка при ограничении пользователя: %v", err)
+		return nil, restrictionError(err, "ошибка при ограничении пользователя")
+	}
+
+	return restrictionToProto(restriction), nil
+}
+
+// LiftRestriction снимает ограничение участника чата
+func (h *ChatServiceHandler) LiftRestriction(ctx context.Context, req *pb.LiftRestrictionRequest) (*pb.LiftRestrictionResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.chatService.LiftRestriction(ctx, req.ChatId, userID, req.Username, restrictionKindFromProto(req.Kind)); err != nil {
+		log.Printf("Ошибка при снятии ограничения: %v", err)
+		return nil, restrictionError(err, "ошибка при снятии ограничения")
+	}
+
+	return &pb.LiftRestrictionResponse{}, nil
+}
+
+// ListRestrictions возвращает действующие ограничения участников чата
+func (h *ChatServiceHandler) ListRestrictions(ctx context.Context, req *pb.ListRestrictionsRequest) (*pb.ListRestrictionsResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictions, err := h.chatService.ListRestrictions(ctx, req.ChatId, userID)
+	if err != nil {
+		log.Printf("Ошибка при получении ограничений: %v", err)
+		return nil, restrictionError(err, "ошибка при получении ограничений")
+	}
+
+	response := &pb.ListRestrictionsResponse{}
+	for _, restriction := range restrictions {
+		response.Restrictions = append(response.Restrictions, restrictionToProto(restriction))
+	}
+
+	return response, nil
+}
+
+// GetAuditLog возвращает журнал модерации чата
+func (h *ChatServiceHandler) GetAuditLog(ctx context.Context, req *pb.GetAuditLogRequest) (*pb.GetAuditLogResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := h.chatService.GetAuditLog(ctx, req.ChatId, userID, int(req.Limit))
+	if err != nil {
+		log.Printf("Ошибка при получении журнала модерации: %v", err)
+		return nil, restrictionError(err, "ошибка при получении журнала модерации")
+	}
+
+	response := &pb.GetAuditLogResponse{}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, &pb.AuditEntry{
+			EntryId:         entry.ID,
+			ChatId:          entry.ChatID,
+			ActorId:         entry.ActorID,
+			ActorUsername:   entry.ActorUsername,
+			Action:          entry.Action,
+			TargetUserId:    entry.TargetUserID,
+			TargetUsername:  entry.TargetUsername,
+			TargetMessageId: entry.TargetMessageID,
+			Details:         entry.Details,
+			CreatedAt:       timestamppb.New(entry.CreatedAt),
+		})
+	}
+
+	return response, nil
+}
+
+// ScheduleMessage планирует отправку сообщения
+func (h *ChatServiceHandler) ScheduleMessage(ctx context.Context, req *pb.ScheduleMessageRequest) (*pb.ScheduledMessage, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SendAt == nil {
+		return nil, status.Error(codes.InvalidArgument, "не указано время отправки")
+	}
+
+	scheduled, err := h.chatService.ScheduleMessage(ctx, req.ChatId, userID, req.Text, req.SendAt.AsTime())
+	if err != nil {
+		log.Printf("Ошибка при планировании сообщения: %v", err)
+		switch err {
+		case chat_service.ErrInvalidChatID, chat_service.ErrInvalidMessage, chat_service.ErrInvalidSchedule, chat_service.ErrMessageTooLong:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case chat_service.ErrUserNotInChat, chat_service.ErrChannelReadOnly, chat_service.ErrUserBanned, chat_service.ErrUserMuted:
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case chat_service.ErrChatNotFound:
+			return nil, status.Error(codes.NotFound, "чат не найден")
+		default:
+			return nil, status.Error(codes.Internal, "ошибка при планировании сообщения")
+		}
+	}
+
+	return scheduledToProto(scheduled), nil
+}
+
+// ListScheduledMessages возвращает ожидающие отправки сообщения пользователя
+func (h *ChatServiceHandler) ListScheduledMessages(ctx context.Context, req *pb.ListScheduledMessagesRequest) (*pb.ListScheduledMessagesResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := h.chatService.ListScheduledMessages(ctx, userID, req.ChatId)
+	if err != nil {
+		log.Printf("Ошибка при получении отложенных сообщений: %v", err)
+		return nil, status.Error(codes.Internal, "ошибка при получении отложенных сообщений")
+	}
+
+	resp := &pb.ListScheduledMessagesResponse{
+		Messages: make([]*pb.ScheduledMessage, 0, len(messages)),
+	}
+	for _, scheduled := range messages {
+		resp.Messages = append(resp.Messages, scheduledToProto(scheduled))
+	}
+
+	return resp, nil
+}
+
+// CancelScheduledMessage отменяет отложенное сообщение
+func (h *ChatServiceHandler) CancelScheduledMessage(ctx context.Context, req *pb.CancelScheduledMessageRequest) (*pb.CancelScheduledMessageResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.chatService.CancelScheduledMessage(ctx, req.ScheduledMessageId, userID); err != nil {
+		log.Printf("Ошибка при отмене отложенного сообщения: %v", err)
+		switch err {
+		case chat_service.ErrScheduledMessageNotFound:
+			return nil, status.Error(codes.NotFound, "отложенное сообщение не найдено или уже отправлено")
+		default:
+			return nil, status.Error(codes.Internal, "ошибка при отмене отложенного сообщения")
+		}
+	}
+
+	return &pb.CancelScheduledMessageResponse{}, nil
+}
+
+// SearchPublicChats ищет публичные чаты по началу названия
+func (h *ChatServiceHandler) SearchPublicChats(ctx context.Context, req *pb.SearchPublicChatsRequest) (*pb.SearchPublicChatsResponse, error) {
+	chats, err := h.chatService.SearchPublicChats(ctx, req.Query, int(req.Limit))
+	if err != nil {
+		log.Printf("Ошибка при поиске публичных чатов: %v", err)
+		return nil, status.Error(codes.Internal, "ошибка при поиске чатов")
+	}
+
+	resp := &pb.SearchPublicChatsResponse{
+		Chats: make([]*pb.ChatInfo, 0, len(chats)),
+	}
+	for _, chat := range chats {
+		resp.Chats = append(resp.Chats, chatToProto(chat))
+	}
+
+	return resp, nil
+}
+
+// JoinChat добавляет пользователя в публичный чат
+func (h *ChatServiceHandler) JoinChat(ctx context.Context, req *pb.JoinChatRequest) (*pb.JoinChatResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chat, err := h.chatService.JoinChat(ctx, req.ChatId, userID)
+	if err != nil {
+		log.Printf("Ошибка при вступлении в чат: %v", err)
+		switch err {
+		case chat_service.ErrInvalidChatID:
+			return nil, status.Error(codes.InvalidArgument, "некорректный ID чата")
+		case chat_service.ErrChatNotFound:
+			return nil, status.Error(codes.NotFound, "чат не найден")
+		case chat_service.ErrChatNotPublic:
+			return nil, status.Error(codes.PermissionDenied, "вступить в приватный чат можно только по приглашению")
+		case chat_service.ErrUserBanned:
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, "ошибка при вступлении в чат")
+		}
+	}
+
+	return &pb.JoinChatResponse{
+		Chat: chatToProto(chat),
+	}, nil
+}
+
+// UpdateChatSettings изменяет персональные настройки чата
+func (h *ChatServiceHandler) UpdateChatSettings(ctx context.Context, req *pb.UpdateChatSettingsRequest) (*pb.ChatSettings, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	update := chat_service.SettingsUpdate{
+		NotifyLevel: notifyLevelFromProto(req.NotifyLevel),
+		Hidden:      req.Hidden,
+	}
+	if req.MutedUntil != nil {
+		mutedUntil := req.MutedUntil.AsTime()
+		update.MutedUntil = &mutedUntil
+	}
+
+	settings, err := h.chatService.UpdateChatSettings(ctx, req.ChatId, userID, update)
+	if err != nil {
+		log.Printf("Ошибка при обновлении настроек чата: %v", err)
+		switch err {
+		case chat_service.ErrInvalidChatID, chat_service.ErrInvalidSettings:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case chat_service.ErrUserNotInChat:
+			return nil, status.Error(codes.PermissionDenied, "пользователь не является участником чата")
+		default:
+			return nil, status.Error(codes.Internal, "ошибка при обновлении настроек чата")
+		}
+	}
+
+	return settingsToProto(settings), nil
+}
+
+// GetUnreadCounters возвращает счетчики непрочитанных сообщений по чатам пользователя
+func (h *ChatServiceHandler) GetUnreadCounters(ctx context.Context, req *pb.GetUnreadCountersRequest) (*pb.GetUnreadCountersResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counters, err := h.chatService.GetUnreadCounters(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка при получении счетчиков непрочитанных сообщений: %v", err)
+		return nil, status.Error(codes.Internal, "ошибка при получении счетчиков")
+	}
+
+	now := time.Now()
+	resp := &pb.GetUnreadCountersResponse{
+		Counters: make([]*pb.UnreadCounter, 0, len(counters)),
+	}
+	for _, counter := range counters {
+		resp.Counters = append(resp.Counters, &pb.UnreadCounter{
+			ChatId:       counter.ChatID,
+			UnreadCount:  int32(counter.Unread),
+			MentionCount: int32(counter.Mentions),
+			Muted:        counter.IsMuted(now),
+		})
+	}
+
+	return resp, nil
+}
+
+// MarkChatRead отмечает чат прочитанным
+func (h *ChatServiceHandler) MarkChatRead(ctx context.Context, req *pb.MarkChatReadRequest) (*pb.MarkChatReadResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.chatService.MarkChatRead(ctx, req.ChatId, userID); err != nil {
+		log.Printf("Ошибка при отметке чата прочитанным: %v", err)
+		switch err {
+		case chat_service.ErrInvalidChatID:
+			return nil, status.Error(codes.InvalidArgument, "некорректный ID чата")
+		default:
+			return nil, status.Error(codes.Internal, "ошибка при отметке чата прочитанным")
+		}
+	}
+
+	return &pb.MarkChatReadResponse{}, nil
+}
+
+// StreamNotifications отправляет клиенту уведомления по всем чатам пользователя
+func (h *ChatServiceHandler) StreamNotifications(req *pb.StreamNotificationsRequest, stream pb.ChatService_StreamNotificationsServer) error {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	notificationChan, subscriptionID, err := h.chatService.SubscribeToNotifications(userID)
+	if err != nil {
+		log.Printf("Ошибка при подписке на уведомления: %v", err)
+		return status.Error(codes.Internal, "ошибка при подписке на уведомления")
+	}
+
+	// Обеспечиваем отписку при завершении соединения
+	defer h.chatService.UnsubscribeFromNotifications(userID, subscriptionID)
+
+	for {
+		select {
+		case notification, ok := <-notificationChan:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.Send(notificationToProto(notification)); err != nil {
+				log.Printf("Ошибка при отправке уведомления клиенту: %v", err)
+				return status.Error(codes.Internal, "ошибка при отправке уведомления")
+			}
+
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// CreateInvite создает пригласительную ссылку в чат
+func (h *ChatServiceHandler) CreateInvite(ctx context.Context, req *pb.CreateInviteRequest) (*pb.CreateInviteResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt time.Time
+	if req.ExpiresAt != nil {
+		expiresAt = req.ExpiresAt.AsTime()
+	}
+
+	invite, code, err := h.chatService.CreateInvite(ctx, req.ChatId, userID, expiresAt, int(req.MaxUses), roleFromProto(req.DefaultRole))
+	if err != nil {
+		log.Printf("Ошибка при создании приглашения: %v", err)
+		return nil, inviteError(err)
+	}
+
+	return &pb.CreateInviteResponse{
+		InviteId:  invite.ID,
+		Code:      code,
+		ExpiresAt: timestamppb.New(invite.ExpiresAt),
+	}, nil
+}
+
+// RevokeInvite отзывает пригласительную ссылку
+func (h *ChatServiceHandler) RevokeInvite(ctx context.Context, req *pb.RevokeInviteRequest) (*pb.RevokeInviteResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.chatService.RevokeInvite(ctx, req.InviteId, userID); err != nil {
+		log.Printf("Ошибка при отзыве приглашения: %v", err)
+		return nil, inviteError(err)
+	}
+
+	return &pb.RevokeInviteResponse{}, nil
+}
+
+// JoinByInvite добавляет пользователя в чат по коду приглашения
+func (h *ChatServiceHandler) JoinByInvite(ctx context.Context, req *pb.JoinByInviteRequest) (*pb.JoinByInviteResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "код приглашения не указан")
+	}
+
+	chatID, role, err := h.chatService.JoinByInvite(ctx, req.Code, userID)
+	if err != nil {
+		log.Printf("Ошибка при вступлении в чат по приглашению: %v", err)
+		return nil, inviteError(err)
+	}
+
+	return &pb.JoinByInviteResponse{
+		ChatId: chatID,
+		Role:   roleToProto(role),
+	}, nil
+}
+
+// CreateWebhook создает исходящий вебхук чата
+func (h *ChatServiceHandler) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.Webhook, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := h.chatService.CreateWebhook(ctx, req.ChatId, userID, req.Url)
+	if err != nil {
+		log.Printf("Ошибка при создании вебхука: %v", err)
+		return nil, webhookError(err)
+	}
+
+	// Секрет возвращается только при создании вебхука
+	resp := webhookToProto(webhook)
+	resp.Secret = webhook.Secret
+
+	return resp, nil
+}
+
+// ListWebhooks возвращает вебхуки чата
+func (h *ChatServiceHandler) ListWebhooks(ctx context.Context, req *pb.ListWebhooksRequest) (*pb.ListWebhooksResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := h.chatService.ListWebhooks(ctx, req.ChatId, userID)
+	if err != nil {
+		log.Printf("Ошибка при получении вебхуков: %v", err)
+		return nil, webhookError(err)
+	}
+
+	resp := &pb.ListWebhooksResponse{
+		Webhooks: make([]*pb.Webhook, 0, len(webhooks)),
+	}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, webhookToProto(webhook))
+	}
+
+	return resp, nil
+}
+
+// DeleteWebhook удаляет вебхук
+func (h *ChatServiceHandler) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.DeleteWebhookResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.chatService.DeleteWebhook(ctx, req.WebhookId, userID); err != nil {
+		log.Printf("Ошибка при удалении вебхука: %v", err)
+		return nil, webhookError(err)
+	}
+
+	return &pb.DeleteWebhookResponse{}, nil
+}
+
+// ListWebhookDeliveries возвращает журнал доставки вебхука
+func (h *ChatServiceHandler) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := h.chatService.ListWebhookDeliveries(ctx, req.WebhookId, userID, int(req.Limit))
+	if err != nil {
+		log.Printf("Ошибка при получении журнала доставки вебхука: %v", err)
+		return nil, webhookError(err)
+	}
+
+	resp := &pb.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*pb.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, &pb.WebhookDelivery{
+			DeliveryId: delivery.ID,
+			WebhookId:  delivery.WebhookID,
+			MessageId:  delivery.MessageID,
+			Attempt:    int32(delivery.Attempt),
+			StatusCode: int32(delivery.StatusCode),
+			Error:      delivery.Error,
+			Success:    delivery.Success,
+			DurationMs: delivery.DurationMs,
+			CreatedAt:  timestamppb.New(delivery.CreatedAt),
+		})
+	}
+
+	return resp, nil
+}
+
+// CreateIncomingWebhook создает входящий вебхук чата
+func (h *ChatServiceHandler) CreateIncomingWebhook(ctx context.Context, req *pb.CreateIncomingWebhookRequest) (*pb.IncomingWebhook, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, token, err := h.chatService.CreateIncomingWebhook(ctx, req.ChatId, userID, req.Name)
+	if err != nil {
+		log.Printf("Ошибка при создании входящего вебхука: %v", err)
+		return nil, webhookError(err)
+	}
+
+	// Токен возвращается только при создании вебхука
+	resp := incomingWebhookToProto(webhook)
+	resp.Token = token
+	resp.Path = chat_service.IncomingWebhookPath(token)
+
+	return resp, nil
+}
+
+// ListIncomingWebhooks возвращает действующие входящие вебхуки чата
+func (h *ChatServiceHandler) ListIncomingWebhooks(ctx context.Context, req *pb.ListIncomingWebhooksRequest) (*pb.ListIncomingWebhooksResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := h.chatService.ListIncomingWebhooks(ctx, req.ChatId, userID)
+	if err != nil {
+		log.Printf("Ошибка при получении входящих вебхуков: %v", err)
+		return nil, webhookError(err)
+	}
+
+	resp := &pb.ListIncomingWebhooksResponse{
+		Webhooks: make([]*pb.IncomingWebhook, 0, len(webhooks)),
+	}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, incomingWebhookToProto(webhook))
+	}
+
+	return resp, nil
+}
+
+// RevokeIncomingWebhook отзывает входящий вебхук
+func (h *ChatServiceHandler) RevokeIncomingWebhook(ctx context.Context, req *pb.RevokeIncomingWebhookRequest) (*pb.RevokeIncomingWebhookResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.chatService.RevokeIncomingWebhook(ctx, req.IncomingWebhookId, userID); err != nil {
+		log.Printf("Ошибка при отзыве входящего вебхука: %v", err)
+		return nil, webhookError(err)
+	}
+
+	return &pb.RevokeIncomingWebhookResponse{}, nil
+}
+
+// RegisterCommand регистрирует slash-команду бота в чате
+func (h *ChatServiceHandler) RegisterCommand(ctx context.Context, req *pb.RegisterCommandRequest) (*pb.RegisterCommandResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.chatService.RegisterCommand(ctx, req.ChatId, userID, req.Name, req.Description); err != nil {
+		log.Printf("Ошибка при регистрации команды: %v", err)
+		return nil, commandError(err, "ошибка при регистрации команды")
+	}
+
+	return &pb.RegisterCommandResponse{}, nil
+}
+
+// UnregisterCommand удаляет slash-команду бота из чата
+func (h *ChatServiceHandler) UnregisterCommand(ctx context.Context, req *pb.UnregisterCommandRequest) (*pb.UnregisterCommandResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.chatService.UnregisterCommand(ctx, req.ChatId, userID, req.Name); err != nil {
+		log.Printf("Ошибка при удалении команды: %v", err)
+		return nil, commandError(err, "ошибка при удалении команды")
+	}
+
+	return &pb.UnregisterCommandResponse{}, nil
+}
+
+// ListCommands возвращает команды, доступные в чате
+func (h *ChatServiceHandler) ListCommands(ctx context.Context, req *pb.ListCommandsRequest) (*pb.ListCommandsResponse, error) {
+	if _, err := getUserIDFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	commands, err := h.chatService.ListCommands(ctx, req.ChatId)
+	if err != nil {
+		log.Printf("Ошибка при получении списка команд: %v", err)
+		return nil, commandError(err, "ошибка при получении списка команд")
+	}
+
+	resp := &pb.ListCommandsResponse{
+		Commands: make([]*pb.CommandInfo, 0, len(commands)),
+	}
+	for _, command := range commands {
+		resp.Commands = append(resp.Commands, &pb.CommandInfo{
+			Name:        command.Name,
+			Usage:       command.Usage,
+			Description: command.Description,
+			BotId:       command.BotID,
+		})
+	}
+
+	return resp, nil
+}
+
+// RespondToCommand публикует ответ бота на вызов команды
+func (h *ChatServiceHandler) RespondToCommand(ctx context.Context, req *pb.RespondToCommandRequest) (*pb.RespondToCommandResponse, error) {
+	// Получаем ID пользователя из контекста
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := h.chatService.RespondToCommand(ctx, req.InvocationId, userID, req.Text, req.Ephemeral)
+	if err != nil {
+		log.Printf("Ошибка при ответе на команду: %v", err)
+		if errors.Is(err, chat_service.ErrMessageRejected) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, commandError(err, "ошибка при ответе на команду")
+	}
+
+	// Эфемерные ответы не сохраняются, их ID не возвращается
+	if message.Ephemeral {
+		return &pb.RespondToCommandResponse{}, nil
+	}
+
+	return &pb.RespondToCommandResponse{MessageId: message.ID, PendingReview: message.PendingReview}, nil
+}
+
+// commandError преобразует ошибки выполнения slash-команд в gRPC статусы
+func commandError(err error, fallback string) error {
+	// Ошибка неизвестной команды содержит список доступных команд
+	if errors.Is(err, chat_service.ErrUnknownCommand) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	switch err {
+	case chat_service.ErrInvalidChatID, chat_service.ErrInvalidMessage, chat_service.ErrInvalidCommand:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case chat_service.ErrUserNotInChat, chat_service.ErrNotChatAdmin, chat_service.ErrChannelReadOnly, chat_service.ErrNotBot,
+		chat_service.ErrUserBanned, chat_service.ErrUserMuted:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case chat_service.ErrChatNotFound, chat_service.ErrUserNotFound, chat_service.ErrCommandNotFound, chat_service.ErrInvocationNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case chat_service.ErrCommandTaken:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case chat_service.ErrBotUnavailable:
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, fallback)
+	}
+}
+
+// webhookError преобразует ошибки работы с вебхуками в gRPC статусы
+func webhookError(err error) error {
+	switch err {
+	case chat_service.ErrInvalidChatID, chat_service.ErrInvalidWebhook, chat_service.ErrInvalidWebhookUsername:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case chat_service.ErrUserNotInChat, chat_service.ErrNotChatAdmin:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case chat_service.ErrWebhookNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, "ошибка при работе с вебхуком")
+	}
+}
+
+// inviteError преобразует ошибки работы с приглашениями в gRPC статусы
+func inviteError(err error) error {
+	switch err {
+	case chat_service.ErrInvalidChatID, chat_service.ErrInvalidInvite:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case chat_service.ErrUserNotInChat, chat_service.ErrNotChatAdmin, chat_service.ErrUserBanned:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case chat_service.ErrInviteNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case chat_service.ErrInviteExpired, chat_service.ErrInviteRevoked, chat_service.ErrInviteExhausted:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, "ошибка при работе с приглашением")
+	}
+}
+
+// pollError преобразует ошибки работы с опросами в gRPC статусы
+func pollError(err error) error {
+	switch err {
+	case chat_service.ErrInvalidChatID, chat_service.ErrInvalidPoll, chat_service.ErrInvalidVote:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case chat_service.ErrUserNotInChat, chat_service.ErrChannelReadOnly, chat_service.ErrNotPollOwner, chat_service.ErrUserMuted:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case chat_service.ErrPollNotFound, chat_service.ErrChatNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case chat_service.ErrPollClosed:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, "ошибка при работе с опросом")
+	}
+}
+
+// roleFromProto преобразует роль из protobuf в строковое представление
+func messageError(err error, fallback string) error {
+	switch err {
+	case chat_service.ErrInvalidChatID, chat_service.ErrInvalidUserID, chat_service.ErrCannotForward:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case chat_service.ErrUserNotInChat, chat_service.ErrChannelReadOnly, chat_service.ErrNotMessageAuthor, chat_service.ErrUserBanned, chat_service.ErrUserMuted:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case chat_service.ErrMessageNotFound, chat_service.ErrChatNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, fallback)
+	}
+}
+
+func moderationError(err error, fallback string) error {
+	switch err {
+	case chat_service.ErrInvalidChatID:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case chat_service.ErrUserNotInChat, chat_service.ErrNotChatAdmin:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case chat_service.ErrFlaggedMessageNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case chat_service.ErrAuthorCannotPost:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, fallback)
+	}
+}
+
+func restrictionError(err error, fallback string) error {
+	switch err {
+	case chat_service.ErrInvalidChatID, chat_service.ErrInvalidRestriction, chat_service.ErrInvalidReport:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case chat_service.ErrUserNotInChat, chat_service.ErrNotChatAdmin, chat_service.ErrCannotRestrict:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case chat_service.ErrUserNotFound, chat_service.ErrMessageNotFound, chat_service.ErrRestrictionNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case chat_service.ErrAlreadyReported:
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, fallback)
+	}
+}
+
+func roleFromProto(role pb.ChatRole) string {
+	switch role {
+	case pb.ChatRole_CHAT_ROLE_ADMIN:
+		return models.RoleAdmin
+	case pb.ChatRole_CHAT_ROLE_MEMBER:
+		return models.RoleMember
+	default:
+		return ""
+	}
+}
+
+// roleToProto преобразует строковую роль в protobuf
+func roleToProto(role string) pb.ChatRole {
+	switch role {
+	case models.RoleAdmin:
+		return pb.ChatRole_CHAT_ROLE_ADMIN
+	case models.RoleMember:
+		return pb.ChatRole_CHAT_ROLE_MEMBER
+	default:
+		return pb.ChatRole_CHAT_ROLE_UNSPECIFIED
+	}
+}
+
+// chatToProto преобразует модель чата в protobuf
+func chatToProto(chat *models.Chat) *pb.ChatInfo {
+	info := &pb.ChatInfo{
+		ChatId:    chat.ID,
+		Name:      chat.Name,
+		Topic:     chat.Topic,
+		CreatedAt: timestamppb.New(chat.CreatedAt),
+	}
+
+	switch chat.Visibility {
+	case models.VisibilityPublic:
+		info.Visibility = pb.ChatVisibility_CHAT_VISIBILITY_PUBLIC
+	default:
+		info.Visibility = pb.ChatVisibility_CHAT_VISIBILITY_PRIVATE
+	}
+
+	switch chat.Kind {
+	case models.ChatKindChannel:
+		info.Type = pb.ChatType_CHAT_TYPE_CHANNEL
+	default:
+		info.Type = pb.ChatType_CHAT_TYPE_GROUP
+	}
+
+	return info
+}
+
+// visibilityFromProto преобразует видимость чата из protobuf
+func visibilityFromProto(visibility pb.ChatVisibility) string {
+	if visibility == pb.ChatVisibility_CHAT_VISIBILITY_PUBLIC {
+		return models.VisibilityPublic
+	}
+	return models.VisibilityPrivate
+}
+
+// chatKindFromProto преобразует тип чата из protobuf
+func chatKindFromProto(chatType pb.ChatType) string {
+	if chatType == pb.ChatType_CHAT_TYPE_CHANNEL {
+		return models.ChatKindChannel
+	}
+	return models.ChatKindGroup
+}
+
+// settingsToProto преобразует настройки участника в protobuf
+func settingsToProto(settings *models.ParticipantSettings) *pb.ChatSettings {
+	resp := &pb.ChatSettings{
+		ChatId:      settings.ChatID,
+		NotifyLevel: pb.NotifyLevel_NOTIFY_LEVEL_ALL,
+		Hidden:      settings.Hidden,
+	}
+
+	if settings.NotifyLevel == models.NotifyMentions {
+		resp.NotifyLevel = pb.NotifyLevel_NOTIFY_LEVEL_MENTIONS
+	}
+
+	if settings.IsMuted(time.Now()) {
+		resp.MutedUntil = timestamppb.New(*settings.MutedUntil)
+	}
+
+	return resp
+}
+
+// notifyLevelFromProto преобразует уровень уведомлений из protobuf
+func notifyLevelFromProto(level pb.NotifyLevel) string {
+	switch level {
+	case pb.NotifyLevel_NOTIFY_LEVEL_ALL:
+		return models.NotifyAll
+	case pb.NotifyLevel_NOTIFY_LEVEL_MENTIONS:
+		return models.NotifyMentions
+	default:
+		return ""
+	}
+}
+
+// scheduledToProto преобразует отложенное сообщение в protobuf
+func scheduledToProto(scheduled *models.ScheduledMessage) *pb.ScheduledMessage {
+	return &pb.ScheduledMessage{
+		ScheduledMessageId: scheduled.ID,
+		ChatId:             scheduled.ChatID,
+		Text:               scheduled.Text,
+		SendAt:             timestamppb.New(scheduled.SendAt),
+		CreatedAt:          timestamppb.New(scheduled.CreatedAt),
+	}
+}
+
+// messageToProto преобразует сообщение в protobuf
+func messageToProto(message *models.Message) *pb.ChatMessage {
+	pbMsg := &pb.ChatMessage{
+		MessageId: message.ID,
+		ChatId:    message.ChatID,
+		UserId:    message.UserID,
+		Username:  message.Username,
+		Text:      message.Text,
+		Timestamp: timestamppb.New(message.CreatedAt),
+		Event:     pb.MessageEvent_MESSAGE_EVENT_NEW,
+		Kind:      pb.MessageKind_MESSAGE_KIND_TEXT,
+		Ephemeral: message.Ephemeral,
+		Deleted:   message.DeletedAt != nil,
+	}
+
+	switch message.Event {
+	case models.MessageEventUpdated:
+		pbMsg.Event = pb.MessageEvent_MESSAGE_EVENT_UPDATED
+	case models.MessageEventDeleted:
+		pbMsg.Event = pb.MessageEvent_MESSAGE_EVENT_DELETED
+	case models.MessageEventCommand:
+		pbMsg.Event = pb.MessageEvent_MESSAGE_EVENT_COMMAND
+		if message.Command != nil {
+			pbMsg.Command = &pb.CommandInvocation{
+				InvocationId: message.Command.ID,
+				Name:         message.Command.Name,
+				Args:         message.Command.Args,
+			}
+		}
+	}
+
+	if message.IsForwarded() {
+		pbMsg.ForwardedFrom = &pb.ForwardedFrom{
+			MessageId: message.ForwardMessageID,
+			ChatId:    message.ForwardChatID,
+			UserId:    message.ForwardUserID,
+			Username:  message.ForwardUsername,
+			Deleted:   message.ForwardDeleted,
+		}
+	}
+
+	for _, entity := range message.Entities {
+		pbMsg.Entities = append(pbMsg.Entities, entityToProto(entity))
+	}
+
+	for _, preview := range message.Previews {
+		pbMsg.Previews = append(pbMsg.Previews, &pb.LinkPreview{
+			Url:         preview.URL,
+			Title:       preview.Title,
+			Description: preview.Description,
+			ImageUrl:    preview.ImageURL,
+		})
+	}
+
+	switch message.Kind {
+	case models.MessageKindPoll:
+		pbMsg.Kind = pb.MessageKind_MESSAGE_KIND_POLL
+		if message.Poll != nil {
+			pbMsg.Poll = pollToProto(message.Poll)
+		}
+	case models.MessageKindAction:
+		pbMsg.Kind = pb.MessageKind_MESSAGE_KIND_ACTION
+	case models.MessageKindSystem:
+		pbMsg.Kind = pb.MessageKind_MESSAGE_KIND_SYSTEM
+	}
+
+	return pbMsg
+}
+
+// entityToProto преобразует фрагмент разметки в protobuf
+func entityToProto(entity models.MessageEntity) *pb.MessageEntity {
+	pbEntity := &pb.MessageEntity{
+		Offset: int32(entity.Offset),
+		Length: int32(entity.Length),
+		Url:    entity.URL,
+	}
+
+	switch entity.Type {
+	case models.EntityBold:
+		pbEntity.Type = pb.MessageEntityType_MESSAGE_ENTITY_TYPE_BOLD
+	case models.EntityCode:
+		pbEntity.Type = pb.MessageEntityType_MESSAGE_ENTITY_TYPE_CODE
+	case models.EntityLink:
+		pbEntity.Type = pb.MessageEntityType_MESSAGE_ENTITY_TYPE_LINK
+	case models.EntityMention:
+		pbEntity.Type = pb.MessageEntityType_MESSAGE_ENTITY_TYPE_MENTION
+	}
+
+	return pbEntity
+}
+
+// formatFromProto преобразует формат текста сообщения из protobuf
+func formatFromProto(format pb.MessageFormat) string {
+	if format == pb.MessageFormat_MESSAGE_FORMAT_MARKDOWN {
+		return models.MessageFormatMarkdown
+	}
+	return models.MessageFormatPlain
+}
+
+// pollToProto преобразует опрос в protobuf. Для анонимных опросов список проголосовавших не раскрывается
+func pollToProto(poll *models.Poll) *pb.Poll {
+	resp := &pb.Poll{
+		PollId:         poll.ID,
+		Question:       poll.Question,
+		Options:        make([]*pb.PollOption, 0, len(poll.Options)),
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		Closed:         poll.IsClosed(time.Now()),
+		TotalVoters:    int32(poll.TotalVoters),
+	}
+
+	if poll.ClosesAt != nil {
+		resp.ClosesAt = timestamppb.New(*poll.ClosesAt)
+	}
+
+	for _, option := range poll.Options {
+		pbOption := &pb.PollOption{
+			Index: int32(option.Index),
+			Text:  option.Text,
+			Votes: int32(option.Votes),
+		}
+		if !poll.Anonymous {
+			pbOption.VoterIds = option.VoterIDs
+		}
+		resp.Options = append(resp.Options, pbOption)
+	}
+
+	return resp
+}
+
+// webhookToProto преобразует вебхук в protobuf без секрета
+func webhookToProto(webhook *models.Webhook) *pb.Webhook {
+	return &pb.Webhook{
+		WebhookId: webhook.ID,
+		ChatId:    webhook.ChatID,
+		Url:       webhook.URL,
+		CreatedAt: timestamppb.New(webhook.CreatedAt),
+	}
+}
+
+// incomingWebhookToProto преобразует входящий вебхук в protobuf без токена
+func incomingWebhookToProto(webhook *models.IncomingWebhook) *pb.IncomingWebhook {
+	return &pb.IncomingWebhook{
+		IncomingWebhookId: webhook.ID,
+		ChatId:            webhook.ChatID,
+		Name:              webhook.Name,
+		CreatedAt:         timestamppb.New(webhook.CreatedAt),
+		BotId:             webhook.BotID,
+	}
+}
+
+func notificationToProto(notification *models.Notification) *pb.Notification {
+	pbNotification := &pb.Notification{
+		ChatId:        notification.ChatID,
+		ChatName:      notification.ChatName,
+		ActorUsername: notification.ActorUsername,
+		CreatedAt:     timestamppb.New(notification.CreatedAt),
+	}
+
+	switch notification.Kind {
+	case models.NotificationMessage:
+		pbNotification.Kind = pb.NotificationKind_NOTIFICATION_KIND_MESSAGE
+	case models.NotificationMention:
+		pbNotification.Kind = pb.NotificationKind_NOTIFICATION_KIND_MENTION
+	case models.NotificationInvite:
+		pbNotification.Kind = pb.NotificationKind_NOTIFICATION_KIND_INVITE
+	}
+
+	if notification.Message != nil {
+		pbNotification.Message = messageToProto(notification.Message)
+	}
+
+	return pbNotification
+}
+
+func flaggedToProto(message *models.FlaggedMessage) *pb.FlaggedMessage {
+	pbFlagged := &pb.FlaggedMessage{
+		FlaggedMessageId: message.ID,
+		ChatId:           message.ChatID,
+		UserId:           message.UserID,
+		Username:         message.Username,
+		Text:             message.Text,
+		Reason:           message.Reason,
+		CreatedAt:        timestamppb.New(message.CreatedAt),
+	}
+
+	if message.ForwardMessageID != "" {
+		pbFlagged.ForwardedFrom = &pb.ForwardedFrom{
+			MessageId: message.ForwardMessageID,
+			ChatId:    message.ForwardChatID,
+			UserId:    message.ForwardUserID,
+			Username:  message.ForwardUsername,
+		}
+	}
+
+	return pbFlagged
+}
+
+func restrictionToProto(restriction *models.ChatRestriction) *pb.ChatRestriction {
+	pbRestriction := &pb.ChatRestriction{
+		RestrictionId: restriction.ID,
+		ChatId:        restriction.ChatID,
+		UserId:        restriction.UserID,
+		Username:      restriction.Username,
+		Kind:          pb.RestrictionKind_RESTRICTION_KIND_BAN,
+		Reason:        restriction.Reason,
+		CreatedBy:     restriction.CreatedBy,
+		CreatedAt:     timestamppb.New(restriction.CreatedAt),
+	}
+
+	if restriction.Kind == models.RestrictionMute {
+		pbRestriction.Kind = pb.RestrictionKind_RESTRICTION_KIND_MUTE
+	}
+
+	if restriction.ExpiresAt != nil {
+		pbRestriction.ExpiresAt = timestamppb.New(*restriction.ExpiresAt)
+	}
+
+	return pbRestriction
+}
+
+func restrictionKindFromProto(kind pb.RestrictionKind) string {
+	switch kind {
+	case pb.RestrictionKind_RESTRICTION_KIND_BAN:
+		return models.RestrictionBan
+	case pb.RestrictionKind_RESTRICTION_KIND_MUTE:
+		return models.RestrictionMute
+	default:
+		return ""
+	}
+}
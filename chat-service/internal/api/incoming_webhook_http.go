@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"chat.service/internal/middleware"
+	"chat.service/internal/models"
+	"chat.service/internal/service/chat_service"
+)
+
+// maxIncomingWebhookBody ограничивает размер тела запроса входящего вебхука
+const maxIncomingWebhookBody = 64 << 10
+
+// IncomingWebhookHandler принимает HTTP-запросы входящих вебхуков вида POST /hooks/<token>
+type IncomingWebhookHandler struct {
+	chatService *chat_service.ChatService
+	limiter     *middleware.KeyLimiter
+}
+
+// NewIncomingWebhookHandler создает обработчик входящих вебхуков.
+// limit ограничивает частоту запросов каждого вебхука, нулевая скорость отключает ограничение
+func NewIncomingWebhookHandler(chatService *chat_service.ChatService, limit middleware.Limit) *IncomingWebhookHandler {
+	return &IncomingWebhookHandler{
+		chatService: chatService,
+		limiter:     middleware.NewKeyLimiter(limit),
+	}
+}
+
+// incomingWebhookRequest тело запроса входящего вебхука
+type incomingWebhookRequest struct {
+	Text     string `json:"text"`
+	Username string `json:"username"`
+	Format   string `json:"format"` // "markdown" или пусто для обычного текста
+}
+
+// ServeHTTP публикует сообщение из тела запроса в чат вебхука
+func (h *IncomingWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed, "метод не поддерживается")
+		return
+	}
+
+	token := strings.TrimPrefix(r.URL.Path, chat_service.IncomingWebhookPath(""))
+	if token == "" || strings.Contains(token, "/") {
+		writeJSONError(w, http.StatusNotFound, "вебхук не найден")
+		return
+	}
+
+	if retryAfter, ok := h.limiter.Allow(token); !ok {
+		w.Header().Set("Retry-After", middleware.RetryAfterSeconds(retryAfter))
+		writeJSONError(w, http.StatusTooManyRequests, "слишком много запросов")
+		return
+	}
+
+	var req incomingWebhookRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxIncomingWebhookBody))
+	if err := decoder.Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "некорректное тело запроса, ожидается JSON {\"text\": \"...\"}")
+		return
+	}
+
+	if req.Format != "" && req.Format != models.MessageFormatMarkdown {
+		writeJSONError(w, http.StatusBadRequest, "неизвестный формат сообщения")
+		return
+	}
+
+	message, err := h.chatService.PostIncomingWebhookMessage(r.Context(), token, req.Text, req.Username, req.Format)
+	if err != nil {
+		switch {
+		case errors.Is(err, chat_service.ErrWebhookNotFound):
+			writeJSONError(w, http.StatusNotFound, "вебхук не найден")
+		case errors.Is(err, chat_service.ErrInvalidMessage),
+			errors.Is(err, chat_service.ErrMessageTooLong),
+			errors.Is(err, chat_service.ErrInvalidMarkup),
+			errors.Is(err, chat_service.ErrMessageRejected),
+			errors.Is(err, chat_service.ErrInvalidWebhookUsername):
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, chat_service.ErrUserBanned),
+			errors.Is(err, chat_service.ErrUserMuted),
+			errors.Is(err, chat_service.ErrUserNotInChat),
+			errors.Is(err, chat_service.ErrChannelReadOnly):
+			writeJSONError(w, http.StatusForbidden, err.Error())
+		default:
+			log.Printf("Ошибка при публикации сообщения входящего вебхука: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, "ошибка при отправке сообщения")
+		}
+		return
+	}
+
+	// Сообщение, отправленное модераторам на проверку, появится в чате после одобрения
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message_id":     message.ID,
+		"pending_review": message.PendingReview,
+	})
+}
+
+// writeJSON отправляет ответ в формате JSON
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Ошибка при отправке ответа: %v", err)
+	}
+}
+
+// writeJSONError отправляет ошибку в формате JSON
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
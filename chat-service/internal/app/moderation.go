@@ -0,0 +1,88 @@
+package app
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat.service/internal/service/chat_service"
+	"chat.service/internal/service/moderation"
+)
+
+// moderationFilters создает цепочку фильтров модерации по переменным окружения:
+//
+//	MODERATION_BANNED_WORDS         запрещенные слова через запятую
+//	MODERATION_BANNED_WORDS_ACTION  решение для запрещенных слов: flag (по умолчанию) или reject
+//	MODERATION_MAX_LENGTH           максимальная длина сообщения в символах, 0 отключает фильтр
+//	MODERATION_LINKS                решение для сообщений со ссылками: allow (по умолчанию), flag или reject
+//	MODERATION_SPAM_REPEATS         сколько одинаковых сообщений допускается за окно, 0 отключает фильтр
+//	MODERATION_SPAM_WINDOW          окно для подсчета одинаковых сообщений (по умолчанию 1m)
+func moderationFilters() []chat_service.ModerationFilter {
+	var filters []chat_service.ModerationFilter
+
+	if maxLength := getIntEnv("MODERATION_MAX_LENGTH", 0); maxLength > 0 {
+		filters = append(filters, moderation.NewMaxLengthFilter(maxLength))
+	}
+
+	if words := splitList(os.Getenv("MODERATION_BANNED_WORDS")); len(words) > 0 {
+		action := getActionEnv("MODERATION_BANNED_WORDS_ACTION", moderation.Flag)
+		if action != moderation.Allow {
+			filters = append(filters, moderation.NewBannedWordsFilter(words, action))
+		}
+	}
+
+	if action := getActionEnv("MODERATION_LINKS", moderation.Allow); action != moderation.Allow {
+		filters = append(filters, moderation.NewLinkFilter(action))
+	}
+
+	if repeats := getIntEnv("MODERATION_SPAM_REPEATS", 3); repeats > 0 {
+		filters = append(filters, moderation.NewSpamFilter(repeats, getDurationEnv("MODERATION_SPAM_WINDOW", time.Minute)))
+	}
+
+	return filters
+}
+
+// getActionEnv возвращает решение модерации из переменной окружения или значение по умолчанию
+func getActionEnv(key string, defaultValue moderation.Action) moderation.Action {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+
+	action, err := moderation.ParseAction(value)
+	if err != nil {
+		log.Printf("Некорректное значение %s=%q, используем значение по умолчанию", key, value)
+		return defaultValue
+	}
+
+	return action
+}
+
+// getIntEnv возвращает неотрицательное число из переменной окружения или значение по умолчанию
+func getIntEnv(key string, defaultValue int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		log.Printf("Некорректное значение %s=%q, используем %d", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return number
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
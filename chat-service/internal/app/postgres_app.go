@@ -2,11 +2,14 @@ package app
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	pb "chat.service/api/proto"
 	"chat.service/internal/api"
@@ -15,6 +18,8 @@ import (
 	"chat.service/internal/repository/postgres"
 	"chat.service/internal/service/auth_client"
 	"chat.service/internal/service/chat_service"
+	"chat.service/internal/service/unfurl"
+	"chat.service/internal/service/webhook_dispatcher"
 	"github.com/jmoiron/sqlx"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -22,11 +27,21 @@ import (
 
 // PostgresApp представляет приложение чат-сервиса с PostgreSQL
 type PostgresApp struct {
-	chatRepo    repository.ChatRepository
-	messageRepo repository.MessageRepository
-	authClient  *auth_client.AuthClient
-	grpcServer  *grpc.Server
-	port        string
+	chatRepo       repository.ChatRepository
+	messageRepo    repository.MessageRepository
+	inviteRepo     repository.InviteRepository
+	scheduleRepo   repository.ScheduledMessageRepository
+	pollRepo       repository.PollRepository
+	webhookRepo    repository.WebhookRepository
+	incomingRepo   repository.IncomingWebhookRepository
+	commandRepo    repository.CommandRepository
+	previewRepo    repository.LinkPreviewRepository
+	moderationRepo repository.ModerationRepository
+	authClient     *auth_client.AuthClient
+	grpcServer     *grpc.Server
+	httpServer     *http.Server
+	port           string
+	httpPort       string
 }
 
 // NewPostgresApp создает новый экземпляр приложения с PostgreSQL
@@ -35,10 +50,19 @@ func NewPostgresApp(ctx context.Context, db *sqlx.DB, authServiceAddr string) (*
 		log.Printf("Ошибка при выполнении миграций: %v", err)
 	}
 	port := getEnv("GRPC_PORT", "50052")
+	httpPort := getEnv("HTTP_PORT", "8082")
 
 	// Создаем репозитории PostgreSQL
 	chatRepo := postgres.NewChatRepository(db)
 	messageRepo := postgres.NewMessageRepository(db)
+	inviteRepo := postgres.NewInviteRepository(db)
+	scheduleRepo := postgres.NewScheduledMessageRepository(db)
+	pollRepo := postgres.NewPollRepository(db)
+	webhookRepo := postgres.NewWebhookRepository(db)
+	incomingRepo := postgres.NewIncomingWebhookRepository(db)
+	commandRepo := postgres.NewCommandRepository(db)
+	previewRepo := postgres.NewLinkPreviewRepository(db)
+	moderationRepo := postgres.NewModerationRepository(db)
 
 	// Создаем клиент для сервиса аутентификации
 	authClient, err := auth_client.NewAuthClient(authServiceAddr)
@@ -47,10 +71,19 @@ func NewPostgresApp(ctx context.Context, db *sqlx.DB, authServiceAddr string) (*
 	}
 
 	return &PostgresApp{
-		chatRepo:    chatRepo,
-		messageRepo: messageRepo,
-		authClient:  authClient,
-		port:        port,
+		chatRepo:       chatRepo,
+		messageRepo:    messageRepo,
+		inviteRepo:     inviteRepo,
+		scheduleRepo:   scheduleRepo,
+		pollRepo:       pollRepo,
+		webhookRepo:    webhookRepo,
+		incomingRepo:   incomingRepo,
+		commandRepo:    commandRepo,
+		previewRepo:    previewRepo,
+		moderationRepo: moderationRepo,
+		authClient:     authClient,
+		port:           port,
+		httpPort:       httpPort,
 	}, nil
 }
 
@@ -59,19 +92,56 @@ func (a *PostgresApp) Run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// Создаем диспетчер исходящих вебхуков с блокировкой запросов во внутреннюю сеть
+	// и запускаем его вместе с контекстом приложения
+	webhookConfig := webhook_dispatcher.DefaultConfig()
+	webhookConfig.Timeout = getDurationEnv("WEBHOOK_TIMEOUT", webhookConfig.Timeout)
+	webhooks := webhook_dispatcher.NewDispatcher(a.webhookRepo, webhook_dispatcher.NewClient(webhookConfig), webhookConfig)
+	go webhooks.Run(ctx)
+
+	// Создаем загрузчик превью ссылок с блокировкой запросов во внутреннюю сеть
+	unfurler := unfurl.NewFetcher(unfurl.NewClient(unfurl.Config{Timeout: getDurationEnv("LINK_PREVIEW_TIMEOUT", unfurl.DefaultTimeout)}), unfurl.DefaultMaxBodySize)
+
 	// Создаем сервис чата
-	chatService := chat_service.NewChatService(a.chatRepo, a.messageRepo, a.authClient)
+	chatService := chat_service.NewChatService(
+		a.chatRepo,
+		a.messageRepo,
+		a.inviteRepo,
+		a.scheduleRepo,
+		a.pollRepo,
+		a.webhookRepo,
+		webhooks,
+		a.incomingRepo,
+		a.commandRepo,
+		a.previewRepo,
+		unfurler,
+		a.moderationRepo,
+		moderationFilters(),
+		a.authClient,
+	)
+
+	// Запускаем диспетчер отложенных сообщений, он остановится вместе с контекстом приложения
+	go chatService.RunScheduledDispatcher(ctx, getDurationEnv("SCHEDULER_INTERVAL", 5*time.Second))
 
 	// Создаем обработчик API
 	chatHandler := api.NewChatServiceHandler(chatService)
 
+	// Периодически обновляем публичные ключи для локальной проверки токенов доступа
+	go a.authClient.RunKeyRefresher(ctx, getDurationEnv("AUTH_KEYS_REFRESH_INTERVAL", 5*time.Minute))
+
+	// Периодически загружаем отозванные токены доступа, чтобы отклонять их при локальной проверке
+	go a.authClient.RunRevocationSync(ctx, getDurationEnv("AUTH_REVOCATIONS_REFRESH_INTERVAL", 30*time.Second))
+
 	// Создаем аутентификационный перехватчик
 	authInterceptor := middleware.NewAuthInterceptor(a.authClient)
 
+	// Создаем перехватчик ограничения частоты запросов, он использует пользователя из аутентификации
+	rateLimitInterceptor := middleware.NewRateLimitInterceptor(rateLimitConfig())
+
 	// Создаем gRPC сервер с перехватчиками
 	a.grpcServer = grpc.NewServer(
-		grpc.UnaryInterceptor(authInterceptor.UnaryInterceptor),
-		grpc.StreamInterceptor(authInterceptor.StreamInterceptor),
+		grpc.ChainUnaryInterceptor(authInterceptor.UnaryInterceptor, rateLimitInterceptor.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(authInterceptor.StreamInterceptor, rateLimitInterceptor.StreamInterceptor),
 	)
 
 	// Регистрируем сервисы
@@ -93,6 +163,23 @@ func (a *PostgresApp) Run(ctx context.Context) error {
 		}
 	}()
 
+	// HTTP сервер принимает сообщения входящих вебхуков без gRPC аутентификации:
+	// доступ определяется секретным токеном в URL
+	mux := http.NewServeMux()
+	mux.Handle(chat_service.IncomingWebhookPath(""), api.NewIncomingWebhookHandler(chatService, incomingWebhookLimit()))
+	a.httpServer = &http.Server{
+		Addr:              ":" + a.httpPort,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Printf("Запуск HTTP сервера входящих вебхуков на порту %s", a.httpPort)
+		if err := a.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Ошибка при запуске HTTP сервера: %v", err)
+		}
+	}()
+
 	return a.GracefulShutdown(ctx)
 }
 
@@ -110,6 +197,12 @@ func (a *PostgresApp) GracefulShutdown(ctx context.Context) error {
 
 	log.Println("Завершение работы сервера...")
 	a.grpcServer.GracefulStop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := a.httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Ошибка при остановке HTTP сервера: %v", err)
+	}
 	log.Println("Сервер остановлен")
 
 	return nil
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"log"
+	"os"
+
+	"chat.service/internal/middleware"
+)
+
+// rateLimitConfig создает лимиты частоты запросов. Лимиты по умолчанию дополняются
+// и переопределяются переменными окружения в формате "Method=N/unit[:burst],...":
+//
+//	RATE_LIMITS       лимиты запросов пользователя по всем чатам, например "SendMessage=30/m:10,*=0/s"
+//	RATE_LIMITS_CHAT  общие лимиты запросов всех пользователей чата, например "SendMessage=20/s:50"
+//
+// Метод "*" задает лимит для остальных методов, нулевое число запросов отключает ограничение
+func rateLimitConfig() middleware.RateLimitConfig {
+	config := middleware.DefaultRateLimitConfig()
+	mergeLimitsEnv("RATE_LIMITS", config.PerUser)
+	mergeLimitsEnv("RATE_LIMITS_CHAT", config.PerChat)
+
+	return config
+}
+
+// mergeLimitsEnv добавляет в limits лимиты из переменной окружения
+func mergeLimitsEnv(key string, limits map[string]middleware.Limit) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+
+	parsed, err := middleware.ParseLimits(value)
+	if err != nil {
+		log.Printf("Некорректное значение %s: %v, используем лимиты по умолчанию", key, err)
+		return
+	}
+
+	for method, limit := range parsed {
+		limits[method] = limit
+	}
+}
+
+// defaultIncomingWebhookLimit лимит запросов одного входящего вебхука по умолчанию
+const defaultIncomingWebhookLimit = "30/m:10"
+
+// incomingWebhookLimit возвращает лимит запросов одного входящего вебхука из переменной
+// INCOMING_WEBHOOK_RATE_LIMIT в формате "N/unit[:burst]", нулевое число запросов отключает ограничение
+func incomingWebhookLimit() middleware.Limit {
+	value := os.Getenv("INCOMING_WEBHOOK_RATE_LIMIT")
+	if value != "" {
+		limit, err := middleware.ParseLimit(value)
+		if err == nil {
+			return limit
+		}
+		log.Printf("Некорректное значение INCOMING_WEBHOOK_RATE_LIMIT: %v, используем лимит по умолчанию", err)
+	}
+
+	limit, _ := middleware.ParseLimit(defaultIncomingWebhookLimit)
+	return limit
+}
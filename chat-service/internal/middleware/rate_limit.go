@@ -0,0 +1,349 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfterKey ключ метаданных с числом секунд, через которое можно повторить запрос
+const RetryAfterKey = "retry-after"
+
+// defaultMethod ключ лимита для методов без собственного лимита
+const defaultMethod = "*"
+
+// Limit задает параметры корзины токенов: скорость пополнения и размер корзины.
+// Нулевая скорость означает отсутствие ограничения
+type Limit struct {
+	Rate  float64 // Токенов в секунду
+	Burst int     // Максимальное число запросов подряд
+}
+
+// RateLimitConfig задает лимиты по имени метода (например, "SendMessage").
+// Лимит "*" применяется к методам без собственного лимита
+type RateLimitConfig struct {
+	PerUser map[string]Limit // Лимит запросов пользователя по всем чатам
+	PerChat map[string]Limit // Общий лимит запросов всех пользователей в одном чате
+}
+
+// DefaultRateLimitConfig возвращает лимиты по умолчанию
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		PerUser: map[string]Limit{
+			defaultMethod:         {Rate: 10, Burst: 20},
+			"SendMessage":         {Rate: 1, Burst: 5},
+			"ConnectChat":         {Rate: 0.2, Burst: 5},
+			"StreamNotifications": {Rate: 0.2, Burst: 5},
+			"ReportMessage":       {Rate: 1.0 / 60, Burst: 5},
+		},
+		PerChat: map[string]Limit{
+			"SendMessage": {Rate: 20, Burst: 50},
+		},
+	}
+}
+
+// ParseLimits разбирает лимиты в формате "Method=N/unit[:burst],...", где unit - s, m или h.
+// Если размер корзины не указан, он равен N
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		method, value, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(method) == "" {
+			return nil, fmt.Errorf("некорректный лимит %q", item)
+		}
+
+		limit, err := ParseLimit(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("некорректный лимит %q: %w", item, err)
+		}
+
+		limits[strings.TrimSpace(method)] = limit
+	}
+
+	return limits, nil
+}
+
+// ParseLimit разбирает один лимит в формате "N/unit[:burst]"
+func ParseLimit(value string) (Limit, error) {
+	rateValue, burstValue, hasBurst := strings.Cut(value, ":")
+
+	countValue, unit, ok := strings.Cut(rateValue, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("ожидается формат N/unit")
+	}
+
+	count, err := strconv.Atoi(countValue)
+	if err != nil || count < 0 {
+		return Limit{}, fmt.Errorf("некорректное число запросов")
+	}
+
+	var period time.Duration
+	switch unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("неизвестная единица времени %q", unit)
+	}
+
+	limit := Limit{Rate: float64(count) / period.Seconds(), Burst: count}
+	if hasBurst {
+		limit.Burst, err = strconv.Atoi(burstValue)
+		if err != nil || limit.Burst < 1 {
+			return Limit{}, fmt.Errorf("некорректный размер корзины")
+		}
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	return limit, nil
+}
+
+// RateLimitInterceptor ограничивает частоту запросов алгоритмом корзины токенов.
+// Запросы учитываются по пользователю из контекста и по чату из запроса, поэтому перехватчик
+// должен выполняться после AuthInterceptor
+type RateLimitInterceptor struct {
+	config  RateLimitConfig
+	buckets *bucketStore
+}
+
+// NewRateLimitInterceptor создает перехватчик ограничения частоты запросов
+func NewRateLimitInterceptor(config RateLimitConfig) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		config:  config,
+		buckets: newBucketStore(),
+	}
+}
+
+// chatRequest запрос, относящийся к конкретному чату
+type chatRequest interface {
+	GetChatId() string
+}
+
+// UnaryInterceptor перехватчик для унарных RPC
+func (i *RateLimitInterceptor) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := i.check(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamInterceptor перехватчик для потоковых RPC. Чат известен только после получения запроса,
+// поэтому лимит проверяется при чтении первого сообщения клиента
+func (i *RateLimitInterceptor) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &rateLimitedStream{ServerStream: ss, interceptor: i, method: info.FullMethod})
+}
+
+// check расходует токены пользователя и чата для метода. При превышении лимита возвращает
+// ResourceExhausted и передает время до следующей попытки в трейлере retry-after
+func (i *RateLimitInterceptor) check(ctx context.Context, fullMethod string, req interface{}) error {
+	retryAfter, ok := i.allow(ctx, fullMethod, req)
+	if ok {
+		return nil
+	}
+
+	seconds := RetryAfterSeconds(retryAfter)
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterKey, seconds))
+
+	return status.Errorf(codes.ResourceExhausted, "слишком много запросов, повторите через %s с", seconds)
+}
+
+func (i *RateLimitInterceptor) allow(ctx context.Context, fullMethod string, req interface{}) (time.Duration, bool) {
+	method := path.Base(fullMethod)
+	userID := userIDFromContext(ctx)
+
+	var chatID string
+	if r, ok := req.(chatRequest); ok {
+		chatID = r.GetChatId()
+	}
+
+	now := time.Now()
+
+	if limit, ok := lookupLimit(i.config.PerUser, method); ok {
+		// Корзина пользователя общая для всех чатов, иначе лимит обходится рассылкой по разным чатам
+		key := method + "|user|" + userID
+		if retryAfter, ok := i.buckets.take(key, limit, now); !ok {
+			return retryAfter, false
+		}
+	}
+
+	if chatID == "" {
+		return 0, true
+	}
+
+	if limit, ok := lookupLimit(i.config.PerChat, method); ok {
+		key := method + "|chat|" + chatID
+		if retryAfter, ok := i.buckets.take(key, limit, now); !ok {
+			return retryAfter, false
+		}
+	}
+
+	return 0, true
+}
+
+// RetryAfterSeconds округляет время ожидания до целых секунд в большую сторону
+func RetryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// lookupLimit возвращает лимит метода или лимит по умолчанию
+func lookupLimit(limits map[string]Limit, method string) (Limit, bool) {
+	limit, ok := limits[method]
+	if !ok {
+		limit, ok = limits[defaultMethod]
+	}
+
+	return limit, ok && limit.Rate > 0
+}
+
+// rateLimitedStream проверяет лимит при получении первого сообщения потока
+type rateLimitedStream struct {
+	grpc.ServerStream
+	interceptor *RateLimitInterceptor
+	method      string
+	checked     bool
+}
+
+func (s *rateLimitedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if s.checked {
+		return nil
+	}
+	s.checked = true
+
+	retryAfter, ok := s.interceptor.allow(s.Context(), s.method, m)
+	if ok {
+		return nil
+	}
+
+	seconds := RetryAfterSeconds(retryAfter)
+	s.SetTrailer(metadata.Pairs(RetryAfterKey, seconds))
+
+	return status.Errorf(codes.ResourceExhausted, "слишком много запросов, повторите через %s с", seconds)
+}
+
+// KeyLimiter ограничивает частоту событий по произвольному ключу, например по токену вебхука,
+// для запросов, которые не проходят через gRPC-перехватчики
+type KeyLimiter struct {
+	limit   Limit
+	buckets *bucketStore
+}
+
+// NewKeyLimiter создает ограничитель с одинаковым лимитом для всех ключей
+func NewKeyLimiter(limit Limit) *KeyLimiter {
+	return &KeyLimiter{
+		limit:   limit,
+		buckets: newBucketStore(),
+	}
+}
+
+// Allow расходует токен ключа. Если токенов нет, возвращает время до следующей попытки
+func (l *KeyLimiter) Allow(key string) (time.Duration, bool) {
+	if l == nil || l.limit.Rate <= 0 {
+		return 0, true
+	}
+
+	return l.buckets.take(key, l.limit, time.Now())
+}
+
+// bucket корзина токенов одного ключа
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// bucketStore хранит корзины токенов и периодически удаляет заполненные корзины,
+// которые не отличаются от новых
+type bucketStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// sweepInterval интервал удаления неиспользуемых корзин
+const sweepInterval = time.Minute
+
+func newBucketStore() *bucketStore {
+	return &bucketStore{buckets: make(map[string]*bucket)}
+}
+
+// take забирает токен из корзины ключа. Если токенов нет, возвращает время до появления токена
+func (s *bucketStore) take(key string, limit Limit, now time.Time) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+
+	// Пополняем корзину за прошедшее время
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return wait, false
+}
+
+// sweep удаляет корзины, не использовавшиеся дольше интервала очистки.
+// Корзина удаляется, только если за это время она гарантированно успела заполниться
+func (s *bucketStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		idle := now.Sub(b.last)
+		if idle >= sweepInterval && b.tokens+idle.Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// userIDFromContext возвращает ID пользователя, добавленный в контекст AuthInterceptor
+func userIDFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("user-id")
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+type testChatRequest struct{ chatID string }
+
+func (r testChatRequest) GetChatId() string { return r.chatID }
+
+func userContext(userID string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("user-id", userID))
+}
+
+func TestUserLimitIsSharedAcrossChats(t *testing.T) {
+	interceptor := NewRateLimitInterceptor(RateLimitConfig{
+		PerUser: map[string]Limit{"SendMessage": {Rate: 0.001, Burst: 2}},
+	})
+	ctx := userContext("alice")
+
+	for _, chatID := range []string{"chat-1", "chat-2"} {
+		if _, ok := interceptor.allow(ctx, "/chat.ChatService/SendMessage", testChatRequest{chatID}); !ok {
+			t.Fatalf("request to %s must be allowed", chatID)
+		}
+	}
+
+	// Новый чат не дает пользователю новую корзину
+	if _, ok := interceptor.allow(ctx, "/chat.ChatService/SendMessage", testChatRequest{"chat-3"}); ok {
+		t.Fatal("the user limit must apply across chats")
+	}
+
+	if _, ok := interceptor.allow(userContext("bob"), "/chat.ChatService/SendMessage", testChatRequest{"chat-3"}); !ok {
+		t.Fatal("other users have their own limit")
+	}
+}
+
+func TestChatLimitIsSeparate(t *testing.T) {
+	interceptor := NewRateLimitInterceptor(RateLimitConfig{
+		PerUser: map[string]Limit{"SendMessage": {Rate: 0.001, Burst: 10}},
+		PerChat: map[string]Limit{"SendMessage": {Rate: 0.001, Burst: 2}},
+	})
+
+	for _, userID := range []string{"alice", "bob"} {
+		if _, ok := interceptor.allow(userContext(userID), "/chat.ChatService/SendMessage", testChatRequest{"chat-1"}); !ok {
+			t.Fatalf("request of %s must be allowed", userID)
+		}
+	}
+
+	retryAfter, ok := interceptor.allow(userContext("carol"), "/chat.ChatService/SendMessage", testChatRequest{"chat-1"})
+	if ok || retryAfter <= 0 {
+		t.Fatalf("the chat limit must reject the third request, got ok=%v retry after %s", ok, retryAfter)
+	}
+
+	if _, ok := interceptor.allow(userContext("carol"), "/chat.ChatService/SendMessage", testChatRequest{"chat-2"}); !ok {
+		t.Fatal("other chats have their own limit")
+	}
+}
+
+func TestKeyLimiter(t *testing.T) {
+	limiter := NewKeyLimiter(Limit{Rate: 0.001, Burst: 2})
+
+	for i := 0; i < 2; i++ {
+		if _, ok := limiter.Allow("hook-1"); !ok {
+			t.Fatalf("request %d must be allowed", i+1)
+		}
+	}
+
+	if retryAfter, ok := limiter.Allow("hook-1"); ok || retryAfter <= 0 {
+		t.Fatalf("the third request must be rejected, got ok=%v retry after %s", ok, retryAfter)
+	}
+
+	if _, ok := limiter.Allow("hook-2"); !ok {
+		t.Fatal("other keys have their own limit")
+	}
+
+	if _, ok := NewKeyLimiter(Limit{}).Allow("hook-1"); !ok {
+		t.Fatal("a zero rate must disable the limit")
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS chat_invites;
+ALTER TABLE chat_participants DROP COLUMN IF EXISTS role;
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- Роли участников чата
+ALTER TABLE chat_participants ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'member';
+
+-- Создатели существующих чатов становятся администраторами
+UPDATE chat_participants p
+SET role = 'admin'
+FROM chats c
+WHERE c.id = p.chat_id AND c.created_by_id = p.user_id;
+
+-- Таблица пригласительных ссылок
+CREATE TABLE IF NOT EXISTS chat_invites (
+    id UUID PRIMARY KEY,
+    chat_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_by_id UUID NOT NULL,
+    role VARCHAR(16) NOT NULL DEFAULT 'member',
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (chat_id) REFERENCES chats (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_invites_chat_id ON chat_invites (chat_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_chats_public_name;
+ALTER TABLE chats DROP COLUMN IF EXISTS kind;
+ALTER TABLE chats DROP COLUMN IF EXISTS visibility;
\ No newline at end of file
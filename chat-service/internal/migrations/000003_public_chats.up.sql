@@ -0,0 +1,6 @@
+-- Видимость и тип чата
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS visibility VARCHAR(16) NOT NULL DEFAULT 'private';
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'group';
+
+-- Индекс для поиска публичных чатов по началу названия
+CREATE INDEX IF NOT EXISTS idx_chats_public_name ON chats (lower(name) text_pattern_ops) WHERE visibility = 'public';
@@ -0,0 +1,4 @@
+ALTER TABLE chat_participants DROP COLUMN IF EXISTS last_read_at;
+ALTER TABLE chat_participants DROP COLUMN IF EXISTS hidden;
+ALTER TABLE chat_participants DROP COLUMN IF EXISTS notify_level;
+ALTER TABLE chat_participants DROP COLUMN IF EXISTS muted_until;
\ No newline at end of file
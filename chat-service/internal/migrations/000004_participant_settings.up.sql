@@ -0,0 +1,9 @@
+-- Персональные настройки участника чата
+ALTER TABLE chat_participants ADD COLUMN IF NOT EXISTS muted_until TIMESTAMP;
+ALTER TABLE chat_participants ADD COLUMN IF NOT EXISTS notify_level VARCHAR(16) NOT NULL DEFAULT 'all';
+ALTER TABLE chat_participants ADD COLUMN IF NOT EXISTS hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Время последнего прочтения чата для подсчета непрочитанных сообщений
+ALTER TABLE chat_participants ADD COLUMN IF NOT EXISTS last_read_at TIMESTAMP;
+
+UPDATE chat_participants SET last_read_at = joined_at WHERE last_read_at IS NULL;
@@ -0,0 +1,18 @@
+-- Таблица отложенных сообщений
+CREATE TABLE IF NOT EXISTS scheduled_messages (
+    id UUID PRIMARY KEY,
+    chat_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    message_id UUID NOT NULL UNIQUE,
+    text TEXT NOT NULL,
+    send_at TIMESTAMP NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    FOREIGN KEY (chat_id) REFERENCES chats (id) ON DELETE CASCADE
+);
+
+-- Индекс для выборки сообщений, которые пора отправить
+CREATE INDEX IF NOT EXISTS idx_scheduled_messages_pending ON scheduled_messages (send_at) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_messages_user_id ON scheduled_messages (user_id);
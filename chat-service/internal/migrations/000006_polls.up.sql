@@ -0,0 +1,39 @@
+-- Тип сообщения
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'text';
+
+-- Таблица опросов, ID опроса совпадает с ID сообщения
+CREATE TABLE IF NOT EXISTS polls (
+    id UUID PRIMARY KEY,
+    chat_id UUID NOT NULL,
+    created_by_id UUID NOT NULL,
+    question TEXT NOT NULL,
+    multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    closes_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (id) REFERENCES messages (id) ON DELETE CASCADE,
+    FOREIGN KEY (chat_id) REFERENCES chats (id) ON DELETE CASCADE
+);
+
+-- Варианты ответов
+CREATE TABLE IF NOT EXISTS poll_options (
+    poll_id UUID NOT NULL,
+    idx INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (poll_id, idx),
+    FOREIGN KEY (poll_id) REFERENCES polls (id) ON DELETE CASCADE
+);
+
+-- Голоса
+CREATE TABLE IF NOT EXISTS poll_votes (
+    poll_id UUID NOT NULL,
+    option_idx INTEGER NOT NULL,
+    user_id UUID NOT NULL,
+    voted_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (poll_id, user_id, option_idx),
+    FOREIGN KEY (poll_id, option_idx) REFERENCES poll_options (poll_id, idx) ON DELETE CASCADE
+);
+
+-- Индекс для автоматического закрытия опросов
+CREATE INDEX IF NOT EXISTS idx_polls_closes_at ON polls (closes_at) WHERE closed_at IS NULL AND closes_at IS NOT NULL;
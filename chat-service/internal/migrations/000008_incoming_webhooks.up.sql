@@ -0,0 +1,13 @@
+-- Входящие вебхуки чатов
+CREATE TABLE IF NOT EXISTS incoming_webhooks (
+    id UUID PRIMARY KEY,
+    chat_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_by_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    FOREIGN KEY (chat_id) REFERENCES chats (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_incoming_webhooks_chat_id ON incoming_webhooks (chat_id);
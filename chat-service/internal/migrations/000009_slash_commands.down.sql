@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS chat_commands;
+ALTER TABLE chats DROP COLUMN IF EXISTS topic;
@@ -0,0 +1,13 @@
+-- Тема чата, устанавливается командой /topic
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS topic TEXT NOT NULL DEFAULT '';
+
+-- Slash-команды, зарегистрированные ботами
+CREATE TABLE IF NOT EXISTS chat_commands (
+    chat_id UUID NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    bot_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (chat_id, name),
+    FOREIGN KEY (chat_id) REFERENCES chats (id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- Разметка текста сообщения в формате JSON, пустая строка для простого текста
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS entities TEXT NOT NULL DEFAULT '';
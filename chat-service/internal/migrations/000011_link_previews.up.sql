@@ -0,0 +1,8 @@
+-- Кеш превью ссылок из сообщений
+CREATE TABLE IF NOT EXISTS link_previews (
+    url TEXT PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    image_url TEXT NOT NULL DEFAULT '',
+    fetched_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,5 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS forward_username;
+ALTER TABLE messages DROP COLUMN IF EXISTS forward_user_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS forward_chat_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS forward_message_id;
@@ -0,0 +1,8 @@
+-- Ссылка на исходное сообщение для пересланных сообщений
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS forward_message_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS forward_chat_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS forward_user_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS forward_username TEXT NOT NULL DEFAULT '';
+
+-- Время удаления сообщения; удаленные сообщения остаются в истории как отметки об удалении
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
@@ -0,0 +1,20 @@
+-- Очередь сообщений, отмеченных фильтрами модерации для проверки администратором чата.
+-- Одобренное сообщение публикуется с ID записи очереди
+CREATE TABLE IF NOT EXISTS moderation_queue (
+    id UUID PRIMARY KEY,
+    chat_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    username VARCHAR(255) NOT NULL DEFAULT '',
+    text TEXT NOT NULL,
+    kind VARCHAR(16) NOT NULL DEFAULT 'text',
+    entities TEXT NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL,
+    reviewed_by UUID,
+    reviewed_at TIMESTAMP,
+    FOREIGN KEY (chat_id) REFERENCES chats (id) ON DELETE CASCADE
+);
+
+-- Индекс для выборки сообщений, ожидающих проверки в чате
+CREATE INDEX IF NOT EXISTS idx_moderation_queue_pending ON moderation_queue (chat_id, created_at) WHERE status = 'pending';
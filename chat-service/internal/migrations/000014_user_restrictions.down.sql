@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS chat_audit_log;
+DROP TABLE IF EXISTS message_reports;
+DROP TABLE IF EXISTS chat_restrictions;
@@ -0,0 +1,49 @@
+-- Ограничения участников чата: блокировка (ban) без срока и временный запрет писать (mute).
+-- Ограничение действует, пока не снято и не истек срок expires_at
+CREATE TABLE IF NOT EXISTS chat_restrictions (
+    id UUID PRIMARY KEY,
+    chat_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    username VARCHAR(255) NOT NULL DEFAULT '',
+    kind VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP,
+    lifted_by UUID,
+    lifted_at TIMESTAMP,
+    FOREIGN KEY (chat_id) REFERENCES chats (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_restrictions_user ON chat_restrictions (chat_id, user_id) WHERE lifted_at IS NULL;
+
+-- Жалобы участников на сообщения, один участник может пожаловаться на сообщение один раз
+CREATE TABLE IF NOT EXISTS message_reports (
+    id UUID PRIMARY KEY,
+    chat_id UUID NOT NULL,
+    message_id UUID NOT NULL,
+    reporter_id UUID NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (message_id, reporter_id),
+    FOREIGN KEY (chat_id) REFERENCES chats (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_reports_chat_id ON message_reports (chat_id, created_at);
+
+-- Журнал действий модерации в чате
+CREATE TABLE IF NOT EXISTS chat_audit_log (
+    id UUID PRIMARY KEY,
+    chat_id UUID NOT NULL,
+    actor_id UUID NOT NULL,
+    actor_username VARCHAR(255) NOT NULL DEFAULT '',
+    action VARCHAR(32) NOT NULL,
+    target_user_id TEXT NOT NULL DEFAULT '',
+    target_username VARCHAR(255) NOT NULL DEFAULT '',
+    target_message_id TEXT NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (chat_id) REFERENCES chats (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_audit_log_chat_id ON chat_audit_log (chat_id, created_at);
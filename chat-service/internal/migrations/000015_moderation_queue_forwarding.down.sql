@@ -0,0 +1,4 @@
+ALTER TABLE moderation_queue DROP COLUMN IF EXISTS forward_username;
+ALTER TABLE moderation_queue DROP COLUMN IF EXISTS forward_user_id;
+ALTER TABLE moderation_queue DROP COLUMN IF EXISTS forward_chat_id;
+ALTER TABLE moderation_queue DROP COLUMN IF EXISTS forward_message_id;
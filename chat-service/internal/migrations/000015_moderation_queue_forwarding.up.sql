@@ -0,0 +1,5 @@
+-- Источник пересланного сообщения для сообщений в очереди модерации, сохраняется при одобрении
+ALTER TABLE moderation_queue ADD COLUMN IF NOT EXISTS forward_message_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE moderation_queue ADD COLUMN IF NOT EXISTS forward_chat_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE moderation_queue ADD COLUMN IF NOT EXISTS forward_user_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE moderation_queue ADD COLUMN IF NOT EXISTS forward_username TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_incoming_webhooks_bot_id;
+ALTER TABLE incoming_webhooks DROP COLUMN IF EXISTS bot_id;
@@ -0,0 +1,5 @@
+-- Бот-аккаунт входящего вебхука: сообщения вебхука отправляются от его имени.
+-- У вебхуков, созданных раньше, бота нет, их нужно создать заново
+ALTER TABLE incoming_webhooks ADD COLUMN IF NOT EXISTS bot_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_incoming_webhooks_bot_id ON incoming_webhooks (bot_id);
@@ -4,27 +4,94 @@ import (
 	"time"
 )
 
+// Видимость чата
+const (
+	VisibilityPrivate = "private"
+	VisibilityPublic  = "public"
+)
+
+// Типы чатов
+const (
+	ChatKindGroup   = "group"
+	ChatKindChannel = "channel"
+)
+
 // Chat представляет модель чата
 type Chat struct {
 	ID          string    `db:"id"`
 	Name        string    `db:"name"`
+	Visibility  string    `db:"visibility"`
+	Kind        string    `db:"kind"`
+	Topic       string    `db:"topic"`
 	CreatedAt   time.Time `db:"created_at"`
 	CreatedByID string    `db:"created_by_id"`
 }
 
+// Роли участников чата
+const (
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
 // ChatParticipant представляет участника чата
 type ChatParticipant struct {
 	ChatID   string    `db:"chat_id"`
 	UserID   string    `db:"user_id"`
+	Role     string    `db:"role"`
 	JoinedAt time.Time `db:"joined_at"`
 }
 
+// Типы сообщений
+const (
+	MessageKindText   = "text"
+	MessageKindPoll   = "poll"
+	MessageKindAction = "action" // Действие пользователя, команда /me
+	MessageKindSystem = "system" // Служебное сообщение о событии в чате
+)
+
+// События, с которыми сообщение рассылается подписчикам чата
+const (
+	MessageEventNew     = "new"
+	MessageEventUpdated = "updated"
+	MessageEventCommand = "command" // Вызов команды бота, доставляется только боту
+	MessageEventDeleted = "deleted"
+)
+
 // Message представляет сообщение в чате
 type Message struct {
-	ID        string    `db:"id"`
-	ChatID    string    `db:"chat_id"`
-	UserID    string    `db:"user_id"`
-	Username  string    `db:"username"`
-	Text      string    `db:"text"`
-	CreatedAt time.Time `db:"created_at"`
+	ID        string          `db:"id"`
+	ChatID    string          `db:"chat_id"`
+	UserID    string          `db:"user_id"`
+	Username  string          `db:"username"`
+	Text      string          `db:"text"`
+	Kind      string          `db:"kind"`
+	Entities  MessageEntities `db:"entities"` // Разметка текста, пустая для простого текста
+	CreatedAt time.Time       `db:"created_at"`
+	DeletedAt *time.Time      `db:"deleted_at"` // Время удаления, сообщение остается в истории как отметка об удалении
+
+	// Источник пересланного сообщения, пустые значения для обычных сообщений
+	ForwardMessageID string `db:"forward_message_id"`
+	ForwardChatID    string `db:"forward_chat_id"`
+	ForwardUserID    string `db:"forward_user_id"`
+	ForwardUsername  string `db:"forward_username"`
+	ForwardDeleted   bool   `db:"forward_deleted"` // Исходное сообщение удалено, вычисляется при чтении
+
+	Event       string             `db:"-"` // Событие рассылки, пустое значение означает новое сообщение
+	Poll        *Poll              `db:"-"` // Опрос для сообщений типа MessageKindPoll
+	RecipientID string             `db:"-"` // Если задан, сообщение рассылается только подпискам этого пользователя
+	Ephemeral   bool               `db:"-"` // Эфемерное сообщение не сохраняется в истории
+	Command     *CommandInvocation `db:"-"` // Вызов команды для событий MessageEventCommand
+	Previews    []*LinkPreview     `db:"-"` // Превью ссылок из текста сообщения
+
+	PendingReview bool `db:"-"` // Сообщение отмечено модерацией и ожидает проверки администратором чата
+}
+
+// IsForwarded проверяет, является ли сообщение пересланным
+func (m *Message) IsForwarded() bool {
+	return m.ForwardMessageID != ""
+}
+
+// IsTombstone проверяет, нужно ли скрыть текст сообщения: оно удалено или удален его исходник
+func (m *Message) IsTombstone() bool {
+	return m.DeletedAt != nil || m.ForwardDeleted
 }
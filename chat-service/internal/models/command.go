@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// ChatCommand представляет slash-команду, зарегистрированную ботом в чате
+type ChatCommand struct {
+	ChatID      string    `db:"chat_id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	BotID       string    `db:"bot_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// CommandInvocation представляет вызов команды бота, ожидающий ответа
+type CommandInvocation struct {
+	ID        string
+	ChatID    string
+	Name      string
+	Args      string
+	UserID    string // Пользователь, вызвавший команду
+	BotID     string // Бот, которому принадлежит команда
+	CreatedAt time.Time
+}
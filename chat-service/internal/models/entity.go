@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Форматы текста сообщения
+const (
+	MessageFormatPlain    = "plain"
+	MessageFormatMarkdown = "markdown"
+)
+
+// Типы фрагментов разметки
+const (
+	EntityBold    = "bold"
+	EntityCode    = "code"
+	EntityLink    = "link"
+	EntityMention = "mention"
+)
+
+// MessageEntity представляет фрагмент разметки текста сообщения.
+// Offset и Length считаются в единицах UTF-16, как в клиентах на большинстве платформ
+type MessageEntity struct {
+	Type   string `json:"type"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+	URL    string `json:"url,omitempty"`
+}
+
+// MessageEntities хранится в базе как JSON массив
+type MessageEntities []MessageEntity
+
+// Value реализует driver.Valuer
+func (e MessageEntities) Value() (driver.Value, error) {
+	if len(e) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan реализует sql.Scanner
+func (e *MessageEntities) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("неподдерживаемый тип разметки сообщения: %T", src)
+	}
+
+	if len(data) == 0 {
+		*e = nil
+		return nil
+	}
+
+	return json.Unmarshal(data, e)
+}
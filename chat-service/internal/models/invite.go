@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// ChatInvite представляет пригласительную ссылку в чат.
+// Сам код приглашения не хранится, в базе лежит только его хеш
+type ChatInvite struct {
+	ID          string     `db:"id"`
+	ChatID      string     `db:"chat_id"`
+	TokenHash   string     `db:"token_hash"`
+	CreatedByID string     `db:"created_by_id"`
+	Role        string     `db:"role"`
+	MaxUses     int        `db:"max_uses"` // 0 - без ограничения
+	Uses        int        `db:"uses"`
+	ExpiresAt   time.Time  `db:"expires_at"`
+	RevokedAt   *time.Time `db:"revoked_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
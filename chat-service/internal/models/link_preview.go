@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// LinkPreview представляет превью ссылки, полученное со страницы по ее адресу
+type LinkPreview struct {
+	URL         string    `db:"url"`
+	Title       string    `db:"title"`
+	Description string    `db:"description"`
+	ImageURL    string    `db:"image_url"`
+	FetchedAt   time.Time `db:"fetched_at"`
+}
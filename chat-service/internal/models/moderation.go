@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// Статусы сообщений в очереди модерации
+const (
+	FlaggedPending  = "pending"
+	FlaggedApproved = "approved"
+	FlaggedRemoved  = "removed"
+)
+
+// FlaggedMessage представляет сообщение, отмеченное фильтром модерации.
+// Сообщение не рассылается участникам, пока администратор чата его не одобрит,
+// после одобрения оно публикуется с тем же ID
+type FlaggedMessage struct {
+	ID       string          `db:"id"`
+	ChatID   string          `db:"chat_id"`
+	UserID   string          `db:"user_id"`
+	Username string          `db:"username"`
+	Text     string          `db:"text"`
+	Kind     string          `db:"kind"`
+	Entities MessageEntities `db:"entities"`
+	Reason   string          `db:"reason"`
+
+	// Источник пересланного сообщения, пустые значения для обычных сообщений
+	ForwardMessageID string `db:"forward_message_id"`
+	ForwardChatID    string `db:"forward_chat_id"`
+	ForwardUserID    string `db:"forward_user_id"`
+	ForwardUsername  string `db:"forward_username"`
+
+	Status     string     `db:"status"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ReviewedBy *string    `db:"reviewed_by"`
+	ReviewedAt *time.Time `db:"reviewed_at"`
+}
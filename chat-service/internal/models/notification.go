@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// Типы уведомлений
+const (
+	NotificationMessage = "message" // Новое сообщение в чате
+	NotificationMention = "mention" // Сообщение с упоминанием пользователя
+	NotificationInvite  = "invite"  // Пользователя добавили в чат
+)
+
+// Notification представляет уведомление пользователя о событии в одном из его чатов
+type Notification struct {
+	Kind          string
+	ChatID        string
+	ChatName      string
+	Message       *Message // Сообщение для NotificationMessage и NotificationMention
+	ActorUsername string   // Автор сообщения или пользователь, добавивший в чат
+	CreatedAt     time.Time
+}
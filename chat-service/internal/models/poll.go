@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// Poll представляет опрос. ID опроса совпадает с ID сообщения, в котором он опубликован
+type Poll struct {
+	ID             string     `db:"id"`
+	ChatID         string     `db:"chat_id"`
+	CreatedByID    string     `db:"created_by_id"`
+	Question       string     `db:"question"`
+	MultipleChoice bool       `db:"multiple_choice"`
+	Anonymous      bool       `db:"anonymous"`
+	ClosesAt       *time.Time `db:"closes_at"`
+	ClosedAt       *time.Time `db:"closed_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+
+	Options     []*PollOption `db:"-"`
+	TotalVoters int           `db:"-"`
+}
+
+// PollOption представляет вариант ответа с текущими результатами голосования
+type PollOption struct {
+	Index    int      `db:"idx"`
+	Text     string   `db:"text"`
+	Votes    int      `db:"-"`
+	VoterIDs []string `db:"-"`
+}
+
+// PollVote представляет голос пользователя за вариант ответа
+type PollVote struct {
+	PollID      string    `db:"poll_id"`
+	OptionIndex int       `db:"option_idx"`
+	UserID      string    `db:"user_id"`
+	VotedAt     time.Time `db:"voted_at"`
+}
+
+// IsClosed возвращает true, если опрос закрыт вручную или истек его срок
+func (p *Poll) IsClosed(now time.Time) bool {
+	if p.ClosedAt != nil {
+		return true
+	}
+
+	return p.ClosesAt != nil && !now.Before(*p.ClosesAt)
+}
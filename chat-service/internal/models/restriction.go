@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// Виды ограничений участников чата
+const (
+	RestrictionBan  = "ban"  // Пользователь исключен из чата и не может вступить снова
+	RestrictionMute = "mute" // Пользователь временно не может писать в чат
+)
+
+// Действия в журнале модерации чата
+const (
+	AuditReport = "report"
+	AuditBan    = "ban"
+	AuditUnban  = "unban"
+	AuditMute   = "mute"
+	AuditUnmute = "unmute"
+)
+
+// ChatRestriction представляет ограничение участника чата.
+// Ограничение действует, пока не снято администратором и не истек срок ExpiresAt
+type ChatRestriction struct {
+	ID        string     `db:"id"`
+	ChatID    string     `db:"chat_id"`
+	UserID    string     `db:"user_id"`
+	Username  string     `db:"username"`
+	Kind      string     `db:"kind"`
+	Reason    string     `db:"reason"`
+	CreatedBy string     `db:"created_by"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt *time.Time `db:"expires_at"` // Пустое значение для бессрочных ограничений
+	LiftedBy  *string    `db:"lifted_by"`
+	LiftedAt  *time.Time `db:"lifted_at"`
+}
+
+// MessageReport представляет жалобу участника на сообщение
+type MessageReport struct {
+	ID         string    `db:"id"`
+	ChatID     string    `db:"chat_id"`
+	MessageID  string    `db:"message_id"`
+	ReporterID string    `db:"reporter_id"`
+	Reason     string    `db:"reason"`
+	CreatedAt  time.Time `db:"created_at"`
+
+	// Автор и текст сообщения, заполняются при чтении жалоб
+	AuthorID       string `db:"author_id"`
+	AuthorUsername string `db:"author_username"`
+	MessageText    string `db:"message_text"`
+}
+
+// AuditEntry представляет запись журнала модерации: кто, над кем и какое действие выполнил
+type AuditEntry struct {
+	ID              string    `db:"id"`
+	ChatID          string    `db:"chat_id"`
+	ActorID         string    `db:"actor_id"`
+	ActorUsername   string    `db:"actor_username"`
+	Action          string    `db:"action"`
+	TargetUserID    string    `db:"target_user_id"`
+	TargetUsername  string    `db:"target_username"`
+	TargetMessageID string    `db:"target_message_id"`
+	Details         string    `db:"details"`
+	CreatedAt       time.Time `db:"created_at"`
+}
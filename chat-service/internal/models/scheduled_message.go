@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Статусы отложенных сообщений
+const (
+	ScheduledPending   = "pending"
+	ScheduledSent      = "sent"
+	ScheduledCancelled = "cancelled"
+	ScheduledFailed    = "failed"
+)
+
+// ScheduledMessage представляет сообщение, ожидающее отправки в указанное время.
+// MessageID назначается при планировании, чтобы повторная попытка отправки
+// после перезапуска сервиса не создала дубликат сообщения
+type ScheduledMessage struct {
+	ID        string     `db:"id"`
+	ChatID    string     `db:"chat_id"`
+	UserID    string     `db:"user_id"`
+	MessageID string     `db:"message_id"`
+	Text      string     `db:"text"`
+	SendAt    time.Time  `db:"send_at"`
+	Status    string     `db:"status"`
+	CreatedAt time.Time  `db:"created_at"`
+	SentAt    *time.Time `db:"sent_at"`
+}
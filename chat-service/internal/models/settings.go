@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// Уровни уведомлений
+const (
+	NotifyAll      = "all"
+	NotifyMentions = "mentions"
+)
+
+// ParticipantSettings представляет персональные настройки чата участника
+type ParticipantSettings struct {
+	ChatID      string     `db:"chat_id"`
+	UserID      string     `db:"user_id"`
+	MutedUntil  *time.Time `db:"muted_until"`
+	NotifyLevel string     `db:"notify_level"`
+	Hidden      bool       `db:"hidden"`
+}
+
+// IsMuted возвращает true, если чат заглушен на момент now
+func (s *ParticipantSettings) IsMuted(now time.Time) bool {
+	return s.MutedUntil != nil && now.Before(*s.MutedUntil)
+}
+
+// ShouldNotify определяет, нужно ли уведомлять участника о сообщении.
+// Заглушенные и скрытые чаты не уведомляют, а при уровне "только упоминания"
+// уведомление отправляется лишь тогда, когда участник упомянут
+func (s *ParticipantSettings) ShouldNotify(now time.Time, mentioned bool) bool {
+	if s.Hidden || s.IsMuted(now) {
+		return false
+	}
+
+	if s.NotifyLevel == NotifyMentions {
+		return mentioned
+	}
+
+	return true
+}
+
+// UnreadCounter представляет счетчики непрочитанных сообщений чата вместе с настройками участника
+type UnreadCounter struct {
+	ParticipantSettings
+	Unread   int `db:"unread"`
+	Mentions int `db:"mentions"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// Webhook представляет исходящий вебхук чата. Каждое новое сообщение чата
+// отправляется на URL вебхука POST-запросом, подписанным секретом вебхука
+type Webhook struct {
+	ID          string    `db:"id"`
+	ChatID      string    `db:"chat_id"`
+	URL         string    `db:"url"`
+	Secret      string    `db:"secret"`
+	CreatedByID string    `db:"created_by_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// WebhookDelivery представляет запись журнала об одной попытке доставки сообщения на вебхук
+type WebhookDelivery struct {
+	ID         string    `db:"id"`
+	WebhookID  string    `db:"webhook_id"`
+	MessageID  string    `db:"message_id"`
+	Attempt    int       `db:"attempt"`
+	StatusCode int       `db:"status_code"`
+	Error      string    `db:"error"`
+	Success    bool      `db:"success"`
+	DurationMs int64     `db:"duration_ms"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// IncomingWebhook представляет входящий вебхук чата: внешние скрипты публикуют сообщения
+// в чат HTTP-запросом на секретный URL. В базе хранится только хеш токена
+type IncomingWebhook struct {
+	ID          string     `db:"id"`
+	ChatID      string     `db:"chat_id"`
+	Name        string     `db:"name"`
+	TokenHash   string     `db:"token_hash"`
+	CreatedByID string     `db:"created_by_id"`
+	BotID       string     `db:"bot_id"` // Бот-аккаунт, от имени которого вебхук пишет в чат
+	CreatedAt   time.Time  `db:"created_at"`
+	RevokedAt   *time.Time `db:"revoked_at"`
+}
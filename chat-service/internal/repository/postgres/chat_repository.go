@@ -4,16 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	"chat.service/internal/models"
+	"chat.service/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
 var (
-	ErrChatNotFound  = errors.New("чат не найден")
-	ErrUserNotInChat = errors.New("пользователь не является участником чата")
+	ErrChatNotFound  = repository.ErrChatNotFound
+	ErrUserNotInChat = repository.ErrUserNotInChat
 )
 
 type ChatRepository struct {
@@ -35,8 +37,8 @@ func (r *ChatRepository) CreateChat(ctx context.Context, chat *models.Chat) (str
 
 	chat.CreatedAt = time.Now()
 
-	query := `INSERT INTO chats (id, name, created_at, created_by_id) VALUES ($1, $2, $3, $4)`
-	_, err := r.db.ExecContext(ctx, query, chat.ID, chat.Name, chat.CreatedAt, chat.CreatedByID)
+	query := `INSERT INTO chats (id, name, visibility, kind, created_at, created_by_id) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.db.ExecContext(ctx, query, chat.ID, chat.Name, chat.Visibility, chat.Kind, chat.CreatedAt, chat.CreatedByID)
 	if err != nil {
 		return "", err
 	}
@@ -44,7 +46,7 @@ func (r *ChatRepository) CreateChat(ctx context.Context, chat *models.Chat) (str
 	return chat.ID, nil
 }
 
-func (r *ChatRepository) AddParticipant(ctx context.Context, chatID, userID string) error {
+func (r *ChatRepository) AddParticipant(ctx context.Context, chatID, userID, role string) error {
 	// Проверяем существование чата
 	_, err := r.GetChatByID(ctx, chatID)
 	if err != nil {
@@ -62,8 +64,9 @@ func (r *ChatRepository) AddParticipant(ctx context.Context, chatID, userID stri
 	}
 
 	// Добавляем пользователя в чат
-	query := `INSERT INTO chat_participants (chat_id, user_id, joined_at) VALUES ($1, $2, $3)`
-	_, err = r.db.ExecContext(ctx, query, chatID, userID, time.Now())
+	now := time.Now()
+	query := `INSERT INTO chat_participants (chat_id, user_id, role, joined_at, last_read_at) VALUES ($1, $2, $3, $4, $4)`
+	_, err = r.db.ExecContext(ctx, query, chatID, userID, role, now)
 	if err != nil {
 		return err
 	}
@@ -71,8 +74,48 @@ func (r *ChatRepository) AddParticipant(ctx context.Context, chatID, userID stri
 	return nil
 }
 
+func (r *ChatRepository) RemoveParticipant(ctx context.Context, chatID, userID string) error {
+	query := `DELETE FROM chat_participants WHERE chat_id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, chatID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrUserNotInChat
+	}
+
+	return nil
+}
+
+func (r *ChatRepository) UpdateChatTopic(ctx context.Context, chatID, topic string) error {
+	query := `UPDATE chats SET topic = $1 WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, topic, chatID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrChatNotFound
+	}
+
+	return nil
+}
+
 func (r *ChatRepository) GetChatByID(ctx context.Context, chatID string) (*models.Chat, error) {
-	query := `SELECT id, name, created_at, created_by_id FROM chats WHERE id = $1`
+	query := `SELECT id, name, visibility, kind, topic, created_at, created_by_id FROM chats WHERE id = $1`
 
 	var chat models.Chat
 	err := r.db.GetContext(ctx, &chat, query, chatID)
@@ -86,6 +129,24 @@ func (r *ChatRepository) GetChatByID(ctx context.Context, chatID string) (*model
 	return &chat, nil
 }
 
+func (r *ChatRepository) SearchPublicChats(ctx context.Context, prefix string, limit int) ([]*models.Chat, error) {
+	query := `
+		SELECT id, name, visibility, kind, topic, created_at, created_by_id
+		FROM chats
+		WHERE visibility = 'public' AND lower(name) LIKE $1 ESCAPE '\'
+		ORDER BY lower(name), created_at
+		LIMIT $2
+	`
+
+	var chats []*models.Chat
+	err := r.db.SelectContext(ctx, &chats, query, likePrefix(prefix), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return chats, nil
+}
+
 func (r *ChatRepository) GetChatParticipants(ctx context.Context, chatID string) ([]string, error) {
 	query := `SELECT user_id FROM chat_participants WHERE chat_id = $1`
 
@@ -110,6 +171,135 @@ func (r *ChatRepository) CheckUserInChat(ctx context.Context, chatID, userID str
 	return count > 0, nil
 }
 
+func (r *ChatRepository) GetParticipantRole(ctx context.Context, chatID, userID string) (string, error) {
+	query := `SELECT role FROM chat_participants WHERE chat_id = $1 AND user_id = $2`
+
+	var role string
+	err := r.db.GetContext(ctx, &role, query, chatID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", repository.ErrUserNotInChat
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (r *ChatRepository) GetParticipantSettings(ctx context.Context, chatID, userID string) (*models.ParticipantSettings, error) {
+	query := `
+		SELECT chat_id, user_id, muted_until, notify_level, hidden
+		FROM chat_participants
+		WHERE chat_id = $1 AND user_id = $2
+	`
+
+	var settings models.ParticipantSettings
+	err := r.db.GetContext(ctx, &settings, query, chatID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrUserNotInChat
+		}
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *ChatRepository) GetParticipantsSettings(ctx context.Context, chatID string, userIDs []string) ([]*models.ParticipantSettings, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT chat_id, user_id, muted_until, notify_level, hidden
+		FROM chat_participants
+		WHERE chat_id = ? AND user_id IN (?)
+	`, chatID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []*models.ParticipantSettings
+	if err := r.db.SelectContext(ctx, &settings, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (r *ChatRepository) UpdateParticipantSettings(ctx context.Context, settings *models.ParticipantSettings) error {
+	query := `
+		UPDATE chat_participants
+		SET muted_until = $1, notify_level = $2, hidden = $3
+		WHERE chat_id = $4 AND user_id = $5
+	`
+
+	result, err := r.db.ExecContext(ctx, query, settings.MutedUntil, settings.NotifyLevel, settings.Hidden, settings.ChatID, settings.UserID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrUserNotInChat
+	}
+
+	return nil
+}
+
+func (r *ChatRepository) GetUnreadCounters(ctx context.Context, userID, username string) ([]*models.UnreadCounter, error) {
+	query := `
+		SELECT
+			p.chat_id, p.user_id, p.muted_until, p.notify_level, p.hidden,
+			COUNT(m.id) AS unread,
+			COALESCE(SUM(CASE WHEN lower(m.text) LIKE $2 ESCAPE '\' THEN 1 ELSE 0 END), 0) AS mentions
+		FROM chat_participants p
+		LEFT JOIN messages m
+			ON m.chat_id = p.chat_id
+			AND m.user_id <> p.user_id
+			AND m.created_at > COALESCE(p.last_read_at, p.joined_at)
+			AND m.deleted_at IS NULL
+		WHERE p.user_id = $1
+		GROUP BY p.chat_id, p.user_id, p.muted_until, p.notify_level, p.hidden
+	`
+
+	var counters []*models.UnreadCounter
+	err := r.db.SelectContext(ctx, &counters, query, userID, mentionPattern(username))
+	if err != nil {
+		return nil, err
+	}
+
+	return counters, nil
+}
+
+func (r *ChatRepository) MarkRead(ctx context.Context, chatID, userID string, readAt time.Time) error {
+	query := `UPDATE chat_participants SET last_read_at = $1 WHERE chat_id = $2 AND user_id = $3`
+
+	_, err := r.db.ExecContext(ctx, query, readAt, chatID, userID)
+	return err
+}
+
+// mentionPattern строит шаблон LIKE для поиска упоминания пользователя в тексте
+func mentionPattern(username string) string {
+	return "%@" + escapeLike(strings.ToLower(username)) + "%"
+}
+
+// likePrefix строит шаблон LIKE для поиска по началу строки без учета регистра,
+// экранируя спецсимволы шаблона во введенном пользователем префиксе
+func likePrefix(prefix string) string {
+	return escapeLike(strings.ToLower(prefix)) + "%"
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
+
 type MessageRepository struct {
 	db *sqlx.DB
 }
@@ -129,8 +319,8 @@ func (r *MessageRepository) SaveMessage(ctx context.Context, message *models.Mes
 
 	message.CreatedAt = time.Now()
 
-	query := `INSERT INTO messages (id, chat_id, user_id, username, text, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := r.db.ExecContext(
+	query := `INSERT INTO messages (id, chat_id, user_id, username, text, kind, entities, created_at, forward_message_id, forward_chat_id, forward_user_id, forward_username) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) ON CONFLICT (id) DO NOTHING`
+	result, err := r.db.ExecContext(
 		ctx,
 		query,
 		message.ID,
@@ -138,21 +328,34 @@ func (r *MessageRepository) SaveMessage(ctx context.Context, message *models.Mes
 		message.UserID,
 		message.Username,
 		message.Text,
+		message.Kind,
+		message.Entities,
 		message.CreatedAt,
+		message.ForwardMessageID,
+		message.ForwardChatID,
+		message.ForwardUserID,
+		message.ForwardUsername,
 	)
 	if err != nil {
 		return "", err
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+
+	if rowsAffected == 0 {
+		return "", repository.ErrMessageExists
+	}
+
 	return message.ID, nil
 }
 
 func (r *MessageRepository) GetChatMessages(ctx context.Context, chatID string, limit, offset int) ([]*models.Message, error) {
-	query := `
-		SELECT id, chat_id, user_id, username, text, created_at 
-		FROM messages 
-		WHERE chat_id = $1 
-		ORDER BY created_at DESC 
+	query := selectMessages + `
+		WHERE m.chat_id = $1
+		ORDER BY m.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
@@ -164,3 +367,49 @@ func (r *MessageRepository) GetChatMessages(ctx context.Context, chatID string,
 
 	return messages, nil
 }
+
+func (r *MessageRepository) GetMessageByID(ctx context.Context, messageID string) (*models.Message, error) {
+	query := selectMessages + ` WHERE m.id = $1`
+
+	var message models.Message
+	err := r.db.GetContext(ctx, &message, query, messageID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (r *MessageRepository) DeleteMessage(ctx context.Context, messageID string, deletedAt time.Time) error {
+	query := `UPDATE messages SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, deletedAt, messageID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrMessageNotFound
+	}
+
+	return nil
+}
+
+// selectMessages выбирает сообщения вместе с признаком удаления исходника пересланного сообщения.
+// Пересланное сообщение считается удаленным, если исходник удален или отсутствует
+const selectMessages = `
+	SELECT
+		m.id, m.chat_id, m.user_id, m.username, m.text, m.kind, m.entities, m.created_at, m.deleted_at,
+		m.forward_message_id, m.forward_chat_id, m.forward_user_id, m.forward_username,
+		CASE WHEN m.forward_message_id <> '' AND (src.id IS NULL OR src.deleted_at IS NOT NULL) THEN 1 ELSE 0 END = 1 AS forward_deleted
+	FROM messages m
+	LEFT JOIN messages src ON src.id = NULLIF(m.forward_message_id, '')::uuid
+`
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// PollRepository реализует интерфейс repository.PollRepository
+type PollRepository struct {
+	db *sqlx.DB
+}
+
+func NewPollRepository(db *sqlx.DB) *PollRepository {
+	return &PollRepository{db: db}
+}
+
+func (r *PollRepository) CreatePoll(ctx context.Context, poll *models.Poll) error {
+	poll.CreatedAt = time.Now()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO polls (id, chat_id, created_by_id, question, multiple_choice, anonymous, closes_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		poll.ID,
+		poll.ChatID,
+		poll.CreatedByID,
+		poll.Question,
+		poll.MultipleChoice,
+		poll.Anonymous,
+		poll.ClosesAt,
+		poll.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, option := range poll.Options {
+		_, err = tx.ExecContext(ctx, `INSERT INTO poll_options (poll_id, idx, text) VALUES ($1, $2, $3)`, poll.ID, option.Index, option.Text)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PollRepository) GetPoll(ctx context.Context, pollID string) (*models.Poll, error) {
+	query := `
+		SELECT id, chat_id, created_by_id, question, multiple_choice, anonymous, closes_at, closed_at, created_at
+		FROM polls
+		WHERE id = $1
+	`
+
+	var poll models.Poll
+	err := r.db.GetContext(ctx, &poll, query, pollID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrPollNotFound
+		}
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &poll.Options, `SELECT idx, text FROM poll_options WHERE poll_id = $1 ORDER BY idx`, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	var votes []models.PollVote
+	err = r.db.SelectContext(ctx, &votes, `SELECT poll_id, option_idx, user_id, voted_at FROM poll_votes WHERE poll_id = $1 ORDER BY voted_at`, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyVotes(&poll, votes)
+
+	return &poll, nil
+}
+
+func (r *PollRepository) ReplaceVotes(ctx context.Context, pollID, userID string, optionIndexes []int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM poll_votes WHERE poll_id = $1 AND user_id = $2`, pollID, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, idx := range optionIndexes {
+		_, err = tx.ExecContext(ctx, `INSERT INTO poll_votes (poll_id, option_idx, user_id, voted_at) VALUES ($1, $2, $3, $4)`, pollID, idx, userID, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PollRepository) ClosePoll(ctx context.Context, pollID string, closedAt time.Time) (bool, error) {
+	query := `UPDATE polls SET closed_at = $1 WHERE id = $2 AND closed_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, closedAt, pollID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *PollRepository) GetExpiredPollIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id
+		FROM polls
+		WHERE closed_at IS NULL AND closes_at IS NOT NULL AND closes_at <= $1
+		ORDER BY closes_at
+		LIMIT $2
+	`
+
+	var pollIDs []string
+	err := r.db.SelectContext(ctx, &pollIDs, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pollIDs, nil
+}
+
+// applyVotes подсчитывает голоса по вариантам ответа и количество проголосовавших
+func applyVotes(poll *models.Poll, votes []models.PollVote) {
+	voters := make(map[string]struct{})
+	for _, vote := range votes {
+		if vote.OptionIndex < 0 || vote.OptionIndex >= len(poll.Options) {
+			continue
+		}
+
+		option := poll.Options[vote.OptionIndex]
+		option.Votes++
+		option.VoterIDs = append(option.VoterIDs, vote.UserID)
+		voters[vote.UserID] = struct{}{}
+	}
+
+	poll.TotalVoters = len(voters)
+}
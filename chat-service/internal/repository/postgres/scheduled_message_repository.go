@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ScheduledMessageRepository реализует интерфейс repository.ScheduledMessageRepository
+type ScheduledMessageRepository struct {
+	db *sqlx.DB
+}
+
+func NewScheduledMessageRepository(db *sqlx.DB) *ScheduledMessageRepository {
+	return &ScheduledMessageRepository{db: db}
+}
+
+func (r *ScheduledMessageRepository) CreateScheduledMessage(ctx context.Context, message *models.ScheduledMessage) (string, error) {
+	if message.ID == "" {
+		message.ID = uuid.New().String()
+	}
+
+	if message.MessageID == "" {
+		message.MessageID = uuid.New().String()
+	}
+
+	message.Status = models.ScheduledPending
+	message.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO scheduled_messages (id, chat_id, user_id, message_id, text, send_at, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		message.ID,
+		message.ChatID,
+		message.UserID,
+		message.MessageID,
+		message.Text,
+		message.SendAt,
+		message.Status,
+		message.CreatedAt,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return message.ID, nil
+}
+
+func (r *ScheduledMessageRepository) GetDueScheduledMessages(ctx context.Context, now, afterSendAt time.Time, afterID string, limit int) ([]*models.ScheduledMessage, error) {
+	query := `
+		SELECT id, chat_id, user_id, message_id, text, send_at, status, created_at, sent_at
+		FROM scheduled_messages
+		WHERE status = 'pending' AND send_at <= $1 AND (send_at > $2 OR (send_at = $2 AND id::text > $3))
+		ORDER BY send_at, id
+		LIMIT $4
+	`
+
+	var messages []*models.ScheduledMessage
+	err := r.db.SelectContext(ctx, &messages, query, now, afterSendAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *ScheduledMessageRepository) ListPendingScheduledMessages(ctx context.Context, userID, chatID string) ([]*models.ScheduledMessage, error) {
+	query := `
+		SELECT id, chat_id, user_id, message_id, text, send_at, status, created_at, sent_at
+		FROM scheduled_messages
+		WHERE status = 'pending' AND user_id = $1 AND ($2 = '' OR chat_id::text = $2)
+		ORDER BY send_at
+	`
+
+	var messages []*models.ScheduledMessage
+	err := r.db.SelectContext(ctx, &messages, query, userID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *ScheduledMessageRepository) CancelScheduledMessage(ctx context.Context, id, userID string) error {
+	query := `UPDATE scheduled_messages SET status = 'cancelled' WHERE id = $1 AND user_id = $2 AND status = 'pending'`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrScheduledMessageNotFound
+	}
+
+	return nil
+}
+
+func (r *ScheduledMessageRepository) RescheduleMessage(ctx context.Context, id string, sendAt time.Time) error {
+	query := `UPDATE scheduled_messages SET send_at = $1 WHERE id = $2 AND status = 'pending'`
+
+	_, err := r.db.ExecContext(ctx, query, sendAt, id)
+	return err
+}
+
+func (r *ScheduledMessageRepository) SetScheduledMessageStatus(ctx context.Context, id, status string, at time.Time) error {
+	query := `UPDATE scheduled_messages SET status = $1, sent_at = $2 WHERE id = $3 AND status = 'pending'`
+
+	_, err := r.db.ExecContext(ctx, query, status, at, id)
+	return err
+}
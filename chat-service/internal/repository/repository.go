@@ -2,28 +2,207 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"chat.service/internal/models"
 )
 
+var (
+	ErrChatNotFound    = errors.New("чат не найден")
+	ErrUserNotInChat   = errors.New("пользователь не является участником чата")
+	ErrInviteNotFound  = errors.New("приглашение не найдено")
+	ErrInviteExhausted = errors.New("приглашение недействительно или исчерпано")
+	ErrMessageExists   = errors.New("сообщение с таким ID уже существует")
+
+	ErrMessageNotFound = errors.New("сообщение не найдено")
+	ErrPollNotFound    = errors.New("опрос не найден")
+
+	ErrScheduledMessageNotFound = errors.New("отложенное сообщение не найдено")
+
+	ErrWebhookNotFound = errors.New("вебхук не найден")
+
+	ErrCommandNotFound = errors.New("команда не найдена")
+	ErrCommandTaken    = errors.New("команда уже зарегистрирована другим ботом")
+
+	ErrFlaggedMessageNotFound = errors.New("сообщение в очереди модерации не найдено")
+	ErrRestrictionNotFound    = errors.New("действующее ограничение не найдено")
+	ErrAlreadyReported        = errors.New("жалоба на сообщение уже отправлена")
+)
+
 // ChatRepository определяет интерфейс для работы с чатами
 type ChatRepository interface {
 	// CreateChat создает новый чат
 	CreateChat(ctx context.Context, chat *models.Chat) (string, error)
-	// AddParticipant добавляет участника в чат
-	AddParticipant(ctx context.Context, chatID, userID string) error
+	// AddParticipant добавляет участника в чат с указанной ролью
+	AddParticipant(ctx context.Context, chatID, userID, role string) error
+	// RemoveParticipant удаляет участника из чата
+	RemoveParticipant(ctx context.Context, chatID, userID string) error
+	// UpdateChatTopic изменяет тему чата
+	UpdateChatTopic(ctx context.Context, chatID, topic string) error
 	// GetChatByID возвращает чат по ID
 	GetChatByID(ctx context.Context, chatID string) (*models.Chat, error)
+	// SearchPublicChats ищет публичные чаты по началу названия
+	SearchPublicChats(ctx context.Context, prefix string, limit int) ([]*models.Chat, error)
 	// GetChatParticipants возвращает список участников чата
 	GetChatParticipants(ctx context.Context, chatID string) ([]string, error)
 	// CheckUserInChat проверяет, является ли пользователь участником чата
 	CheckUserInChat(ctx context.Context, chatID, userID string) (bool, error)
+	// GetParticipantRole возвращает роль пользователя в чате
+	GetParticipantRole(ctx context.Context, chatID, userID string) (string, error)
+	// GetParticipantSettings возвращает персональные настройки чата участника
+	GetParticipantSettings(ctx context.Context, chatID, userID string) (*models.ParticipantSettings, error)
+	// GetParticipantsSettings возвращает настройки чата нескольких участников одним запросом,
+	// пользователи, не состоящие в чате, пропускаются
+	GetParticipantsSettings(ctx context.Context, chatID string, userIDs []string) ([]*models.ParticipantSettings, error)
+	// UpdateParticipantSettings сохраняет персональные настройки чата участника
+	UpdateParticipantSettings(ctx context.Context, settings *models.ParticipantSettings) error
+	// GetUnreadCounters возвращает счетчики непрочитанных сообщений и упоминаний по всем чатам пользователя
+	GetUnreadCounters(ctx context.Context, userID, username string) ([]*models.UnreadCounter, error)
+	// MarkRead обновляет время последнего прочтения чата участником
+	MarkRead(ctx context.Context, chatID, userID string, readAt time.Time) error
 }
 
 // MessageRepository определяет интерфейс для работы с сообщениями
 type MessageRepository interface {
-	// SaveMessage сохраняет сообщение в базе данных
+	// SaveMessage сохраняет сообщение в базе данных.
+	// Если сообщение с заданным ID уже существует, возвращает ErrMessageExists
 	SaveMessage(ctx context.Context, message *models.Message) (string, error)
 	// GetChatMessages возвращает сообщения чата
 	GetChatMessages(ctx context.Context, chatID string, limit, offset int) ([]*models.Message, error)
+	// GetMessageByID возвращает сообщение по ID
+	GetMessageByID(ctx context.Context, messageID string) (*models.Message, error)
+	// DeleteMessage помечает сообщение удаленным. Если сообщение не найдено или уже удалено, возвращает ErrMessageNotFound
+	DeleteMessage(ctx context.Context, messageID string, deletedAt time.Time) error
+}
+
+// PollRepository определяет интерфейс для работы с опросами
+type PollRepository interface {
+	// CreatePoll сохраняет опрос вместе с вариантами ответов
+	CreatePoll(ctx context.Context, poll *models.Poll) error
+	// GetPoll возвращает опрос с вариантами ответов и текущими результатами
+	GetPoll(ctx context.Context, pollID string) (*models.Poll, error)
+	// ReplaceVotes заменяет голоса пользователя в опросе
+	ReplaceVotes(ctx context.Context, pollID, userID string, optionIndexes []int) error
+	// ClosePoll закрывает опрос, если он еще не закрыт
+	ClosePoll(ctx context.Context, pollID string, closedAt time.Time) (bool, error)
+	// GetExpiredPollIDs возвращает ID незакрытых опросов, срок которых истек
+	GetExpiredPollIDs(ctx context.Context, now time.Time, limit int) ([]string, error)
+}
+
+// InviteRepository определяет интерфейс для работы с приглашениями в чаты
+type InviteRepository interface {
+	// CreateInvite сохраняет новое приглашение
+	CreateInvite(ctx context.Context, invite *models.ChatInvite) (string, error)
+	// GetInviteByID возвращает приглашение по ID
+	GetInviteByID(ctx context.Context, inviteID string) (*models.ChatInvite, error)
+	// GetInviteByTokenHash возвращает приглашение по хешу кода
+	GetInviteByTokenHash(ctx context.Context, tokenHash string) (*models.ChatInvite, error)
+	// UseInvite атомарно увеличивает счетчик использований действующего приглашения
+	UseInvite(ctx context.Context, inviteID string) error
+	// RevokeInvite помечает приглашение как отозванное
+	RevokeInvite(ctx context.Context, inviteID string) error
+}
+
+// ScheduledMessageRepository определяет интерфейс для работы с отложенными сообщениями
+type ScheduledMessageRepository interface {
+	// CreateScheduledMessage сохраняет новое отложенное сообщение
+	CreateScheduledMessage(ctx context.Context, message *models.ScheduledMessage) (string, error)
+	// GetDueScheduledMessages возвращает ожидающие сообщения, время отправки которых наступило,
+	// в порядке (send_at, id) после сообщения afterSendAt, afterID. Нулевые значения выбирают с начала очереди
+	GetDueScheduledMessages(ctx context.Context, now, afterSendAt time.Time, afterID string, limit int) ([]*models.ScheduledMessage, error)
+	// ListPendingScheduledMessages возвращает ожидающие сообщения пользователя, при chatID != "" только для этого чата
+	ListPendingScheduledMessages(ctx context.Context, userID, chatID string) ([]*models.ScheduledMessage, error)
+	// CancelScheduledMessage отменяет ожидающее сообщение пользователя
+	CancelScheduledMessage(ctx context.Context, id, userID string) error
+	// RescheduleMessage переносит время отправки ожидающего сообщения
+	RescheduleMessage(ctx context.Context, id string, sendAt time.Time) error
+	// SetScheduledMessageStatus переводит ожидающее сообщение в итоговый статус
+	SetScheduledMessageStatus(ctx context.Context, id, status string, at time.Time) error
+}
+
+// WebhookRepository определяет интерфейс для работы с исходящими вебхуками и журналом их доставки
+type WebhookRepository interface {
+	// CreateWebhook сохраняет новый вебхук
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	// GetWebhook возвращает вебхук по ID
+	GetWebhook(ctx context.Context, webhookID string) (*models.Webhook, error)
+	// GetChatWebhooks возвращает все вебхуки чата
+	GetChatWebhooks(ctx context.Context, chatID string) ([]*models.Webhook, error)
+	// DeleteWebhook удаляет вебхук вместе с журналом доставки
+	DeleteWebhook(ctx context.Context, webhookID string) error
+	// SaveDelivery добавляет запись в журнал доставки
+	SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	// GetDeliveries возвращает последние записи журнала доставки вебхука
+	GetDeliveries(ctx context.Context, webhookID string, limit int) ([]*models.WebhookDelivery, error)
+}
+
+// IncomingWebhookRepository определяет интерфейс для работы с входящими вебхуками
+type IncomingWebhookRepository interface {
+	// CreateIncomingWebhook сохраняет новый входящий вебхук
+	CreateIncomingWebhook(ctx context.Context, webhook *models.IncomingWebhook) error
+	// GetIncomingWebhook возвращает входящий вебхук по ID
+	GetIncomingWebhook(ctx context.Context, webhookID string) (*models.IncomingWebhook, error)
+	// GetIncomingWebhookByTokenHash возвращает действующий входящий вебхук по хешу токена
+	GetIncomingWebhookByTokenHash(ctx context.Context, tokenHash string) (*models.IncomingWebhook, error)
+	// GetChatIncomingWebhooks возвращает действующие входящие вебхуки чата
+	GetChatIncomingWebhooks(ctx context.Context, chatID string) ([]*models.IncomingWebhook, error)
+	// IsIncomingWebhookBot проверяет, принадлежит ли бот-аккаунт действующему входящему вебхуку чата
+	IsIncomingWebhookBot(ctx context.Context, chatID, botID string) (bool, error)
+	// RevokeIncomingWebhook отзывает входящий вебхук
+	RevokeIncomingWebhook(ctx context.Context, webhookID string, revokedAt time.Time) error
+}
+
+// CommandRepository определяет интерфейс для работы со slash-командами ботов
+type CommandRepository interface {
+	// RegisterCommand сохраняет команду или обновляет ее описание.
+	// Если команда принадлежит другому боту, возвращает ErrCommandTaken
+	RegisterCommand(ctx context.Context, command *models.ChatCommand) error
+	// UnregisterCommand удаляет команду, зарегистрированную ботом
+	UnregisterCommand(ctx context.Context, chatID, name, botID string) error
+	// GetCommand возвращает команду чата по имени
+	GetCommand(ctx context.Context, chatID, name string) (*models.ChatCommand, error)
+	// ListCommands возвращает команды, зарегистрированные в чате
+	ListCommands(ctx context.Context, chatID string) ([]*models.ChatCommand, error)
+}
+
+// LinkPreviewRepository определяет интерфейс для работы с кешем превью ссылок
+type LinkPreviewRepository interface {
+	// SaveLinkPreview сохраняет превью ссылки, заменяя ранее сохраненное
+	SaveLinkPreview(ctx context.Context, preview *models.LinkPreview) error
+	// GetLinkPreviews возвращает сохраненные превью для указанных адресов, отсутствующие адреса пропускаются
+	GetLinkPreviews(ctx context.Context, urls []string) ([]*models.LinkPreview, error)
+}
+
+// ModerationRepository определяет интерфейс для работы с очередью модерации,
+// ограничениями участников, жалобами и журналом модерации
+type ModerationRepository interface {
+	// QueueMessage добавляет отмеченное сообщение в очередь модерации.
+	// Если запись с заданным ID уже существует, возвращает ErrMessageExists
+	QueueMessage(ctx context.Context, message *models.FlaggedMessage) error
+	// GetFlaggedMessage возвращает сообщение из очереди модерации по ID
+	GetFlaggedMessage(ctx context.Context, id string) (*models.FlaggedMessage, error)
+	// GetPendingFlaggedMessages возвращает сообщения чата, ожидающие проверки
+	GetPendingFlaggedMessages(ctx context.Context, chatID string) ([]*models.FlaggedMessage, error)
+	// ReviewFlaggedMessage устанавливает решение администратора для ожидающего проверки сообщения
+	ReviewFlaggedMessage(ctx context.Context, id, status, reviewedBy string, reviewedAt time.Time) error
+
+	// CreateRestriction сохраняет ограничение участника, заменяя действующее ограничение того же вида
+	CreateRestriction(ctx context.Context, restriction *models.ChatRestriction) error
+	// GetActiveRestrictions возвращает ограничения пользователя в чате, действующие на момент now
+	GetActiveRestrictions(ctx context.Context, chatID, userID string, now time.Time) ([]*models.ChatRestriction, error)
+	// GetChatRestrictions возвращает все ограничения чата, действующие на момент now
+	GetChatRestrictions(ctx context.Context, chatID string, now time.Time) ([]*models.ChatRestriction, error)
+	// LiftRestriction снимает действующее ограничение пользователя указанного вида
+	LiftRestriction(ctx context.Context, chatID, userID, kind, liftedBy string, liftedAt time.Time) error
+
+	// CreateReport сохраняет жалобу на сообщение
+	CreateReport(ctx context.Context, report *models.MessageReport) error
+	// GetChatReports возвращает последние жалобы на сообщения чата вместе с автором и текстом сообщений
+	GetChatReports(ctx context.Context, chatID string, limit int) ([]*models.MessageReport, error)
+
+	// AddAuditEntry добавляет запись в журнал модерации чата
+	AddAuditEntry(ctx context.Context, entry *models.AuditEntry) error
+	// GetAuditLog возвращает последние записи журнала модерации чата
+	GetAuditLog(ctx context.Context, chatID string, limit int) ([]*models.AuditEntry, error)
 }
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// CommandRepository реализует интерфейс repository.CommandRepository
+type CommandRepository struct {
+	db *sqlx.DB
+}
+
+func NewCommandRepository(db *sqlx.DB) *CommandRepository {
+	return &CommandRepository{db: db}
+}
+
+func (r *CommandRepository) RegisterCommand(ctx context.Context, command *models.ChatCommand) error {
+	command.CreatedAt = time.Now()
+
+	// Повторная регистрация тем же ботом обновляет описание,
+	// команда другого бота не перезаписывается
+	query := `
+		INSERT INTO chat_commands (chat_id, name, description, bot_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (chat_id, name) DO UPDATE SET description = excluded.description
+		WHERE chat_commands.bot_id = excluded.bot_id
+	`
+	result, err := r.db.ExecContext(ctx, query, command.ChatID, command.Name, command.Description, command.BotID, command.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrCommandTaken
+	}
+
+	return nil
+}
+
+func (r *CommandRepository) UnregisterCommand(ctx context.Context, chatID, name, botID string) error {
+	query := `DELETE FROM chat_commands WHERE chat_id = ? AND name = ? AND bot_id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, chatID, name, botID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrCommandNotFound
+	}
+
+	return nil
+}
+
+func (r *CommandRepository) GetCommand(ctx context.Context, chatID, name string) (*models.ChatCommand, error) {
+	query := `SELECT chat_id, name, description, bot_id, created_at FROM chat_commands WHERE chat_id = ? AND name = ?`
+
+	var command models.ChatCommand
+	err := r.db.GetContext(ctx, &command, query, chatID, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrCommandNotFound
+		}
+		return nil, err
+	}
+
+	return &command, nil
+}
+
+func (r *CommandRepository) ListCommands(ctx context.Context, chatID string) ([]*models.ChatCommand, error) {
+	query := `
+		SELECT chat_id, name, description, bot_id, created_at
+		FROM chat_commands
+		WHERE chat_id = ?
+		ORDER BY name
+	`
+
+	var commands []*models.ChatCommand
+	if err := r.db.SelectContext(ctx, &commands, query, chatID); err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// InviteRepository реализует интерфейс repository.InviteRepository
+type InviteRepository struct {
+	db *sqlx.DB
+}
+
+func NewInviteRepository(db *sqlx.DB) *InviteRepository {
+	return &InviteRepository{db: db}
+}
+
+func (r *InviteRepository) CreateInvite(ctx context.Context, invite *models.ChatInvite) (string, error) {
+	if invite.ID == "" {
+		invite.ID = uuid.New().String()
+	}
+
+	invite.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO chat_invites (id, chat_id, token_hash, created_by_id, role, max_uses, uses, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, 0, ?, ?)
+	`
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		invite.ID,
+		invite.ChatID,
+		invite.TokenHash,
+		invite.CreatedByID,
+		invite.Role,
+		invite.MaxUses,
+		invite.ExpiresAt,
+		invite.CreatedAt,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return invite.ID, nil
+}
+
+func (r *InviteRepository) GetInviteByID(ctx context.Context, inviteID string) (*models.ChatInvite, error) {
+	query := `
+		SELECT id, chat_id, token_hash, created_by_id, role, max_uses, uses, expires_at, revoked_at, created_at
+		FROM chat_invites
+		WHERE id = ?
+	`
+
+	var invite models.ChatInvite
+	err := r.db.GetContext(ctx, &invite, query, inviteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrInviteNotFound
+		}
+		return nil, err
+	}
+
+	return &invite, nil
+}
+
+func (r *InviteRepository) GetInviteByTokenHash(ctx context.Context, tokenHash string) (*models.ChatInvite, error) {
+	query := `
+		SELECT id, chat_id, token_hash, created_by_id, role, max_uses, uses, expires_at, revoked_at, created_at
+		FROM chat_invites
+		WHERE token_hash = ?
+	`
+
+	var invite models.ChatInvite
+	err := r.db.GetContext(ctx, &invite, query, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrInviteNotFound
+		}
+		return nil, err
+	}
+
+	return &invite, nil
+}
+
+func (r *InviteRepository) UseInvite(ctx context.Context, inviteID string) error {
+	// Проверка срока действия и лимита выполняется в том же запросе,
+	// чтобы параллельные вступления не превысили max_uses
+	query := `
+		UPDATE chat_invites
+		SET uses = uses + 1
+		WHERE id = ?
+		  AND revoked_at IS NULL
+		  AND expires_at > ?
+		  AND (max_uses = 0 OR uses < max_uses)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, inviteID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrInviteExhausted
+	}
+
+	return nil
+}
+
+func (r *InviteRepository) RevokeInvite(ctx context.Context, inviteID string) error {
+	query := `UPDATE chat_invites SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, time.Now(), inviteID)
+	return err
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+
+	"chat.service/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// LinkPreviewRepository реализует интерфейс repository.LinkPreviewRepository
+type LinkPreviewRepository struct {
+	db *sqlx.DB
+}
+
+func NewLinkPreviewRepository(db *sqlx.DB) *LinkPreviewRepository {
+	return &LinkPreviewRepository{db: db}
+}
+
+func (r *LinkPreviewRepository) SaveLinkPreview(ctx context.Context, preview *models.LinkPreview) error {
+	query := `
+		INSERT INTO link_previews (url, title, description, image_url, fetched_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (url) DO UPDATE SET
+			title = excluded.title,
+			description = excluded.description,
+			image_url = excluded.image_url,
+			fetched_at = excluded.fetched_at
+	`
+	_, err := r.db.ExecContext(ctx, query, preview.URL, preview.Title, preview.Description, preview.ImageURL, preview.FetchedAt)
+	return err
+}
+
+func (r *LinkPreviewRepository) GetLinkPreviews(ctx context.Context, urls []string) ([]*models.LinkPreview, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT url, title, description, image_url, fetched_at FROM link_previews WHERE url IN (?)`, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	var previews []*models.LinkPreview
+	if err := r.db.SelectContext(ctx, &previews, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return previews, nil
+}
@@ -0,0 +1,328 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ModerationRepository реализует интерфейс repository.ModerationRepository
+type ModerationRepository struct {
+	db *sqlx.DB
+}
+
+func NewModerationRepository(db *sqlx.DB) *ModerationRepository {
+	return &ModerationRepository{db: db}
+}
+
+func (r *ModerationRepository) QueueMessage(ctx context.Context, message *models.FlaggedMessage) error {
+	if message.ID == "" {
+		message.ID = uuid.New().String()
+	}
+	message.Status = models.FlaggedPending
+	message.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO moderation_queue (id, chat_id, user_id, username, text, kind, entities, reason, status, created_at,
+			forward_message_id, forward_chat_id, forward_user_id, forward_username)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING
+	`
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		message.ID,
+		message.ChatID,
+		message.UserID,
+		message.Username,
+		message.Text,
+		message.Kind,
+		message.Entities,
+		message.Reason,
+		message.Status,
+		message.CreatedAt,
+		message.ForwardMessageID,
+		message.ForwardChatID,
+		message.ForwardUserID,
+		message.ForwardUsername,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrMessageExists
+	}
+
+	return nil
+}
+
+func (r *ModerationRepository) GetFlaggedMessage(ctx context.Context, id string) (*models.FlaggedMessage, error) {
+	query := `
+		SELECT id, chat_id, user_id, username, text, kind, entities, reason, status, created_at, reviewed_by, reviewed_at,
+			forward_message_id, forward_chat_id, forward_user_id, forward_username
+		FROM moderation_queue
+		WHERE id = ?
+	`
+
+	var message models.FlaggedMessage
+	err := r.db.GetContext(ctx, &message, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrFlaggedMessageNotFound
+		}
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (r *ModerationRepository) GetPendingFlaggedMessages(ctx context.Context, chatID string) ([]*models.FlaggedMessage, error) {
+	query := `
+		SELECT id, chat_id, user_id, username, text, kind, entities, reason, status, created_at, reviewed_by, reviewed_at,
+			forward_message_id, forward_chat_id, forward_user_id, forward_username
+		FROM moderation_queue
+		WHERE chat_id = ? AND status = ?
+		ORDER BY created_at
+	`
+
+	var messages []*models.FlaggedMessage
+	if err := r.db.SelectContext(ctx, &messages, query, chatID, models.FlaggedPending); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *ModerationRepository) ReviewFlaggedMessage(ctx context.Context, id, status, reviewedBy string, reviewedAt time.Time) error {
+	// Решение принимается один раз: повторное одобрение или удаление не найдет ожидающую запись
+	query := `
+		UPDATE moderation_queue
+		SET status = ?, reviewed_by = ?, reviewed_at = ?
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, status, reviewedBy, reviewedAt, id, models.FlaggedPending)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrFlaggedMessageNotFound
+	}
+
+	return nil
+}
+
+func (r *ModerationRepository) CreateRestriction(ctx context.Context, restriction *models.ChatRestriction) error {
+	if restriction.ID == "" {
+		restriction.ID = uuid.New().String()
+	}
+	restriction.CreatedAt = time.Now()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Новое ограничение заменяет действующее ограничение того же вида
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE chat_restrictions SET lifted_by = ?, lifted_at = ? WHERE chat_id = ? AND user_id = ? AND kind = ? AND lifted_at IS NULL`,
+		restriction.CreatedBy,
+		restriction.CreatedAt,
+		restriction.ChatID,
+		restriction.UserID,
+		restriction.Kind,
+	)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_restrictions (id, chat_id, user_id, username, kind, reason, created_by, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		restriction.ID,
+		restriction.ChatID,
+		restriction.UserID,
+		restriction.Username,
+		restriction.Kind,
+		restriction.Reason,
+		restriction.CreatedBy,
+		restriction.CreatedAt,
+		restriction.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ModerationRepository) GetActiveRestrictions(ctx context.Context, chatID, userID string, now time.Time) ([]*models.ChatRestriction, error) {
+	query := `
+		SELECT id, chat_id, user_id, username, kind, reason, created_by, created_at, expires_at, lifted_by, lifted_at
+		FROM chat_restrictions
+		WHERE chat_id = ? AND user_id = ? AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+	`
+
+	var restrictions []*models.ChatRestriction
+	if err := r.db.SelectContext(ctx, &restrictions, query, chatID, userID, now); err != nil {
+		return nil, err
+	}
+
+	return restrictions, nil
+}
+
+func (r *ModerationRepository) GetChatRestrictions(ctx context.Context, chatID string, now time.Time) ([]*models.ChatRestriction, error) {
+	query := `
+		SELECT id, chat_id, user_id, username, kind, reason, created_by, created_at, expires_at, lifted_by, lifted_at
+		FROM chat_restrictions
+		WHERE chat_id = ? AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY created_at
+	`
+
+	var restrictions []*models.ChatRestriction
+	if err := r.db.SelectContext(ctx, &restrictions, query, chatID, now); err != nil {
+		return nil, err
+	}
+
+	return restrictions, nil
+}
+
+func (r *ModerationRepository) LiftRestriction(ctx context.Context, chatID, userID, kind, liftedBy string, liftedAt time.Time) error {
+	query := `
+		UPDATE chat_restrictions
+		SET lifted_by = ?, lifted_at = ?
+		WHERE chat_id = ? AND user_id = ? AND kind = ? AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, liftedBy, liftedAt, chatID, userID, kind, liftedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrRestrictionNotFound
+	}
+
+	return nil
+}
+
+func (r *ModerationRepository) CreateReport(ctx context.Context, report *models.MessageReport) error {
+	if report.ID == "" {
+		report.ID = uuid.New().String()
+	}
+	report.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO message_reports (id, chat_id, message_id, reporter_id, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (message_id, reporter_id) DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query, report.ID, report.ChatID, report.MessageID, report.ReporterID, report.Reason, report.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrAlreadyReported
+	}
+
+	return nil
+}
+
+func (r *ModerationRepository) GetChatReports(ctx context.Context, chatID string, limit int) ([]*models.MessageReport, error) {
+	query := `
+		SELECT
+			r.id, r.chat_id, r.message_id, r.reporter_id, r.reason, r.created_at,
+			m.user_id AS author_id, m.username AS author_username, m.text AS message_text
+		FROM message_reports r
+		JOIN messages m ON m.id = r.message_id
+		WHERE r.chat_id = ?
+		ORDER BY r.created_at DESC
+		LIMIT ?
+	`
+
+	var reports []*models.MessageReport
+	if err := r.db.SelectContext(ctx, &reports, query, chatID, limit); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+func (r *ModerationRepository) AddAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	entry.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO chat_audit_log (id, chat_id, actor_id, actor_username, action, target_user_id, target_username, target_message_id, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		entry.ID,
+		entry.ChatID,
+		entry.ActorID,
+		entry.ActorUsername,
+		entry.Action,
+		entry.TargetUserID,
+		entry.TargetUsername,
+		entry.TargetMessageID,
+		entry.Details,
+		entry.CreatedAt,
+	)
+	return err
+}
+
+func (r *ModerationRepository) GetAuditLog(ctx context.Context, chatID string, limit int) ([]*models.AuditEntry, error) {
+	query := `
+		SELECT id, chat_id, actor_id, actor_username, action, target_user_id, target_username, target_message_id, details, created_at
+		FROM chat_audit_log
+		WHERE chat_id = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+
+	var entries []*models.AuditEntry
+	if err := r.db.SelectContext(ctx, &entries, query, chatID, limit); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,230 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// WebhookRepository реализует интерфейс repository.WebhookRepository
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	if webhook.ID == "" {
+		webhook.ID = uuid.New().String()
+	}
+	webhook.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO chat_webhooks (id, chat_id, url, secret, created_by_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query, webhook.ID, webhook.ChatID, webhook.URL, webhook.Secret, webhook.CreatedByID, webhook.CreatedAt)
+	return err
+}
+
+func (r *WebhookRepository) GetWebhook(ctx context.Context, webhookID string) (*models.Webhook, error) {
+	query := `SELECT id, chat_id, url, secret, created_by_id, created_at FROM chat_webhooks WHERE id = ?`
+
+	var webhook models.Webhook
+	err := r.db.GetContext(ctx, &webhook, query, webhookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) GetChatWebhooks(ctx context.Context, chatID string) ([]*models.Webhook, error) {
+	query := `
+		SELECT id, chat_id, url, secret, created_by_id, created_at
+		FROM chat_webhooks
+		WHERE chat_id = ?
+		ORDER BY created_at
+	`
+
+	var webhooks []*models.Webhook
+	if err := r.db.SelectContext(ctx, &webhooks, query, chatID); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, webhookID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM chat_webhooks WHERE id = ?`, webhookID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.ID == "" {
+		delivery.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, message_id, attempt, status_code, error, success, duration_ms, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		delivery.ID,
+		delivery.WebhookID,
+		delivery.MessageID,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.Success,
+		delivery.DurationMs,
+		delivery.CreatedAt,
+	)
+	return err
+}
+
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID string, limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, message_id, attempt, status_code, error, success, duration_ms, created_at
+		FROM webhook_deliveries
+		WHERE webhook_id = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+
+	var deliveries []*models.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, webhookID, limit); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// IncomingWebhookRepository реализует интерфейс repository.IncomingWebhookRepository
+type IncomingWebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewIncomingWebhookRepository(db *sqlx.DB) *IncomingWebhookRepository {
+	return &IncomingWebhookRepository{db: db}
+}
+
+func (r *IncomingWebhookRepository) CreateIncomingWebhook(ctx context.Context, webhook *models.IncomingWebhook) error {
+	if webhook.ID == "" {
+		webhook.ID = uuid.New().String()
+	}
+	webhook.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO incoming_webhooks (id, chat_id, name, token_hash, created_by_id, bot_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query, webhook.ID, webhook.ChatID, webhook.Name, webhook.TokenHash, webhook.CreatedByID, webhook.BotID, webhook.CreatedAt)
+	return err
+}
+
+func (r *IncomingWebhookRepository) GetIncomingWebhook(ctx context.Context, webhookID string) (*models.IncomingWebhook, error) {
+	query := `
+		SELECT id, chat_id, name, token_hash, created_by_id, COALESCE(bot_id, '') AS bot_id, created_at, revoked_at
+		FROM incoming_webhooks
+		WHERE id = ?
+	`
+
+	return r.get(ctx, query, webhookID)
+}
+
+func (r *IncomingWebhookRepository) GetIncomingWebhookByTokenHash(ctx context.Context, tokenHash string) (*models.IncomingWebhook, error) {
+	query := `
+		SELECT id, chat_id, name, token_hash, created_by_id, COALESCE(bot_id, '') AS bot_id, created_at, revoked_at
+		FROM incoming_webhooks
+		WHERE token_hash = ? AND revoked_at IS NULL
+	`
+
+	return r.get(ctx, query, tokenHash)
+}
+
+func (r *IncomingWebhookRepository) get(ctx context.Context, query string, arg string) (*models.IncomingWebhook, error) {
+	var webhook models.IncomingWebhook
+	err := r.db.GetContext(ctx, &webhook, query, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *IncomingWebhookRepository) GetChatIncomingWebhooks(ctx context.Context, chatID string) ([]*models.IncomingWebhook, error) {
+	query := `
+		SELECT id, chat_id, name, token_hash, created_by_id, COALESCE(bot_id, '') AS bot_id, created_at, revoked_at
+		FROM incoming_webhooks
+		WHERE chat_id = ? AND revoked_at IS NULL
+		ORDER BY created_at
+	`
+
+	var webhooks []*models.IncomingWebhook
+	if err := r.db.SelectContext(ctx, &webhooks, query, chatID); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *IncomingWebhookRepository) IsIncomingWebhookBot(ctx context.Context, chatID, botID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM incoming_webhooks WHERE chat_id = ? AND bot_id = ? AND revoked_at IS NULL)`
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, query, chatID, botID); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *IncomingWebhookRepository) RevokeIncomingWebhook(ctx context.Context, webhookID string, revokedAt time.Time) error {
+	query := `UPDATE incoming_webhooks SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, revokedAt, webhookID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrWebhookNotFound
+	}
+
+	return nil
+}
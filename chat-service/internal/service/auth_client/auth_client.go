@@ -8,18 +8,23 @@ import (
 	authpb "auth.service/api/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 var (
-	ErrUserNotFound = errors.New("пользователь не найден")
-	ErrInvalidToken = errors.New("недействительный токен")
+	ErrUserNotFound  = errors.New("пользователь не найден")
+	ErrInvalidToken  = errors.New("недействительный токен")
+	ErrBotNotCreated = errors.New("не удалось создать бот-аккаунт")
 )
 
 // AuthClient предоставляет методы для взаимодействия с сервисом аутентификации
 type AuthClient struct {
 	accessClient authpb.AccessServiceClient
 	userClient   authpb.UserServiceClient
+	botClient    authpb.BotServiceClient
 	conn         *grpc.ClientConn
+	keys         *keySet        // Публичные ключи для локальной проверки токенов
+	revocations  *revocationSet // Отозванные токены, которые отклоняются при локальной проверке
 }
 
 // NewAuthClient создает новый клиент для сервиса аутентификации
@@ -33,11 +38,15 @@ func NewAuthClient(authServiceAddr string) (*AuthClient, error) {
 	// Создаем клиенты для сервисов аутентификации
 	accessClient := authpb.NewAccessServiceClient(conn)
 	userClient := authpb.NewUserServiceClient(conn)
+	botClient := authpb.NewBotServiceClient(conn)
 
 	return &AuthClient{
 		accessClient: accessClient,
 		userClient:   userClient,
+		botClient:    botClient,
 		conn:         conn,
+		keys:         newKeySet(),
+		revocations:  newRevocationSet(),
 	}, nil
 }
 
@@ -46,8 +55,14 @@ func (c *AuthClient) Close() error {
 	return c.conn.Close()
 }
 
-// ValidateToken проверяет токен доступа и возвращает ID пользователя
+// ValidateToken проверяет токен доступа и возвращает ID пользователя.
+// Токены, подписанные известным публичным ключом, проверяются локально без обращения к сервису
+// аутентификации; API-ключи ботов и токены с неизвестным ключом проверяются через Check
 func (c *AuthClient) ValidateToken(ctx context.Context, token string) (string, error) {
+	if key, ok := c.lookupKey(ctx, token); ok {
+		return c.verifyToken(token, key)
+	}
+
 	// Вызываем метод проверки токена
 	resp, err := c.accessClient.Check(ctx, &authpb.CheckAccessRequest{
 		AccessToken: token,
@@ -78,3 +93,77 @@ func (c *AuthClient) GetUserByID(ctx context.Context, userID string) (string, er
 
 	return resp.Username, nil
 }
+
+// GetUserIDByUsername возвращает ID пользователя по имени
+func (c *AuthClient) GetUserIDByUsername(ctx context.Context, username string) (string, error) {
+	resp, err := c.userClient.GetUserByUsername(ctx, &authpb.GetUserByUsernameRequest{
+		Username: username,
+	})
+	if err != nil {
+		log.Printf("Ошибка при получении пользователя по имени: %v", err)
+		return "", ErrUserNotFound
+	}
+
+	return resp.UserId, nil
+}
+
+// GetUsernames возвращает имена пользователей по их ID одним запросом.
+// Неизвестные пользователи в результат не попадают
+func (c *AuthClient) GetUsernames(ctx context.Context, userIDs []string) (map[string]string, error) {
+	usernames := make(map[string]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return usernames, nil
+	}
+
+	resp, err := c.userClient.GetUsers(ctx, &authpb.GetUsersRequest{
+		UserIds: userIDs,
+	})
+	if err != nil {
+		log.Printf("Ошибка при получении пользователей: %v", err)
+		return nil, err
+	}
+
+	for _, user := range resp.Users {
+		usernames[user.UserId] = user.Username
+	}
+
+	return usernames, nil
+}
+
+// IsBot проверяет, является ли пользователь бот-аккаунтом
+func (c *AuthClient) IsBot(ctx context.Context, userID string) (bool, error) {
+	resp, err := c.userClient.GetUser(ctx, &authpb.GetUserRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		log.Printf("Ошибка при получении пользователя: %v", err)
+		return false, ErrUserNotFound
+	}
+
+	return resp.IsBot, nil
+}
+
+// CreateBot создает бот-аккаунт, владельцем которого становится автор запроса: его токен доступа
+// из входящих метаданных передается сервису аутентификации. API-ключ бота сразу отзывается,
+// поэтому писать от имени бота может только сам чат-сервис. Возвращает ID бота
+func (c *AuthClient) CreateBot(ctx context.Context, username string) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("authorization")) == 0 {
+		return "", ErrInvalidToken
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", md.Get("authorization")[0])
+
+	resp, err := c.botClient.CreateBot(ctx, &authpb.CreateBotRequest{
+		Username: username,
+	})
+	if err != nil {
+		log.Printf("Ошибка при создании бот-аккаунта %s: %v", username, err)
+		return "", ErrBotNotCreated
+	}
+
+	if _, err := c.botClient.RevokeBotAPIKey(ctx, &authpb.RevokeBotAPIKeyRequest{ApiKeyId: resp.ApiKeyId}); err != nil {
+		log.Printf("Не удалось отозвать API-ключ бота %s: %v", resp.UserId, err)
+	}
+
+	return resp.UserId, nil
+}
@@ -0,0 +1,280 @@
+package auth_client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	authpb "auth.service/api/proto"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// stubAccessClient отдает заданные ключи и отозванные токены и считает обращения к сервису
+type stubAccessClient struct {
+	authpb.AccessServiceClient
+
+	mu          sync.Mutex
+	keys        []*authpb.JSONWebKey
+	revoked     []*authpb.RevokedToken
+	checkUserID string // Пользователь, которого возвращает Check, пусто - токен недействителен
+	keyRequests int
+	checks      int
+}
+
+func (s *stubAccessClient) GetPublicKeys(ctx context.Context, in *authpb.GetPublicKeysRequest, opts ...grpc.CallOption) (*authpb.GetPublicKeysResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keyRequests++
+	return &authpb.GetPublicKeysResponse{Keys: s.keys}, nil
+}
+
+func (s *stubAccessClient) Check(ctx context.Context, in *authpb.CheckAccessRequest, opts ...grpc.CallOption) (*authpb.CheckAccessResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checks++
+	return &authpb.CheckAccessResponse{IsValid: s.checkUserID != "", UserId: s.checkUserID}, nil
+}
+
+func (s *stubAccessClient) ListRevokedTokens(ctx context.Context, in *authpb.ListRevokedTokensRequest, opts ...grpc.CallOption) (*authpb.ListRevokedTokensResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &authpb.ListRevokedTokensResponse{Tokens: s.revoked, ServerTime: timestamppb.Now()}, nil
+}
+
+func (s *stubAccessClient) publish(keys ...*testKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = nil
+	for _, key := range keys {
+		s.keys = append(s.keys, &authpb.JSONWebKey{
+			Kid: key.id,
+			Kty: "OKP",
+			Alg: "EdDSA",
+			Use: "sig",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key.private.Public().(ed25519.PublicKey)),
+		})
+	}
+}
+
+func (s *stubAccessClient) counts() (keyRequests, checks int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.keyRequests, s.checks
+}
+
+// testKey ключ подписи сервиса аутентификации
+type testKey struct {
+	id      string
+	private ed25519.PrivateKey
+}
+
+func newTestKey(t *testing.T, id string) *testKey {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testKey{id: id, private: private}
+}
+
+// sign выпускает токен доступа так же, как сервис аутентификации
+func (k *testKey) sign(t *testing.T, userID, jti string, expiresAt time.Time) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, &tokenClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   userID,
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	if k.id != "" {
+		token.Header["kid"] = k.id
+	}
+
+	signed, err := token.SignedString(k.private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func newTestClient(stub *stubAccessClient) *AuthClient {
+	return &AuthClient{
+		accessClient: stub,
+		keys:         newKeySet(),
+		revocations:  newRevocationSet(),
+	}
+}
+
+func TestValidateTokenLocally(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	stub := &stubAccessClient{}
+	stub.publish(key)
+	client := newTestClient(stub)
+
+	if err := client.RefreshKeys(context.Background()); err != nil {
+		t.Fatalf("RefreshKeys: %v", err)
+	}
+
+	userID, err := client.ValidateToken(context.Background(), key.sign(t, "alice", "jti-1", time.Now().Add(time.Minute)))
+	if err != nil || userID != "alice" {
+		t.Fatalf("ValidateToken = %q, %v, want alice", userID, err)
+	}
+
+	if keyRequests, checks := stub.counts(); keyRequests != 1 || checks != 0 {
+		t.Fatalf("a known key must be checked locally, got %d key requests and %d checks", keyRequests, checks)
+	}
+}
+
+func TestValidateTokenRotatedKey(t *testing.T) {
+	oldKey, newKey := newTestKey(t, "key-1"), newTestKey(t, "key-2")
+	stub := &stubAccessClient{}
+	stub.publish(oldKey)
+	client := newTestClient(stub)
+
+	if err := client.RefreshKeys(context.Background()); err != nil {
+		t.Fatalf("RefreshKeys: %v", err)
+	}
+
+	// Сервис аутентификации сменил ключ, старый еще действует в течение переходного периода
+	stub.publish(oldKey, newKey)
+
+	for _, key := range []*testKey{newKey, oldKey} {
+		userID, err := client.ValidateToken(context.Background(), key.sign(t, "alice", "", time.Now().Add(time.Minute)))
+		if err != nil || userID != "alice" {
+			t.Fatalf("token of %s: ValidateToken = %q, %v, want alice", key.id, userID, err)
+		}
+	}
+
+	if keyRequests, checks := stub.counts(); keyRequests != 2 || checks != 0 {
+		t.Fatalf("the new key must be fetched once, got %d key requests and %d checks", keyRequests, checks)
+	}
+}
+
+func TestValidateTokenUnknownKeyThrottlesRefresh(t *testing.T) {
+	known, unknown := newTestKey(t, "key-1"), newTestKey(t, "forged")
+	stub := &stubAccessClient{}
+	stub.publish(known)
+	client := newTestClient(stub)
+	token := unknown.sign(t, "alice", "", time.Now().Add(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ValidateToken(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("token of an unknown key: got %v, want ErrInvalidToken", err)
+		}
+	}
+
+	// Неизвестный ключ запрашивается один раз, дальше токены проверяются через Check
+	if keyRequests, checks := stub.counts(); keyRequests != 1 || checks != 3 {
+		t.Fatalf("got %d key requests and %d checks, want 1 and 3", keyRequests, checks)
+	}
+
+	client.keys.mu.Lock()
+	client.keys.lastAttempt = time.Now().Add(-minKeyRefreshInterval)
+	client.keys.mu.Unlock()
+
+	if _, err := client.ValidateToken(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("token of an unknown key: got %v, want ErrInvalidToken", err)
+	}
+	if keyRequests, _ := stub.counts(); keyRequests != 2 {
+		t.Fatalf("keys must be fetched again after %s, got %d key requests", minKeyRefreshInterval, keyRequests)
+	}
+}
+
+func TestValidateTokenExpired(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	stub := &stubAccessClient{checkUserID: "alice"}
+	stub.publish(key)
+	client := newTestClient(stub)
+
+	if _, err := client.ValidateToken(context.Background(), key.sign(t, "alice", "", time.Now().Add(-time.Minute))); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expired token: got %v, want ErrInvalidToken", err)
+	}
+
+	if _, checks := stub.counts(); checks != 0 {
+		t.Fatalf("an expired token signed with a known key must not fall back to Check, got %d checks", checks)
+	}
+}
+
+func TestValidateTokenWithoutKeyID(t *testing.T) {
+	stub := &stubAccessClient{checkUserID: "bot-1"}
+	client := newTestClient(stub)
+
+	// API-ключи ботов и токены без kid проверяет сервис аутентификации
+	for _, token := range []string{"bot-api-key", newTestKey(t, "").sign(t, "bot-1", "", time.Now().Add(time.Minute))} {
+		userID, err := client.ValidateToken(context.Background(), token)
+		if err != nil || userID != "bot-1" {
+			t.Fatalf("ValidateToken(%q) = %q, %v, want bot-1 from Check", token, userID, err)
+		}
+	}
+
+	if keyRequests, checks := stub.counts(); keyRequests != 0 || checks != 2 {
+		t.Fatalf("got %d key requests and %d checks, want 0 and 2", keyRequests, checks)
+	}
+}
+
+func TestValidateTokenRevoked(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	stub := &stubAccessClient{}
+	stub.publish(key)
+	client := newTestClient(stub)
+
+	expiresAt := time.Now().Add(time.Minute)
+	revoked := key.sign(t, "alice", "jti-revoked", expiresAt)
+	active := key.sign(t, "alice", "jti-active", expiresAt)
+
+	if _, err := client.ValidateToken(context.Background(), revoked); err != nil {
+		t.Fatalf("token before it is revoked: %v", err)
+	}
+
+	stub.revoked = []*authpb.RevokedToken{{Jti: "jti-revoked", ExpiresAt: timestamppb.New(expiresAt)}}
+	if err := client.SyncRevocations(context.Background()); err != nil {
+		t.Fatalf("SyncRevocations: %v", err)
+	}
+
+	if _, err := client.ValidateToken(context.Background(), revoked); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("revoked token: got %v, want ErrInvalidToken", err)
+	}
+	if userID, err := client.ValidateToken(context.Background(), active); err != nil || userID != "alice" {
+		t.Fatalf("other token of the user: ValidateToken = %q, %v, want alice", userID, err)
+	}
+}
+
+func TestRevocationSetDropsExpiredTokens(t *testing.T) {
+	now := time.Now()
+	set := newRevocationSet()
+
+	set.update([]*authpb.RevokedToken{
+		{Jti: "expired", ExpiresAt: timestamppb.New(now.Add(-time.Second))},
+		{Jti: "active", ExpiresAt: timestamppb.New(now.Add(time.Minute))},
+	}, now)
+
+	if set.isRevoked("expired") {
+		t.Error("an expired token needs no revocation entry")
+	}
+	if !set.isRevoked("active") {
+		t.Error("an unexpired revoked token must stay revoked")
+	}
+	if since := set.since(); !since.Equal(now.Add(-revocationOverlap)) {
+		t.Errorf("since = %s, want the last sync minus the overlap", since)
+	}
+}
@@ -0,0 +1,215 @@
+package auth_client
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	authpb "auth.service/api/proto"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// tokenIssuer издатель токенов доступа сервиса аутентификации
+	tokenIssuer = "auth.service"
+	// minKeyRefreshInterval минимальный интервал между внеплановыми обновлениями ключей,
+	// чтобы токены с неизвестным ID ключа не приводили к запросу ключей на каждый вызов
+	minKeyRefreshInterval = 30 * time.Second
+)
+
+// tokenClaims утверждения токена доступа, выпущенного сервисом аутентификации
+type tokenClaims struct {
+	UserID string `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// verificationKey публичный ключ и алгоритм подписи, которым он проверяется
+type verificationKey struct {
+	algorithm string
+	key       crypto.PublicKey
+}
+
+// keySet кеш публичных ключей сервиса аутентификации по ID ключа.
+// При недоступности сервиса аутентификации продолжают использоваться ранее полученные ключи
+type keySet struct {
+	mu          sync.RWMutex
+	keys        map[string]verificationKey
+	lastAttempt time.Time
+}
+
+func newKeySet() *keySet {
+	return &keySet{keys: make(map[string]verificationKey)}
+}
+
+func (s *keySet) get(keyID string) (verificationKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[keyID]
+	return key, ok
+}
+
+func (s *keySet) replace(keys map[string]verificationKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = keys
+}
+
+// allowRefresh отмечает попытку внепланового обновления, если с предыдущей прошло достаточно времени
+func (s *keySet) allowRefresh(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastAttempt) < minKeyRefreshInterval {
+		return false
+	}
+	s.lastAttempt = now
+
+	return true
+}
+
+// RefreshKeys загружает публичные ключи сервиса аутентификации.
+// При ошибке кеш не изменяется
+func (c *AuthClient) RefreshKeys(ctx context.Context) error {
+	resp, err := c.accessClient.GetPublicKeys(ctx, &authpb.GetPublicKeysRequest{})
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]verificationKey, len(resp.Keys))
+	for _, jwk := range resp.Keys {
+		key, err := parseJWK(jwk)
+		if err != nil {
+			log.Printf("Пропускаем ключ %s: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	c.keys.replace(keys)
+
+	return nil
+}
+
+// RunKeyRefresher периодически обновляет публичные ключи до отмены контекста
+func (c *AuthClient) RunKeyRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.RefreshKeys(ctx); err != nil {
+			log.Printf("Не удалось обновить ключи проверки токенов: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lookupKey возвращает ключ проверки токена. Если ключ с ID из заголовка токена неизвестен,
+// ключи обновляются не чаще minKeyRefreshInterval
+func (c *AuthClient) lookupKey(ctx context.Context, token string) (verificationKey, bool) {
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &tokenClaims{})
+	if err != nil {
+		return verificationKey{}, false
+	}
+
+	keyID, _ := parsed.Header["kid"].(string)
+	if keyID == "" {
+		return verificationKey{}, false
+	}
+
+	if key, ok := c.keys.get(keyID); ok {
+		return key, true
+	}
+
+	if !c.keys.allowRefresh(time.Now()) {
+		return verificationKey{}, false
+	}
+
+	if err := c.RefreshKeys(ctx); err != nil {
+		log.Printf("Не удалось обновить ключи проверки токенов: %v", err)
+		return verificationKey{}, false
+	}
+
+	return c.keys.get(keyID)
+}
+
+// verifyToken проверяет подпись, издателя, срок действия и отзыв токена и возвращает ID пользователя
+func (c *AuthClient) verifyToken(token string, key verificationKey) (string, error) {
+	claims := &tokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims,
+		func(*jwt.Token) (interface{}, error) {
+			return key.key, nil
+		},
+		jwt.WithValidMethods([]string{key.algorithm}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.UserID == "" {
+		return "", ErrInvalidToken
+	}
+
+	if claims.ID != "" && c.revocations.isRevoked(claims.ID) {
+		return "", ErrInvalidToken
+	}
+
+	return claims.UserID, nil
+}
+
+// parseJWK преобразует ключ в формате JWK в публичный ключ для проверки подписи
+func parseJWK(jwk *authpb.JSONWebKey) (verificationKey, error) {
+	switch jwk.Kty {
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return verificationKey{}, fmt.Errorf("неподдерживаемая кривая %q", jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return verificationKey{}, fmt.Errorf("некорректный ключ Ed25519")
+		}
+
+		return verificationKey{algorithm: algorithmOrDefault(jwk.Alg, "EdDSA"), key: ed25519.PublicKey(x)}, nil
+
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil || len(n) == 0 {
+			return verificationKey{}, fmt.Errorf("некорректный модуль RSA")
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return verificationKey{}, fmt.Errorf("некорректная экспонента RSA")
+		}
+
+		key := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+
+		return verificationKey{algorithm: algorithmOrDefault(jwk.Alg, "RS256"), key: key}, nil
+
+	default:
+		return verificationKey{}, fmt.Errorf("неподдерживаемый тип ключа %q", jwk.Kty)
+	}
+}
+
+func algorithmOrDefault(algorithm, defaultAlgorithm string) string {
+	if algorithm == "" {
+		return defaultAlgorithm
+	}
+
+	return algorithm
+}
@@ -0,0 +1,100 @@
+package auth_client
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	authpb "auth.service/api/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// revocationOverlap запас при запросе отозванных токенов, чтобы не пропустить токены,
+// отозванные одновременно с предыдущим запросом
+const revocationOverlap = time.Minute
+
+// revocationSet кеш ID отозванных токенов доступа (jti) со сроками их действия.
+// Записи удаляются после истечения срока действия токена, так как такие токены отклоняются и без них
+type revocationSet struct {
+	mu       sync.RWMutex
+	tokens   map[string]time.Time
+	lastSync time.Time // Время сервиса аутентификации при последней синхронизации
+}
+
+func newRevocationSet() *revocationSet {
+	return &revocationSet{tokens: make(map[string]time.Time)}
+}
+
+func (s *revocationSet) isRevoked(jti string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.tokens[jti]
+	return ok
+}
+
+func (s *revocationSet) since() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.lastSync.IsZero() {
+		return time.Time{}
+	}
+
+	return s.lastSync.Add(-revocationOverlap)
+}
+
+func (s *revocationSet) update(tokens []*authpb.RevokedToken, serverTime time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range tokens {
+		s.tokens[token.Jti] = token.ExpiresAt.AsTime()
+	}
+
+	for jti, expiresAt := range s.tokens {
+		if !expiresAt.After(serverTime) {
+			delete(s.tokens, jti)
+		}
+	}
+
+	s.lastSync = serverTime
+}
+
+// SyncRevocations загружает токены доступа, отозванные после предыдущей синхронизации.
+// При ошибке кеш не изменяется
+func (c *AuthClient) SyncRevocations(ctx context.Context) error {
+	req := &authpb.ListRevokedTokensRequest{}
+	if since := c.revocations.since(); !since.IsZero() {
+		req.Since = timestamppb.New(since)
+	}
+
+	resp, err := c.accessClient.ListRevokedTokens(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	c.revocations.update(resp.Tokens, resp.ServerTime.AsTime())
+
+	return nil
+}
+
+// RunRevocationSync периодически синхронизирует отозванные токены до отмены контекста.
+// Токен, отозванный после последней синхронизации, принимается до следующей синхронизации
+func (c *AuthClient) RunRevocationSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.SyncRevocations(ctx); err != nil {
+			log.Printf("Не удалось обновить список отозванных токенов: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
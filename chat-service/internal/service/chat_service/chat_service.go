@@ -4,58 +4,138 @@ import (
 	"context"
 	"errors"
 	"log"
-	"time"
+	"strings"
+	"unicode/utf8"
 
 	"chat.service/internal/models"
 	"chat.service/internal/repository"
+	"chat.service/internal/service/markdown"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var (
-	ErrChatNotFound   = errors.New("чат не найден")
-	ErrUserNotInChat  = errors.New("пользователь не является участником чата")
-	ErrUserNotFound   = errors.New("пользователь не найден")
-	ErrInvalidChatID  = errors.New("некорректный ID чата")
-	ErrInvalidUserID  = errors.New("некорректный ID пользователя")
-	ErrInvalidMessage = errors.New("некорректное сообщение")
-	ErrSubscription   = errors.New("ошибка подписки на обновления чата")
+	ErrChatNotFound    = errors.New("чат не найден")
+	ErrUserNotInChat   = errors.New("пользователь не является участником чата")
+	ErrUserNotFound    = errors.New("пользователь не найден")
+	ErrInvalidChatID   = errors.New("некорректный ID чата")
+	ErrInvalidUserID   = errors.New("некорректный ID пользователя")
+	ErrInvalidMessage  = errors.New("некорректное сообщение")
+	ErrSubscription    = errors.New("ошибка подписки на обновления чата")
+	ErrNotChatAdmin    = errors.New("действие доступно только администраторам чата")
+	ErrInvalidChat     = errors.New("некорректные параметры чата")
+	ErrChatNotPublic   = errors.New("чат не является публичным")
+	ErrChannelReadOnly = errors.New("в канале могут писать только администраторы")
+	ErrMessageTooLong  = errors.New("сообщение слишком длинное")
+	ErrInvalidMarkup   = errors.New("некорректная разметка сообщения")
 )
 
+// defaultSearchLimit количество чатов в результатах поиска по умолчанию
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// maxMessageLen максимальная длина текста сообщения в символах
+const maxMessageLen = 4000
+
 // ChatService предоставляет методы для работы с чатами
 type ChatService struct {
-	chatRepo    repository.ChatRepository
-	messageRepo repository.MessageRepository
-	authClient  AuthClient           // Клиент для взаимодействия с сервисом аутентификации
-	subManager  *SubscriptionManager // Менеджер подписок для real-time обновлений
+	chatRepo       repository.ChatRepository
+	messageRepo    repository.MessageRepository
+	inviteRepo     repository.InviteRepository
+	scheduleRepo   repository.ScheduledMessageRepository
+	pollRepo       repository.PollRepository
+	webhookRepo    repository.WebhookRepository
+	webhooks       WebhookNotifier // Доставка новых сообщений на исходящие вебхуки
+	incomingRepo   repository.IncomingWebhookRepository
+	commandRepo    repository.CommandRepository
+	previewRepo    repository.LinkPreviewRepository
+	unfurler       UnfurlFetcher // Загрузка превью ссылок из сообщений
+	unfurlSlots    chan struct{} // Ограничение числа одновременных загрузок превью
+	moderationRepo repository.ModerationRepository
+	filters        []ModerationFilter   // Фильтры модерации сообщений пользователей
+	authClient     AuthClient           // Клиент для взаимодействия с сервисом аутентификации
+	subManager     *SubscriptionManager // Менеджер подписок для real-time обновлений
+	invocations    *commandInvocations  // Вызовы команд ботов, ожидающие ответа
 }
 
 // AuthClient определяет интерфейс для взаимодействия с сервисом аутентификации
 type AuthClient interface {
 	// GetUserByID возвращает информацию о пользователе по ID
 	GetUserByID(ctx context.Context, userID string) (string, error)
+	// GetUsernames возвращает имена пользователей по их ID одним запросом
+	GetUsernames(ctx context.Context, userIDs []string) (map[string]string, error)
 	// ValidateToken проверяет токен доступа и возвращает ID пользователя
 	ValidateToken(ctx context.Context, token string) (string, error)
+	// GetUserIDByUsername возвращает ID пользователя по имени
+	GetUserIDByUsername(ctx context.Context, username string) (string, error)
+	// IsBot проверяет, является ли пользователь бот-аккаунтом
+	IsBot(ctx context.Context, userID string) (bool, error)
+	// CreateBot создает бот-аккаунт от имени автора запроса и возвращает его ID
+	CreateBot(ctx context.Context, username string) (string, error)
 }
 
 // NewChatService создает новый экземпляр сервиса чатов
-func NewChatService(chatRepo repository.ChatRepository, messageRepo repository.MessageRepository, authClient AuthClient) *ChatService {
+func NewChatService(
+	chatRepo repository.ChatRepository,
+	messageRepo repository.MessageRepository,
+	inviteRepo repository.InviteRepository,
+	scheduleRepo repository.ScheduledMessageRepository,
+	pollRepo repository.PollRepository,
+	webhookRepo repository.WebhookRepository,
+	webhooks WebhookNotifier,
+	incomingRepo repository.IncomingWebhookRepository,
+	commandRepo repository.CommandRepository,
+	previewRepo repository.LinkPreviewRepository,
+	unfurler UnfurlFetcher,
+	moderationRepo repository.ModerationRepository,
+	filters []ModerationFilter,
+	authClient AuthClient,
+) *ChatService {
 	return &ChatService{
-		chatRepo:    chatRepo,
-		messageRepo: messageRepo,
-		authClient:  authClient,
-		subManager:  NewSubscriptionManager(),
+		chatRepo:       chatRepo,
+		messageRepo:    messageRepo,
+		inviteRepo:     inviteRepo,
+		scheduleRepo:   scheduleRepo,
+		pollRepo:       pollRepo,
+		webhookRepo:    webhookRepo,
+		webhooks:       webhooks,
+		incomingRepo:   incomingRepo,
+		commandRepo:    commandRepo,
+		previewRepo:    previewRepo,
+		unfurler:       unfurler,
+		unfurlSlots:    make(chan struct{}, maxConcurrentUnfurls),
+		moderationRepo: moderationRepo,
+		filters:        filters,
+		authClient:     authClient,
+		subManager:     NewSubscriptionManager(),
+		invocations:    newCommandInvocations(),
 	}
 }
 
 // CreateChat создает новый чат и добавляет в него создателя и указанных участников
-func (s *ChatService) CreateChat(ctx context.Context, name string, creatorID string, participantIDs []string) (string, error) {
+func (s *ChatService) CreateChat(ctx context.Context, name string, creatorID string, participantIDs []string, visibility, kind string) (string, error) {
 	if creatorID == "" {
 		return "", ErrInvalidUserID
 	}
 
+	if visibility == "" {
+		visibility = models.VisibilityPrivate
+	}
+	if kind == "" {
+		kind = models.ChatKindGroup
+	}
+
+	// Публичный чат должен иметь название, чтобы его можно было найти
+	if visibility == models.VisibilityPublic && strings.TrimSpace(name) == "" {
+		return "", ErrInvalidChat
+	}
+
 	// Создаем чат
 	chat := &models.Chat{
 		Name:        name,
+		Visibility:  visibility,
+		Kind:        kind,
 		CreatedByID: creatorID,
 	}
 
@@ -64,8 +144,8 @@ func (s *ChatService) CreateChat(ctx context.Context, name string, creatorID str
 		return "", err
 	}
 
-	// Добавляем создателя как участника
-	err = s.chatRepo.AddParticipant(ctx, chatID, creatorID)
+	// Добавляем создателя как администратора чата
+	err = s.chatRepo.AddParticipant(ctx, chatID, creatorID, models.RoleAdmin)
 	if err != nil {
 		return "", err
 	}
@@ -80,88 +160,305 @@ func (s *ChatService) CreateChat(ctx context.Context, name string, creatorID str
 		}
 
 		// Добавляем пользователя в чат
-		_ = s.chatRepo.AddParticipant(ctx, chatID, userID)
+		if err := s.chatRepo.AddParticipant(ctx, chatID, userID, models.RoleMember); err == nil {
+			s.notifyInvited(ctx, chatID, userID, creatorID)
+		}
 	}
 
 	return chatID, nil
 }
 
-// SendMessage отправляет сообщение в чат
-func (s *ChatService) SendMessage(ctx context.Context, chatID, userID, text string) (string, time.Time, error) {
+// SendMessage отправляет сообщение в чат.
+// Сообщения, начинающиеся с "/", выполняются как slash-команды, "//" отправляет текст с одним "/".
+// Текст в формате models.MessageFormatMarkdown разбирается в текст без разметки и фрагменты разметки
+func (s *ChatService) SendMessage(ctx context.Context, chatID, userID, text, format string) (*models.Message, error) {
 	if chatID == "" {
 		log.Printf("Ошибка: пустой ID чата")
-		return "", time.Time{}, ErrInvalidChatID
+		return nil, ErrInvalidChatID
 	}
 
 	if userID == "" {
 		log.Printf("Ошибка: пустой ID пользователя")
-		return "", time.Time{}, ErrInvalidUserID
+		return nil, ErrInvalidUserID
 	}
 
-	if text == "" {
-		log.Printf("Ошибка: пустой текст сообщения")
-		return "", time.Time{}, ErrInvalidMessage
+	if text == "" || !utf8.ValidString(text) {
+		log.Printf("Ошибка: пустой или некорректный текст сообщения")
+		return nil, ErrInvalidMessage
 	}
 
-	// Проверяем, что пользователь является участником чата
-	// isParticipant, err := s.chatRepo.CheckUserInChat(ctx, chatID, userID)
-	// if err != nil {
-	// 	log.Printf("Ошибка при проверке участия пользователя в чате: %v", err)
-	// 	return "", time.Time{}, err
-	// }
-
-	// if !isParticipant {
-	// 	log.Printf("Пользователь %s не является участником чата %s", userID, chatID)
-	// 	return "", time.Time{}, ErrUserNotInChat
-	// }
-
-	// Получаем имя пользователя через сервис аутентификации
+	if utf8.RuneCountInString(text) > maxMessageLen {
+		return nil, ErrMessageTooLong
+	}
+
+	name, args, literal, isCommand := parseCommand(text)
+	if isCommand {
+		message, err := s.executeCommand(ctx, &commandCall{
+			chatID: chatID,
+			userID: userID,
+			name:   name,
+			args:   args,
+			text:   text,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return message, nil
+	}
+
+	message, err := newTextMessage(chatID, userID, literal, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sendUserMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// newTextMessage создает текстовое сообщение. Текст в формате models.MessageFormatMarkdown
+// разбирается в текст без разметки и фрагменты разметки
+func newTextMessage(chatID, userID, text, format string) (*models.Message, error) {
+	message := &models.Message{
+		ChatID: chatID,
+		UserID: userID,
+		Text:   text,
+	}
+
+	if format == models.MessageFormatMarkdown {
+		plain, entities, err := markdown.Parse(text)
+		if err != nil {
+			log.Printf("Ошибка разбора разметки сообщения: %v", err)
+			return nil, ErrInvalidMarkup
+		}
+		if strings.TrimSpace(plain) == "" {
+			return nil, ErrInvalidMessage
+		}
+		message.Text = plain
+		message.Entities = entities
+	}
+
+	return message, nil
+}
+
+// deliverMessage проверяет права отправителя, сохраняет сообщение и рассылает его подписчикам чата.
+// Через этот метод проходят все сообщения, независимо от того, как они были отправлены
+func (s *ChatService) deliverMessage(ctx context.Context, message *models.Message) error {
+	chatID, userID := message.ChatID, message.UserID
+
+	// Писать могут только участники чата, в каналах - только администраторы
+	if err := s.checkCanPost(ctx, chatID, userID); err != nil {
+		return err
+	}
+
+	message.Username = s.lookupUsername(ctx, userID)
+
+	return s.publishMessage(ctx, message)
+}
+
+// lookupUsername получает имя пользователя через сервис аутентификации
+func (s *ChatService) lookupUsername(ctx context.Context, userID string) string {
 	username, err := s.authClient.GetUserByID(ctx, userID)
 	if err != nil {
 		log.Printf("Не удалось получить имя пользователя %s: %v, используем ID", userID, err)
 		// Если не удалось получить имя пользователя, используем ID
-		username = userID
+		return userID
 	}
 
-	// Создаем сообщение
-	message := &models.Message{
-		ChatID:   chatID,
-		UserID:   userID,
-		Username: username,
-		Text:     text,
+	return username
+}
+
+// publishMessage сохраняет сообщение и рассылает его подписчикам и на вебхуки чата.
+// Права отправителя и его имя должны быть проверены и заполнены вызывающей стороной
+func (s *ChatService) publishMessage(ctx context.Context, message *models.Message) error {
+	chatID, userID := message.ChatID, message.UserID
+
+	if message.Kind == "" {
+		message.Kind = models.MessageKindText
 	}
 
 	// Сохраняем сообщение
 	messageID, err := s.messageRepo.SaveMessage(ctx, message)
 	if err != nil {
 		log.Printf("Ошибка при сохранении сообщения: %v", err)
-		return "", time.Time{}, err
+		return err
 	}
 
 	// Устанавливаем ID сообщения
 	message.ID = messageID
 
+	// Сохраняем опрос, прикрепленный к сообщению, до рассылки подписчикам
+	if message.Poll != nil {
+		message.Poll.ID = messageID
+		if err := s.pollRepo.CreatePoll(ctx, message.Poll); err != nil {
+			log.Printf("Ошибка при сохранении опроса %s: %v", messageID, err)
+			return err
+		}
+	}
+
 	// Публикуем сообщение для всех подписчиков
 	s.subManager.PublishMessage(chatID, message)
+
+	// Уведомляем участников, подписанных на уведомления по всем чатам
+	s.notifyNewMessage(message)
+
+	// Отправляем сообщение на исходящие вебхуки чата
+	s.webhooks.Notify(ctx, message)
+
+	// Превью ссылок рассылаются отдельным обновлением, когда будут готовы
+	s.unfurlMessage(message)
 	log.Printf("Сообщение %s успешно отправлено в чат %s пользователем %s", messageID, chatID, userID)
 
-	return messageID, message.CreatedAt, nil
+	return nil
+}
+
+// checkCanPost проверяет, может ли пользователь писать в чат: он должен быть участником чата,
+// не иметь блокировки или запрета писать, а в канале - быть администратором
+func (s *ChatService) checkCanPost(ctx context.Context, chatID, userID string) error {
+	// Заблокированные пользователи и пользователи с запретом писать не могут отправлять сообщения
+	if err := s.checkNotRestricted(ctx, chatID, userID); err != nil {
+		return err
+	}
+
+	chat, err := s.getChat(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	role, err := s.chatRepo.GetParticipantRole(ctx, chatID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotInChat) {
+			log.Printf("Пользователь %s не является участником чата %s", userID, chatID)
+			return ErrUserNotInChat
+		}
+		return err
+	}
+
+	if chat.Kind != models.ChatKindChannel || role == models.RoleAdmin {
+		return nil
+	}
+
+	// Входящие вебхуки канала создают его администраторы, поэтому их боты тоже пишут в канал,
+	// оставаясь обычными участниками, которых можно заблокировать
+	isWebhookBot, err := s.incomingRepo.IsIncomingWebhookBot(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+	if !isWebhookBot {
+		log.Printf("Пользователь %s не может писать в канал %s", userID, chatID)
+		return ErrChannelReadOnly
+	}
+
+	return nil
+}
+
+// SearchPublicChats ищет публичные чаты по началу названия
+func (s *ChatService) SearchPublicChats(ctx context.Context, query string, limit int) ([]*models.Chat, error) {
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	return s.chatRepo.SearchPublicChats(ctx, strings.TrimSpace(query), limit)
+}
+
+// JoinChat добавляет пользователя в публичный чат
+func (s *ChatService) JoinChat(ctx context.Context, chatID, userID string) (*models.Chat, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	chat, err := s.getChat(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if chat.Visibility != models.VisibilityPublic {
+		return nil, ErrChatNotPublic
+	}
+
+	if err := s.checkNotBanned(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.chatRepo.AddParticipant(ctx, chatID, userID, models.RoleMember); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Пользователь %s вступил в публичный чат %s", userID, chatID)
+
+	return chat, nil
+}
+
+// getChat возвращает чат по ID, приводя ошибку отсутствия чата к ErrChatNotFound
+func (s *ChatService) getChat(ctx context.Context, chatID string) (*models.Chat, error) {
+	chat, err := s.chatRepo.GetChatByID(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, repository.ErrChatNotFound) {
+			return nil, ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	return chat, nil
 }
 
 // GetChatMessages возвращает сообщения чата
 func (s *ChatService) GetChatMessages(ctx context.Context, chatID, userID string, limit, offset int) ([]*models.Message, error) {
-	// Проверяем, что пользователь является участником чата
-	// isParticipant, err := s.chatRepo.CheckUserInChat(ctx, chatID, userID)
-	// if err != nil {
-	// 	return nil, err
-	// }
+	// Заблокированный пользователь не получает историю чата
+	if err := s.checkNotBanned(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
 
-	// if !isParticipant {
-	// 	return nil, ErrUserNotInChat
-	// }
+	// Историю чата видят только его участники
+	isParticipant, err := s.chatRepo.CheckUserInChat(ctx, chatID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isParticipant {
+		return nil, ErrUserNotInChat
+	}
 
 	// Получаем сообщения
-	return s.messageRepo.GetChatMessages(ctx, chatID, limit, offset)
+	messages, err := s.messageRepo.GetChatMessages(ctx, chatID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Подгружаем актуальные результаты опросов
+	for _, message := range messages {
+		// Текст удаленных сообщений и пересланных копий удаленных сообщений не возвращается
+		if message.IsTombstone() {
+			redactMessage(message)
+			continue
+		}
+
+		if message.Kind != models.MessageKindPoll {
+			continue
+		}
+
+		poll, err := s.pollRepo.GetPoll(ctx, message.ID)
+		if err != nil {
+			log.Printf("Ошибка при получении опроса %s: %v", message.ID, err)
+			continue
+		}
+		message.Poll = poll
+	}
+
+	// Превью ссылок в истории берутся только из кеша
+	s.attachLinkPreviews(ctx, messages)
+
+	return messages, nil
 }
 
 // ConvertMessageToProto конвертирует модель сообщения в protobuf формат
@@ -180,20 +477,25 @@ func ConvertMessageToProto(message *models.Message) *ChatMessage {
 func (s *ChatService) SubscribeToChat(ctx context.Context, chatID, userID string) (<-chan *models.Message, string, error) {
 	log.Printf("Попытка подписки пользователя %s на обновления чата %s", userID, chatID)
 
+	if err := s.checkNotBanned(ctx, chatID, userID); err != nil {
+		log.Printf("Пользователь %s заблокирован в чате %s", userID, chatID)
+		return nil, "", err
+	}
+
 	// Проверяем, что пользователь является участником чата
-	// isParticipant, err := s.chatRepo.CheckUserInChat(ctx, chatID, userID)
-	// if err != nil {
-	// 	log.Printf("Ошибка при проверке участия пользователя %s в чате %s: %v", userID, chatID, err)
-	// 	return nil, "", err
-	// }
+	isParticipant, err := s.chatRepo.CheckUserInChat(ctx, chatID, userID)
+	if err != nil {
+		log.Printf("Ошибка при проверке участия пользователя %s в чате %s: %v", userID, chatID, err)
+		return nil, "", err
+	}
 
-	// if !isParticipant {
-	// 	log.Printf("Пользователь %s не является участником чата %s", userID, chatID)
-	// 	return nil, "", ErrUserNotInChat
-	// }
+	if !isParticipant {
+		log.Printf("Пользователь %s не является участником чата %s", userID, chatID)
+		return nil, "", ErrUserNotInChat
+	}
 
 	// Создаем подписку
-	messageChan, subscriptionID := s.subManager.Subscribe(chatID)
+	messageChan, subscriptionID := s.subManager.Subscribe(chatID, userID)
 	log.Printf("Пользователь %s успешно подписан на обновления чата %s, ID подписки: %s", userID, chatID, subscriptionID)
 
 	return messageChan, subscriptionID, nil
@@ -0,0 +1,564 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Ограничения slash-команд
+const (
+	commandPrefix         = "/"
+	maxCommandDescription = 200
+	maxTopicLen           = 250
+	commandInvocationTTL  = 10 * time.Minute
+	systemMessageUsername = "system"
+)
+
+var (
+	ErrUnknownCommand     = errors.New("неизвестная команда")
+	ErrInvalidCommand     = errors.New("некорректные параметры команды")
+	ErrCommandNotFound    = errors.New("команда не найдена")
+	ErrCommandTaken       = errors.New("команда уже зарегистрирована другим ботом")
+	ErrNotBot             = errors.New("действие доступно только бот-аккаунтам")
+	ErrInvocationNotFound = errors.New("вызов команды не найден или устарел")
+	ErrBotUnavailable     = errors.New("бот, обрабатывающий команду, не подключен к чату")
+)
+
+// commandNamePattern допустимое имя команды бота
+var commandNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// CommandInfo описывает команду, доступную в чате
+type CommandInfo struct {
+	Name        string
+	Usage       string
+	Description string
+	BotID       string // Пустой для встроенных команд
+}
+
+// commandCall представляет разобранный вызов команды
+type commandCall struct {
+	chatID string
+	userID string
+	name   string
+	args   string
+	text   string // Исходный текст сообщения
+}
+
+// builtinCommand описывает встроенную команду сервера
+type builtinCommand struct {
+	info CommandInfo
+	run  func(s *ChatService, ctx context.Context, call *commandCall) (*models.Message, error)
+}
+
+// builtinCommands встроенные команды в порядке вывода в списке команд
+var builtinCommands = []builtinCommand{
+	{
+		info: CommandInfo{Name: "me", Usage: "/me <действие>", Description: "написать сообщение от третьего лица"},
+		run:  (*ChatService).commandMe,
+	},
+	{
+		info: CommandInfo{Name: "topic", Usage: "/topic [тема]", Description: "показать или изменить тему чата"},
+		run:  (*ChatService).commandTopic,
+	},
+	{
+		info: CommandInfo{Name: "invite", Usage: "/invite @user", Description: "добавить пользователя в чат (только администраторы)"},
+		run:  (*ChatService).commandInvite,
+	},
+	{
+		info: CommandInfo{Name: "kick", Usage: "/kick @user", Description: "исключить пользователя из чата (только администраторы)"},
+		run:  (*ChatService).commandKick,
+	},
+	{
+		info: CommandInfo{Name: "leave", Usage: "/leave", Description: "покинуть чат"},
+		run:  (*ChatService).commandLeave,
+	},
+}
+
+// findBuiltinCommand возвращает встроенную команду по имени
+func findBuiltinCommand(name string) (builtinCommand, bool) {
+	for _, command := range builtinCommands {
+		if command.info.Name == name {
+			return command, true
+		}
+	}
+
+	return builtinCommand{}, false
+}
+
+// commandInvocations хранит вызовы команд ботов, ожидающие ответа.
+// Вызовы живут в памяти процесса и устаревают через commandInvocationTTL
+type commandInvocations struct {
+	items map[string]*models.CommandInvocation
+	mutex sync.Mutex
+}
+
+func newCommandInvocations() *commandInvocations {
+	return &commandInvocations{items: make(map[string]*models.CommandInvocation)}
+}
+
+// add сохраняет вызов и удаляет устаревшие
+func (c *commandInvocations) add(invocation *models.CommandInvocation) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for id, item := range c.items {
+		if time.Since(item.CreatedAt) > commandInvocationTTL {
+			delete(c.items, id)
+		}
+	}
+
+	c.items[invocation.ID] = invocation
+}
+
+// get возвращает действующий вызов по ID
+func (c *commandInvocations) get(id string) (*models.CommandInvocation, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	invocation, ok := c.items[id]
+	if !ok || time.Since(invocation.CreatedAt) > commandInvocationTTL {
+		return nil, false
+	}
+
+	return invocation, true
+}
+
+// parseCommand разбирает текст сообщения вида "/name args".
+// Возвращает false, если сообщение не является командой; "//" экранирует команду
+func parseCommand(text string) (name, args string, literal string, isCommand bool) {
+	if strings.HasPrefix(text, commandPrefix+commandPrefix) {
+		return "", "", strings.TrimPrefix(text, commandPrefix), false
+	}
+
+	if !strings.HasPrefix(text, commandPrefix) {
+		return "", "", text, false
+	}
+
+	body := strings.TrimPrefix(text, commandPrefix)
+	name, args, _ = strings.Cut(body, " ")
+
+	return strings.ToLower(name), strings.TrimSpace(args), "", true
+}
+
+// executeCommand выполняет slash-команду: встроенную или зарегистрированную ботом
+func (s *ChatService) executeCommand(ctx context.Context, call *commandCall) (*models.Message, error) {
+	if call.name == "" {
+		return nil, ErrInvalidCommand
+	}
+
+	if command, ok := findBuiltinCommand(call.name); ok {
+		return command.run(s, ctx, call)
+	}
+
+	command, err := s.commandRepo.GetCommand(ctx, call.chatID, call.name)
+	if err != nil {
+		if errors.Is(err, repository.ErrCommandNotFound) {
+			return nil, s.unknownCommandError(ctx, call.chatID, call.name)
+		}
+		return nil, err
+	}
+
+	return s.invokeBotCommand(ctx, call, command)
+}
+
+// unknownCommandError возвращает ошибку неизвестной команды со списком доступных команд чата
+func (s *ChatService) unknownCommandError(ctx context.Context, chatID, name string) error {
+	var names []string
+	for _, command := range builtinCommands {
+		names = append(names, commandPrefix+command.info.Name)
+	}
+
+	registered, err := s.commandRepo.ListCommands(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка при получении списка команд чата %s: %v", chatID, err)
+	}
+	for _, command := range registered {
+		names = append(names, commandPrefix+command.Name)
+	}
+
+	return fmt.Errorf("%w %s%s, доступные команды: %s", ErrUnknownCommand, commandPrefix, name, strings.Join(names, ", "))
+}
+
+// invokeBotCommand передает вызов команды боту, которому она принадлежит.
+// Сообщение с вызовом доставляется только в поток бота и не сохраняется в истории
+func (s *ChatService) invokeBotCommand(ctx context.Context, call *commandCall, command *models.ChatCommand) (*models.Message, error) {
+	if !s.subManager.HasSubscriber(call.chatID, command.BotID) {
+		return nil, ErrBotUnavailable
+	}
+
+	invocation := &models.CommandInvocation{
+		ID:        uuid.New().String(),
+		ChatID:    call.chatID,
+		Name:      call.name,
+		Args:      call.args,
+		UserID:    call.userID,
+		BotID:     command.BotID,
+		CreatedAt: time.Now(),
+	}
+	s.invocations.add(invocation)
+
+	message := &models.Message{
+		ID:          invocation.ID,
+		ChatID:      call.chatID,
+		UserID:      call.userID,
+		Username:    s.lookupUsername(ctx, call.userID),
+		Text:        call.text,
+		Kind:        models.MessageKindText,
+		CreatedAt:   invocation.CreatedAt,
+		Event:       models.MessageEventCommand,
+		RecipientID: command.BotID,
+		Command:     invocation,
+	}
+	s.subManager.PublishMessage(call.chatID, message)
+
+	log.Printf("Пользователь %s вызвал команду /%s бота %s в чате %s", call.userID, call.name, command.BotID, call.chatID)
+
+	return message, nil
+}
+
+// RespondToCommand публикует ответ бота на вызов команды.
+// Эфемерный ответ получает только вызвавший команду пользователь, он не сохраняется в истории.
+// Обычный ответ проходит фильтры модерации так же, как сообщения пользователей
+func (s *ChatService) RespondToCommand(ctx context.Context, invocationID, botID, text string, ephemeral bool) (*models.Message, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrInvalidMessage
+	}
+
+	invocation, ok := s.invocations.get(invocationID)
+	if !ok || invocation.BotID != botID {
+		return nil, ErrInvocationNotFound
+	}
+
+	if ephemeral {
+		message := &models.Message{
+			ID:       uuid.New().String(),
+			ChatID:   invocation.ChatID,
+			UserID:   botID,
+			Username: s.lookupUsername(ctx, botID),
+			Text:     text,
+		}
+		s.publishEphemeral(message, invocation.UserID)
+
+		return message, nil
+	}
+
+	message := &models.Message{
+		ChatID: invocation.ChatID,
+		UserID: botID,
+		Text:   text,
+	}
+
+	if err := s.sendUserMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// RegisterCommand регистрирует команду бота в чате. Доступно только бот-аккаунтам, состоящим в чате
+func (s *ChatService) RegisterCommand(ctx context.Context, chatID, botID, name, description string) (*models.ChatCommand, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), commandPrefix))
+	description = strings.TrimSpace(description)
+	if !commandNamePattern.MatchString(name) || utf8.RuneCountInString(description) > maxCommandDescription {
+		return nil, ErrInvalidCommand
+	}
+
+	if _, ok := findBuiltinCommand(name); ok {
+		return nil, ErrCommandTaken
+	}
+
+	if err := s.requireBotInChat(ctx, chatID, botID); err != nil {
+		return nil, err
+	}
+
+	command := &models.ChatCommand{
+		ChatID:      chatID,
+		Name:        name,
+		Description: description,
+		BotID:       botID,
+	}
+
+	if err := s.commandRepo.RegisterCommand(ctx, command); err != nil {
+		if errors.Is(err, repository.ErrCommandTaken) {
+			return nil, ErrCommandTaken
+		}
+		return nil, err
+	}
+
+	log.Printf("Бот %s зарегистрировал команду /%s в чате %s", botID, name, chatID)
+
+	return command, nil
+}
+
+// UnregisterCommand удаляет команду, зарегистрированную ботом в чате
+func (s *ChatService) UnregisterCommand(ctx context.Context, chatID, botID, name string) error {
+	if chatID == "" {
+		return ErrInvalidChatID
+	}
+
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), commandPrefix))
+
+	if err := s.commandRepo.UnregisterCommand(ctx, chatID, name, botID); err != nil {
+		if errors.Is(err, repository.ErrCommandNotFound) {
+			return ErrCommandNotFound
+		}
+		return err
+	}
+
+	log.Printf("Бот %s удалил команду /%s из чата %s", botID, name, chatID)
+
+	return nil
+}
+
+// ListCommands возвращает встроенные команды и команды ботов, доступные в чате
+func (s *ChatService) ListCommands(ctx context.Context, chatID string) ([]CommandInfo, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	registered, err := s.commandRepo.ListCommands(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make([]CommandInfo, 0, len(builtinCommands)+len(registered))
+	for _, command := range builtinCommands {
+		commands = append(commands, command.info)
+	}
+
+	for _, command := range registered {
+		commands = append(commands, CommandInfo{
+			Name:        command.Name,
+			Usage:       commandPrefix + command.Name,
+			Description: command.Description,
+			BotID:       command.BotID,
+		})
+	}
+
+	return commands, nil
+}
+
+// requireBotInChat проверяет, что пользователь является бот-аккаунтом и участником чата
+func (s *ChatService) requireBotInChat(ctx context.Context, chatID, userID string) error {
+	isBot, err := s.authClient.IsBot(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if !isBot {
+		return ErrNotBot
+	}
+
+	isParticipant, err := s.chatRepo.CheckUserInChat(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+	if !isParticipant {
+		return ErrUserNotInChat
+	}
+
+	return nil
+}
+
+// commandMe публикует сообщение-действие от третьего лица
+func (s *ChatService) commandMe(ctx context.Context, call *commandCall) (*models.Message, error) {
+	if call.args == "" {
+		return nil, ErrInvalidCommand
+	}
+
+	message := &models.Message{
+		ChatID: call.chatID,
+		UserID: call.userID,
+		Text:   call.args,
+		Kind:   models.MessageKindAction,
+	}
+
+	if err := s.sendUserMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// commandTopic показывает тему чата или, если передан аргумент, изменяет ее
+func (s *ChatService) commandTopic(ctx context.Context, call *commandCall) (*models.Message, error) {
+	if call.args == "" {
+		chat, err := s.getChat(ctx, call.chatID)
+		if err != nil {
+			return nil, err
+		}
+
+		text := "Тема чата не задана"
+		if chat.Topic != "" {
+			text = "Тема чата: " + chat.Topic
+		}
+
+		return s.replySystem(call, text), nil
+	}
+
+	if utf8.RuneCountInString(call.args) > maxTopicLen {
+		return nil, ErrInvalidCommand
+	}
+
+	// Тему меняют те же, кто может писать в чат
+	if err := s.checkCanPost(ctx, call.chatID, call.userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.chatRepo.UpdateChatTopic(ctx, call.chatID, call.args); err != nil {
+		if errors.Is(err, repository.ErrChatNotFound) {
+			return nil, ErrChatNotFound
+		}
+		return nil, err
+	}
+
+	username := s.lookupUsername(ctx, call.userID)
+	return s.postSystemMessage(ctx, call, fmt.Sprintf("Тема чата изменена (%s): %s", username, call.args))
+}
+
+// commandInvite добавляет пользователя в чат по имени
+func (s *ChatService) commandInvite(ctx context.Context, call *commandCall) (*models.Message, error) {
+	targetName, targetID, err := s.resolveCommandTarget(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, call.chatID, call.userID); err != nil {
+		return nil, err
+	}
+
+	isParticipant, err := s.chatRepo.CheckUserInChat(ctx, call.chatID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if isParticipant {
+		return s.replySystem(call, targetName+" уже является участником чата"), nil
+	}
+
+	if err := s.checkNotBanned(ctx, call.chatID, targetID); err != nil {
+		return nil, err
+	}
+
+	if err := s.chatRepo.AddParticipant(ctx, call.chatID, targetID, models.RoleMember); err != nil {
+		return nil, err
+	}
+
+	s.notifyInvited(ctx, call.chatID, targetID, call.userID)
+
+	return s.postSystemMessage(ctx, call, targetName+" добавлен в чат")
+}
+
+// commandKick исключает пользователя из чата по имени
+func (s *ChatService) commandKick(ctx context.Context, call *commandCall) (*models.Message, error) {
+	targetName, targetID, err := s.resolveCommandTarget(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID == call.userID {
+		return nil, ErrInvalidCommand
+	}
+
+	if err := s.requireAdmin(ctx, call.chatID, call.userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.chatRepo.RemoveParticipant(ctx, call.chatID, targetID); err != nil {
+		if errors.Is(err, repository.ErrUserNotInChat) {
+			return nil, ErrUserNotInChat
+		}
+		return nil, err
+	}
+
+	return s.postSystemMessage(ctx, call, targetName+" исключен из чата")
+}
+
+// commandLeave удаляет вызвавшего команду пользователя из чата
+func (s *ChatService) commandLeave(ctx context.Context, call *commandCall) (*models.Message, error) {
+	if call.args != "" {
+		return nil, ErrInvalidCommand
+	}
+
+	if err := s.chatRepo.RemoveParticipant(ctx, call.chatID, call.userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotInChat) {
+			return nil, ErrUserNotInChat
+		}
+		return nil, err
+	}
+
+	username := s.lookupUsername(ctx, call.userID)
+	return s.postSystemMessage(ctx, call, username+" покидает чат")
+}
+
+// resolveCommandTarget возвращает имя и ID пользователя, указанного в аргументе команды как @user
+func (s *ChatService) resolveCommandTarget(ctx context.Context, call *commandCall) (string, string, error) {
+	targetName := strings.TrimPrefix(call.args, "@")
+	if targetName == "" || strings.ContainsAny(targetName, " \t") {
+		return "", "", ErrInvalidCommand
+	}
+
+	targetID, err := s.authClient.GetUserIDByUsername(ctx, targetName)
+	if err != nil {
+		return "", "", ErrUserNotFound
+	}
+
+	return targetName, targetID, nil
+}
+
+// postSystemMessage сохраняет и рассылает служебное сообщение о событии, вызванном командой
+func (s *ChatService) postSystemMessage(ctx context.Context, call *commandCall, text string) (*models.Message, error) {
+	message := &models.Message{
+		ChatID:   call.chatID,
+		UserID:   call.userID,
+		Username: systemMessageUsername,
+		Text:     text,
+		Kind:     models.MessageKindSystem,
+	}
+
+	if err := s.publishMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// replySystem отправляет служебный эфемерный ответ только вызвавшему команду пользователю
+func (s *ChatService) replySystem(call *commandCall, text string) *models.Message {
+	message := &models.Message{
+		ID:       uuid.New().String(),
+		ChatID:   call.chatID,
+		Username: systemMessageUsername,
+		Text:     text,
+		Kind:     models.MessageKindSystem,
+	}
+	s.publishEphemeral(message, call.userID)
+
+	return message
+}
+
+// publishEphemeral рассылает сообщение только подпискам получателя без сохранения в истории
+func (s *ChatService) publishEphemeral(message *models.Message, recipientID string) {
+	if message.Kind == "" {
+		message.Kind = models.MessageKindText
+	}
+	message.CreatedAt = time.Now()
+	message.Ephemeral = true
+	message.RecipientID = recipientID
+
+	s.subManager.PublishMessage(message.ChatID, message)
+}
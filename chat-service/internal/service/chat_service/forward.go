@@ -0,0 +1,147 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+)
+
+var (
+	ErrMessageNotFound  = errors.New("сообщение не найдено")
+	ErrNotMessageAuthor = errors.New("удалить сообщение может только автор или администратор чата")
+	ErrCannotForward    = errors.New("это сообщение нельзя переслать")
+)
+
+// ForwardMessage пересылает сообщение в другой чат от имени пользователя.
+// Пользователь должен состоять и в исходном, и в целевом чате. Пересланное сообщение хранит ссылку
+// на исходные сообщение, автора и чат; при пересылке пересланного сообщения сохраняется самый первый источник.
+// Отмеченное фильтрами сообщение возвращается с PendingReview и публикуется после одобрения администратором
+func (s *ChatService) ForwardMessage(ctx context.Context, sourceMessageID, targetChatID, userID string) (*models.Message, error) {
+	if targetChatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	source, err := s.getMessage(ctx, sourceMessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.IsTombstone() {
+		return nil, ErrMessageNotFound
+	}
+
+	// Опросы и служебные сообщения привязаны к своему чату
+	if source.Kind != models.MessageKindText && source.Kind != models.MessageKindAction {
+		return nil, ErrCannotForward
+	}
+
+	for _, chatID := range []string{source.ChatID, targetChatID} {
+		isParticipant, err := s.chatRepo.CheckUserInChat(ctx, chatID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isParticipant {
+			return nil, ErrUserNotInChat
+		}
+	}
+
+	message := &models.Message{
+		ChatID:           targetChatID,
+		UserID:           userID,
+		Text:             source.Text,
+		Kind:             source.Kind,
+		Entities:         source.Entities,
+		ForwardMessageID: source.ID,
+		ForwardChatID:    source.ChatID,
+		ForwardUserID:    source.UserID,
+		ForwardUsername:  source.Username,
+	}
+
+	if source.IsForwarded() {
+		message.ForwardMessageID = source.ForwardMessageID
+		message.ForwardChatID = source.ForwardChatID
+		message.ForwardUserID = source.ForwardUserID
+		message.ForwardUsername = source.ForwardUsername
+	}
+
+	// Пересланный текст проходит те же фильтры модерации, что и написанный в целевом чате
+	if err := s.sendUserMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Пользователь %s переслал сообщение %s в чат %s", userID, source.ID, targetChatID)
+
+	return message, nil
+}
+
+// DeleteMessage удаляет сообщение. Удалить сообщение может его автор или администратор чата.
+// Сообщение остается в истории как отметка об удалении, пересланные копии тоже показываются как удаленные
+func (s *ChatService) DeleteMessage(ctx context.Context, messageID, userID string) error {
+	message, err := s.getMessage(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if message.DeletedAt != nil {
+		return ErrMessageNotFound
+	}
+
+	if message.UserID != userID {
+		if err := s.requireAdmin(ctx, message.ChatID, userID); err != nil {
+			if errors.Is(err, ErrNotChatAdmin) || errors.Is(err, ErrUserNotInChat) {
+				return ErrNotMessageAuthor
+			}
+			return err
+		}
+	}
+
+	now := time.Now()
+	if err := s.messageRepo.DeleteMessage(ctx, messageID, now); err != nil {
+		if errors.Is(err, repository.ErrMessageNotFound) {
+			return ErrMessageNotFound
+		}
+		return err
+	}
+
+	message.DeletedAt = &now
+	message.Event = models.MessageEventDeleted
+	redactMessage(message)
+	s.subManager.PublishMessage(message.ChatID, message)
+
+	log.Printf("Пользователь %s удалил сообщение %s в чате %s", userID, messageID, message.ChatID)
+
+	return nil
+}
+
+// getMessage возвращает сообщение по ID, приводя ошибку отсутствия сообщения к ErrMessageNotFound
+func (s *ChatService) getMessage(ctx context.Context, messageID string) (*models.Message, error) {
+	if messageID == "" {
+		return nil, ErrMessageNotFound
+	}
+
+	message, err := s.messageRepo.GetMessageByID(ctx, messageID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMessageNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// redactMessage убирает содержимое удаленного сообщения, оставляя отметку об удалении
+func redactMessage(message *models.Message) {
+	message.Text = ""
+	message.Entities = nil
+	message.Poll = nil
+	message.Previews = nil
+}
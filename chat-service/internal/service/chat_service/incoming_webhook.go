@@ -0,0 +1,213 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Ограничения входящих вебхуков
+const (
+	defaultIncomingWebhookName = "webhook"
+	maxIncomingWebhookNameLen  = 64
+	incomingWebhookBotPrefix   = "hook-"
+)
+
+// ErrInvalidWebhookUsername означает, что вебхук подписывается служебным именем или именем другого пользователя
+var ErrInvalidWebhookUsername = errors.New("имя отправителя вебхука занято пользователем или служебным именем")
+
+// IncomingWebhookPath возвращает путь HTTP эндпоинта для публикации сообщений по токену
+func IncomingWebhookPath(token string) string {
+	return "/hooks/" + token
+}
+
+// CreateIncomingWebhook создает входящий вебхук чата и возвращает его секретный токен.
+// Токен показывается только один раз, в базе хранится его хеш. Для вебхука создается бот-аккаунт,
+// владельцем которого становится администратор; бот добавляется в чат и пишет в него от имени вебхука.
+// Доступно только администраторам чата
+func (s *ChatService) CreateIncomingWebhook(ctx context.Context, chatID, userID, name string) (*models.IncomingWebhook, string, error) {
+	if chatID == "" {
+		return nil, "", ErrInvalidChatID
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultIncomingWebhookName
+	}
+	if utf8.RuneCountInString(name) > maxIncomingWebhookNameLen {
+		return nil, "", ErrInvalidWebhookUsername
+	}
+
+	if err := s.requireAdmin(ctx, chatID, userID); err != nil {
+		return nil, "", err
+	}
+
+	if err := s.checkWebhookUsername(ctx, name, ""); err != nil {
+		return nil, "", err
+	}
+
+	token, err := generateInviteCode()
+	if err != nil {
+		return nil, "", err
+	}
+
+	webhookID := uuid.New().String()
+	botID, err := s.authClient.CreateBot(ctx, incomingWebhookBotName(webhookID))
+	if err != nil {
+		return nil, "", fmt.Errorf("не удалось создать бот-аккаунт вебхука: %w", err)
+	}
+
+	// Бот становится обычным участником чата: на него действуют блокировка, запрет писать и модерация
+	if err := s.chatRepo.AddParticipant(ctx, chatID, botID, models.RoleMember); err != nil {
+		log.Printf("Ошибка при добавлении бота %s входящего вебхука в чат %s: %v", botID, chatID, err)
+		return nil, "", err
+	}
+
+	webhook := &models.IncomingWebhook{
+		ID:          webhookID,
+		ChatID:      chatID,
+		Name:        name,
+		TokenHash:   hashInviteCode(token),
+		CreatedByID: userID,
+		BotID:       botID,
+	}
+
+	if err := s.incomingRepo.CreateIncomingWebhook(ctx, webhook); err != nil {
+		log.Printf("Ошибка при создании входящего вебхука: %v", err)
+		if err := s.chatRepo.RemoveParticipant(ctx, chatID, botID); err != nil {
+			log.Printf("Ошибка при удалении бота %s из чата %s: %v", botID, chatID, err)
+		}
+		return nil, "", err
+	}
+
+	log.Printf("Пользователь %s создал входящий вебхук %s для чата %s", userID, webhook.ID, chatID)
+
+	return webhook, token, nil
+}
+
+// ListIncomingWebhooks возвращает действующие входящие вебхуки чата. Доступно только администраторам чата
+func (s *ChatService) ListIncomingWebhooks(ctx context.Context, chatID, userID string) ([]*models.IncomingWebhook, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	if err := s.requireAdmin(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.incomingRepo.GetChatIncomingWebhooks(ctx, chatID)
+}
+
+// RevokeIncomingWebhook отзывает входящий вебхук и исключает его бота из чата. Доступно только администраторам чата
+func (s *ChatService) RevokeIncomingWebhook(ctx context.Context, webhookID, userID string) error {
+	webhook, err := s.incomingRepo.GetIncomingWebhook(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+
+	if err := s.requireAdmin(ctx, webhook.ChatID, userID); err != nil {
+		return err
+	}
+
+	if err := s.incomingRepo.RevokeIncomingWebhook(ctx, webhook.ID, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+
+	if webhook.BotID != "" {
+		if err := s.chatRepo.RemoveParticipant(ctx, webhook.ChatID, webhook.BotID); err != nil && !errors.Is(err, repository.ErrUserNotInChat) {
+			log.Printf("Ошибка при удалении бота %s отозванного вебхука из чата %s: %v", webhook.BotID, webhook.ChatID, err)
+		}
+	}
+
+	log.Printf("Пользователь %s отозвал входящий вебхук %s чата %s", userID, webhook.ID, webhook.ChatID)
+
+	return nil
+}
+
+// PostIncomingWebhookMessage публикует сообщение в чат входящего вебхука от имени его бота.
+// Сообщение проходит те же проверки, что и сообщения пользователей: права на отправку, блокировку
+// и запрет писать, фильтры модерации. Текст не выполняется как slash-команда.
+// username переопределяет отображаемое имя, но не может совпадать с именем пользователя или служебным именем
+func (s *ChatService) PostIncomingWebhookMessage(ctx context.Context, token, text, username, format string) (*models.Message, error) {
+	if token == "" {
+		return nil, ErrWebhookNotFound
+	}
+
+	webhook, err := s.incomingRepo.GetIncomingWebhookByTokenHash(ctx, hashInviteCode(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	if webhook.BotID == "" {
+		log.Printf("Входящий вебхук %s создан без бот-аккаунта, его нужно создать заново", webhook.ID)
+		return nil, ErrWebhookNotFound
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" || !utf8.ValidString(text) {
+		return nil, ErrInvalidMessage
+	}
+	if utf8.RuneCountInString(text) > maxMessageLen {
+		return nil, ErrMessageTooLong
+	}
+
+	username = strings.TrimSpace(username)
+	if username == "" {
+		username = webhook.Name
+	}
+	if utf8.RuneCountInString(username) > maxIncomingWebhookNameLen {
+		return nil, ErrInvalidWebhookUsername
+	}
+	if err := s.checkWebhookUsername(ctx, username, webhook.BotID); err != nil {
+		return nil, err
+	}
+
+	message, err := newTextMessage(webhook.ChatID, webhook.BotID, text, format)
+	if err != nil {
+		return nil, err
+	}
+	message.Username = username
+
+	if err := s.sendUserMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// checkWebhookUsername запрещает вебхуку подписываться служебным именем или именем другого пользователя,
+// чтобы сообщения вебхука нельзя было выдать за сообщения участников. botID - бот самого вебхука
+func (s *ChatService) checkWebhookUsername(ctx context.Context, username, botID string) error {
+	if strings.EqualFold(username, systemMessageUsername) {
+		return ErrInvalidWebhookUsername
+	}
+
+	userID, err := s.authClient.GetUserIDByUsername(ctx, username)
+	if err == nil && userID != botID {
+		return ErrInvalidWebhookUsername
+	}
+
+	return nil
+}
+
+// incomingWebhookBotName возвращает имя бот-аккаунта вебхука, уникальное благодаря ID вебхука
+func incomingWebhookBotName(webhookID string) string {
+	return incomingWebhookBotPrefix + strings.ReplaceAll(webhookID, "-", "")[:12]
+}
@@ -0,0 +1,183 @@
+package chat_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+)
+
+// defaultInviteTTL срок действия приглашения, если он не указан явно
+const defaultInviteTTL = 24 * time.Hour
+
+var (
+	ErrInviteNotFound  = errors.New("приглашение не найдено")
+	ErrInviteExpired   = errors.New("срок действия приглашения истек")
+	ErrInviteRevoked   = errors.New("приглашение отозвано")
+	ErrInviteExhausted = errors.New("приглашение исчерпало лимит использований")
+	ErrInvalidInvite   = errors.New("некорректные параметры приглашения")
+)
+
+// CreateInvite создает пригласительную ссылку в чат.
+// Возвращает приглашение и код, который больше нигде не сохраняется
+func (s *ChatService) CreateInvite(ctx context.Context, chatID, userID string, expiresAt time.Time, maxUses int, role string) (*models.ChatInvite, string, error) {
+	if chatID == "" {
+		return nil, "", ErrInvalidChatID
+	}
+
+	if maxUses < 0 {
+		return nil, "", ErrInvalidInvite
+	}
+
+	if role == "" {
+		role = models.RoleMember
+	}
+	if role != models.RoleMember && role != models.RoleAdmin {
+		return nil, "", ErrInvalidInvite
+	}
+
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(defaultInviteTTL)
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidInvite
+	}
+
+	if err := s.requireAdmin(ctx, chatID, userID); err != nil {
+		return nil, "", err
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, "", err
+	}
+
+	invite := &models.ChatInvite{
+		ChatID:      chatID,
+		TokenHash:   hashInviteCode(code),
+		CreatedByID: userID,
+		Role:        role,
+		MaxUses:     maxUses,
+		ExpiresAt:   expiresAt,
+	}
+
+	inviteID, err := s.inviteRepo.CreateInvite(ctx, invite)
+	if err != nil {
+		log.Printf("Ошибка при сохранении приглашения в чат %s: %v", chatID, err)
+		return nil, "", err
+	}
+	invite.ID = inviteID
+
+	log.Printf("Пользователь %s создал приглашение %s в чат %s", userID, inviteID, chatID)
+
+	return invite, code, nil
+}
+
+// RevokeInvite отзывает приглашение. Доступно администраторам чата
+func (s *ChatService) RevokeInvite(ctx context.Context, inviteID, userID string) error {
+	invite, err := s.inviteRepo.GetInviteByID(ctx, inviteID)
+	if err != nil {
+		if errors.Is(err, repository.ErrInviteNotFound) {
+			return ErrInviteNotFound
+		}
+		return err
+	}
+
+	if err := s.requireAdmin(ctx, invite.ChatID, userID); err != nil {
+		return err
+	}
+
+	return s.inviteRepo.RevokeInvite(ctx, inviteID)
+}
+
+// JoinByInvite добавляет пользователя в чат по коду приглашения.
+// Возвращает ID чата и роль, с которой пользователь состоит в чате
+func (s *ChatService) JoinByInvite(ctx context.Context, code, userID string) (string, string, error) {
+	if userID == "" {
+		return "", "", ErrInvalidUserID
+	}
+
+	invite, err := s.inviteRepo.GetInviteByTokenHash(ctx, hashInviteCode(code))
+	if err != nil {
+		if errors.Is(err, repository.ErrInviteNotFound) {
+			return "", "", ErrInviteNotFound
+		}
+		return "", "", err
+	}
+
+	// Уже состоящий в чате пользователь не расходует приглашение
+	role, err := s.chatRepo.GetParticipantRole(ctx, invite.ChatID, userID)
+	if err == nil {
+		return invite.ChatID, role, nil
+	}
+	if !errors.Is(err, repository.ErrUserNotInChat) {
+		return "", "", err
+	}
+
+	switch {
+	case invite.RevokedAt != nil:
+		return "", "", ErrInviteRevoked
+	case !time.Now().Before(invite.ExpiresAt):
+		return "", "", ErrInviteExpired
+	}
+
+	// Заблокированный пользователь не может вернуться в чат по приглашению
+	if err := s.checkNotBanned(ctx, invite.ChatID, userID); err != nil {
+		return "", "", err
+	}
+
+	if err := s.inviteRepo.UseInvite(ctx, invite.ID); err != nil {
+		if errors.Is(err, repository.ErrInviteExhausted) {
+			return "", "", ErrInviteExhausted
+		}
+		return "", "", err
+	}
+
+	if err := s.chatRepo.AddParticipant(ctx, invite.ChatID, userID, invite.Role); err != nil {
+		return "", "", err
+	}
+
+	log.Printf("Пользователь %s вступил в чат %s по приглашению %s", userID, invite.ChatID, invite.ID)
+
+	return invite.ChatID, invite.Role, nil
+}
+
+// requireAdmin проверяет, что пользователь является администратором чата
+func (s *ChatService) requireAdmin(ctx context.Context, chatID, userID string) error {
+	role, err := s.chatRepo.GetParticipantRole(ctx, chatID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotInChat) {
+			return ErrUserNotInChat
+		}
+		return err
+	}
+
+	if role != models.RoleAdmin {
+		return ErrNotChatAdmin
+	}
+
+	return nil
+}
+
+// generateInviteCode генерирует случайный код приглашения
+func generateInviteCode() (string, error) {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashInviteCode возвращает хеш кода приглашения для хранения в базе
+func hashInviteCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
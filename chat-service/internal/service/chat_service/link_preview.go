@@ -0,0 +1,159 @@
+package chat_service
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"chat.service/internal/models"
+)
+
+// Ограничения получения превью ссылок
+const (
+	maxPreviewsPerMessage = 3
+	linkPreviewTimeout    = 15 * time.Second
+	maxConcurrentUnfurls  = 8
+)
+
+// urlPattern находит http(s) ссылки в тексте сообщения
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// UnfurlFetcher получает превью страницы по адресу ссылки
+type UnfurlFetcher interface {
+	// Fetch загружает страницу и возвращает ее заголовок, описание и изображение
+	Fetch(ctx context.Context, url string) (*models.LinkPreview, error)
+}
+
+// extractURLs возвращает уникальные ссылки сообщения: адреса ссылок разметки и ссылки из текста
+func extractURLs(message *models.Message) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(url string) {
+		if seen[url] || len(urls) >= maxPreviewsPerMessage {
+			return
+		}
+		seen[url] = true
+		urls = append(urls, url)
+	}
+
+	for _, entity := range message.Entities {
+		if entity.Type == models.EntityLink {
+			add(entity.URL)
+		}
+	}
+
+	for _, url := range urlPattern.FindAllString(message.Text, -1) {
+		// Знаки препинания в конце предложения не относятся к ссылке
+		add(strings.TrimRight(url, ".,;:!?)]}"))
+	}
+
+	return urls
+}
+
+// unfurlMessage асинхронно получает превью ссылок сообщения и рассылает обновленное сообщение подписчикам.
+// Превью берутся из кеша, отсутствующие загружаются через UnfurlFetcher и сохраняются в кеш
+func (s *ChatService) unfurlMessage(message *models.Message) {
+	urls := extractURLs(message)
+	if len(urls) == 0 {
+		return
+	}
+
+	// Ограничиваем число одновременных загрузок, лишние сообщения остаются без превью
+	select {
+	case s.unfurlSlots <- struct{}{}:
+	default:
+		log.Printf("Пропуск превью ссылок сообщения %s: слишком много одновременных загрузок", message.ID)
+		return
+	}
+
+	go func() {
+		defer func() { <-s.unfurlSlots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), linkPreviewTimeout)
+		defer cancel()
+
+		previews := s.fetchLinkPreviews(ctx, urls)
+		if len(previews) == 0 {
+			return
+		}
+
+		// Рассылаем копию, чтобы не изменять сообщение, уже переданное подписчикам
+		updated := *message
+		updated.Event = models.MessageEventUpdated
+		updated.Previews = previews
+		s.subManager.PublishMessage(updated.ChatID, &updated)
+	}()
+}
+
+// fetchLinkPreviews возвращает превью ссылок в порядке адресов.
+// Отсутствующие в кеше превью загружаются и сохраняются
+func (s *ChatService) fetchLinkPreviews(ctx context.Context, urls []string) []*models.LinkPreview {
+	cached, err := s.previewRepo.GetLinkPreviews(ctx, urls)
+	if err != nil {
+		log.Printf("Ошибка при получении превью ссылок из кеша: %v", err)
+	}
+
+	byURL := make(map[string]*models.LinkPreview, len(cached))
+	for _, preview := range cached {
+		byURL[preview.URL] = preview
+	}
+
+	var previews []*models.LinkPreview
+	for _, url := range urls {
+		preview, ok := byURL[url]
+		if !ok {
+			preview, err = s.unfurler.Fetch(ctx, url)
+			if err != nil {
+				log.Printf("Не удалось получить превью ссылки %s: %v", url, err)
+				continue
+			}
+
+			if err := s.previewRepo.SaveLinkPreview(ctx, preview); err != nil {
+				log.Printf("Ошибка при сохранении превью ссылки %s: %v", url, err)
+			}
+		}
+
+		previews = append(previews, preview)
+	}
+
+	return previews
+}
+
+// attachLinkPreviews добавляет к сообщениям истории превью ссылок из кеша
+func (s *ChatService) attachLinkPreviews(ctx context.Context, messages []*models.Message) {
+	urlsByMessage := make(map[*models.Message][]string)
+	var all []string
+	for _, message := range messages {
+		urls := extractURLs(message)
+		if len(urls) == 0 {
+			continue
+		}
+		urlsByMessage[message] = urls
+		all = append(all, urls...)
+	}
+
+	if len(all) == 0 {
+		return
+	}
+
+	cached, err := s.previewRepo.GetLinkPreviews(ctx, all)
+	if err != nil {
+		log.Printf("Ошибка при получении превью ссылок из кеша: %v", err)
+		return
+	}
+
+	byURL := make(map[string]*models.LinkPreview, len(cached))
+	for _, preview := range cached {
+		byURL[preview.URL] = preview
+	}
+
+	for message, urls := range urlsByMessage {
+		for _, url := range urls {
+			if preview, ok := byURL[url]; ok {
+				message.Previews = append(message.Previews, preview)
+			}
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"chat.service/internal/service/moderation"
+)
+
+var (
+	ErrMessageRejected        = errors.New("сообщение отклонено модерацией")
+	ErrFlaggedMessageNotFound = errors.New("сообщение в очереди модерации не найдено или уже проверено")
+	ErrAuthorCannotPost       = errors.New("автор сообщения сейчас не может писать в этот чат")
+)
+
+// ModerationFilter проверяет сообщение пользователя перед отправкой в чат
+type ModerationFilter interface {
+	// Check возвращает решение о сообщении: пропустить, отклонить или отправить на проверку
+	Check(ctx context.Context, message *models.Message) moderation.Verdict
+}
+
+// moderateMessage проверяет сообщение цепочкой фильтров в порядке их передачи в сервис.
+// Первое отклонение прерывает проверку, отметки всех фильтров объединяются в одну причину
+func (s *ChatService) moderateMessage(ctx context.Context, message *models.Message) moderation.Verdict {
+	var reasons []string
+	for _, filter := range s.filters {
+		verdict := filter.Check(ctx, message)
+		switch verdict.Action {
+		case moderation.Reject:
+			return verdict
+		case moderation.Flag:
+			reasons = append(reasons, verdict.Reason)
+		}
+	}
+
+	if len(reasons) > 0 {
+		return moderation.Verdict{Action: moderation.Flag, Reason: strings.Join(reasons, "; ")}
+	}
+
+	return moderation.Allowed()
+}
+
+// sendUserMessage отправляет в чат сообщение, написанное пользователем, после проверки фильтрами модерации.
+// Отмеченное сообщение сохраняется в очереди модерации и не рассылается, пока администратор его не одобрит
+func (s *ChatService) sendUserMessage(ctx context.Context, message *models.Message) error {
+	if err := s.checkCanPost(ctx, message.ChatID, message.UserID); err != nil {
+		return err
+	}
+
+	// Подпись, заданную вызывающей стороной (например, входящим вебхуком), не заменяем
+	if message.Username == "" {
+		message.Username = s.lookupUsername(ctx, message.UserID)
+	}
+	if message.Kind == "" {
+		message.Kind = models.MessageKindText
+	}
+
+	verdict := s.moderateMessage(ctx, message)
+	switch verdict.Action {
+	case moderation.Reject:
+		log.Printf("Сообщение пользователя %s в чат %s отклонено модерацией: %s", message.UserID, message.ChatID, verdict.Reason)
+		return fmt.Errorf("%w: %s", ErrMessageRejected, verdict.Reason)
+
+	case moderation.Flag:
+		// Заранее назначенный ID сообщения переходит в очередь, чтобы повторная отправка не создала дубликат
+		flagged := &models.FlaggedMessage{
+			ID:       message.ID,
+			ChatID:   message.ChatID,
+			UserID:   message.UserID,
+			Username: message.Username,
+			Text:     message.Text,
+			Kind:     message.Kind,
+			Entities: message.Entities,
+			Reason:   verdict.Reason,
+
+			ForwardMessageID: message.ForwardMessageID,
+			ForwardChatID:    message.ForwardChatID,
+			ForwardUserID:    message.ForwardUserID,
+			ForwardUsername:  message.ForwardUsername,
+		}
+		if err := s.moderationRepo.QueueMessage(ctx, flagged); err != nil {
+			log.Printf("Ошибка при добавлении сообщения в очередь модерации: %v", err)
+			return err
+		}
+
+		message.ID = flagged.ID
+		message.CreatedAt = flagged.CreatedAt
+		message.PendingReview = true
+		log.Printf("Сообщение %s пользователя %s в чат %s отправлено на проверку: %s", flagged.ID, message.UserID, message.ChatID, verdict.Reason)
+
+		return nil
+	}
+
+	return s.publishMessage(ctx, message)
+}
+
+// ListFlaggedMessages возвращает сообщения чата, ожидающие проверки. Доступно администраторам чата
+func (s *ChatService) ListFlaggedMessages(ctx context.Context, chatID, userID string) ([]*models.FlaggedMessage, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	if err := s.requireAdmin(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.moderationRepo.GetPendingFlaggedMessages(ctx, chatID)
+}
+
+// ApproveFlaggedMessage одобряет сообщение из очереди модерации и публикует его в чат от имени автора.
+// Если автор был заблокирован, получил запрет писать или покинул чат, пока сообщение ждало проверки,
+// возвращается ErrAuthorCannotPost и сообщение остается в очереди
+func (s *ChatService) ApproveFlaggedMessage(ctx context.Context, flaggedID, userID string) (*models.Message, error) {
+	flagged, err := s.reviewFlaggedMessage(ctx, flaggedID, userID, models.FlaggedApproved, s.checkAuthorCanPost)
+	if err != nil {
+		return nil, err
+	}
+
+	// Сообщение публикуется с ID записи очереди, поэтому одно одобрение не может создать два сообщения
+	message := &models.Message{
+		ID:       flagged.ID,
+		ChatID:   flagged.ChatID,
+		UserID:   flagged.UserID,
+		Username: flagged.Username,
+		Text:     flagged.Text,
+		Kind:     flagged.Kind,
+		Entities: flagged.Entities,
+
+		ForwardMessageID: flagged.ForwardMessageID,
+		ForwardChatID:    flagged.ForwardChatID,
+		ForwardUserID:    flagged.ForwardUserID,
+		ForwardUsername:  flagged.ForwardUsername,
+	}
+
+	if err := s.publishMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// RemoveFlaggedMessage отклоняет сообщение из очереди модерации, сообщение не будет опубликовано
+func (s *ChatService) RemoveFlaggedMessage(ctx context.Context, flaggedID, userID string) error {
+	_, err := s.reviewFlaggedMessage(ctx, flaggedID, userID, models.FlaggedRemoved, nil)
+	return err
+}
+
+// checkAuthorCanPost проверяет, что автор сообщения из очереди по-прежнему может писать в чат
+func (s *ChatService) checkAuthorCanPost(ctx context.Context, flagged *models.FlaggedMessage) error {
+	err := s.checkCanPost(ctx, flagged.ChatID, flagged.UserID)
+	if errors.Is(err, ErrUserBanned) || errors.Is(err, ErrUserMuted) || errors.Is(err, ErrUserNotInChat) || errors.Is(err, ErrChannelReadOnly) {
+		log.Printf("Сообщение %s не может быть опубликовано: %v", flagged.ID, err)
+		return ErrAuthorCannotPost
+	}
+
+	return err
+}
+
+// reviewFlaggedMessage проверяет права администратора и сохраняет решение по сообщению из очереди.
+// check, если задан, выполняется перед сохранением решения; при ошибке сообщение остается в очереди
+func (s *ChatService) reviewFlaggedMessage(ctx context.Context, flaggedID, userID, status string, check func(context.Context, *models.FlaggedMessage) error) (*models.FlaggedMessage, error) {
+	if flaggedID == "" {
+		return nil, ErrFlaggedMessageNotFound
+	}
+
+	flagged, err := s.moderationRepo.GetFlaggedMessage(ctx, flaggedID)
+	if err != nil {
+		if errors.Is(err, repository.ErrFlaggedMessageNotFound) {
+			return nil, ErrFlaggedMessageNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, flagged.ChatID, userID); err != nil {
+		return nil, err
+	}
+
+	if flagged.Status != models.FlaggedPending {
+		return nil, ErrFlaggedMessageNotFound
+	}
+
+	if check != nil {
+		if err := check(ctx, flagged); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.moderationRepo.ReviewFlaggedMessage(ctx, flaggedID, status, userID, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrFlaggedMessageNotFound) {
+			return nil, ErrFlaggedMessageNotFound
+		}
+		return nil, err
+	}
+
+	log.Printf("Администратор %s изменил статус сообщения %s в очереди модерации чата %s на %s", userID, flaggedID, flagged.ChatID, status)
+
+	return flagged, nil
+}
@@ -0,0 +1,178 @@
+package chat_service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"chat.service/internal/models"
+)
+
+// Ограничения рассылки уведомлений о новых сообщениях
+const (
+	notifyTimeout   = 30 * time.Second
+	notifyBatchSize = 500
+)
+
+// SubscribeToNotifications подписывает пользователя на уведомления по всем его чатам
+func (s *ChatService) SubscribeToNotifications(userID string) (<-chan *models.Notification, string, error) {
+	if userID == "" {
+		return nil, "", ErrInvalidUserID
+	}
+
+	notificationChan, subscriptionID := s.subManager.SubscribeNotifications(userID)
+	log.Printf("Пользователь %s подписан на уведомления, ID подписки: %s", userID, subscriptionID)
+
+	return notificationChan, subscriptionID, nil
+}
+
+// UnsubscribeFromNotifications отменяет подписку на уведомления
+func (s *ChatService) UnsubscribeFromNotifications(userID, subscriptionID string) {
+	log.Printf("Отписка пользователя %s от уведомлений, ID подписки: %s", userID, subscriptionID)
+	s.subManager.UnsubscribeNotifications(userID, subscriptionID)
+}
+
+// notifyNewMessage рассылает уведомления о новом сообщении участникам чата, подписанным на уведомления.
+// Автор сообщения уведомление не получает, остальные получают его с учетом персональных настроек чата:
+// упоминание уведомляет и при уровне "только упоминания", но не в заглушенном или скрытом чате.
+// Рассылка выполняется в фоне, чтобы не задерживать отправку сообщения
+func (s *ChatService) notifyNewMessage(message *models.Message) {
+	if !s.subManager.HasNotificationSubscribers() {
+		return
+	}
+
+	// Служебные, эфемерные сообщения и вызовы команд ботов не уведомляют
+	if message.Kind == models.MessageKindSystem || message.RecipientID != "" || (message.Event != "" && message.Event != models.MessageEventNew) {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+
+		s.fanOutNotifications(ctx, message)
+	}()
+}
+
+// fanOutNotifications рассылает уведомления о сообщении. Настройки чата и имена получателей
+// загружаются пакетами, а не отдельным запросом на каждого получателя
+func (s *ChatService) fanOutNotifications(ctx context.Context, message *models.Message) {
+	participants, err := s.chatRepo.GetChatParticipants(ctx, message.ChatID)
+	if err != nil {
+		log.Printf("Ошибка при получении участников чата %s для уведомлений: %v", message.ChatID, err)
+		return
+	}
+
+	var recipients []string
+	for _, userID := range s.subManager.NotificationSubscribers(participants) {
+		if userID != message.UserID {
+			recipients = append(recipients, userID)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	chatName := s.chatName(ctx, message.ChatID)
+	now := time.Now()
+	for start := 0; start < len(recipients); start += notifyBatchSize {
+		batch := recipients[start:min(start+notifyBatchSize, len(recipients))]
+
+		settings, err := s.chatRepo.GetParticipantsSettings(ctx, message.ChatID, batch)
+		if err != nil {
+			log.Printf("Ошибка при получении настроек чата %s для уведомлений: %v", message.ChatID, err)
+			return
+		}
+
+		// Без имен упоминания не распознаются, но обычные уведомления все равно отправляются
+		usernames, err := s.authClient.GetUsernames(ctx, batch)
+		if err != nil {
+			log.Printf("Не удалось получить имена получателей уведомлений чата %s: %v", message.ChatID, err)
+		}
+
+		for _, userSettings := range settings {
+			mentioned := mentions(message.Text, usernames[userSettings.UserID])
+			if !userSettings.ShouldNotify(now, mentioned) {
+				continue
+			}
+
+			kind := models.NotificationMessage
+			if mentioned {
+				kind = models.NotificationMention
+			}
+
+			s.subManager.Notify(userSettings.UserID, &models.Notification{
+				Kind:          kind,
+				ChatID:        message.ChatID,
+				ChatName:      chatName,
+				Message:       message,
+				ActorUsername: message.Username,
+				CreatedAt:     message.CreatedAt,
+			})
+		}
+	}
+}
+
+// notifyInvited уведомляет пользователя о том, что его добавили в чат
+func (s *ChatService) notifyInvited(ctx context.Context, chatID, userID, actorID string) {
+	if len(s.subManager.NotificationSubscribers([]string{userID})) == 0 {
+		return
+	}
+
+	s.subManager.Notify(userID, &models.Notification{
+		Kind:          models.NotificationInvite,
+		ChatID:        chatID,
+		ChatName:      s.chatName(ctx, chatID),
+		ActorUsername: s.lookupUsername(ctx, actorID),
+		CreatedAt:     time.Now(),
+	})
+}
+
+// chatName возвращает название чата для уведомления или пустую строку, если чат не найден
+func (s *ChatService) chatName(ctx context.Context, chatID string) string {
+	chat, err := s.chatRepo.GetChatByID(ctx, chatID)
+	if err != nil {
+		log.Printf("Не удалось получить чат %s для уведомления: %v", chatID, err)
+		return ""
+	}
+
+	return chat.Name
+}
+
+// mentions проверяет, упомянут ли пользователь в тексте как @username без учета регистра.
+// Упоминание должно быть отдельным словом: @bob не упоминает bobby, а адрес alice@bob.com - пользователя bob
+func mentions(text, username string) bool {
+	if username == "" {
+		return false
+	}
+
+	text = strings.ToLower(text)
+	mention := "@" + strings.ToLower(username)
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], mention)
+		if idx < 0 {
+			return false
+		}
+		start := offset + idx
+		end := start + len(mention)
+		offset = start + 1
+
+		if start > 0 && isUsernameChar(text[start-1]) {
+			continue
+		}
+		if end < len(text) && isUsernameChar(text[end]) {
+			// Точка или дефис после имени - знак препинания, если за ними не продолжается имя
+			if (text[end] != '.' && text[end] != '-') || (end+1 < len(text) && isUsernameChar(text[end+1])) {
+				continue
+			}
+		}
+
+		return true
+	}
+}
+
+// isUsernameChar сообщает, может ли байт входить в имя пользователя
+func isUsernameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-'
+}
@@ -0,0 +1,276 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"chat.service/internal/service/moderation"
+	"github.com/google/uuid"
+)
+
+// Ограничения на размер опроса
+const (
+	minPollOptions     = 2
+	maxPollOptions     = 10
+	maxPollQuestionLen = 300
+	maxPollOptionLen   = 100
+	expiredPollsBatch  = 100
+)
+
+var (
+	ErrInvalidPoll  = errors.New("некорректные параметры опроса")
+	ErrPollNotFound = errors.New("опрос не найден")
+	ErrPollClosed   = errors.New("опрос закрыт")
+	ErrInvalidVote  = errors.New("некорректный выбор вариантов ответа")
+	ErrNotPollOwner = errors.New("закрыть опрос может только его автор или администратор чата")
+)
+
+// PollParams описывает параметры нового опроса
+type PollParams struct {
+	Question       string
+	Options        []string
+	MultipleChoice bool
+	Anonymous      bool
+	ClosesAt       *time.Time
+}
+
+// CreatePoll публикует в чате сообщение с опросом. Вопрос и варианты ответа проверяются
+// фильтрами модерации; опрос не может ждать проверки в очереди, поэтому отмеченный опрос отклоняется
+func (s *ChatService) CreatePoll(ctx context.Context, chatID, userID string, params PollParams) (*models.Message, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	poll, err := newPoll(chatID, userID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &models.Message{
+		ID:     uuid.New().String(),
+		ChatID: chatID,
+		UserID: userID,
+		Text:   poll.Question,
+		Kind:   models.MessageKindPoll,
+		Poll:   poll,
+	}
+
+	if err := s.moderatePoll(ctx, message); err != nil {
+		return nil, err
+	}
+
+	if err := s.deliverMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// moderatePoll проверяет фильтрами модерации вопрос и варианты ответа опроса как один текст
+func (s *ChatService) moderatePoll(ctx context.Context, message *models.Message) error {
+	texts := []string{message.Poll.Question}
+	for _, option := range message.Poll.Options {
+		texts = append(texts, option.Text)
+	}
+
+	check := *message
+	check.Text = strings.Join(texts, "\n")
+
+	verdict := s.moderateMessage(ctx, &check)
+	if verdict.Action == moderation.Allow {
+		return nil
+	}
+
+	log.Printf("Опрос пользователя %s в чат %s отклонен модерацией: %s", message.UserID, message.ChatID, verdict.Reason)
+	return fmt.Errorf("%w: %s", ErrMessageRejected, verdict.Reason)
+}
+
+// Vote заменяет голос пользователя в опросе и рассылает обновленные результаты
+func (s *ChatService) Vote(ctx context.Context, pollID, userID string, optionIndexes []int) (*models.Poll, error) {
+	poll, err := s.getPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Голосовать могут только участники чата
+	isParticipant, err := s.chatRepo.CheckUserInChat(ctx, poll.ChatID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant {
+		return nil, ErrUserNotInChat
+	}
+
+	if poll.IsClosed(time.Now()) {
+		return nil, ErrPollClosed
+	}
+
+	if err := validateVote(poll, optionIndexes); err != nil {
+		return nil, err
+	}
+
+	if err := s.pollRepo.ReplaceVotes(ctx, pollID, userID, optionIndexes); err != nil {
+		log.Printf("Ошибка при сохранении голоса в опросе %s: %v", pollID, err)
+		return nil, err
+	}
+
+	return s.publishPollUpdate(ctx, pollID)
+}
+
+// ClosePoll досрочно закрывает опрос. Доступно автору опроса и администраторам чата
+func (s *ChatService) ClosePoll(ctx context.Context, pollID, userID string) (*models.Poll, error) {
+	poll, err := s.getPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.CreatedByID != userID {
+		if err := s.requireAdmin(ctx, poll.ChatID, userID); err != nil {
+			if errors.Is(err, ErrNotChatAdmin) || errors.Is(err, ErrUserNotInChat) {
+				return nil, ErrNotPollOwner
+			}
+			return nil, err
+		}
+	}
+
+	if poll.IsClosed(time.Now()) {
+		return nil, ErrPollClosed
+	}
+
+	if _, err := s.pollRepo.ClosePoll(ctx, pollID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Пользователь %s закрыл опрос %s", userID, pollID)
+
+	return s.publishPollUpdate(ctx, pollID)
+}
+
+// closeExpiredPolls закрывает опросы, срок которых истек, и рассылает их итоговые результаты
+func (s *ChatService) closeExpiredPolls(ctx context.Context) {
+	pollIDs, err := s.pollRepo.GetExpiredPollIDs(ctx, time.Now(), expiredPollsBatch)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Ошибка при получении истекших опросов: %v", err)
+		}
+		return
+	}
+
+	for _, pollID := range pollIDs {
+		closed, err := s.pollRepo.ClosePoll(ctx, pollID, time.Now())
+		if err != nil {
+			log.Printf("Ошибка при закрытии опроса %s: %v", pollID, err)
+			continue
+		}
+
+		if closed {
+			if _, err := s.publishPollUpdate(ctx, pollID); err != nil {
+				log.Printf("Ошибка при рассылке результатов опроса %s: %v", pollID, err)
+			}
+		}
+	}
+}
+
+// publishPollUpdate рассылает подписчикам чата актуальные результаты опроса
+func (s *ChatService) publishPollUpdate(ctx context.Context, pollID string) (*models.Poll, error) {
+	poll, err := s.getPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := s.messageRepo.GetMessageByID(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Event = models.MessageEventUpdated
+	message.Poll = poll
+	s.subManager.PublishMessage(poll.ChatID, message)
+
+	return poll, nil
+}
+
+// getPoll возвращает опрос по ID, приводя ошибку отсутствия опроса к ErrPollNotFound
+func (s *ChatService) getPoll(ctx context.Context, pollID string) (*models.Poll, error) {
+	poll, err := s.pollRepo.GetPoll(ctx, pollID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPollNotFound) {
+			return nil, ErrPollNotFound
+		}
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+// newPoll проверяет параметры и создает модель опроса
+func newPoll(chatID, userID string, params PollParams) (*models.Poll, error) {
+	question := strings.TrimSpace(params.Question)
+	if question == "" || utf8.RuneCountInString(question) > maxPollQuestionLen {
+		return nil, ErrInvalidPoll
+	}
+
+	if len(params.Options) < minPollOptions || len(params.Options) > maxPollOptions {
+		return nil, ErrInvalidPoll
+	}
+
+	if params.ClosesAt != nil && !params.ClosesAt.After(time.Now()) {
+		return nil, ErrInvalidPoll
+	}
+
+	poll := &models.Poll{
+		ChatID:         chatID,
+		CreatedByID:    userID,
+		Question:       question,
+		MultipleChoice: params.MultipleChoice,
+		Anonymous:      params.Anonymous,
+		ClosesAt:       params.ClosesAt,
+	}
+
+	for i, text := range params.Options {
+		text = strings.TrimSpace(text)
+		if text == "" || utf8.RuneCountInString(text) > maxPollOptionLen {
+			return nil, ErrInvalidPoll
+		}
+
+		poll.Options = append(poll.Options, &models.PollOption{Index: i, Text: text})
+	}
+
+	return poll, nil
+}
+
+// validateVote проверяет выбранные варианты ответа
+func validateVote(poll *models.Poll, optionIndexes []int) error {
+	if len(optionIndexes) == 0 {
+		return ErrInvalidVote
+	}
+
+	if !poll.MultipleChoice && len(optionIndexes) > 1 {
+		return ErrInvalidVote
+	}
+
+	seen := make(map[int]struct{}, len(optionIndexes))
+	for _, idx := range optionIndexes {
+		if idx < 0 || idx >= len(poll.Options) {
+			return ErrInvalidVote
+		}
+
+		if _, ok := seen[idx]; ok {
+			return ErrInvalidVote
+		}
+		seen[idx] = struct{}{}
+	}
+
+	return nil
+}
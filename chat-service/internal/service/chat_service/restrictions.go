@@ -0,0 +1,345 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUserBanned          = errors.New("пользователь заблокирован в этом чате")
+	ErrUserMuted           = errors.New("пользователю временно запрещено писать в этот чат")
+	ErrCannotRestrict      = errors.New("нельзя ограничить себя или администратора чата")
+	ErrInvalidRestriction  = errors.New("некорректная длительность ограничения")
+	ErrRestrictionNotFound = errors.New("действующее ограничение не найдено")
+	ErrInvalidReport       = errors.New("укажите причину жалобы длиной до 500 символов")
+	ErrAlreadyReported     = errors.New("вы уже отправили жалобу на это сообщение")
+)
+
+// Ограничения модерации участников
+const (
+	maxMuteDuration    = 30 * 24 * time.Hour
+	maxReasonLen       = 500
+	defaultReportLimit = 50
+	maxReportLimit     = 200
+)
+
+// checkNotRestricted проверяет, что пользователь не заблокирован в чате и может в него писать.
+// Истекшие ограничения не учитываются, поэтому снимать их отдельно не нужно
+func (s *ChatService) checkNotRestricted(ctx context.Context, chatID, userID string) error {
+	restrictions, err := s.moderationRepo.GetActiveRestrictions(ctx, chatID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Блокировка важнее временного запрета писать
+	err = nil
+	for _, restriction := range restrictions {
+		switch restriction.Kind {
+		case models.RestrictionBan:
+			return ErrUserBanned
+		case models.RestrictionMute:
+			err = ErrUserMuted
+		}
+	}
+
+	return err
+}
+
+// checkNotBanned проверяет, что пользователь не заблокирован в чате
+func (s *ChatService) checkNotBanned(ctx context.Context, chatID, userID string) error {
+	if err := s.checkNotRestricted(ctx, chatID, userID); err != nil && !errors.Is(err, ErrUserMuted) {
+		return err
+	}
+
+	return nil
+}
+
+// mutedUntil возвращает время окончания действующего запрета писать в чат.
+// Для бессрочного запрета возвращает нулевое время, false означает, что запрета нет
+func (s *ChatService) mutedUntil(ctx context.Context, chatID, userID string) (time.Time, bool, error) {
+	restrictions, err := s.moderationRepo.GetActiveRestrictions(ctx, chatID, userID, time.Now())
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	// Если запретов несколько, пользователь сможет писать после окончания последнего
+	var until time.Time
+	muted := false
+	for _, restriction := range restrictions {
+		if restriction.Kind != models.RestrictionMute {
+			continue
+		}
+		if restriction.ExpiresAt == nil {
+			return time.Time{}, true, nil
+		}
+		muted = true
+		if restriction.ExpiresAt.After(until) {
+			until = *restriction.ExpiresAt
+		}
+	}
+
+	return until, muted, nil
+}
+
+// ReportMessage сохраняет жалобу участника чата на сообщение
+func (s *ChatService) ReportMessage(ctx context.Context, messageID, userID, reason string) (*models.MessageReport, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxReasonLen {
+		return nil, ErrInvalidReport
+	}
+
+	message, err := s.getMessage(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.DeletedAt != nil || message.Kind == models.MessageKindSystem {
+		return nil, ErrMessageNotFound
+	}
+
+	isParticipant, err := s.chatRepo.CheckUserInChat(ctx, message.ChatID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant {
+		return nil, ErrUserNotInChat
+	}
+
+	report := &models.MessageReport{
+		ChatID:     message.ChatID,
+		MessageID:  message.ID,
+		ReporterID: userID,
+		Reason:     reason,
+	}
+	if err := s.moderationRepo.CreateReport(ctx, report); err != nil {
+		if errors.Is(err, repository.ErrAlreadyReported) {
+			return nil, ErrAlreadyReported
+		}
+		return nil, err
+	}
+
+	s.audit(ctx, &models.AuditEntry{
+		ChatID:          message.ChatID,
+		ActorID:         userID,
+		Action:          models.AuditReport,
+		TargetUserID:    message.UserID,
+		TargetUsername:  message.Username,
+		TargetMessageID: message.ID,
+		Details:         reason,
+	})
+
+	return report, nil
+}
+
+// ListReports возвращает последние жалобы на сообщения чата. Доступно администраторам чата
+func (s *ChatService) ListReports(ctx context.Context, chatID, userID string, limit int) ([]*models.MessageReport, error) {
+	if err := s.requireAdmin(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.moderationRepo.GetChatReports(ctx, chatID, clampLimit(limit, defaultReportLimit, maxReportLimit))
+}
+
+// BanUser блокирует пользователя в чате: пользователь исключается из чата, отключается от потока сообщений
+// и не может вступить в чат снова, пока блокировка не снята
+func (s *ChatService) BanUser(ctx context.Context, chatID, adminID, username, reason string) (*models.ChatRestriction, error) {
+	restriction, err := s.restrictUser(ctx, chatID, adminID, username, models.RestrictionBan, reason, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.chatRepo.RemoveParticipant(ctx, chatID, restriction.UserID); err != nil && !errors.Is(err, repository.ErrUserNotInChat) {
+		return nil, err
+	}
+
+	// Сообщаем пользователю о блокировке и закрываем его открытые потоки сообщений чата
+	s.publishEphemeral(&models.Message{
+		ID:       uuid.New().String(),
+		ChatID:   chatID,
+		Username: systemMessageUsername,
+		Text:     "Доступ к чату заблокирован администратором",
+		Kind:     models.MessageKindSystem,
+	}, restriction.UserID)
+	s.subManager.DisconnectUser(chatID, restriction.UserID)
+
+	call := &commandCall{chatID: chatID, userID: adminID}
+	if _, err := s.postSystemMessage(ctx, call, restriction.Username+": доступ к чату заблокирован"); err != nil {
+		log.Printf("Ошибка при отправке сообщения о блокировке: %v", err)
+	}
+
+	return restriction, nil
+}
+
+// MuteUser временно запрещает пользователю писать в чат. Запрет снимается автоматически по истечении срока
+func (s *ChatService) MuteUser(ctx context.Context, chatID, adminID, username, reason string, duration time.Duration) (*models.ChatRestriction, error) {
+	if duration <= 0 || duration > maxMuteDuration {
+		return nil, ErrInvalidRestriction
+	}
+
+	expiresAt := time.Now().Add(duration)
+	restriction, err := s.restrictUser(ctx, chatID, adminID, username, models.RestrictionMute, reason, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	call := &commandCall{chatID: chatID, userID: adminID}
+	text := fmt.Sprintf("%s: отправка сообщений в чат ограничена на %s", restriction.Username, duration.Round(time.Second))
+	if _, err := s.postSystemMessage(ctx, call, text); err != nil {
+		log.Printf("Ошибка при отправке сообщения об ограничении: %v", err)
+	}
+
+	return restriction, nil
+}
+
+// LiftRestriction снимает блокировку или запрет писать до истечения срока
+func (s *ChatService) LiftRestriction(ctx context.Context, chatID, adminID, username, kind string) error {
+	if kind != models.RestrictionBan && kind != models.RestrictionMute {
+		return ErrInvalidRestriction
+	}
+
+	if err := s.requireAdmin(ctx, chatID, adminID); err != nil {
+		return err
+	}
+
+	targetID, err := s.authClient.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.moderationRepo.LiftRestriction(ctx, chatID, targetID, kind, adminID, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrRestrictionNotFound) {
+			return ErrRestrictionNotFound
+		}
+		return err
+	}
+
+	action := models.AuditUnmute
+	if kind == models.RestrictionBan {
+		action = models.AuditUnban
+	}
+	s.audit(ctx, &models.AuditEntry{
+		ChatID:         chatID,
+		ActorID:        adminID,
+		Action:         action,
+		TargetUserID:   targetID,
+		TargetUsername: username,
+	})
+
+	return nil
+}
+
+// ListRestrictions возвращает действующие ограничения участников чата. Доступно администраторам чата
+func (s *ChatService) ListRestrictions(ctx context.Context, chatID, userID string) ([]*models.ChatRestriction, error) {
+	if err := s.requireAdmin(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.moderationRepo.GetChatRestrictions(ctx, chatID, time.Now())
+}
+
+// GetAuditLog возвращает журнал модерации чата: жалобы, блокировки и запреты писать. Доступно администраторам чата
+func (s *ChatService) GetAuditLog(ctx context.Context, chatID, userID string, limit int) ([]*models.AuditEntry, error) {
+	if err := s.requireAdmin(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.moderationRepo.GetAuditLog(ctx, chatID, clampLimit(limit, defaultReportLimit, maxReportLimit))
+}
+
+// restrictUser проверяет права администратора, сохраняет ограничение и добавляет запись в журнал
+func (s *ChatService) restrictUser(ctx context.Context, chatID, adminID, username, kind, reason string, expiresAt *time.Time) (*models.ChatRestriction, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > maxReasonLen {
+		return nil, ErrInvalidRestriction
+	}
+
+	if err := s.requireAdmin(ctx, chatID, adminID); err != nil {
+		return nil, err
+	}
+
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+	targetID, err := s.authClient.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if targetID == adminID {
+		return nil, ErrCannotRestrict
+	}
+
+	// Администраторов можно ограничить только после снятия роли
+	role, err := s.chatRepo.GetParticipantRole(ctx, chatID, targetID)
+	if err != nil && !errors.Is(err, repository.ErrUserNotInChat) {
+		return nil, err
+	}
+	if role == models.RoleAdmin {
+		return nil, ErrCannotRestrict
+	}
+
+	restriction := &models.ChatRestriction{
+		ChatID:    chatID,
+		UserID:    targetID,
+		Username:  username,
+		Kind:      kind,
+		Reason:    reason,
+		CreatedBy: adminID,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.moderationRepo.CreateRestriction(ctx, restriction); err != nil {
+		return nil, err
+	}
+
+	action := models.AuditBan
+	details := reason
+	if expiresAt != nil {
+		action = models.AuditMute
+		details = strings.TrimSpace(fmt.Sprintf("до %s %s", expiresAt.UTC().Format(time.RFC3339), reason))
+	}
+	s.audit(ctx, &models.AuditEntry{
+		ChatID:         chatID,
+		ActorID:        adminID,
+		Action:         action,
+		TargetUserID:   targetID,
+		TargetUsername: username,
+		Details:        details,
+	})
+
+	log.Printf("Администратор %s ограничил пользователя %s в чате %s: %s", adminID, targetID, chatID, kind)
+
+	return restriction, nil
+}
+
+// audit добавляет запись в журнал модерации. Ошибка записи журнала не отменяет выполненное действие
+func (s *ChatService) audit(ctx context.Context, entry *models.AuditEntry) {
+	if entry.ActorUsername == "" {
+		entry.ActorUsername = s.lookupUsername(ctx, entry.ActorID)
+	}
+
+	if err := s.moderationRepo.AddAuditEntry(ctx, entry); err != nil {
+		log.Printf("Ошибка при записи в журнал модерации чата %s: %v", entry.ChatID, err)
+	}
+}
+
+// clampLimit приводит размер выборки к значению по умолчанию и ограничивает сверху
+func clampLimit(limit, defaultLimit, maxLimit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	if limit > maxLimit {
+		return maxLimit
+	}
+	return limit
+}
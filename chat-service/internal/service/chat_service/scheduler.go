@@ -0,0 +1,214 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+)
+
+// scheduledBatchSize максимальное количество отложенных сообщений, отправляемых за один проход
+const scheduledBatchSize = 100
+
+var (
+	ErrInvalidSchedule          = errors.New("время отправки должно быть в будущем")
+	ErrScheduledMessageNotFound = errors.New("отложенное сообщение не найдено")
+)
+
+// ScheduleMessage планирует отправку сообщения в чат в момент sendAt
+func (s *ChatService) ScheduleMessage(ctx context.Context, chatID, userID, text string, sendAt time.Time) (*models.ScheduledMessage, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	if text == "" || !utf8.ValidString(text) {
+		return nil, ErrInvalidMessage
+	}
+
+	if utf8.RuneCountInString(text) > maxMessageLen {
+		return nil, ErrMessageTooLong
+	}
+
+	if !sendAt.After(time.Now()) {
+		return nil, ErrInvalidSchedule
+	}
+
+	// Проверяем права заранее, чтобы не принимать сообщения, которые не удастся отправить
+	if err := s.checkCanPost(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	scheduled := &models.ScheduledMessage{
+		ChatID: chatID,
+		UserID: userID,
+		Text:   text,
+		SendAt: sendAt,
+	}
+
+	id, err := s.scheduleRepo.CreateScheduledMessage(ctx, scheduled)
+	if err != nil {
+		log.Printf("Ошибка при сохранении отложенного сообщения: %v", err)
+		return nil, err
+	}
+	scheduled.ID = id
+
+	log.Printf("Пользователь %s запланировал сообщение %s в чат %s на %s", userID, id, chatID, sendAt.Format(time.RFC3339))
+
+	return scheduled, nil
+}
+
+// ListScheduledMessages возвращает ожидающие отправки сообщения пользователя
+func (s *ChatService) ListScheduledMessages(ctx context.Context, userID, chatID string) ([]*models.ScheduledMessage, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	return s.scheduleRepo.ListPendingScheduledMessages(ctx, userID, chatID)
+}
+
+// CancelScheduledMessage отменяет ожидающее отправки сообщение пользователя
+func (s *ChatService) CancelScheduledMessage(ctx context.Context, id, userID string) error {
+	err := s.scheduleRepo.CancelScheduledMessage(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScheduledMessageNotFound) {
+			return ErrScheduledMessageNotFound
+		}
+		return err
+	}
+
+	log.Printf("Пользователь %s отменил отложенное сообщение %s", userID, id)
+
+	return nil
+}
+
+// RunScheduledDispatcher периодически отправляет отложенные сообщения, время которых наступило,
+// и закрывает опросы с истекшим сроком.
+// Блокируется до отмены контекста. Сообщения, просроченные за время простоя сервиса,
+// отправляются при первом проходе после запуска
+func (s *ChatService) RunScheduledDispatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Запуск диспетчера отложенных сообщений с интервалом %s", interval)
+
+	for {
+		s.dispatchDueMessages(ctx)
+		s.closeExpiredPolls(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Диспетчер отложенных сообщений остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchDueMessages отправляет все отложенные сообщения, время которых наступило.
+// Очередь просматривается по порядку отправки: сообщения, оставшиеся в очереди из-за временной ошибки,
+// пропускаются до следующего прохода и не мешают отправке следующих за ними
+func (s *ChatService) dispatchDueMessages(ctx context.Context) {
+	now := time.Now()
+	var afterSendAt time.Time
+	var afterID string
+
+	for {
+		due, err := s.scheduleRepo.GetDueScheduledMessages(ctx, now, afterSendAt, afterID, scheduledBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Ошибка при получении отложенных сообщений: %v", err)
+			}
+			return
+		}
+
+		for _, scheduled := range due {
+			if ctx.Err() != nil {
+				return
+			}
+			s.dispatchScheduledMessage(ctx, scheduled)
+		}
+
+		if len(due) < scheduledBatchSize {
+			return
+		}
+
+		last := due[len(due)-1]
+		afterSendAt, afterID = last.SendAt, last.ID
+	}
+}
+
+// dispatchScheduledMessage отправляет одно отложенное сообщение тем же путем, что и сообщения пользователей,
+// включая фильтры модерации. Сообщение пользователя, которому временно запрещено писать,
+// переносится на окончание запрета. При временной ошибке сообщение остается в очереди
+func (s *ChatService) dispatchScheduledMessage(ctx context.Context, scheduled *models.ScheduledMessage) {
+	// ID сообщения назначен заранее: если сервис упал после сохранения сообщения или постановки его
+	// в очередь модерации, но до смены статуса, повторная вставка вернет ErrMessageExists и дубликата не будет
+	message := &models.Message{
+		ID:     scheduled.MessageID,
+		ChatID: scheduled.ChatID,
+		UserID: scheduled.UserID,
+		Text:   scheduled.Text,
+	}
+
+	status := models.ScheduledSent
+	err := s.sendUserMessage(ctx, message)
+	switch {
+	case err == nil:
+	case errors.Is(err, repository.ErrMessageExists):
+		log.Printf("Отложенное сообщение %s уже было отправлено ранее", scheduled.ID)
+	case errors.Is(err, ErrUserMuted):
+		if s.postponeScheduledMessage(ctx, scheduled) {
+			return
+		}
+		log.Printf("Отложенное сообщение %s не может быть отправлено: %v", scheduled.ID, err)
+		status = models.ScheduledFailed
+	case errors.Is(err, ErrChatNotFound), errors.Is(err, ErrUserNotInChat), errors.Is(err, ErrChannelReadOnly),
+		errors.Is(err, ErrUserBanned), errors.Is(err, ErrMessageRejected):
+		// Отправка больше невозможна, повторять бессмысленно
+		log.Printf("Отложенное сообщение %s не может быть отправлено: %v", scheduled.ID, err)
+		status = models.ScheduledFailed
+	default:
+		// Временная ошибка, сообщение останется в очереди до следующего прохода
+		log.Printf("Ошибка при отправке отложенного сообщения %s: %v", scheduled.ID, err)
+		return
+	}
+
+	if err := s.scheduleRepo.SetScheduledMessageStatus(ctx, scheduled.ID, status, time.Now()); err != nil {
+		log.Printf("Ошибка при обновлении статуса отложенного сообщения %s: %v", scheduled.ID, err)
+	}
+}
+
+// postponeScheduledMessage переносит отложенное сообщение на окончание запрета писать.
+// Возвращает false, если запрет бессрочный и сообщение отправить не удастся
+func (s *ChatService) postponeScheduledMessage(ctx context.Context, scheduled *models.ScheduledMessage) bool {
+	until, muted, err := s.mutedUntil(ctx, scheduled.ChatID, scheduled.UserID)
+	if err != nil {
+		// Сообщение останется в очереди и будет проверено при следующем проходе
+		log.Printf("Ошибка при получении срока запрета для отложенного сообщения %s: %v", scheduled.ID, err)
+		return true
+	}
+	if !muted {
+		// Запрет закончился после попытки отправки, сообщение уйдет при следующем проходе
+		return true
+	}
+	if until.IsZero() {
+		return false
+	}
+
+	if err := s.scheduleRepo.RescheduleMessage(ctx, scheduled.ID, until); err != nil {
+		log.Printf("Ошибка при переносе отложенного сообщения %s: %v", scheduled.ID, err)
+		return true
+	}
+
+	log.Printf("Отложенное сообщение %s перенесено на %s: пользователю временно запрещено писать", scheduled.ID, until.Format(time.RFC3339))
+
+	return true
+}
@@ -0,0 +1,127 @@
+package chat_service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+)
+
+var ErrInvalidSettings = errors.New("некорректные настройки чата")
+
+// SettingsUpdate описывает изменение персональных настроек чата.
+// Незаполненные поля оставляют соответствующую настройку без изменений
+type SettingsUpdate struct {
+	MutedUntil  *time.Time // Время в прошлом снимает заглушение
+	NotifyLevel string
+	Hidden      *bool
+}
+
+// UpdateChatSettings изменяет персональные настройки чата участника
+func (s *ChatService) UpdateChatSettings(ctx context.Context, chatID, userID string, update SettingsUpdate) (*models.ParticipantSettings, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	settings, err := s.getParticipantSettings(ctx, chatID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.MutedUntil != nil {
+		if update.MutedUntil.After(time.Now()) {
+			mutedUntil := *update.MutedUntil
+			settings.MutedUntil = &mutedUntil
+		} else {
+			settings.MutedUntil = nil
+		}
+	}
+
+	switch update.NotifyLevel {
+	case "":
+	case models.NotifyAll, models.NotifyMentions:
+		settings.NotifyLevel = update.NotifyLevel
+	default:
+		return nil, ErrInvalidSettings
+	}
+
+	if update.Hidden != nil {
+		settings.Hidden = *update.Hidden
+	}
+
+	if err := s.chatRepo.UpdateParticipantSettings(ctx, settings); err != nil {
+		if errors.Is(err, repository.ErrUserNotInChat) {
+			return nil, ErrUserNotInChat
+		}
+		return nil, err
+	}
+
+	log.Printf("Пользователь %s обновил настройки чата %s", userID, chatID)
+
+	return settings, nil
+}
+
+// GetUnreadCounters возвращает счетчики непрочитанных сообщений по чатам пользователя
+// с учетом его настроек: скрытые чаты не возвращаются, а в заглушенных чатах
+// и чатах с уведомлениями только об упоминаниях непрочитанными считаются лишь упоминания
+func (s *ChatService) GetUnreadCounters(ctx context.Context, userID string) ([]*models.UnreadCounter, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	username, err := s.authClient.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("Не удалось получить имя пользователя %s: %v, упоминания не учитываются", userID, err)
+		username = ""
+	}
+
+	counters, err := s.chatRepo.GetUnreadCounters(ctx, userID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]*models.UnreadCounter, 0, len(counters))
+	for _, counter := range counters {
+		if counter.Hidden {
+			continue
+		}
+
+		if username == "" {
+			counter.Mentions = 0
+		}
+
+		if !counter.ShouldNotify(now, false) {
+			counter.Unread = counter.Mentions
+		}
+
+		result = append(result, counter)
+	}
+
+	return result, nil
+}
+
+// MarkChatRead отмечает все сообщения чата как прочитанные пользователем
+func (s *ChatService) MarkChatRead(ctx context.Context, chatID, userID string) error {
+	if chatID == "" {
+		return ErrInvalidChatID
+	}
+
+	return s.chatRepo.MarkRead(ctx, chatID, userID, time.Now())
+}
+
+// getParticipantSettings возвращает настройки участника, приводя ошибку к ErrUserNotInChat
+func (s *ChatService) getParticipantSettings(ctx context.Context, chatID, userID string) (*models.ParticipantSettings, error) {
+	settings, err := s.chatRepo.GetParticipantSettings(ctx, chatID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotInChat) {
+			return nil, ErrUserNotInChat
+		}
+		return nil, err
+	}
+
+	return settings, nil
+}
@@ -7,22 +7,30 @@ import (
 	"github.com/google/uuid"
 )
 
-// SubscriptionManager управляет подписками на обновления чатов
+// subscription представляет подписку пользователя на обновления чата
+type subscription struct {
+	userID      string
+	messageChan chan *models.Message
+}
+
+// SubscriptionManager управляет подписками на обновления чатов и на уведомления пользователей
 type SubscriptionManager struct {
-	subscriptions map[string]map[string]chan *models.Message // map[chatID]map[subscriptionID]channel
+	subscriptions map[string]map[string]*subscription             // map[chatID]map[subscriptionID]subscription
+	notifications map[string]map[string]chan *models.Notification // map[userID]map[subscriptionID]канал уведомлений
 	mutex         sync.RWMutex
 }
 
 // NewSubscriptionManager создает новый менеджер подписок
 func NewSubscriptionManager() *SubscriptionManager {
 	return &SubscriptionManager{
-		subscriptions: make(map[string]map[string]chan *models.Message),
+		subscriptions: make(map[string]map[string]*subscription),
+		notifications: make(map[string]map[string]chan *models.Notification),
 	}
 }
 
-// Subscribe создает новую подписку на обновления чата
+// Subscribe создает новую подписку пользователя на обновления чата
 // Возвращает канал для получения сообщений и ID подписки
-func (m *SubscriptionManager) Subscribe(chatID string) (chan *models.Message, string) {
+func (m *SubscriptionManager) Subscribe(chatID, userID string) (chan *models.Message, string) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -34,11 +42,11 @@ func (m *SubscriptionManager) Subscribe(chatID string) (chan *models.Message, st
 
 	// Проверяем, существует ли уже мапа подписок для этого чата
 	if _, ok := m.subscriptions[chatID]; !ok {
-		m.subscriptions[chatID] = make(map[string]chan *models.Message)
+		m.subscriptions[chatID] = make(map[string]*subscription)
 	}
 
 	// Добавляем подписку
-	m.subscriptions[chatID][subscriptionID] = messageChan
+	m.subscriptions[chatID][subscriptionID] = &subscription{userID: userID, messageChan: messageChan}
 
 	return messageChan, subscriptionID
 }
@@ -51,9 +59,9 @@ func (m *SubscriptionManager) Unsubscribe(chatID, subscriptionID string) {
 	// Проверяем, существует ли карта подписок для этого чата
 	if chatSubscriptions, ok := m.subscriptions[chatID]; ok {
 		// Проверяем, существует ли подписка
-		if messageChan, ok := chatSubscriptions[subscriptionID]; ok {
+		if sub, ok := chatSubscriptions[subscriptionID]; ok {
 			// Закрываем канал
-			close(messageChan)
+			close(sub.messageChan)
 			// Удаляем подписку
 			delete(chatSubscriptions, subscriptionID)
 		}
@@ -65,7 +73,8 @@ func (m *SubscriptionManager) Unsubscribe(chatID, subscriptionID string) {
 	}
 }
 
-// PublishMessage отправляет сообщение всем подписчикам чата
+// PublishMessage отправляет сообщение всем подписчикам чата.
+// Если у сообщения задан RecipientID, оно доставляется только подпискам этого пользователя
 func (m *SubscriptionManager) PublishMessage(chatID string, message *models.Message) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
@@ -73,10 +82,13 @@ func (m *SubscriptionManager) PublishMessage(chatID string, message *models.Mess
 	// Проверяем, есть ли подписчики для этого чата
 	if chatSubscriptions, ok := m.subscriptions[chatID]; ok {
 		// Отправляем сообщение всем подписчикам
-		for _, messageChan := range chatSubscriptions {
+		for _, sub := range chatSubscriptions {
+			if message.RecipientID != "" && sub.userID != message.RecipientID {
+				continue
+			}
 			// Используем неблокирующую отправку, чтобы не зависать, если канал полон
 			select {
-			case messageChan <- message:
+			case sub.messageChan <- message:
 				// Сообщение успешно отправлено
 			default:
 				// Канал полон, пропускаем отправку
@@ -85,6 +97,116 @@ func (m *SubscriptionManager) PublishMessage(chatID string, message *models.Mess
 	}
 }
 
+// DisconnectUser закрывает все подписки пользователя на обновления чата
+func (m *SubscriptionManager) DisconnectUser(chatID, userID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	chatSubscriptions, ok := m.subscriptions[chatID]
+	if !ok {
+		return
+	}
+
+	for subscriptionID, sub := range chatSubscriptions {
+		if sub.userID == userID {
+			close(sub.messageChan)
+			delete(chatSubscriptions, subscriptionID)
+		}
+	}
+
+	if len(chatSubscriptions) == 0 {
+		delete(m.subscriptions, chatID)
+	}
+}
+
+// HasSubscriber проверяет, подписан ли пользователь на обновления чата
+func (m *SubscriptionManager) HasSubscriber(chatID, userID string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, sub := range m.subscriptions[chatID] {
+		if sub.userID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SubscribeNotifications создает подписку пользователя на уведомления по всем его чатам.
+// Возвращает канал для получения уведомлений и ID подписки
+func (m *SubscriptionManager) SubscribeNotifications(userID string) (chan *models.Notification, string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	notificationChan := make(chan *models.Notification, 100)
+	subscriptionID := generateSubscriptionID()
+
+	if _, ok := m.notifications[userID]; !ok {
+		m.notifications[userID] = make(map[string]chan *models.Notification)
+	}
+	m.notifications[userID][subscriptionID] = notificationChan
+
+	return notificationChan, subscriptionID
+}
+
+// UnsubscribeNotifications отменяет подписку на уведомления
+func (m *SubscriptionManager) UnsubscribeNotifications(userID, subscriptionID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	userSubscriptions, ok := m.notifications[userID]
+	if !ok {
+		return
+	}
+
+	if notificationChan, ok := userSubscriptions[subscriptionID]; ok {
+		close(notificationChan)
+		delete(userSubscriptions, subscriptionID)
+	}
+
+	if len(userSubscriptions) == 0 {
+		delete(m.notifications, userID)
+	}
+}
+
+// Notify отправляет уведомление во все подписки пользователя
+func (m *SubscriptionManager) Notify(userID string, notification *models.Notification) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, notificationChan := range m.notifications[userID] {
+		// Используем неблокирующую отправку, чтобы медленный клиент не задерживал рассылку
+		select {
+		case notificationChan <- notification:
+		default:
+		}
+	}
+}
+
+// NotificationSubscribers возвращает пользователей из userIDs, подписанных на уведомления
+func (m *SubscriptionManager) NotificationSubscribers(userIDs []string) []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var subscribers []string
+	for _, userID := range userIDs {
+		if len(m.notifications[userID]) > 0 {
+			subscribers = append(subscribers, userID)
+		}
+	}
+
+	return subscribers
+}
+
+// HasNotificationSubscribers проверяет, есть ли хотя бы одна подписка на уведомления
+func (m *SubscriptionManager) HasNotificationSubscribers() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return len(m.notifications) > 0
+}
+
 // generateSubscriptionID генерирует уникальный ID подписки
 func generateSubscriptionID() string {
 	// Для простоты используем текущее время в наносекундах
@@ -0,0 +1,143 @@
+package chat_service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+)
+
+// Ограничения журнала доставки
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 500
+)
+
+var (
+	ErrWebhookNotFound = errors.New("вебхук не найден")
+	ErrInvalidWebhook  = errors.New("URL вебхука должен быть внешним http или https адресом")
+)
+
+// WebhookNotifier доставляет новые сообщения на исходящие вебхуки чатов
+type WebhookNotifier interface {
+	// Notify ставит сообщение в очередь доставки на все вебхуки его чата
+	Notify(ctx context.Context, message *models.Message)
+	// CheckURL проверяет, что на адрес можно доставлять вебхуки
+	CheckURL(rawURL string) error
+}
+
+// CreateWebhook создает исходящий вебхук чата. Доступно только администраторам чата
+func (s *ChatService) CreateWebhook(ctx context.Context, chatID, userID, rawURL string) (*models.Webhook, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	if err := s.webhooks.CheckURL(rawURL); err != nil {
+		log.Printf("Отклонен адрес вебхука %q: %v", rawURL, err)
+		return nil, ErrInvalidWebhook
+	}
+
+	if err := s.requireAdmin(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		ChatID:      chatID,
+		URL:         rawURL,
+		Secret:      secret,
+		CreatedByID: userID,
+	}
+
+	if err := s.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		log.Printf("Ошибка при создании вебхука: %v", err)
+		return nil, err
+	}
+
+	log.Printf("Пользователь %s создал вебхук %s для чата %s", userID, webhook.ID, chatID)
+
+	return webhook, nil
+}
+
+// ListWebhooks возвращает вебхуки чата. Доступно только администраторам чата
+func (s *ChatService) ListWebhooks(ctx context.Context, chatID, userID string) ([]*models.Webhook, error) {
+	if chatID == "" {
+		return nil, ErrInvalidChatID
+	}
+
+	if err := s.requireAdmin(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepo.GetChatWebhooks(ctx, chatID)
+}
+
+// DeleteWebhook удаляет вебхук. Доступно только администраторам чата
+func (s *ChatService) DeleteWebhook(ctx context.Context, webhookID, userID string) error {
+	webhook, err := s.getWebhookAsAdmin(ctx, webhookID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.DeleteWebhook(ctx, webhook.ID); err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+
+	log.Printf("Пользователь %s удалил вебхук %s чата %s", userID, webhook.ID, webhook.ChatID)
+
+	return nil
+}
+
+// ListWebhookDeliveries возвращает последние попытки доставки на вебхук. Доступно только администраторам чата
+func (s *ChatService) ListWebhookDeliveries(ctx context.Context, webhookID, userID string, limit int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.getWebhookAsAdmin(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+	if limit > maxDeliveriesLimit {
+		limit = maxDeliveriesLimit
+	}
+
+	return s.webhookRepo.GetDeliveries(ctx, webhookID, limit)
+}
+
+// getWebhookAsAdmin возвращает вебхук, если пользователь является администратором его чата
+func (s *ChatService) getWebhookAsAdmin(ctx context.Context, webhookID, userID string) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, webhook.ChatID, userID); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// generateWebhookSecret генерирует секрет для подписи запросов вебхука
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,276 @@
+package markdown
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"chat.service/internal/models"
+)
+
+// Ограничения разметки
+const (
+	MaxEntities  = 100
+	maxURLLength = 2048
+)
+
+var (
+	ErrInvalidText     = errors.New("текст сообщения содержит некорректные символы")
+	ErrInvalidLink     = errors.New("некорректная ссылка в сообщении")
+	ErrTooManyEntities = errors.New("слишком много элементов разметки в сообщении")
+)
+
+// Parse разбирает текст в формате markdown и возвращает текст без разметки вместе с фрагментами разметки.
+//
+// Поддерживается **жирный текст**, `код`, [ссылка](https://example.com) и упоминания @username.
+// Обратный слэш экранирует следующий символ разметки, незакрытая разметка остается в тексте как есть.
+// Смещения фрагментов считаются в единицах UTF-16 относительно возвращаемого текста
+func Parse(src string) (string, []models.MessageEntity, error) {
+	if !utf8.ValidString(src) {
+		return "", nil, ErrInvalidText
+	}
+
+	p := &parser{src: []rune(src)}
+	if err := p.parse(); err != nil {
+		return "", nil, err
+	}
+
+	if len(p.entities) > MaxEntities {
+		return "", nil, ErrTooManyEntities
+	}
+
+	// Внешний фрагмент идет раньше вложенного, начинающегося с той же позиции
+	sort.SliceStable(p.entities, func(i, j int) bool {
+		if p.entities[i].Offset != p.entities[j].Offset {
+			return p.entities[i].Offset < p.entities[j].Offset
+		}
+		return p.entities[i].Length > p.entities[j].Length
+	})
+
+	return p.out.String(), p.entities, nil
+}
+
+// parser хранит состояние разбора одного сообщения
+type parser struct {
+	src      []rune
+	out      strings.Builder
+	pos      int // Текущая позиция в выходном тексте в единицах UTF-16
+	entities []models.MessageEntity
+}
+
+func (p *parser) parse() error {
+	boldStart := -1 // Начало открытого жирного текста в единицах UTF-16
+	boldByte := 0   // То же начало в байтах выходного текста
+
+	for i := 0; i < len(p.src); {
+		r := p.src[i]
+
+		switch {
+		case r == '\\' && i+1 < len(p.src) && isMarkup(p.src[i+1]):
+			p.emit(p.src[i+1])
+			i += 2
+
+		case r == '`':
+			end := p.find(i+1, "`")
+			if end <= i+1 {
+				p.emit(r)
+				i++
+				continue
+			}
+
+			start := p.pos
+			p.emitAll(p.src[i+1 : end])
+			p.add(models.EntityCode, start, "")
+			i = end + 1
+
+		case r == '*' && p.hasPrefix(i, "**"):
+			if boldStart >= 0 {
+				if p.pos > boldStart {
+					p.add(models.EntityBold, boldStart, "")
+				} else {
+					// Пустой жирный текст остается в сообщении как есть
+					p.emitAll([]rune("****"))
+				}
+				boldStart = -1
+			} else if p.find(i+2, "**") >= 0 {
+				boldStart, boldByte = p.pos, p.out.Len()
+			} else {
+				p.emitAll(p.src[i : i+2])
+			}
+			i += 2
+
+		case r == '[':
+			next, ok, err := p.parseLink(i)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				p.emit(r)
+				i++
+				continue
+			}
+			i = next
+
+		case r == '@' && (i == 0 || !isUsernameRune(p.src[i-1])):
+			end := i + 1
+			for end < len(p.src) && isUsernameRune(p.src[end]) {
+				end++
+			}
+
+			start := p.pos
+			p.emitAll(p.src[i:end])
+			if end > i+1 {
+				p.add(models.EntityMention, start, "")
+			}
+			i = end
+
+		default:
+			p.emit(r)
+			i++
+		}
+	}
+
+	// Закрывающий маркер мог оказаться внутри кода или ссылки, тогда открывающий остается в тексте
+	if boldStart >= 0 {
+		p.insert(boldByte, boldStart, "**")
+	}
+
+	return nil
+}
+
+// parseLink разбирает ссылку вида [текст](url), начинающуюся с позиции i.
+// Возвращает позицию после ссылки и false, если в этой позиции нет ссылки
+func (p *parser) parseLink(i int) (int, bool, error) {
+	textEnd := p.find(i+1, "](")
+	if textEnd < 0 {
+		return 0, false, nil
+	}
+
+	urlEnd := p.find(textEnd+2, ")")
+	if urlEnd < 0 {
+		return 0, false, nil
+	}
+
+	text := p.src[i+1 : textEnd]
+	rawURL := string(p.src[textEnd+2 : urlEnd])
+	if containsLineBreak(text) || strings.ContainsAny(rawURL, " \t\r\n") {
+		return 0, false, nil
+	}
+
+	if err := validateURL(rawURL); err != nil {
+		return 0, false, err
+	}
+
+	// Ссылка без текста отображается своим адресом
+	if len(text) == 0 {
+		text = []rune(rawURL)
+	}
+
+	start := p.pos
+	p.emitAll(text)
+	p.add(models.EntityLink, start, rawURL)
+
+	return urlEnd + 1, true, nil
+}
+
+// emit добавляет символ в выходной текст
+func (p *parser) emit(r rune) {
+	p.out.WriteRune(r)
+	p.pos += utf16.RuneLen(r)
+}
+
+// emitAll добавляет символы в выходной текст без разбора разметки
+func (p *parser) emitAll(runes []rune) {
+	for _, r := range runes {
+		p.emit(r)
+	}
+}
+
+// insert вставляет text в уже сформированный выходной текст и сдвигает фрагменты,
+// начинающиеся не раньше места вставки. byteOffset и pos задают место вставки в байтах и в UTF-16
+func (p *parser) insert(byteOffset, pos int, text string) {
+	out := p.out.String()
+	p.out.Reset()
+	p.out.WriteString(out[:byteOffset])
+	p.out.WriteString(text)
+	p.out.WriteString(out[byteOffset:])
+
+	shift := len(utf16.Encode([]rune(text)))
+	p.pos += shift
+	for i := range p.entities {
+		if p.entities[i].Offset >= pos {
+			p.entities[i].Offset += shift
+		}
+	}
+}
+
+// add добавляет фрагмент разметки от start до текущей позиции
+func (p *parser) add(entityType string, start int, url string) {
+	p.entities = append(p.entities, models.MessageEntity{
+		Type:   entityType,
+		Offset: start,
+		Length: p.pos - start,
+		URL:    url,
+	})
+}
+
+// find возвращает позицию первого вхождения token начиная с from или -1
+func (p *parser) find(from int, token string) int {
+	for i := from; i < len(p.src); i++ {
+		if p.hasPrefix(i, token) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// hasPrefix проверяет, начинается ли текст с позиции i с token
+func (p *parser) hasPrefix(i int, token string) bool {
+	for _, r := range token {
+		if i >= len(p.src) || p.src[i] != r {
+			return false
+		}
+		i++
+	}
+
+	return true
+}
+
+// validateURL проверяет, что ссылка ведет на http(s) адрес
+func validateURL(rawURL string) error {
+	if len(rawURL) > maxURLLength {
+		return ErrInvalidLink
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidLink
+	}
+
+	return nil
+}
+
+// isMarkup проверяет, является ли символ частью синтаксиса разметки
+func isMarkup(r rune) bool {
+	return strings.ContainsRune("\\*`[]()@", r)
+}
+
+// isUsernameRune проверяет, может ли символ входить в имя пользователя в упоминании
+func isUsernameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func containsLineBreak(runes []rune) bool {
+	for _, r := range runes {
+		if r == '\n' || r == '\r' {
+			return true
+		}
+	}
+
+	return false
+}
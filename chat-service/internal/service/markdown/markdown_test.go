@@ -0,0 +1,169 @@
+package markdown
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"chat.service/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		text     string
+		entities []models.MessageEntity
+	}{
+		{
+			name: "plain text",
+			src:  "hello",
+			text: "hello",
+		},
+		{
+			name: "bold",
+			src:  "a **b** c",
+			text: "a b c",
+			entities: []models.MessageEntity{
+				{Type: models.EntityBold, Offset: 2, Length: 1},
+			},
+		},
+		{
+			name: "code inside bold",
+			src:  "**see `x`**",
+			text: "see x",
+			entities: []models.MessageEntity{
+				{Type: models.EntityBold, Offset: 0, Length: 5},
+				{Type: models.EntityCode, Offset: 4, Length: 1},
+			},
+		},
+		{
+			name: "link and mention inside bold",
+			src:  "**[site](https://example.com) @bob**",
+			text: "site @bob",
+			entities: []models.MessageEntity{
+				{Type: models.EntityBold, Offset: 0, Length: 9},
+				{Type: models.EntityLink, Offset: 0, Length: 4, URL: "https://example.com"},
+				{Type: models.EntityMention, Offset: 5, Length: 4},
+			},
+		},
+		{
+			name: "markup inside code is literal",
+			src:  "`**a**`",
+			text: "**a**",
+			entities: []models.MessageEntity{
+				{Type: models.EntityCode, Offset: 0, Length: 5},
+			},
+		},
+		{
+			name: "unclosed bold",
+			src:  "**a",
+			text: "**a",
+		},
+		{
+			name: "bold closed only inside code",
+			src:  "**a `**`",
+			text: "**a **",
+			entities: []models.MessageEntity{
+				{Type: models.EntityCode, Offset: 4, Length: 2},
+			},
+		},
+		{
+			name: "bold closed only inside a link",
+			src:  "x **y [**](https://example.com)",
+			text: "x **y **",
+			entities: []models.MessageEntity{
+				{Type: models.EntityLink, Offset: 6, Length: 2, URL: "https://example.com"},
+			},
+		},
+		{
+			name: "empty bold",
+			src:  "****",
+			text: "****",
+		},
+		{
+			name: "unclosed code",
+			src:  "`a",
+			text: "`a",
+		},
+		{
+			name: "unclosed link",
+			src:  "[a](https://example.com",
+			text: "[a](https://example.com",
+		},
+		{
+			name: "escaped markup",
+			src:  `\*\*a\*\* \@bob`,
+			text: "**a** @bob",
+		},
+		{
+			name: "email is not a mention",
+			src:  "alice@example.com",
+			text: "alice@example.com",
+		},
+		{
+			name: "offsets after a surrogate pair",
+			src:  "😀 **b** @bob",
+			text: "😀 b @bob",
+			entities: []models.MessageEntity{
+				{Type: models.EntityBold, Offset: 3, Length: 1},
+				{Type: models.EntityMention, Offset: 5, Length: 4},
+			},
+		},
+		{
+			name: "surrogate pair inside an entity",
+			src:  "**a😀b**",
+			text: "a😀b",
+			entities: []models.MessageEntity{
+				{Type: models.EntityBold, Offset: 0, Length: 4},
+			},
+		},
+		{
+			name: "unclosed bold after a surrogate pair",
+			src:  "😀**a `**` 😀`c`",
+			text: "😀**a ** 😀c",
+			entities: []models.MessageEntity{
+				{Type: models.EntityCode, Offset: 6, Length: 2},
+				{Type: models.EntityCode, Offset: 11, Length: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, entities, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.src, err)
+			}
+			if text != tt.text {
+				t.Errorf("text = %q, want %q", text, tt.text)
+			}
+			if len(entities) == 0 && len(tt.entities) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(entities, tt.entities) {
+				t.Errorf("entities = %+v, want %+v", entities, tt.entities)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want error
+	}{
+		{"invalid UTF-8", "a\xffb", ErrInvalidText},
+		{"link to a non-http scheme", "[a](javascript:alert(1))", ErrInvalidLink},
+		{"link without a host", "[a](https://)", ErrInvalidLink},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Parse(tt.src); !errors.Is(err, tt.want) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.src, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"chat.service/internal/models"
+)
+
+// Action решение фильтра модерации о сообщении
+type Action int
+
+const (
+	// Allow пропускает сообщение
+	Allow Action = iota
+	// Flag отправляет сообщение на проверку администратору чата
+	Flag
+	// Reject отклоняет сообщение
+	Reject
+)
+
+// ParseAction разбирает название решения: allow, flag или reject
+func ParseAction(value string) (Action, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "allow", "":
+		return Allow, nil
+	case "flag":
+		return Flag, nil
+	case "reject":
+		return Reject, nil
+	default:
+		return Allow, fmt.Errorf("неизвестное решение модерации %q", value)
+	}
+}
+
+// Verdict результат проверки сообщения фильтром
+type Verdict struct {
+	Action Action
+	Reason string // Причина для отклоненных и отмеченных сообщений
+}
+
+// Allowed возвращает решение, пропускающее сообщение
+func Allowed() Verdict {
+	return Verdict{Action: Allow}
+}
+
+// linkPattern находит ссылки в тексте сообщения, в том числе без схемы
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// BannedWordsFilter находит в сообщении запрещенные слова. Слова сравниваются без учета регистра
+type BannedWordsFilter struct {
+	words  map[string]bool
+	action Action
+}
+
+// NewBannedWordsFilter создает фильтр запрещенных слов с решением action для найденных слов
+func NewBannedWordsFilter(words []string, action Action) *BannedWordsFilter {
+	filter := &BannedWordsFilter{words: make(map[string]bool, len(words)), action: action}
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			filter.words[word] = true
+		}
+	}
+
+	return filter
+}
+
+func (f *BannedWordsFilter) Check(_ context.Context, message *models.Message) Verdict {
+	words := strings.FieldsFunc(strings.ToLower(message.Text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if f.words[word] {
+			return Verdict{Action: f.action, Reason: "сообщение содержит запрещенные слова"}
+		}
+	}
+
+	return Allowed()
+}
+
+// MaxLengthFilter отклоняет сообщения длиннее заданного числа символов
+type MaxLengthFilter struct {
+	limit int
+}
+
+func NewMaxLengthFilter(limit int) *MaxLengthFilter {
+	return &MaxLengthFilter{limit: limit}
+}
+
+func (f *MaxLengthFilter) Check(_ context.Context, message *models.Message) Verdict {
+	if utf8.RuneCountInString(message.Text) > f.limit {
+		return Verdict{Action: Reject, Reason: fmt.Sprintf("сообщение длиннее %d символов", f.limit)}
+	}
+
+	return Allowed()
+}
+
+// LinkFilter применяет решение action к сообщениям со ссылками в тексте или в разметке
+type LinkFilter struct {
+	action Action
+}
+
+func NewLinkFilter(action Action) *LinkFilter {
+	return &LinkFilter{action: action}
+}
+
+func (f *LinkFilter) Check(_ context.Context, message *models.Message) Verdict {
+	hasLink := linkPattern.MatchString(message.Text)
+	for _, entity := range message.Entities {
+		if entity.Type == models.EntityLink {
+			hasLink = true
+		}
+	}
+
+	if hasLink {
+		return Verdict{Action: f.action, Reason: "ссылки в сообщениях запрещены"}
+	}
+
+	return Allowed()
+}
+
+// SpamFilter отклоняет сообщение, если пользователь уже отправил такой же текст в чат
+// repeats раз за последние window. История хранится в памяти процесса
+type SpamFilter struct {
+	repeats int
+	window  time.Duration
+
+	mu        sync.Mutex
+	history   map[string][]sentText // Ключ - чат и пользователь
+	lastSweep time.Time
+}
+
+type sentText struct {
+	text   string
+	sentAt time.Time
+}
+
+func NewSpamFilter(repeats int, window time.Duration) *SpamFilter {
+	return &SpamFilter{
+		repeats: repeats,
+		window:  window,
+		history: make(map[string][]sentText),
+	}
+}
+
+func (f *SpamFilter) Check(_ context.Context, message *models.Message) Verdict {
+	now := time.Now()
+	key := message.ChatID + "/" + message.UserID
+	text := strings.ToLower(strings.Join(strings.Fields(message.Text), " "))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sweep(now)
+
+	recent := f.history[key][:0]
+	repeated := 0
+	for _, sent := range f.history[key] {
+		if now.Sub(sent.sentAt) > f.window {
+			continue
+		}
+		recent = append(recent, sent)
+		if sent.text == text {
+			repeated++
+		}
+	}
+
+	if repeated >= f.repeats {
+		f.history[key] = recent
+		return Verdict{Action: Reject, Reason: "слишком много одинаковых сообщений, подождите перед повторной отправкой"}
+	}
+
+	f.history[key] = append(recent, sentText{text: text, sentAt: now})
+
+	return Allowed()
+}
+
+// sweep удаляет историю пользователей, не писавших дольше window, чтобы она не накапливалась
+func (f *SpamFilter) sweep(now time.Time) {
+	if now.Sub(f.lastSweep) < f.window {
+		return
+	}
+	f.lastSweep = now
+
+	for key, sent := range f.history {
+		if len(sent) == 0 || now.Sub(sent[len(sent)-1].sentAt) > f.window {
+			delete(f.history, key)
+		}
+	}
+}
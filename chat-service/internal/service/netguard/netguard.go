@@ -0,0 +1,87 @@
+// Package netguard запрещает исходящие соединения сервиса с внутренней сетью.
+// Используется для запросов по адресам, которые задают пользователи: превью ссылок и исходящих вебхуков
+package netguard
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress означает, что адрес указывает на loopback, внутреннюю или служебную сеть
+var ErrBlockedAddress = errors.New("адрес указывает на внутреннюю сеть")
+
+// blockedNetworks внутренние сети, не попадающие под net.IP.IsPrivate
+var blockedNetworks = []*net.IPNet{
+	mustParseCIDR("100.64.0.0/10"), // Carrier-grade NAT
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+}
+
+// NewDialer создает dialer с таймаутом, который не устанавливает соединения с внутренними адресами.
+// Адрес проверяется после DNS разрешения, поэтому блокировку нельзя обойти доменом, указывающим во внутреннюю сеть.
+// allowPrivate отключает проверку, например для httptest сервера или локальной разработки
+func NewDialer(timeout time.Duration, allowPrivate bool) *net.Dialer {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = blockPrivateAddress
+	}
+
+	return dialer
+}
+
+// CheckHost заранее отклоняет хост, который заведомо указывает во внутреннюю сеть: localhost или IP адрес
+// внутренней сети. Доменные имена проверяются только при установке соединения
+func CheckHost(host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrBlockedAddress
+	}
+
+	if ip := net.ParseIP(host); ip != nil && IsBlockedIP(ip) {
+		return ErrBlockedAddress
+	}
+
+	return nil
+}
+
+// IsBlockedIP проверяет, относится ли адрес к внутренней или служебной сети
+func IsBlockedIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// blockPrivateAddress запрещает соединения с loopback, внутренними и служебными адресами
+func blockPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || IsBlockedIP(ip) {
+		return ErrBlockedAddress
+	}
+
+	return nil
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
@@ -0,0 +1,41 @@
+package netguard
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestIsBlockedIP(t *testing.T) {
+	blocked := []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1",
+		"192.0.0.8", "198.18.0.1", "0.0.0.0", "224.0.0.1", "::1", "fe80::1", "fc00::1", "::", "::ffff:10.0.0.1",
+	}
+	for _, address := range blocked {
+		if !IsBlockedIP(net.ParseIP(address)) {
+			t.Errorf("%s must be blocked", address)
+		}
+	}
+
+	public := []string{"93.184.216.34", "8.8.8.8", "2606:2800:220:1:248:1893:25c8:1946"}
+	for _, address := range public {
+		if IsBlockedIP(net.ParseIP(address)) {
+			t.Errorf("%s must be allowed", address)
+		}
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	for _, host := range []string{"localhost", "LOCALHOST.", "api.localhost", "127.0.0.1", "::1", "10.0.0.1"} {
+		if err := CheckHost(host); !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("CheckHost(%q) = %v, want ErrBlockedAddress", host, err)
+		}
+	}
+
+	// Доменные имена проверяются при соединении
+	for _, host := range []string{"example.com", "internal.example.com", "93.184.216.34"} {
+		if err := CheckHost(host); err != nil {
+			t.Errorf("CheckHost(%q) = %v, want nil", host, err)
+		}
+	}
+}
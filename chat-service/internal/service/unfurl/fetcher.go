@@ -0,0 +1,235 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chat.service/internal/models"
+	"chat.service/internal/service/netguard"
+	"golang.org/x/net/html"
+)
+
+// Ограничения загрузки страниц
+const (
+	DefaultTimeout     = 5 * time.Second
+	DefaultMaxBodySize = 512 << 10
+	maxRedirects       = 5
+	maxTitleLen        = 300
+	maxDescriptionLen  = 1000
+	userAgent          = "chat-service-unfurl/1.0"
+)
+
+var (
+	ErrUnsupportedURL     = errors.New("адрес ссылки не поддерживается")
+	ErrBlockedAddress     = netguard.ErrBlockedAddress
+	ErrUnsupportedContent = errors.New("страница не является HTML документом")
+	ErrNoPreview          = errors.New("на странице нет данных для превью")
+)
+
+// Config задает параметры HTTP клиента для загрузки страниц
+type Config struct {
+	Timeout time.Duration
+	// AllowPrivateNetworks разрешает запросы на loopback и внутренние адреса, например к httptest серверу
+	AllowPrivateNetworks bool
+}
+
+// DefaultConfig возвращает параметры клиента по умолчанию
+func DefaultConfig() Config {
+	return Config{Timeout: DefaultTimeout}
+}
+
+// NewClient создает HTTP клиент с таймаутом, ограничением редиректов и блокировкой внутренних адресов.
+// Адрес проверяется при установке соединения, поэтому блокировка работает и после DNS разрешения, и для редиректов
+func NewClient(cfg Config) *http.Client {
+	dialer := netguard.NewDialer(cfg.Timeout, cfg.AllowPrivateNetworks)
+
+	// Прокси из окружения не используется: проверка адреса должна выполняться для конечного сервера
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   cfg.Timeout,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return fmt.Errorf("слишком много перенаправлений")
+			}
+			if !isSupportedScheme(req.URL) {
+				return ErrUnsupportedURL
+			}
+			return nil
+		},
+	}
+}
+
+// Fetcher получает превью ссылок, загружая HTML страницы и разбирая title и Open Graph метаданные
+type Fetcher struct {
+	client      *http.Client
+	maxBodySize int64
+}
+
+// NewFetcher создает загрузчик превью. Из ответа читается не больше maxBodySize байт
+func NewFetcher(client *http.Client, maxBodySize int64) *Fetcher {
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	return &Fetcher{client: client, maxBodySize: maxBodySize}
+}
+
+// Fetch загружает страницу по адресу и возвращает ее превью
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*models.LinkPreview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !isSupportedScheme(u) || u.Host == "" {
+		return nil, ErrUnsupportedURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("страница вернула статус %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrUnsupportedContent
+	}
+
+	preview := parseHead(io.LimitReader(resp.Body, f.maxBodySize), resp.Request.URL)
+	if preview.Title == "" && preview.Description == "" {
+		return nil, ErrNoPreview
+	}
+
+	preview.URL = rawURL
+	preview.FetchedAt = time.Now()
+
+	return preview, nil
+}
+
+// parseHead разбирает заголовок HTML документа: <title>, og:title, og:description, og:image и description
+func parseHead(r io.Reader, base *url.URL) *models.LinkPreview {
+	preview := &models.LinkPreview{}
+	var title, ogTitle, description, ogDescription string
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			return finishPreview(preview, base, title, ogTitle, description, ogDescription)
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				if tokenizer.Next() == html.TextToken && title == "" {
+					title = string(tokenizer.Text())
+				}
+			case "meta":
+				key, content := metaAttributes(token)
+				switch key {
+				case "og:title":
+					ogTitle = content
+				case "og:description":
+					ogDescription = content
+				case "description":
+					description = content
+				case "og:image":
+					preview.ImageURL = content
+				}
+			case "body":
+				// Метаданные находятся в <head>, остальную страницу не читаем
+				return finishPreview(preview, base, title, ogTitle, description, ogDescription)
+			}
+
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "head" {
+				return finishPreview(preview, base, title, ogTitle, description, ogDescription)
+			}
+		}
+	}
+}
+
+// finishPreview выбирает Open Graph значения в приоритете, нормализует и обрезает их
+func finishPreview(preview *models.LinkPreview, base *url.URL, title, ogTitle, description, ogDescription string) *models.LinkPreview {
+	if ogTitle != "" {
+		title = ogTitle
+	}
+	if ogDescription != "" {
+		description = ogDescription
+	}
+
+	preview.Title = truncate(normalizeSpace(title), maxTitleLen)
+	preview.Description = truncate(normalizeSpace(description), maxDescriptionLen)
+
+	// Изображение допускается только по http(s), относительный адрес разрешается от адреса страницы
+	if preview.ImageURL != "" {
+		image, err := base.Parse(strings.TrimSpace(preview.ImageURL))
+		if err != nil || !isSupportedScheme(image) {
+			preview.ImageURL = ""
+		} else {
+			preview.ImageURL = image.String()
+		}
+	}
+
+	return preview
+}
+
+// metaAttributes возвращает имя (property или name) и содержимое тега <meta>
+func metaAttributes(token html.Token) (string, string) {
+	var key, content string
+	for _, attr := range token.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(attr.Val))
+			}
+		case "content":
+			content = attr.Val
+		}
+	}
+
+	return key, content
+}
+
+func isSupportedScheme(u *url.URL) bool {
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+func normalizeSpace(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// truncate обрезает строку до limit символов, не разрывая символы UTF-8
+func truncate(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+
+	runes := []rune(value)
+	return string(runes[:limit])
+}
@@ -0,0 +1,207 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testFetcher создает загрузчик, которому разрешены запросы к httptest серверу
+func testFetcher(maxBodySize int64) *Fetcher {
+	return NewFetcher(NewClient(Config{Timeout: DefaultTimeout, AllowPrivateNetworks: true}), maxBodySize)
+}
+
+// htmlServer отдает страницу как text/html
+func htmlServer(t *testing.T, page string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFetchPrefersOpenGraph(t *testing.T) {
+	server := htmlServer(t, `<!doctype html>
+<html><head>
+<title>Plain title</title>
+<meta name="description" content="Plain description">
+<meta property="og:title" content="  Open   Graph title ">
+<meta property="og:description" content="Open Graph description">
+<meta property="og:image" content="/images/cover.png">
+</head><body><title>Not a title</title></body></html>`)
+
+	preview, err := testFetcher(0).Fetch(context.Background(), server.URL+"/article")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if preview.Title != "Open Graph title" {
+		t.Errorf("title = %q, want the og:title with normalized spaces", preview.Title)
+	}
+	if preview.Description != "Open Graph description" {
+		t.Errorf("description = %q, want the og:description", preview.Description)
+	}
+	if preview.ImageURL != server.URL+"/images/cover.png" {
+		t.Errorf("image = %q, want the og:image resolved against the page", preview.ImageURL)
+	}
+	if preview.URL != server.URL+"/article" {
+		t.Errorf("url = %q, want the requested URL", preview.URL)
+	}
+}
+
+func TestFetchFallsBackToTitle(t *testing.T) {
+	server := htmlServer(t, `<html><head><title>Plain title</title>
+<meta name="description" content="Plain description">
+<meta property="og:image" content="javascript:alert(1)"></head></html>`)
+
+	preview, err := testFetcher(0).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if preview.Title != "Plain title" || preview.Description != "Plain description" {
+		t.Errorf("got %q / %q, want the <title> and description", preview.Title, preview.Description)
+	}
+	if preview.ImageURL != "" {
+		t.Errorf("image = %q, non-http images must be dropped", preview.ImageURL)
+	}
+}
+
+func TestFetchBodySizeLimit(t *testing.T) {
+	const limit = 1024
+	padding := `<meta name="keywords" content="` + strings.Repeat("x", 2*limit) + `">`
+
+	early := htmlServer(t, `<html><head><title>Early title</title>`+padding+`</head></html>`)
+	preview, err := testFetcher(limit).Fetch(context.Background(), early.URL)
+	if err != nil || preview.Title != "Early title" {
+		t.Fatalf("title before the limit: got %+v, %v", preview, err)
+	}
+
+	// Заголовок за пределами лимита не читается
+	late := htmlServer(t, `<html><head>`+padding+`<title>Late title</title></head></html>`)
+	if _, err := testFetcher(limit).Fetch(context.Background(), late.URL); !errors.Is(err, ErrNoPreview) {
+		t.Fatalf("title after the limit: got %v, want ErrNoPreview", err)
+	}
+}
+
+func TestFetchRejectsNonHTML(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"title": "<title>json</title>"}`)
+	}))
+	defer server.Close()
+
+	if _, err := testFetcher(0).Fetch(context.Background(), server.URL); !errors.Is(err, ErrUnsupportedContent) {
+		t.Fatalf("got %v, want ErrUnsupportedContent", err)
+	}
+}
+
+func TestFetchRejectsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<html><head><title>Not found</title></head></html>`)
+	}))
+	defer server.Close()
+
+	if _, err := testFetcher(0).Fetch(context.Background(), server.URL); err == nil {
+		t.Fatal("error pages must not produce a preview")
+	}
+}
+
+func TestFetchRejectsUnsupportedURL(t *testing.T) {
+	for _, rawURL := range []string{"ftp://example.com/file", "mailto:user@example.com", "/relative", "http://"} {
+		if _, err := testFetcher(0).Fetch(context.Background(), rawURL); !errors.Is(err, ErrUnsupportedURL) {
+			t.Errorf("Fetch(%q) = %v, want ErrUnsupportedURL", rawURL, err)
+		}
+	}
+}
+
+func TestFetchRedirectLimit(t *testing.T) {
+	// /hop/N перенаправляет на /hop/N-1, /hop/0 отдает страницу
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if n > 0 {
+			http.Redirect(w, r, "/hop/"+strconv.Itoa(n-1), http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Final</title></head></html>`)
+	}))
+	defer server.Close()
+
+	preview, err := testFetcher(0).Fetch(context.Background(), server.URL+"/hop/"+strconv.Itoa(maxRedirects))
+	if err != nil || preview.Title != "Final" {
+		t.Fatalf("%d redirects: got %+v, %v", maxRedirects, preview, err)
+	}
+
+	if _, err := testFetcher(0).Fetch(context.Background(), server.URL+"/hop/"+strconv.Itoa(maxRedirects+1)); err == nil {
+		t.Fatalf("%d redirects must fail", maxRedirects+1)
+	}
+}
+
+func TestFetchRejectsRedirectToUnsupportedScheme(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "ftp://example.com/file", http.StatusFound)
+	}))
+	defer server.Close()
+
+	if _, err := testFetcher(0).Fetch(context.Background(), server.URL); !errors.Is(err, ErrUnsupportedURL) {
+		t.Fatalf("got %v, want ErrUnsupportedURL", err)
+	}
+}
+
+func TestFetchBlocksPrivateAddresses(t *testing.T) {
+	server := htmlServer(t, `<html><head><title>Internal</title></head></html>`)
+	fetcher := NewFetcher(NewClient(DefaultConfig()), 0)
+
+	// Адрес httptest сервера и localhost разрешаются в loopback
+	port := server.URL[strings.LastIndex(server.URL, ":")+1:]
+	for _, rawURL := range []string{server.URL, "http://localhost:" + port} {
+		if _, err := fetcher.Fetch(context.Background(), rawURL); !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("Fetch(%q) = %v, want ErrBlockedAddress", rawURL, err)
+		}
+	}
+}
+
+func TestFetchBlocksRedirectToPrivateAddress(t *testing.T) {
+	internal := htmlServer(t, `<html><head><title>Internal</title></head></html>`)
+
+	// Внешний сервер перенаправляет во внутреннюю сеть, адрес проверяется при каждом соединении
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusFound)
+	}))
+	defer external.Close()
+
+	client := NewClient(DefaultConfig())
+	transport := client.Transport.(*http.Transport)
+	dial := transport.DialContext
+	externalHost := strings.TrimPrefix(external.URL, "http://")
+	allowed := NewClient(Config{Timeout: DefaultTimeout, AllowPrivateNetworks: true}).Transport.(*http.Transport).DialContext
+
+	// Соединение с "внешним" сервером проходит в обход проверки, остальные соединения проверяются как обычно
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if address == externalHost {
+			return allowed(ctx, network, address)
+		}
+		return dial(ctx, network, address)
+	}
+
+	if _, err := NewFetcher(client, 0).Fetch(context.Background(), external.URL); !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("got %v, want ErrBlockedAddress", err)
+	}
+}
@@ -0,0 +1,316 @@
+package webhook_dispatcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/repository"
+	"chat.service/internal/service/netguard"
+)
+
+// Заголовки исходящих запросов вебхуков
+const (
+	HeaderEvent     = "X-Chat-Event"
+	HeaderWebhookID = "X-Chat-Webhook-Id"
+	HeaderTimestamp = "X-Chat-Timestamp"
+	HeaderSignature = "X-Chat-Signature"
+
+	// EventMessageCreated тип события о новом сообщении
+	EventMessageCreated = "message.created"
+)
+
+var (
+	// ErrPermanentFailure означает, что получатель отклонил запрос и повторять доставку бессмысленно
+	ErrPermanentFailure = errors.New("получатель вебхука отклонил запрос")
+	// ErrInvalidURL означает, что адрес вебхука не является абсолютным http(s) URL
+	ErrInvalidURL = errors.New("адрес вебхука должен быть абсолютным http или https URL")
+)
+
+// Config задает параметры доставки вебхуков
+type Config struct {
+	Workers     int           // Количество параллельных доставок
+	QueueSize   int           // Размер очереди доставок; при переполнении новые доставки отбрасываются
+	MaxAttempts int           // Максимальное число попыток доставки одного сообщения
+	BaseBackoff time.Duration // Задержка перед второй попыткой, далее удваивается
+	MaxBackoff  time.Duration // Верхняя граница задержки между попытками
+	Timeout     time.Duration // Таймаут одного запроса
+	// AllowPrivateNetworks разрешает вебхуки на loopback и внутренние адреса, например к httptest серверу
+	AllowPrivateNetworks bool
+}
+
+// DefaultConfig возвращает параметры доставки по умолчанию
+func DefaultConfig() Config {
+	return Config{
+		Workers:     4,
+		QueueSize:   1000,
+		MaxAttempts: 5,
+		BaseBackoff: time.Second,
+		MaxBackoff:  time.Minute,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// NewClient создает HTTP клиент для доставки вебхуков с таймаутом и блокировкой внутренних адресов.
+// Перенаправления не выполняются: ответ 3xx считается отказом получателя
+func NewClient(cfg Config) *http.Client {
+	dialer := netguard.NewDialer(cfg.Timeout, cfg.AllowPrivateNetworks)
+
+	// Прокси из окружения не используется: проверка адреса должна выполняться для конечного сервера
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   cfg.Timeout,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Payload тело запроса, отправляемого на вебхук
+type Payload struct {
+	Event     string         `json:"event"`
+	WebhookID string         `json:"webhook_id"`
+	ChatID    string         `json:"chat_id"`
+	Message   PayloadMessage `json:"message"`
+}
+
+// PayloadMessage сообщение в теле запроса вебхука
+type PayloadMessage struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Text      string    `json:"text"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type job struct {
+	webhook *models.Webhook
+	message *models.Message
+}
+
+// Dispatcher доставляет новые сообщения чатов на исходящие вебхуки
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	config Config
+	jobs   chan job
+	now    func() time.Time
+}
+
+// NewDispatcher создает диспетчер вебхуков. HTTP клиент передается снаружи, чтобы подменять транспорт в тестах;
+// без него используется NewClient(config)
+func NewDispatcher(repo repository.WebhookRepository, client *http.Client, config Config) *Dispatcher {
+	if client == nil {
+		client = NewClient(config)
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+
+	return &Dispatcher{
+		repo:   repo,
+		client: client,
+		config: config,
+		jobs:   make(chan job, config.QueueSize),
+		now:    time.Now,
+	}
+}
+
+// Run запускает обработчики очереди доставок и блокируется до отмены контекста
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < d.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case j := <-d.jobs:
+					if err := d.Deliver(ctx, j.webhook, j.message); err != nil && ctx.Err() == nil {
+						log.Printf("Не удалось доставить сообщение %s на вебхук %s: %v", j.message.ID, j.webhook.ID, err)
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// CheckURL проверяет адрес вебхука: абсолютный http(s) URL, не указывающий на localhost и IP адреса внутренней сети.
+// Доменные имена, разрешающиеся во внутреннюю сеть, отклоняются клиентом при доставке
+func (d *Dispatcher) CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+
+	if !d.config.AllowPrivateNetworks {
+		return netguard.CheckHost(u.Hostname())
+	}
+
+	return nil
+}
+
+// Notify ставит в очередь доставку сообщения на все вебхуки его чата
+func (d *Dispatcher) Notify(ctx context.Context, message *models.Message) {
+	webhooks, err := d.repo.GetChatWebhooks(ctx, message.ChatID)
+	if err != nil {
+		log.Printf("Ошибка при получении вебхуков чата %s: %v", message.ChatID, err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		select {
+		case d.jobs <- job{webhook: webhook, message: message}:
+		default:
+			log.Printf("Очередь доставки вебхуков переполнена, сообщение %s не будет отправлено на вебхук %s", message.ID, webhook.ID)
+		}
+	}
+}
+
+// Deliver синхронно доставляет сообщение на вебхук, повторяя попытки с экспоненциальной задержкой.
+// Каждая попытка записывается в журнал доставки
+func (d *Dispatcher) Deliver(ctx context.Context, webhook *models.Webhook, message *models.Message) error {
+	body, err := json.Marshal(Payload{
+		Event:     EventMessageCreated,
+		WebhookID: webhook.ID,
+		ChatID:    message.ChatID,
+		Message: PayloadMessage{
+			ID:        message.ID,
+			UserID:    message.UserID,
+			Username:  message.Username,
+			Text:      message.Text,
+			Kind:      message.Kind,
+			CreatedAt: message.CreatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = d.attempt(ctx, webhook, message.ID, attempt, body)
+		if err == nil || errors.Is(err, ErrPermanentFailure) || attempt >= d.config.MaxAttempts {
+			return err
+		}
+
+		select {
+		case <-time.After(d.backoff(attempt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// attempt выполняет одну попытку доставки и записывает ее результат в журнал
+func (d *Dispatcher) attempt(ctx context.Context, webhook *models.Webhook, messageID string, attempt int, body []byte) error {
+	started := d.now()
+	statusCode, err := d.post(ctx, webhook, body, started)
+
+	delivery := &models.WebhookDelivery{
+		WebhookID:  webhook.ID,
+		MessageID:  messageID,
+		Attempt:    attempt,
+		StatusCode: statusCode,
+		Success:    err == nil,
+		DurationMs: d.now().Sub(started).Milliseconds(),
+		CreatedAt:  started,
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if saveErr := d.repo.SaveDelivery(context.WithoutCancel(ctx), delivery); saveErr != nil {
+		log.Printf("Ошибка при записи журнала доставки вебхука %s: %v", webhook.ID, saveErr)
+	}
+
+	return err
+}
+
+// post отправляет подписанный запрос и классифицирует ответ получателя
+func (d *Dispatcher) post(ctx context.Context, webhook *models.Webhook, body []byte, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPermanentFailure, err)
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, EventMessageCreated)
+	req.Header.Set(HeaderWebhookID, webhook.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if errors.Is(err, netguard.ErrBlockedAddress) {
+			return 0, fmt.Errorf("%w: %w", ErrPermanentFailure, err)
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.StatusCode, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return resp.StatusCode, fmt.Errorf("получатель ответил статусом %d", resp.StatusCode)
+	default:
+		return resp.StatusCode, fmt.Errorf("%w: статус %d", ErrPermanentFailure, resp.StatusCode)
+	}
+}
+
+// backoff возвращает задержку перед следующей попыткой после attempt неудачных
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.config.BaseBackoff << (attempt - 1)
+	if delay <= 0 || (d.config.MaxBackoff > 0 && delay > d.config.MaxBackoff) {
+		return d.config.MaxBackoff
+	}
+	return delay
+}
+
+// Sign вычисляет подпись запроса: HMAC-SHA256 от строки "<timestamp>.<body>" на секрете вебхука.
+// Получатель должен вычислить ту же подпись и сравнить ее со значением заголовка X-Chat-Signature
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
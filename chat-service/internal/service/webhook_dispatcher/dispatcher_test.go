@@ -0,0 +1,276 @@
+package webhook_dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chat.service/internal/models"
+	"chat.service/internal/service/netguard"
+)
+
+// memoryRepo хранит журнал доставки в памяти
+type memoryRepo struct {
+	mu         sync.Mutex
+	deliveries []*models.WebhookDelivery
+}
+
+func (r *memoryRepo) CreateWebhook(ctx context.Context, webhook *models.Webhook) error { return nil }
+
+func (r *memoryRepo) GetWebhook(ctx context.Context, webhookID string) (*models.Webhook, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *memoryRepo) GetChatWebhooks(ctx context.Context, chatID string) ([]*models.Webhook, error) {
+	return nil, nil
+}
+
+func (r *memoryRepo) DeleteWebhook(ctx context.Context, webhookID string) error { return nil }
+
+func (r *memoryRepo) SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *memoryRepo) GetDeliveries(ctx context.Context, webhookID string, limit int) ([]*models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*models.WebhookDelivery(nil), r.deliveries...), nil
+}
+
+func testConfig() Config {
+	return Config{
+		Workers:              1,
+		MaxAttempts:          3,
+		BaseBackoff:          time.Millisecond,
+		MaxBackoff:           5 * time.Millisecond,
+		Timeout:              5 * time.Second,
+		AllowPrivateNetworks: true,
+	}
+}
+
+func testMessage() *models.Message {
+	return &models.Message{
+		ID:        "message-1",
+		ChatID:    "chat-1",
+		UserID:    "user-1",
+		Username:  "alice",
+		Text:      "hello",
+		Kind:      models.MessageKindText,
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// statusServer отвечает статусами из списка по порядку, последний статус повторяется
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n > len(statuses) {
+			n = len(statuses)
+		}
+		w.WriteHeader(statuses[n-1])
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestDeliverSignsRequest(t *testing.T) {
+	webhook := &models.Webhook{ID: "webhook-1", ChatID: "chat-1", Secret: "secret"}
+	now := time.Date(2026, 3, 1, 12, 0, 5, 0, time.UTC)
+
+	var payload Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if err != nil || timestamp != now.Unix() {
+			t.Errorf("timestamp header = %q, want %d", r.Header.Get(HeaderTimestamp), now.Unix())
+		}
+		if got, want := r.Header.Get(HeaderSignature), Sign(webhook.Secret, timestamp, body); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if Sign("other secret", timestamp, body) == r.Header.Get(HeaderSignature) {
+			t.Error("signature must depend on the secret")
+		}
+		if r.Header.Get(HeaderEvent) != EventMessageCreated || r.Header.Get(HeaderWebhookID) != webhook.ID {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+	webhook.URL = server.URL
+
+	repo := &memoryRepo{}
+	dispatcher := NewDispatcher(repo, nil, testConfig())
+	dispatcher.now = func() time.Time { return now }
+
+	if err := dispatcher.Deliver(context.Background(), webhook, testMessage()); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+
+	if payload.Event != EventMessageCreated || payload.WebhookID != webhook.ID || payload.Message.Text != "hello" || payload.Message.Username != "alice" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestSignKnownValue(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686"
+	if got := Sign("secret", 1700000000, []byte(`{"a":1}`)); got != want {
+		t.Fatalf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestDeliverRetriesServerErrors(t *testing.T) {
+	server, calls := statusServer(t, http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK)
+	webhook := &models.Webhook{ID: "webhook-1", URL: server.URL, Secret: "secret"}
+
+	repo := &memoryRepo{}
+	if err := NewDispatcher(repo, nil, testConfig()).Deliver(context.Background(), webhook, testMessage()); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+
+	if *calls != 3 {
+		t.Fatalf("got %d requests, want 3", *calls)
+	}
+
+	wantStatuses := []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK}
+	if len(repo.deliveries) != len(wantStatuses) {
+		t.Fatalf("got %d delivery log entries, want %d", len(repo.deliveries), len(wantStatuses))
+	}
+	for i, delivery := range repo.deliveries {
+		last := i == len(wantStatuses)-1
+		if delivery.Attempt != i+1 || delivery.StatusCode != wantStatuses[i] || delivery.Success != last ||
+			delivery.WebhookID != webhook.ID || delivery.MessageID != "message-1" {
+			t.Errorf("delivery %d = %+v", i, delivery)
+		}
+		if !last && delivery.Error == "" {
+			t.Errorf("failed delivery %d has no error", i)
+		}
+	}
+}
+
+func TestDeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable)
+	webhook := &models.Webhook{ID: "webhook-1", URL: server.URL, Secret: "secret"}
+
+	repo := &memoryRepo{}
+	err := NewDispatcher(repo, nil, testConfig()).Deliver(context.Background(), webhook, testMessage())
+	if err == nil || errors.Is(err, ErrPermanentFailure) {
+		t.Fatalf("Deliver: got %v, want a temporary error", err)
+	}
+
+	if *calls != 3 || len(repo.deliveries) != 3 {
+		t.Fatalf("got %d requests and %d log entries, want 3", *calls, len(repo.deliveries))
+	}
+	for _, delivery := range repo.deliveries {
+		if delivery.Success || delivery.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("unexpected delivery %+v", delivery)
+		}
+	}
+}
+
+func TestDeliverDoesNotRetryRejected(t *testing.T) {
+	for _, code := range []int{http.StatusBadRequest, http.StatusFound} {
+		t.Run(strconv.Itoa(code), func(t *testing.T) {
+			server, calls := statusServer(t, code)
+			webhook := &models.Webhook{ID: "webhook-1", URL: server.URL, Secret: "secret"}
+
+			repo := &memoryRepo{}
+			err := NewDispatcher(repo, nil, testConfig()).Deliver(context.Background(), webhook, testMessage())
+			if !errors.Is(err, ErrPermanentFailure) {
+				t.Fatalf("Deliver: got %v, want ErrPermanentFailure", err)
+			}
+			if *calls != 1 || len(repo.deliveries) != 1 || repo.deliveries[0].StatusCode != code {
+				t.Fatalf("got %d requests, log %+v", *calls, repo.deliveries)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher := NewDispatcher(&memoryRepo{}, nil, Config{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, delay := range want {
+		if got := dispatcher.backoff(i + 1); got != delay {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, delay)
+		}
+	}
+
+	// Сдвиг за пределы int64 не должен давать отрицательную задержку
+	if got := dispatcher.backoff(80); got != 5*time.Second {
+		t.Errorf("backoff(80) = %s, want the maximum", got)
+	}
+}
+
+func TestDeliverBlocksPrivateAddresses(t *testing.T) {
+	server, calls := statusServer(t, http.StatusOK)
+	webhook := &models.Webhook{ID: "webhook-1", URL: server.URL, Secret: "secret"}
+
+	config := testConfig()
+	config.AllowPrivateNetworks = false
+
+	repo := &memoryRepo{}
+	err := NewDispatcher(repo, nil, config).Deliver(context.Background(), webhook, testMessage())
+	if !errors.Is(err, ErrPermanentFailure) || !errors.Is(err, netguard.ErrBlockedAddress) {
+		t.Fatalf("Deliver: got %v, want a permanent blocked address failure", err)
+	}
+	if *calls != 0 || len(repo.deliveries) != 1 {
+		t.Fatalf("got %d requests and %d log entries, want 0 and 1", *calls, len(repo.deliveries))
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	dispatcher := NewDispatcher(&memoryRepo{}, nil, DefaultConfig())
+
+	valid := []string{"https://example.com/hook", "http://93.184.216.34:8080/hook"}
+	for _, rawURL := range valid {
+		if err := dispatcher.CheckURL(rawURL); err != nil {
+			t.Errorf("CheckURL(%q) = %v, want nil", rawURL, err)
+		}
+	}
+
+	invalid := []string{
+		"ftp://example.com/hook",
+		"/hook",
+		"http://localhost:8080/hook",
+		"http://api.localhost/hook",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.5/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://100.64.0.1/hook",
+	}
+	for _, rawURL := range invalid {
+		if err := dispatcher.CheckURL(rawURL); err == nil {
+			t.Errorf("CheckURL(%q) = nil, want an error", rawURL)
+		}
+	}
+
+	config := DefaultConfig()
+	config.AllowPrivateNetworks = true
+	if err := NewDispatcher(&memoryRepo{}, nil, config).CheckURL("http://127.0.0.1/hook"); err != nil {
+		t.Errorf("private addresses must be allowed when configured, got %v", err)
+	}
+}